@@ -0,0 +1,150 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./api/proto/gen/account/v1/account_grpc.pb.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./api/proto/gen/account/v1/account_grpc.pb.go -package=accountv1mocks -destination=./api/proto/gen/account/v1/mocks/account_grpc.mock.go
+//
+
+// Package accountv1mocks is a generated GoMock package.
+package accountv1mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	accountv1 "webook/api/proto/gen/account/v1"
+
+	gomock "go.uber.org/mock/gomock"
+	grpc "google.golang.org/grpc"
+)
+
+// MockAccountServiceClient is a mock of AccountServiceClient interface.
+type MockAccountServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceClientMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountServiceClientMockRecorder is the mock recorder for MockAccountServiceClient.
+type MockAccountServiceClientMockRecorder struct {
+	mock *MockAccountServiceClient
+}
+
+// NewMockAccountServiceClient creates a new mock instance.
+func NewMockAccountServiceClient(ctrl *gomock.Controller) *MockAccountServiceClient {
+	mock := &MockAccountServiceClient{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountServiceClient) EXPECT() *MockAccountServiceClientMockRecorder {
+	return m.recorder
+}
+
+// Credit mocks base method.
+func (m *MockAccountServiceClient) Credit(ctx context.Context, in *accountv1.CreditRequest, opts ...grpc.CallOption) (*accountv1.CreditResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Credit", varargs...)
+	ret0, _ := ret[0].(*accountv1.CreditResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockAccountServiceClientMockRecorder) Credit(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockAccountServiceClient)(nil).Credit), varargs...)
+}
+
+// MockAccountServiceServer is a mock of AccountServiceServer interface.
+type MockAccountServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceServerMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountServiceServerMockRecorder is the mock recorder for MockAccountServiceServer.
+type MockAccountServiceServerMockRecorder struct {
+	mock *MockAccountServiceServer
+}
+
+// NewMockAccountServiceServer creates a new mock instance.
+func NewMockAccountServiceServer(ctrl *gomock.Controller) *MockAccountServiceServer {
+	mock := &MockAccountServiceServer{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountServiceServer) EXPECT() *MockAccountServiceServerMockRecorder {
+	return m.recorder
+}
+
+// Credit mocks base method.
+func (m *MockAccountServiceServer) Credit(arg0 context.Context, arg1 *accountv1.CreditRequest) (*accountv1.CreditResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", arg0, arg1)
+	ret0, _ := ret[0].(*accountv1.CreditResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockAccountServiceServerMockRecorder) Credit(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockAccountServiceServer)(nil).Credit), arg0, arg1)
+}
+
+// mustEmbedUnimplementedAccountServiceServer mocks base method.
+func (m *MockAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedAccountServiceServer")
+}
+
+// mustEmbedUnimplementedAccountServiceServer indicates an expected call of mustEmbedUnimplementedAccountServiceServer.
+func (mr *MockAccountServiceServerMockRecorder) mustEmbedUnimplementedAccountServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedAccountServiceServer", reflect.TypeOf((*MockAccountServiceServer)(nil).mustEmbedUnimplementedAccountServiceServer))
+}
+
+// MockUnsafeAccountServiceServer is a mock of UnsafeAccountServiceServer interface.
+type MockUnsafeAccountServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeAccountServiceServerMockRecorder
+	isgomock struct{}
+}
+
+// MockUnsafeAccountServiceServerMockRecorder is the mock recorder for MockUnsafeAccountServiceServer.
+type MockUnsafeAccountServiceServerMockRecorder struct {
+	mock *MockUnsafeAccountServiceServer
+}
+
+// NewMockUnsafeAccountServiceServer creates a new mock instance.
+func NewMockUnsafeAccountServiceServer(ctrl *gomock.Controller) *MockUnsafeAccountServiceServer {
+	mock := &MockUnsafeAccountServiceServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeAccountServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeAccountServiceServer) EXPECT() *MockUnsafeAccountServiceServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedAccountServiceServer mocks base method.
+func (m *MockUnsafeAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedAccountServiceServer")
+}
+
+// mustEmbedUnimplementedAccountServiceServer indicates an expected call of mustEmbedUnimplementedAccountServiceServer.
+func (mr *MockUnsafeAccountServiceServerMockRecorder) mustEmbedUnimplementedAccountServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedAccountServiceServer", reflect.TypeOf((*MockUnsafeAccountServiceServer)(nil).mustEmbedUnimplementedAccountServiceServer))
+}
@@ -4,10 +4,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 	"webook/internal/events"
+	"webook/pkg/grpcx"
 )
 
 type App struct {
 	server    *gin.Engine
 	consumers []events.Consumer
 	cron      *cron.Cron
+	// 和 web 服务部署在一起的 gRPC 服务
+	grpcServer *grpcx.Server
 }
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/spf13/pflag"
+	"log"
+	"net/http"
+	"webook/internal/service/oauth2/oidc/simulator"
+)
+
+// 本地的 OIDC 提供方，client ID 和密钥要和 webook 的 oauth2.oidc 配置一样，
+// webook 那边启动的时候带上 OIDC_LOCAL_CLIENT_SECRET=webook-secret
+//
+//	go run ./cmd/oidc-simulator --addr=:8098
+func main() {
+	addr := pflag.String("addr", ":8098", "监听地址")
+	clientID := pflag.String("client-id", "webook", "client ID")
+	clientSecret := pflag.String("client-secret", "webook-secret", "client 密钥")
+	pflag.Parse()
+	server := simulator.NewServer(*clientID, *clientSecret)
+	log.Println("OIDC 模拟器启动", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server.Handler()))
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/spf13/pflag"
+	"log"
+	"net/http"
+	"webook/pkg/wechatpay"
+	"webook/pkg/wechatpay/simulator"
+)
+
+// 本地的微信支付模拟器，APIv3 密钥要和 webook 的 wechatpay.apiV3Key 配置一样
+//
+//	go run ./cmd/wechatpay-simulator --addr=:8097
+//	curl -X POST localhost:8097/sim/pay -d '{"out_trade_no":"reward-1"}'
+func main() {
+	addr := pflag.String("addr", ":8097", "监听地址")
+	key := pflag.String("key", "Tg1dZUa1k7qOmNvLZ7vI3m2Q8fKeBxEa", "APIv3 密钥，32 个字节")
+	pflag.Parse()
+	server := simulator.NewServer(wechatpay.NewSigner(*key))
+	log.Println("微信支付模拟器启动", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server.Handler()))
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// AccessTokenPrefix 个人访问令牌的前缀，一眼能跟 JWT 区分开，泄露了也方便扫描出来
+const AccessTokenPrefix = "wbk_pat_"
+
+// 个人访问令牌能用的权限
+const (
+	ScopeArticlesRead  = "articles:read"
+	ScopeArticlesWrite = "articles:write"
+	ScopeProfileRead   = "profile:read"
+)
+
+var AccessTokenScopes = []string{ScopeArticlesRead, ScopeArticlesWrite, ScopeProfileRead}
+
+// AccessToken 个人访问令牌，给脚本和第三方集成用。只存哈希，明文只在创建的时候返回一次
+type AccessToken struct {
+	Id   int64
+	Uid  int64
+	Name string
+	// 明文的前几位，方便用户认出是哪一个
+	Prefix   string
+	Scopes   []string
+	Expire   time.Time
+	LastUsed time.Time
+	Ctime    time.Time
+}
+
+func (t AccessToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+func (t AccessToken) Expired(now time.Time) bool {
+	return !now.Before(t.Expire)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Credit 一次入账，同一个 Biz + BizId 只能入账一次
+type Credit struct {
+	Biz   string
+	BizId int64
+	Items []CreditItem
+}
+
+// CreditItem 给某个账户加钱
+type CreditItem struct {
+	// 系统账户没有 Uid
+	Uid         int64
+	AccountType AccountType
+	// 单位是分
+	Amt      int64
+	Currency string
+}
+
+// Account 一个用户的某一类账户
+type Account struct {
+	Id       int64
+	Uid      int64
+	Type     AccountType
+	Currency string
+	// 余额，单位是分
+	Balance int64
+	Utime   time.Time
+}
+
+// AccountActivity 账户的一条流水，入账是正数，出账是负数
+type AccountActivity struct {
+	Id          int64
+	Uid         int64
+	AccountType AccountType
+	Biz         string
+	BizId       int64
+	Amt         int64
+	Currency    string
+	// 这条流水之后的余额
+	Balance int64
+	Ctime   time.Time
+}
+
+type AccountType uint8
+
+func (t AccountType) ToUint8() uint8 {
+	return uint8(t)
+}
+
+const (
+	AccountTypeUnknown AccountType = iota
+	// AccountTypeReward 个人的打赏账户
+	AccountTypeReward
+	// AccountTypeSystem 平台的分成账户
+	AccountTypeSystem
+	// AccountTypeClearing 支付渠道的清算账户，所有入账的钱都从这里出，
+	// 所以它的余额是负数，代表还没有跟支付渠道结算的钱
+	AccountTypeClearing
+	// AccountTypeFrozen 个人的冻结账户，提现审核和打款期间钱放在这里
+	AccountTypeFrozen
+)
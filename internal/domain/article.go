@@ -8,8 +8,10 @@ type Article struct {
 	Content string
 	Author  Author
 	Status  ArticleStatus
-	Ctime   time.Time
-	Utime   time.Time
+	// Tags 文章标签，推荐的时候用来计算用户的兴趣
+	Tags  []string
+	Ctime time.Time
+	Utime time.Time
 }
 
 func (a Article) Abstract() string {
@@ -36,6 +38,10 @@ const (
 	ArticleStatusPublished
 	// ArticleStatusPrivate 仅自己可见
 	ArticleStatusPrivate
+	// ArticleStatusPending 命中敏感词，等待审核
+	ArticleStatusPending
+	// ArticleStatusRejected 审核没通过
+	ArticleStatusRejected
 )
 
 type Author struct {
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// AuditLog 管理员的操作记录
+type AuditLog struct {
+	Id       int64
+	Operator int64
+	// 方法加上路由，比如 POST /admin/users/ban
+	Action string
+	// 请求体，太长的会截断
+	Detail string
+	IP     string
+	// 业务错误码，0 是成功，AuditCodeNoResult 是没有走到业务逻辑
+	Code  int
+	Ctime time.Time
+}
+
+// AuditCodeNoResult 参数错误之类的，handler 还没有执行业务逻辑就返回了
+const AuditCodeNoResult = -1
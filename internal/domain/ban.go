@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// BanScope 封禁的范围，按位组合
+type BanScope uint8
+
+const (
+	BanScopeLogin BanScope = 1 << iota
+	BanScopePublish
+	// BanScopeComment 评论、点赞、收藏这些互动
+	BanScopeComment
+
+	// BanScopeAll 全部封禁，已经发表的文章也会下线
+	BanScopeAll = BanScopeLogin | BanScopePublish | BanScopeComment
+)
+
+func (s BanScope) Has(scope BanScope) bool {
+	return s&scope == scope
+}
+
+func (s BanScope) Valid() bool {
+	return s != 0 && s&^BanScopeAll == 0
+}
+
+// UserBan 被封禁的用户，解封之后记录就删掉了
+type UserBan struct {
+	Uid    int64
+	Reason string
+	Scope  BanScope
+	// 零值就是永久封禁
+	Expire time.Time
+	// 哪个管理员封的
+	Operator int64
+	Ctime    time.Time
+}
+
+// Active 过期了的封禁不用删，查的时候判断就可以
+func (b UserBan) Active(now time.Time) bool {
+	return b.Expire.IsZero() || now.Before(b.Expire)
+}
+
+// Restricts 现在是不是禁止了 scope 这种操作
+func (b UserBan) Restricts(scope BanScope, now time.Time) bool {
+	return b.Scope.Has(scope) && b.Active(now)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type Comment struct {
+	Id int64
+	// 评论者，只有 Id 是必然有的
+	Commentator User
+	// 评论的资源
+	Biz   string
+	BizId int64
+	// 评论内容
+	Content string
+	// 根评论，为 nil 说明自己就是根评论
+	RootComment *Comment
+	// 父评论，也就是回复的是哪一条评论
+	ParentComment *Comment
+	Status        CommentStatus
+	Ctime         time.Time
+	Utime         time.Time
+}
+
+type CommentStatus uint8
+
+func (s CommentStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	// CommentStatusNormal 正常展示，零值就是正常状态，兼容已有的数据
+	CommentStatusNormal CommentStatus = iota
+	// CommentStatusPending 命中敏感词，等待审核
+	CommentStatusPending
+	// CommentStatusRejected 审核没通过
+	CommentStatusRejected
+)
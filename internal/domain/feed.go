@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// FeedItem 关注流里面的一条，只记录是哪篇文章，文章内容读的时候再查
+type FeedItem struct {
+	Aid int64
+	// 作者
+	Uid int64
+	// 上线时间，关注流按照这个时间倒序
+	Ctime time.Time
+}
+
+// FeedCursor 关注流的分页游标，同一毫秒可能上线好几篇文章，
+// 所以按照 (Ctime, Aid) 一起排序，只用时间会把边界上的文章漏掉
+type FeedCursor struct {
+	// 上一页最后一条的上线时间，毫秒
+	Ctime int64
+	// 上一页最后一条的文章 ID
+	Aid int64
+}
+
+// IsZero 第一页或者没有下一页
+func (c FeedCursor) IsZero() bool {
+	return c.Ctime == 0 && c.Aid == 0
+}
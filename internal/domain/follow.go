@@ -0,0 +1,15 @@
+package domain
+
+// FollowRelation 关注关系，Follower 关注了 Followee
+type FollowRelation struct {
+	Followee int64
+	Follower int64
+}
+
+// FollowStatics 关注相关的统计数据
+type FollowStatics struct {
+	// 被多少人关注
+	Followers int64
+	// 自己关注了多少人
+	Followees int64
+}
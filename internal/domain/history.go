@@ -1,8 +1,12 @@
 package domain
 
+import "time"
+
 // HistoryRecord 阅读历史
 type HistoryRecord struct {
 	BizId int64
 	Biz   string
 	Uid   int64
+	// 最后一次阅读的时间
+	Utime time.Time
 }
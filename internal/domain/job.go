@@ -13,6 +13,34 @@ type Job struct {
 	Executor   string
 	Cfg        string
 	CancelFunc func()
+
+	// 下面这些只有管理后台查看的时候才有
+	Status JobStatus
+	// 下一次调度的时间
+	NextExecTime time.Time
+	// 运行中的任务会定期续约，更新这个时间
+	Utime time.Time
+}
+
+type JobStatus uint8
+
+const (
+	JobStatusWaiting JobStatus = iota
+	JobStatusRunning
+	JobStatusPaused
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobStatusWaiting:
+		return "waiting"
+	case JobStatusRunning:
+		return "running"
+	case JobStatusPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
 }
 
 func (j Job) NextTime() time.Time {
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// LoginAttempts 最近一个统计窗口里面的登录失败情况
+type LoginAttempts struct {
+	AccountFails int64
+	IPFails      int64
+	// 账号还要锁多久，0 就是没有锁
+	Locked time.Duration
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// ModerationTask 命中敏感词之后，等待人工审核的内容
+type ModerationTask struct {
+	Id    int64
+	Biz   string
+	BizId int64
+	// 内容的作者
+	Uid int64
+	// 提交审核时候的内容快照
+	Content string
+	// 快照的摘要，审核通过之前用来确认线上的内容没有被换掉
+	ContentHash string
+	// 命中的敏感词
+	Hits   []string
+	Status ModerationStatus
+	// 审核人
+	Reviewer int64
+	// 拒绝的原因
+	Reason string
+	Ctime  time.Time
+	Utime  time.Time
+}
+
+type ModerationStatus uint8
+
+func (s ModerationStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	ModerationStatusUnknown ModerationStatus = iota
+	// ModerationStatusPending 等待审核
+	ModerationStatusPending
+	// ModerationStatusApproved 审核通过
+	ModerationStatusApproved
+	// ModerationStatusRejected 审核拒绝
+	ModerationStatusRejected
+	// ModerationStatusOutdated 提交审核之后内容又被改过了，这个任务作废
+	ModerationStatusOutdated
+)
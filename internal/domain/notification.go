@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// Notification 站内通知，同一个资源上同一种还没读的通知会合并成一条，
+// 比如 "X 等 13 人赞了你的文章"
+type Notification struct {
+	Id int64
+	// 接收通知的人
+	Uid  int64
+	Type NotificationType
+	// 通知关联的资源，关注的时候是被关注的人自己
+	Biz     string
+	BizId   int64
+	BizName string
+	// 最近的几个触发者，最新的在前面
+	Actors []int64
+	// 一共有多少人
+	ActorCnt int64
+	// 最近一次的内容，比如评论内容、打赏金额
+	Content string
+	Read    bool
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+type NotificationType uint8
+
+func (t NotificationType) ToUint8() uint8 {
+	return uint8(t)
+}
+
+const (
+	NotificationTypeUnknown NotificationType = iota
+	// NotificationTypeLike 点赞
+	NotificationTypeLike
+	// NotificationTypeComment 评论了你的文章
+	NotificationTypeComment
+	// NotificationTypeReply 回复了你的评论
+	NotificationTypeReply
+	// NotificationTypeFollow 关注了你
+	NotificationTypeFollow
+	// NotificationTypeReward 打赏了你
+	NotificationTypeReward
+	// NotificationTypeSystem 系统发的，比如数据导出好了，文案直接放在 Content 里面
+	NotificationTypeSystem
+)
+
+// NotificationMute 用户关掉了哪些类型的通知，按位记录
+type NotificationMute uint32
+
+func (m NotificationMute) Muted(t NotificationType) bool {
+	return m&(1<<t) != 0
+}
+
+func (m NotificationMute) Set(t NotificationType, muted bool) NotificationMute {
+	if muted {
+		return m | 1<<t
+	}
+	return m &^ (1 << t)
+}
+
+// NotificationPush 推给在线用户的消息，新通知和未读数一起推，
+// 客户端不用再来拉一次未读数
+type NotificationPush struct {
+	Uid int64
+	// 只是未读数变了，比如在别的设备上读了，这里是 nil
+	Notification *Notification
+	Unread       map[NotificationType]int64
+}
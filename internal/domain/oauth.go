@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// OAuthIdentity 绑定在用户上的第三方账号，一个用户每个提供方最多绑定一个
+type OAuthIdentity struct {
+	Uid int64
+	// wechat、github 或者配置里面 OIDC 提供方的名字
+	Provider string
+	// 在提供方那边的唯一标识，微信是 openid，OIDC 是 sub
+	Subject string
+	// 只有微信有，同一个开放平台下面的应用是一样的
+	UnionId string
+	// 提供方确认过的邮箱，没有就是空的
+	Email string
+	Name  string
+	Ctime time.Time
+}
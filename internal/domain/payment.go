@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+type Payment struct {
+	Id  int64
+	Amt Amount
+	// 业务方的单号，比如打赏就是 reward-{rid}
+	BizTradeNO  string
+	Description string
+	Status      PaymentStatus
+	// 第三方支付平台的交易号
+	TxnID string
+	Utime time.Time
+}
+
+type Amount struct {
+	Currency string
+	// 单位是分
+	Total int64
+}
+
+type PaymentStatus uint8
+
+func (s PaymentStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	PaymentStatusUnknown PaymentStatus = iota
+	// PaymentStatusInit 等待支付
+	PaymentStatusInit
+	// PaymentStatusSuccess 支付成功
+	PaymentStatusSuccess
+	// PaymentStatusFailed 支付失败，包括关单
+	PaymentStatusFailed
+	// PaymentStatusRefund 已经退款
+	PaymentStatusRefund
+)
+
+// paymentTransitions 支付的状态机，key 能够变成 value 里面的状态
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusInit:    {PaymentStatusSuccess, PaymentStatusFailed},
+	PaymentStatusSuccess: {PaymentStatusRefund},
+}
+
+// CanTransitTo 失败和退款是最终状态，不能再变
+func (s PaymentStatus) CanTransitTo(to PaymentStatus) bool {
+	for _, next := range paymentTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package domain
+
+import "time"
+
+// DataExportStatus 个人数据导出的进度
+type DataExportStatus uint8
+
+func (s DataExportStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s DataExportStatus) String() string {
+	switch s {
+	case DataExportStatusPending:
+		return "pending"
+	case DataExportStatusRunning:
+		return "running"
+	case DataExportStatusDone:
+		return "done"
+	case DataExportStatusFailed:
+		return "failed"
+	case DataExportStatusExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	DataExportStatusUnknown DataExportStatus = iota
+	// DataExportStatusPending 等定时任务来打包
+	DataExportStatusPending
+	DataExportStatusRunning
+	// DataExportStatusDone 打包好了，可以下载
+	DataExportStatusDone
+	DataExportStatusFailed
+	// DataExportStatusExpired 过了下载期限，文件已经删掉了
+	DataExportStatusExpired
+)
+
+// DataExport 用户申请导出自己的数据，异步打包成一个 zip
+type DataExport struct {
+	Id     int64
+	Uid    int64
+	Status DataExportStatus
+	// 打包好的文件在存储里面的 key
+	FileKey string
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+// DeletedUserNickname 注销之后的昵称，文章、评论下面显示这个
+const DeletedUserNickname = "已注销用户"
+
+// AccountDeletionStatus 注销申请的状态
+type AccountDeletionStatus uint8
+
+func (s AccountDeletionStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s AccountDeletionStatus) String() string {
+	switch s {
+	case AccountDeletionStatusPending:
+		return "pending"
+	case AccountDeletionStatusCanceled:
+		return "canceled"
+	case AccountDeletionStatusDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	AccountDeletionStatusUnknown AccountDeletionStatus = iota
+	// AccountDeletionStatusPending 冷静期里面，还可以撤销
+	AccountDeletionStatusPending
+	AccountDeletionStatusCanceled
+	// AccountDeletionStatusDone 已经注销，数据匿名化了
+	AccountDeletionStatusDone
+)
+
+// AccountDeletion 注销申请，冷静期过了之后定时任务来真正注销
+type AccountDeletion struct {
+	Uid    int64
+	Status AccountDeletionStatus
+	// 冷静期结束的时间
+	ScheduledAt time.Time
+	Ctime       time.Time
+}
@@ -0,0 +1,33 @@
+package domain
+
+// ProfilePrivacy 个人主页上隐藏了哪些内容，按位记录
+type ProfilePrivacy uint8
+
+const (
+	// ProfileHideArticles 不展示发表的文章
+	ProfileHideArticles ProfilePrivacy = 1 << iota
+	// ProfileHideFollows 不展示关注数和粉丝数
+	ProfileHideFollows
+	// ProfileHideInteractions 不展示文章的阅读、点赞、收藏总数
+	ProfileHideInteractions
+)
+
+func (p ProfilePrivacy) Hidden(item ProfilePrivacy) bool {
+	return p&item != 0
+}
+
+func (p ProfilePrivacy) Set(item ProfilePrivacy, hidden bool) ProfilePrivacy {
+	if hidden {
+		return p | item
+	}
+	return p &^ item
+}
+
+// PublicProfile 别人看到的个人主页，隐藏了的部分是零值
+type PublicProfile struct {
+	User User
+	// 最近发表的文章，一页
+	Articles []Article
+	// 所有发表的文章加起来的互动数据
+	Interactive Interactive
+}
@@ -0,0 +1,51 @@
+package domain
+
+// 权限，管理后台的接口按照权限控制
+const (
+	PermUserRead        = "user:read"
+	PermUserBan         = "user:ban"
+	PermUserUnlock      = "user:unlock"
+	PermRoleGrant       = "role:grant"
+	PermArticleWithdraw = "article:withdraw"
+	PermModeration      = "moderation:review"
+	PermWithdrawReview  = "withdraw:review"
+	PermJobRead         = "job:read"
+	PermAuditRead       = "audit:read"
+)
+
+// 角色，一个用户可以有好几个
+const (
+	// RoleAdmin 什么都能做
+	RoleAdmin = "admin"
+	// RoleModerator 处理违规的内容和用户
+	RoleModerator = "moderator"
+	// RoleSupport 客服
+	RoleSupport = "support"
+	// RoleFinance 审核提现
+	RoleFinance = "finance"
+)
+
+// RolePermissions 角色和权限的对应关系写死在代码里面，数据库只记用户有哪些角色
+var RolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermUserRead, PermUserBan, PermUserUnlock, PermRoleGrant, PermArticleWithdraw,
+		PermModeration, PermWithdrawReview, PermJobRead, PermAuditRead,
+	},
+	RoleModerator: {PermUserRead, PermUserBan, PermArticleWithdraw, PermModeration},
+	RoleSupport:   {PermUserRead, PermUserUnlock},
+	RoleFinance:   {PermUserRead, PermWithdrawReview},
+}
+
+// Roles 一个用户的所有角色
+type Roles []string
+
+func (r Roles) HasPermission(perm string) bool {
+	for _, role := range r {
+		for _, p := range RolePermissions[role] {
+			if p == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
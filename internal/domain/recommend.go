@@ -0,0 +1,7 @@
+package domain
+
+// RecommendItem 推荐给某个用户的文章以及它的分数
+type RecommendItem struct {
+	Aid   int64
+	Score float64
+}
@@ -0,0 +1,48 @@
+package domain
+
+// Reward 一次打赏
+type Reward struct {
+	Id  int64
+	Uid int64
+	// 打赏的东西
+	Target Target
+	// 打赏的金额，单位是分
+	Amt    int64
+	Status RewardStatus
+}
+
+// Target 被打赏的东西
+type Target struct {
+	Biz     string
+	BizId   int64
+	BizName string
+	// 收钱的人
+	Uid int64
+}
+
+// Completed 是否已经有了最终结果
+func (r Reward) Completed() bool {
+	return r.Status == RewardStatusFailed || r.Status == RewardStatusPayed
+}
+
+type RewardStatus uint8
+
+func (s RewardStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	RewardStatusUnknown RewardStatus = iota
+	// RewardStatusInit 等待支付
+	RewardStatusInit
+	// RewardStatusPayed 支付成功
+	RewardStatusPayed
+	// RewardStatusFailed 支付失败
+	RewardStatusFailed
+)
+
+// CodeURL 扫码支付的二维码链接
+type CodeURL struct {
+	Rid int64
+	URL string
+}
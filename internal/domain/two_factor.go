@@ -0,0 +1,12 @@
+package domain
+
+// TwoFactor 用户的 TOTP 两步验证设置
+type TwoFactor struct {
+	Uid int64
+	// base32 编码的密钥
+	Secret string
+	// 扫码之后还要输入一次验证码确认，确认了才算开启
+	Enabled bool
+	// 最近一次用过的时间步，防止同一个验证码被用两次
+	LastStep int64
+}
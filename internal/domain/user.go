@@ -9,17 +9,51 @@ type User struct {
 	Nickname    string
 	Birthday    time.Time
 	Description string
+	// 邮箱注册的用户要收到验证码确认之后才算验证过
+	EmailVerified bool
 
 	Phone string
 
 	// UTC 0 的时区
 	Ctime time.Time
 
-	WechatInfo WechatInfo
+	// 关掉了哪些通知
+	NotificationMute NotificationMute
+
+	// Handle 个人主页的地址 /u/:handle，全局唯一，没设置过是空的
+	Handle string
+	// 上一次改 Handle 的时间，改完要过一段时间才能再改
+	HandleUtime time.Time
+	// 头像的地址，图片自己传到对象存储上
+	Avatar string
+	// 个人主页上隐藏了哪些内容
+	ProfilePrivacy ProfilePrivacy
 
 	//Addr Address
 }
 
+// IdentityType 可以用来登录的身份，一个用户可以同时绑定好几种，
+// 第三方账号放在 OAuthIdentity 里面
+type IdentityType uint8
+
+const (
+	IdentityTypeUnknown IdentityType = iota
+	IdentityTypePhone
+	IdentityTypeEmail
+)
+
+// LoginIdentities 还能用来登录的手机号和邮箱，邮箱要设置过密码才能登录
+func (u User) LoginIdentities() []IdentityType {
+	var res []IdentityType
+	if u.Phone != "" {
+		res = append(res, IdentityTypePhone)
+	}
+	if u.Email != "" && u.Password != "" {
+		res = append(res, IdentityTypeEmail)
+	}
+	return res
+}
+
 //type Address struct {
 //	Province string
 //	Region   string
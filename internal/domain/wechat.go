@@ -1,6 +0,0 @@
-package domain
-
-type WechatInfo struct {
-	UnionId string
-	OpenId  string
-}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// Withdrawal 一次提现申请
+type Withdrawal struct {
+	Id  int64
+	Uid int64
+	// 申请提现的金额，单位是分，手续费从这里面扣
+	Amt      int64
+	Fee      int64
+	Currency string
+	Status   WithdrawalStatus
+	// 打款渠道的流水号
+	TxnID string
+	Ctime time.Time
+	Utime time.Time
+}
+
+// Paid 实际打到用户手里的钱
+func (w Withdrawal) Paid() int64 {
+	return w.Amt - w.Fee
+}
+
+// WithdrawalLog 提现的每一次状态变化都要留痕
+type WithdrawalLog struct {
+	Id  int64
+	Wid int64
+	// 操作人，系统自动处理的是 0
+	Operator int64
+	From     WithdrawalStatus
+	To       WithdrawalStatus
+	Remark   string
+	Ctime    time.Time
+}
+
+type WithdrawalStatus uint8
+
+func (s WithdrawalStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	WithdrawalStatusUnknown WithdrawalStatus = iota
+	// WithdrawalStatusPending 钱已经冻结，等待管理员审核
+	WithdrawalStatusPending
+	// WithdrawalStatusApproved 审核通过，正在打款
+	WithdrawalStatusApproved
+	// WithdrawalStatusSuccess 打款成功
+	WithdrawalStatusSuccess
+	// WithdrawalStatusFailed 打款失败，钱已经解冻
+	WithdrawalStatusFailed
+	// WithdrawalStatusRejected 审核拒绝，钱已经解冻
+	WithdrawalStatusRejected
+)
@@ -8,6 +8,18 @@ const (
 	UserInvalidOrPassword = 401002
 	// UserDuplicateEmail 用户邮箱冲突
 	UserDuplicateEmail = 401003
+	// UserIdentityConflict 要绑定的手机号、邮箱或者微信已经是别的账号的了
+	UserIdentityConflict = 401004
+	// UserTwoFactorRequired 密码对了，还要输入两步验证的验证码
+	UserTwoFactorRequired = 401005
+	// UserCaptchaRequired 登录失败太多次，要先做人机验证
+	UserCaptchaRequired = 401006
+	// UserLoginLocked 登录失败太多次，暂时不让登录
+	UserLoginLocked = 401007
+	// UserBanned 账号被管理员封禁了
+	UserBanned = 401008
+	// UserEmailNotVerified 邮箱注册之后还没有验证，不能用邮箱密码登录
+	UserEmailNotVerified = 401009
 	// UserInternalServerError 统一的用户模块的系统错误
 	UserInternalServerError = 501001
 	// UserSetTokenInternalServerError 用户模块设置token错误
@@ -16,6 +28,14 @@ const (
 
 const (
 	// ArticleInvalidInput 文章模块的统一的错误码
-	ArticleInvalidInput        = 402001
+	ArticleInvalidInput = 402001
+	// ArticlePublishBanned 被封禁了，不能发表文章
+	ArticlePublishBanned       = 402002
 	ArticleInternalServerError = 502001
 )
+
+// 评论、点赞、收藏这些互动
+const (
+	// InteractionBanned 被封禁了，不能评论、点赞和收藏
+	InteractionBanned = 403001
+)
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/events/activity/producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/events/activity/producer.go -package=evtmocks -destination=./internal/events/activity/mocks/producer.mock.go
+//
+
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+	activity "webook/internal/events/activity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProduceCommentEvent mocks base method.
+func (m *MockProducer) ProduceCommentEvent(evt activity.CommentEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceCommentEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceCommentEvent indicates an expected call of ProduceCommentEvent.
+func (mr *MockProducerMockRecorder) ProduceCommentEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceCommentEvent", reflect.TypeOf((*MockProducer)(nil).ProduceCommentEvent), evt)
+}
+
+// ProduceFollowEvent mocks base method.
+func (m *MockProducer) ProduceFollowEvent(evt activity.FollowEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceFollowEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceFollowEvent indicates an expected call of ProduceFollowEvent.
+func (mr *MockProducerMockRecorder) ProduceFollowEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceFollowEvent", reflect.TypeOf((*MockProducer)(nil).ProduceFollowEvent), evt)
+}
+
+// ProduceLikeEvent mocks base method.
+func (m *MockProducer) ProduceLikeEvent(evt activity.LikeEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceLikeEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceLikeEvent indicates an expected call of ProduceLikeEvent.
+func (mr *MockProducerMockRecorder) ProduceLikeEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceLikeEvent", reflect.TypeOf((*MockProducer)(nil).ProduceLikeEvent), evt)
+}
+
+// ProduceRewardEvent mocks base method.
+func (m *MockProducer) ProduceRewardEvent(evt activity.RewardEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceRewardEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceRewardEvent indicates an expected call of ProduceRewardEvent.
+func (mr *MockProducerMockRecorder) ProduceRewardEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceRewardEvent", reflect.TypeOf((*MockProducer)(nil).ProduceRewardEvent), evt)
+}
@@ -0,0 +1,92 @@
+package activity
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+)
+
+// 用户之间的互动，通知中心会消费这些消息
+const (
+	TopicLikeEvent    = "activity_like"
+	TopicCommentEvent = "activity_comment"
+	TopicFollowEvent  = "activity_follow"
+	TopicRewardEvent  = "activity_reward"
+)
+
+type Producer interface {
+	ProduceLikeEvent(evt LikeEvent) error
+	ProduceCommentEvent(evt CommentEvent) error
+	ProduceFollowEvent(evt FollowEvent) error
+	ProduceRewardEvent(evt RewardEvent) error
+}
+
+// LikeEvent 点赞，取消点赞不发
+type LikeEvent struct {
+	Biz   string
+	BizId int64
+	Uid   int64
+}
+
+// CommentEvent 发表评论
+type CommentEvent struct {
+	Cid   int64
+	Biz   string
+	BizId int64
+	Uid   int64
+	// 回复的那条评论的作者，直接评论资源的时候是 0
+	ParentUid int64
+	Content   string
+}
+
+type FollowEvent struct {
+	Follower int64
+	Followee int64
+}
+
+// RewardEvent 打赏支付成功
+type RewardEvent struct {
+	Rid     int64
+	Uid     int64
+	Biz     string
+	BizId   int64
+	BizName string
+	// 收钱的人
+	TargetUid int64
+	Amt       int64
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProduceLikeEvent(evt LikeEvent) error {
+	return s.produce(TopicLikeEvent, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceCommentEvent(evt CommentEvent) error {
+	return s.produce(TopicCommentEvent, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceFollowEvent(evt FollowEvent) error {
+	return s.produce(TopicFollowEvent, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceRewardEvent(evt RewardEvent) error {
+	return s.produce(TopicRewardEvent, evt)
+}
+
+func (s *SaramaSyncProducer) produce(topic string, evt any) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
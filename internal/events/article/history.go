@@ -17,8 +17,13 @@ type HistoryRecordConsumer struct {
 	l      logger.LoggerV1
 }
 
+func NewHistoryRecordConsumer(repo repository.HistoryRecordRepository, client sarama.Client, l logger.LoggerV1) *HistoryRecordConsumer {
+	return &HistoryRecordConsumer{repo: repo, client: client, l: l}
+}
+
 func (h *HistoryRecordConsumer) Start() error {
-	cg, err := sarama.NewConsumerGroupFromClient("interactive", h.client)
+	// 和阅读计数是两个独立的业务，所以要用自己的消费者组，不然两边会分摊消息
+	cg, err := sarama.NewConsumerGroupFromClient("history", h.client)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/events/article/producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/events/article/producer.go -package=evtmocks -destination=./internal/events/article/mocks/producer.mock.go
+//
+
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+	article "webook/internal/events/article"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProducePublishedEvent mocks base method.
+func (m *MockProducer) ProducePublishedEvent(evt article.PublishedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProducePublishedEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProducePublishedEvent indicates an expected call of ProducePublishedEvent.
+func (mr *MockProducerMockRecorder) ProducePublishedEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProducePublishedEvent", reflect.TypeOf((*MockProducer)(nil).ProducePublishedEvent), evt)
+}
+
+// ProduceReadEvent mocks base method.
+func (m *MockProducer) ProduceReadEvent(evt article.ReadEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceReadEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceReadEvent indicates an expected call of ProduceReadEvent.
+func (mr *MockProducerMockRecorder) ProduceReadEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceReadEvent", reflect.TypeOf((*MockProducer)(nil).ProduceReadEvent), evt)
+}
+
+// ProduceWithdrawnEvent mocks base method.
+func (m *MockProducer) ProduceWithdrawnEvent(evt article.WithdrawnEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceWithdrawnEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceWithdrawnEvent indicates an expected call of ProduceWithdrawnEvent.
+func (mr *MockProducerMockRecorder) ProduceWithdrawnEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceWithdrawnEvent", reflect.TypeOf((*MockProducer)(nil).ProduceWithdrawnEvent), evt)
+}
@@ -5,10 +5,16 @@ import (
 	"github.com/IBM/sarama"
 )
 
-const TopicReadEvent = "article_read"
+const (
+	TopicReadEvent      = "article_read"
+	TopicPublishedEvent = "article_published"
+	TopicWithdrawnEvent = "article_withdrawn"
+)
 
 type Producer interface {
 	ProduceReadEvent(evt ReadEvent) error
+	ProducePublishedEvent(evt PublishedEvent) error
+	ProduceWithdrawnEvent(evt WithdrawnEvent) error
 }
 
 type ReadEvent struct {
@@ -16,6 +22,21 @@ type ReadEvent struct {
 	Uid int64
 }
 
+// PublishedEvent 文章上线，包括审核通过之后上线
+type PublishedEvent struct {
+	Aid int64
+	// 作者
+	Uid int64
+	// 上线时间，毫秒数
+	Ctime int64
+}
+
+// WithdrawnEvent 文章撤回
+type WithdrawnEvent struct {
+	Aid int64
+	Uid int64
+}
+
 type BatchReadEvent struct {
 	Aids []int64
 	Uids []int64
@@ -30,12 +51,24 @@ func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
 }
 
 func (s *SaramaSyncProducer) ProduceReadEvent(evt ReadEvent) error {
+	return s.produce(TopicReadEvent, evt)
+}
+
+func (s *SaramaSyncProducer) ProducePublishedEvent(evt PublishedEvent) error {
+	return s.produce(TopicPublishedEvent, evt)
+}
+
+func (s *SaramaSyncProducer) ProduceWithdrawnEvent(evt WithdrawnEvent) error {
+	return s.produce(TopicWithdrawnEvent, evt)
+}
+
+func (s *SaramaSyncProducer) produce(topic string, evt any) error {
 	val, err := json.Marshal(evt)
 	if err != nil {
 		return err
 	}
 	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
-		Topic: TopicReadEvent,
+		Topic: topic,
 		Value: sarama.StringEncoder(val),
 	})
 	return err
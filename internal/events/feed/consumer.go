@@ -0,0 +1,65 @@
+package feed
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/samarax"
+)
+
+// EventConsumer 根据文章上线、撤回维护关注流
+type EventConsumer struct {
+	svc    service.FeedService
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewEventConsumer(svc service.FeedService, client sarama.Client, l logger.LoggerV1) *EventConsumer {
+	return &EventConsumer{svc: svc, client: client, l: l}
+}
+
+func (f *EventConsumer) Start() error {
+	// 两种消息的结构不一样，分成两个消费者组
+	err := f.start("feed_published", article.TopicPublishedEvent,
+		samarax.NewHandler[article.PublishedEvent](f.l, f.ConsumePublished))
+	if err != nil {
+		return err
+	}
+	return f.start("feed_withdrawn", article.TopicWithdrawnEvent,
+		samarax.NewHandler[article.WithdrawnEvent](f.l, f.ConsumeWithdrawn))
+}
+
+func (f *EventConsumer) start(group, topic string, handler sarama.ConsumerGroupHandler) error {
+	cg, err := sarama.NewConsumerGroupFromClient(group, f.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(), []string{topic}, handler)
+		if er != nil {
+			f.l.Error("退出消费", logger.String("topic", topic), logger.Error(er))
+		}
+	}()
+	return nil
+}
+
+func (f *EventConsumer) ConsumePublished(msg *sarama.ConsumerMessage, event article.PublishedEvent) error {
+	// 粉丝多的时候要分批写很多收件箱
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return f.svc.PublishItem(ctx, domain.FeedItem{
+		Aid:   event.Aid,
+		Uid:   event.Uid,
+		Ctime: time.UnixMilli(event.Ctime),
+	})
+}
+
+func (f *EventConsumer) ConsumeWithdrawn(msg *sarama.ConsumerMessage, event article.WithdrawnEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return f.svc.WithdrawItem(ctx, event.Aid)
+}
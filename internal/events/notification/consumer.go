@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/samarax"
+)
+
+// EventConsumer 把点赞、评论、关注、打赏转成站内通知
+type EventConsumer struct {
+	svc    service.NotificationService
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewEventConsumer(svc service.NotificationService, client sarama.Client, l logger.LoggerV1) *EventConsumer {
+	return &EventConsumer{svc: svc, client: client, l: l}
+}
+
+func (n *EventConsumer) Start() error {
+	err := n.start("notification_like", activity.TopicLikeEvent,
+		samarax.NewHandler[activity.LikeEvent](n.l, n.ConsumeLike))
+	if err != nil {
+		return err
+	}
+	err = n.start("notification_comment", activity.TopicCommentEvent,
+		samarax.NewHandler[activity.CommentEvent](n.l, n.ConsumeComment))
+	if err != nil {
+		return err
+	}
+	err = n.start("notification_follow", activity.TopicFollowEvent,
+		samarax.NewHandler[activity.FollowEvent](n.l, n.ConsumeFollow))
+	if err != nil {
+		return err
+	}
+	return n.start("notification_reward", activity.TopicRewardEvent,
+		samarax.NewHandler[activity.RewardEvent](n.l, n.ConsumeReward))
+}
+
+func (n *EventConsumer) start(group, topic string, handler sarama.ConsumerGroupHandler) error {
+	cg, err := sarama.NewConsumerGroupFromClient(group, n.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(), []string{topic}, handler)
+		if er != nil {
+			n.l.Error("退出消费", logger.String("topic", topic), logger.Error(er))
+		}
+	}()
+	return nil
+}
+
+func (n *EventConsumer) ConsumeLike(msg *sarama.ConsumerMessage, event activity.LikeEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// 接收人是资源的作者，交给 service 去找
+	return n.svc.Notify(ctx, domain.Notification{
+		Type:  domain.NotificationTypeLike,
+		Biz:   event.Biz,
+		BizId: event.BizId,
+	}, event.Uid)
+}
+
+func (n *EventConsumer) ConsumeComment(msg *sarama.ConsumerMessage, event activity.CommentEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := n.svc.Notify(ctx, domain.Notification{
+		Type:    domain.NotificationTypeComment,
+		Biz:     event.Biz,
+		BizId:   event.BizId,
+		Content: event.Content,
+	}, event.Uid)
+	if err != nil || event.ParentUid <= 0 {
+		return err
+	}
+	// 回复还要通知被回复的人
+	return n.svc.Notify(ctx, domain.Notification{
+		Uid:     event.ParentUid,
+		Type:    domain.NotificationTypeReply,
+		Biz:     event.Biz,
+		BizId:   event.BizId,
+		Content: event.Content,
+	}, event.Uid)
+}
+
+func (n *EventConsumer) ConsumeFollow(msg *sarama.ConsumerMessage, event activity.FollowEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return n.svc.Notify(ctx, domain.Notification{
+		Uid:   event.Followee,
+		Type:  domain.NotificationTypeFollow,
+		Biz:   "user",
+		BizId: event.Followee,
+	}, event.Follower)
+}
+
+func (n *EventConsumer) ConsumeReward(msg *sarama.ConsumerMessage, event activity.RewardEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return n.svc.Notify(ctx, domain.Notification{
+		Uid:     event.TargetUid,
+		Type:    domain.NotificationTypeReward,
+		Biz:     event.Biz,
+		BizId:   event.BizId,
+		BizName: event.BizName,
+		// 金额是分，展示成元
+		Content: fmt.Sprintf("%d.%02d", event.Amt/100, event.Amt%100),
+	}, event.Uid)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/events/payment/producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/events/payment/producer.go -package=evtmocks -destination=./internal/events/payment/mocks/producer.mock.go
+//
+
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+	payment "webook/internal/events/payment"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProducePaymentEvent mocks base method.
+func (m *MockProducer) ProducePaymentEvent(evt payment.PaymentEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProducePaymentEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProducePaymentEvent indicates an expected call of ProducePaymentEvent.
+func (mr *MockProducerMockRecorder) ProducePaymentEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProducePaymentEvent", reflect.TypeOf((*MockProducer)(nil).ProducePaymentEvent), evt)
+}
@@ -0,0 +1,32 @@
+package payment
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+)
+
+type Producer interface {
+	ProducePaymentEvent(evt PaymentEvent) error
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProducePaymentEvent(evt PaymentEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicPaymentEvent,
+		// 同一个业务单的消息落在同一个分区，保证顺序
+		Key:   sarama.StringEncoder(evt.BizTradeNO),
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
@@ -0,0 +1,15 @@
+package payment
+
+const TopicPaymentEvent = "payment_events"
+
+// PaymentEvent 支付有了结果，业务方根据 BizTradeNO 找到自己的单子
+type PaymentEvent struct {
+	BizTradeNO string
+	// 和 domain.PaymentStatus 一致
+	Status uint8
+}
+
+const (
+	PaymentStatusSuccess uint8 = 2
+	PaymentStatusFailed  uint8 = 3
+)
@@ -0,0 +1,59 @@
+package reward
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/payment"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/samarax"
+)
+
+// PaymentEventConsumer 支付成功或者失败之后更新打赏
+type PaymentEventConsumer struct {
+	svc    service.RewardService
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewPaymentEventConsumer(svc service.RewardService, client sarama.Client, l logger.LoggerV1) *PaymentEventConsumer {
+	return &PaymentEventConsumer{svc: svc, client: client, l: l}
+}
+
+func (p *PaymentEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("reward", p.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(), []string{payment.TopicPaymentEvent},
+			samarax.NewHandler[payment.PaymentEvent](p.l, p.Consume))
+		if er != nil {
+			p.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+func (p *PaymentEventConsumer) Consume(msg *sarama.ConsumerMessage, event payment.PaymentEvent) error {
+	var status domain.RewardStatus
+	switch event.Status {
+	case payment.PaymentStatusSuccess:
+		status = domain.RewardStatusPayed
+	case payment.PaymentStatusFailed:
+		status = domain.RewardStatusFailed
+	default:
+		// 中间状态不关心
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	err := p.svc.UpdateReward(ctx, event.BizTradeNO, status)
+	if err == service.ErrNotRewardTradeNO {
+		// 别的业务的支付
+		return nil
+	}
+	return err
+}
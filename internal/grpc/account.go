@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	accountv1 "webook/api/proto/gen/account/v1"
+	"webook/internal/domain"
+	"webook/internal/service"
+)
+
+// AccountServiceServer 把 AccountService 适配成 gRPC 接口
+type AccountServiceServer struct {
+	accountv1.UnimplementedAccountServiceServer
+	svc service.AccountService
+}
+
+func NewAccountServiceServer(svc service.AccountService) *AccountServiceServer {
+	return &AccountServiceServer{svc: svc}
+}
+
+func (a *AccountServiceServer) Register(server *grpc.Server) {
+	accountv1.RegisterAccountServiceServer(server, a)
+}
+
+func (a *AccountServiceServer) Credit(ctx context.Context, req *accountv1.CreditRequest) (*accountv1.CreditResponse, error) {
+	err := a.svc.Credit(ctx, domain.Credit{
+		Biz:   req.GetBiz(),
+		BizId: req.GetBizId(),
+		Items: slice.Map[*accountv1.CreditItem, domain.CreditItem](req.GetItems(),
+			func(idx int, src *accountv1.CreditItem) domain.CreditItem {
+				return domain.CreditItem{
+					Uid: src.GetUid(),
+					// 两边的取值是一样的
+					AccountType: domain.AccountType(src.GetAccountType()),
+					Amt:         src.GetAmt(),
+					Currency:    src.GetCurrency(),
+				}
+			}),
+	})
+	switch err {
+	case nil:
+		return &accountv1.CreditResponse{}, nil
+	case service.ErrDuplicateCredit:
+		// 调用方靠这个错误码判断是重复入账
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case service.ErrInvalidCredit:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return nil, err
+	}
+}
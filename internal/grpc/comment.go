@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	"webook/internal/domain"
+	"webook/internal/service"
+)
+
+// CommentServiceServer 把 CommentService 适配成 gRPC 接口
+type CommentServiceServer struct {
+	commentv1.UnimplementedCommentServiceServer
+	svc service.CommentService
+}
+
+func NewCommentServiceServer(svc service.CommentService) *CommentServiceServer {
+	return &CommentServiceServer{svc: svc}
+}
+
+func (c *CommentServiceServer) Register(server *grpc.Server) {
+	commentv1.RegisterCommentServiceServer(server, c)
+}
+
+func (c *CommentServiceServer) GetCommentList(ctx context.Context, req *commentv1.CommentListRequest) (*commentv1.CommentListResponse, error) {
+	if req.GetLimit() <= 0 || req.GetLimit() > 100 {
+		return nil, status.Error(codes.InvalidArgument, "limit 不合法")
+	}
+	cs, err := c.svc.GetCommentList(ctx, req.GetBiz(), req.GetBizid(), req.GetMinId(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.CommentListResponse{
+		Comments: c.toDTOs(cs),
+	}, nil
+}
+
+func (c *CommentServiceServer) DeleteComment(ctx context.Context, req *commentv1.DeleteCommentRequest) (*commentv1.DeleteCommentResponse, error) {
+	uid, ok := uidFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未登录")
+	}
+	err := c.svc.DeleteComment(ctx, uid, req.GetId())
+	if err == service.ErrCommentPermission {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.DeleteCommentResponse{}, nil
+}
+
+func (c *CommentServiceServer) CreateComment(ctx context.Context, req *commentv1.CreateCommentRequest) (*commentv1.CreateCommentResponse, error) {
+	uid, ok := uidFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未登录")
+	}
+	cmt := req.GetComment()
+	if cmt == nil || cmt.GetContent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "评论内容不能为空")
+	}
+	// 评论人以网关透传的为准，请求体里面的只能是同一个人
+	if cmt.GetUid() != 0 && cmt.GetUid() != uid {
+		return nil, status.Error(codes.PermissionDenied, "不能以其他用户的身份评论")
+	}
+	dc := c.toDomain(cmt)
+	dc.Commentator = domain.User{Id: uid}
+	_, err := c.svc.CreateComment(ctx, dc)
+	switch err {
+	case nil:
+		return &commentv1.CreateCommentResponse{}, nil
+	case service.ErrCommentNotFound:
+		return nil, status.Error(codes.NotFound, "回复的评论不存在")
+	case service.ErrInvalidCommentTree:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return nil, err
+	}
+}
+
+func (c *CommentServiceServer) GetMoreReplies(ctx context.Context, req *commentv1.GetMoreRepliesRequest) (*commentv1.GetMoreRepliesResponse, error) {
+	if req.GetLimit() <= 0 || req.GetLimit() > 100 {
+		return nil, status.Error(codes.InvalidArgument, "limit 不合法")
+	}
+	cs, err := c.svc.GetMoreReplies(ctx, req.GetRid(), req.GetMaxId(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.GetMoreRepliesResponse{
+		Replies: c.toDTOs(cs),
+	}, nil
+}
+
+func (c *CommentServiceServer) toDTOs(cs []domain.Comment) []*commentv1.Comment {
+	return slice.Map[domain.Comment, *commentv1.Comment](cs, func(idx int, src domain.Comment) *commentv1.Comment {
+		return c.toDTO(src)
+	})
+}
+
+func (c *CommentServiceServer) toDTO(src domain.Comment) *commentv1.Comment {
+	res := &commentv1.Comment{
+		Id:      src.Id,
+		Uid:     src.Commentator.Id,
+		Biz:     src.Biz,
+		Bizid:   src.BizId,
+		Content: src.Content,
+		Ctime:   timestamppb.New(src.Ctime),
+		Utime:   timestamppb.New(src.Utime),
+	}
+	if src.RootComment != nil {
+		res.RootComment = &commentv1.Comment{Id: src.RootComment.Id}
+	}
+	if src.ParentComment != nil {
+		res.ParentComment = &commentv1.Comment{Id: src.ParentComment.Id}
+	}
+	return res
+}
+
+func (c *CommentServiceServer) toDomain(src *commentv1.Comment) domain.Comment {
+	res := domain.Comment{
+		Id: src.GetId(),
+		Commentator: domain.User{
+			Id: src.GetUid(),
+		},
+		Biz:     src.GetBiz(),
+		BizId:   src.GetBizid(),
+		Content: src.GetContent(),
+	}
+	if src.GetRootComment() != nil {
+		res.RootComment = &domain.Comment{Id: src.GetRootComment().GetId()}
+	}
+	if src.GetParentComment() != nil {
+		res.ParentComment = &domain.Comment{Id: src.GetParentComment().GetId()}
+	}
+	return res
+}
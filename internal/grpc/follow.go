@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/internal/domain"
+	"webook/internal/service"
+)
+
+// followerListLimit 粉丝列表的接口没有分页参数，只返回最近的这么多个
+const followerListLimit = 100
+
+// FollowServiceServer 把 FollowRelationService 适配成 gRPC 接口
+type FollowServiceServer struct {
+	followv1.UnimplementedFollowServiceServer
+	svc service.FollowRelationService
+}
+
+func NewFollowServiceServer(svc service.FollowRelationService) *FollowServiceServer {
+	return &FollowServiceServer{svc: svc}
+}
+
+func (f *FollowServiceServer) Register(server *grpc.Server) {
+	followv1.RegisterFollowServiceServer(server, f)
+}
+
+func (f *FollowServiceServer) GetFollowee(ctx context.Context, req *followv1.GetFolloweeRequest) (*followv1.GetFolloweeResponse, error) {
+	if req.GetLimit() <= 0 || req.GetLimit() > 100 || req.GetOffset() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "分页参数不合法")
+	}
+	rs, err := f.svc.GetFollowee(ctx, req.GetFollower(), req.GetOffset(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFolloweeResponse{
+		FollowRelations: f.toDTOs(rs),
+	}, nil
+}
+
+func (f *FollowServiceServer) GetFollower(ctx context.Context, req *followv1.GetFollowerRequest) (*followv1.GetFollowerResponse, error) {
+	rs, err := f.svc.GetFollower(ctx, req.GetFollowee(), 0, followerListLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFollowerResponse{
+		FollowRelations: f.toDTOs(rs),
+	}, nil
+}
+
+func (f *FollowServiceServer) FollowInfo(ctx context.Context, req *followv1.FollowInfoRequest) (*followv1.FollowInfoResponse, error) {
+	r, err := f.svc.FollowInfo(ctx, req.GetFollower(), req.GetFollowee())
+	if err == service.ErrFollowRelationNotFound {
+		// 没有关注，返回空的关系
+		return &followv1.FollowInfoResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.FollowInfoResponse{
+		FollowRelation: f.toDTO(r),
+	}, nil
+}
+
+func (f *FollowServiceServer) Follow(ctx context.Context, req *followv1.FollowRequest) (*followv1.FollowResponse, error) {
+	err := f.svc.Follow(ctx, req.GetFollower(), req.GetFollowee())
+	if err == service.ErrFollowSelf {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.FollowResponse{}, nil
+}
+
+func (f *FollowServiceServer) CancelFollow(ctx context.Context, req *followv1.CancelFollowRequest) (*followv1.CancelFollowResponse, error) {
+	err := f.svc.CancelFollow(ctx, req.GetFollower(), req.GetFollowee())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.CancelFollowResponse{}, nil
+}
+
+func (f *FollowServiceServer) GetFollowStatic(ctx context.Context, req *followv1.GetFollowStaticRequest) (*followv1.GetFollowStaticResponse, error) {
+	s, err := f.svc.GetFollowStatics(ctx, req.GetFollowee())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFollowStaticResponse{
+		FollowStatic: &followv1.FollowStatic{
+			Followers: s.Followers,
+			Followees: s.Followees,
+		},
+	}, nil
+}
+
+func (f *FollowServiceServer) toDTOs(rs []domain.FollowRelation) []*followv1.FollowRelation {
+	return slice.Map[domain.FollowRelation, *followv1.FollowRelation](rs, func(idx int, src domain.FollowRelation) *followv1.FollowRelation {
+		return f.toDTO(src)
+	})
+}
+
+func (f *FollowServiceServer) toDTO(r domain.FollowRelation) *followv1.FollowRelation {
+	return &followv1.FollowRelation{
+		Followee: r.Followee,
+		Follower: r.Follower,
+	}
+}
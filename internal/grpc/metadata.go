@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"strconv"
+)
+
+// uidKey 网关把当前登录用户放在 metadata 里面透传过来
+const uidKey = "uid"
+
+// WithUid 网关调用 gRPC 之前把当前用户带上
+func WithUid(ctx context.Context, uid int64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, uidKey, strconv.FormatInt(uid, 10))
+}
+
+// uidFromContext 拿不到或者解析不了就返回 false，调用方应该当成未登录处理
+func uidFromContext(ctx context.Context) (int64, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	vals := md.Get(uidKey)
+	if len(vals) == 0 {
+		return 0, false
+	}
+	uid, err := strconv.ParseInt(vals[0], 10, 64)
+	if err != nil || uid <= 0 {
+		return 0, false
+	}
+	return uid, true
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pmtv1 "webook/api/proto/gen/payment/v1"
+	"webook/internal/domain"
+	"webook/internal/service"
+)
+
+// WechatPaymentServiceServer 把 PaymentService 适配成 gRPC 接口
+type WechatPaymentServiceServer struct {
+	pmtv1.UnimplementedWechatPaymentServiceServer
+	svc service.PaymentService
+}
+
+func NewWechatPaymentServiceServer(svc service.PaymentService) *WechatPaymentServiceServer {
+	return &WechatPaymentServiceServer{svc: svc}
+}
+
+func (w *WechatPaymentServiceServer) Register(server *grpc.Server) {
+	pmtv1.RegisterWechatPaymentServiceServer(server, w)
+}
+
+func (w *WechatPaymentServiceServer) NativePrePay(ctx context.Context, req *pmtv1.PrePayRequest) (*pmtv1.NativePrePayResponse, error) {
+	if req.GetBizTradeNo() == "" || req.GetAmt().GetTotal() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "业务单号或者金额不合法")
+	}
+	currency := req.GetAmt().GetCurrency()
+	if currency == "" {
+		currency = "CNY"
+	}
+	codeURL, err := w.svc.Prepay(ctx, domain.Payment{
+		Amt: domain.Amount{
+			Currency: currency,
+			Total:    req.GetAmt().GetTotal(),
+		},
+		BizTradeNO:  req.GetBizTradeNo(),
+		Description: req.GetDescription(),
+	})
+	if err == service.ErrPaymentCompleted {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pmtv1.NativePrePayResponse{CodeUrl: codeURL}, nil
+}
+
+func (w *WechatPaymentServiceServer) GetPayment(ctx context.Context, req *pmtv1.GetPaymentRequest) (*pmtv1.GetPaymentResponse, error) {
+	pmt, err := w.svc.GetPayment(ctx, req.GetBizTradeNo())
+	if err == service.ErrPaymentNotFound {
+		return nil, status.Error(codes.NotFound, "支付不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pmtv1.GetPaymentResponse{
+		// 两边的取值是一样的
+		Status: pmtv1.PaymentStatus(pmt.Status),
+	}, nil
+}
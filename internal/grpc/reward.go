@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	rewardv1 "webook/api/proto/gen/reward/v1"
+	"webook/internal/domain"
+	"webook/internal/service"
+)
+
+// RewardServiceServer 把 RewardService 适配成 gRPC 接口
+type RewardServiceServer struct {
+	rewardv1.UnimplementedRewardServiceServer
+	svc service.RewardService
+}
+
+func NewRewardServiceServer(svc service.RewardService) *RewardServiceServer {
+	return &RewardServiceServer{svc: svc}
+}
+
+func (r *RewardServiceServer) Register(server *grpc.Server) {
+	rewardv1.RegisterRewardServiceServer(server, r)
+}
+
+func (r *RewardServiceServer) PreReward(ctx context.Context, req *rewardv1.PreRewardRequest) (*rewardv1.PreRewardResponse, error) {
+	if req.GetAmt() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "打赏金额不合法")
+	}
+	if req.GetUid() == req.GetTargetUid() {
+		return nil, status.Error(codes.InvalidArgument, "不能打赏自己")
+	}
+	cu, err := r.svc.PreReward(ctx, domain.Reward{
+		Uid: req.GetUid(),
+		Target: domain.Target{
+			Biz:     req.GetBiz(),
+			BizId:   req.GetBizId(),
+			BizName: req.GetBizName(),
+			Uid:     req.GetTargetUid(),
+		},
+		Amt: req.GetAmt(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.PreRewardResponse{
+		CodeUrl: cu.URL,
+		Rid:     cu.Rid,
+	}, nil
+}
+
+func (r *RewardServiceServer) GetReward(ctx context.Context, req *rewardv1.GetRewardRequest) (*rewardv1.GetRewardResponse, error) {
+	rw, err := r.svc.GetReward(ctx, req.GetRid(), req.GetUid())
+	if err == service.ErrRewardNotFound {
+		return nil, status.Error(codes.NotFound, "打赏不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rewardv1.GetRewardResponse{
+		// 两边的取值是一样的
+		Status: rewardv1.RewardStatus(rw.Status),
+	}, nil
+}
@@ -0,0 +1,14 @@
+package startup
+
+import (
+	ijwt "webook/internal/web/jwt"
+	"webook/ioc"
+)
+
+// InitJWTKeys 集成测试不读配置，每次临时生成
+func InitJWTKeys() ijwt.Keys {
+	return ijwt.Keys{
+		Access:  ioc.NewEphemeralJWTKeySet("access"),
+		Refresh: ioc.NewEphemeralJWTKeySet("refresh"),
+	}
+}
@@ -0,0 +1,10 @@
+package startup
+
+import (
+	"webook/internal/service/oauth2"
+)
+
+// InitOAuth2Registry 集成测试不接第三方登录
+func InitOAuth2Registry() *oauth2.Registry {
+	return oauth2.NewRegistry()
+}
@@ -5,14 +5,17 @@ package startup
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
+	"webook/internal/events/activity"
 	"webook/internal/events/article"
+	"webook/internal/events/payment"
 	"webook/internal/job"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
+	"webook/internal/service/push"
 	"webook/internal/web"
-	ijwt "webook/internal/web/jwt"
+	"webook/internal/web/middleware"
 	"webook/ioc"
 )
 
@@ -33,8 +36,27 @@ var userSvcProvider = wire.NewSet(
 	repository.NewCachedUserRepository,
 	service.NewUserService)
 
+var moderationSvcSet = wire.NewSet(
+	dao.NewGORMModerationDAO,
+	repository.NewGORMModerationRepository,
+	ioc.InitSensitiveFilter,
+	service.NewModerationService,
+	// 审核结果要落到评论上
+	dao.NewGORMCommentDAO,
+	cache.NewCommentRedisCache,
+	repository.NewCachedCommentRepository,
+)
+
+var banSvcSet = wire.NewSet(
+	dao.NewGORMUserBanDAO,
+	cache.NewRedisUserBanCache,
+	repository.NewCachedUserBanRepository,
+	service.NewBanService,
+)
+
 var articlSvcProvider = wire.NewSet(
 	repository.NewCachedArticleRepository,
+	dao.NewGORMTagDAO,
 	cache.NewArticleRedisCache,
 	dao.NewArticleGORMDAO,
 	service.NewArticleService)
@@ -42,32 +64,139 @@ var articlSvcProvider = wire.NewSet(
 var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO,
 	cache.NewInteractiveRedisCache,
 	repository.NewCachedInteractiveRepository,
+	activity.NewSaramaSyncProducer,
 	service.NewInteractiveService,
 )
 
+var recommendSvcSet = wire.NewSet(
+	cache.NewRankingRedisCache,
+	repository.NewCachedRankingRepository,
+	service.NewBatchRankingService,
+	dao.NewGORMHistoryRecordDAO,
+	repository.NewGORMHistoryRecordRepository,
+	cache.NewRecommendRedisCache,
+	repository.NewCachedRecommendRepository,
+	service.NewBatchRecommendService,
+)
+
+var paymentSvcSet = wire.NewSet(
+	dao.NewPaymentGORMDAO,
+	repository.NewGORMPaymentRepository,
+	payment.NewSaramaSyncProducer,
+	ioc.InitWechatPaySigner,
+	ioc.InitWechatNotifyHandler,
+	ioc.InitPaymentService,
+)
+
+var accountSvcSet = wire.NewSet(
+	dao.NewAccountGORMDAO,
+	repository.NewGORMAccountRepository,
+	service.NewAccountService,
+	dao.NewGORMWithdrawalDAO,
+	repository.NewGORMWithdrawalRepository,
+	ioc.InitPayoutExecutor,
+	ioc.InitWithdrawService,
+)
+
+var notificationSvcSet = wire.NewSet(
+	dao.NewGORMNotificationDAO,
+	cache.NewNotificationRedisCache,
+	repository.NewCachedNotificationRepository,
+	service.NewNotificationService,
+	ioc.InitPushBroker,
+	push.NewHub,
+)
+
+var feedSvcSet = wire.NewSet(
+	dao.NewGORMFollowRelationDAO,
+	cache.NewFollowRedisCache,
+	repository.NewCachedFollowRepository,
+	service.NewFollowRelationService,
+	dao.NewGORMFeedDAO,
+	cache.NewFeedRedisCache,
+	repository.NewCachedFeedRepository,
+	service.NewFeedService,
+)
+
 func InitWebServer() *gin.Engine {
 	wire.Build(
 		thirdPartySet,
 		userSvcProvider,
 		articlSvcProvider,
+		banSvcSet,
+		jobProviderSet,
 		interactiveSvcSet,
+		recommendSvcSet,
+		moderationSvcSet,
+		feedSvcSet,
 		// cache 部分
 		cache.NewRedisCodeCache,
+		cache.NewRedisLoginAttemptCache,
 
 		// repository 部分
 		repository.NewCodeRepository,
+		dao.NewGORMTwoFactorDAO,
+		repository.NewGORMTwoFactorRepository,
+		repository.NewCachedLoginAttemptRepository,
+		dao.NewGORMAccessTokenDAO,
+		repository.NewGORMAccessTokenRepository,
+		dao.NewGORMUserRoleDAO,
+		cache.NewRedisUserRoleCache,
+		repository.NewCachedUserRoleRepository,
+		dao.NewGORMAuditLogDAO,
+		repository.NewGORMAuditLogRepository,
+		dao.NewGORMDataExportDAO,
+		repository.NewGORMDataExportRepository,
+		dao.NewGORMAccountDeletionDAO,
+		repository.NewGORMAccountDeletionRepository,
 		article.NewSaramaSyncProducer,
 
 		// Service 部分
 		ioc.InitSMSService,
+		ioc.InitEmailService,
 		service.NewCodeService,
-		InitWechatService,
+		service.NewTwoFactorService,
+		service.NewLoginGuardService,
+		service.NewAccessTokenService,
+		ioc.InitRBACService,
+		service.NewAuditService,
+		ioc.InitExportStorage,
+		service.NewDataExportService,
+		ioc.InitAccountDeletionService,
+		service.NewProfileService,
+		ioc.InitCaptchaService,
+		InitOAuth2Registry,
 
 		// handler 部分
 		web.NewUserHandler,
 		web.NewArticleHandler,
-		web.NewOAuth2WechatHandler,
-		ijwt.NewRedisJWTHandler,
+		web.NewFeedHandler,
+		web.NewCommentHandler,
+		ioc.InitCommentClient,
+		web.NewModerationHandler,
+		web.NewFollowHandler,
+		ioc.InitFollowClient,
+		web.NewRewardHandler,
+		ioc.InitRewardClient,
+		paymentSvcSet,
+		web.NewWechatPaymentHandler,
+		accountSvcSet,
+		web.NewAccountHandler,
+		web.NewWithdrawHandler,
+		notificationSvcSet,
+		web.NewNotificationHandler,
+		web.NewAdminUserHandler,
+		web.NewAdminHandler,
+		web.NewJWKSHandler,
+		web.NewAccessTokenHandler,
+		web.NewPrivacyHandler,
+		web.NewProfileHandler,
+		middleware.NewAdminMiddlewareBuilder,
+		ioc.InitOAuth2Handler,
+		ioc.InitMergeTicketKey,
+		ioc.InitTwoFactorKey,
+		InitJWTKeys,
+		ioc.InitJWTHandler,
 		ioc.InitGinMiddlewares,
 		ioc.InitWebServer,
 	)
@@ -83,12 +212,15 @@ func InitWebServer() *gin.Engine {
 //}
 
 // InitArticleHandler 这里采用注入dao的形式方便我们去测试不同的数据存储，例如mysql、mongodb、oss
-func InitArticleHandler(dao dao.ArticleDao) *web.ArticleHandler {
+func InitArticleHandler(artDao dao.ArticleDao) *web.ArticleHandler {
 	wire.Build(
 		thirdPartySet,
 		interactiveSvcSet,
 		userSvcProvider,
+		moderationSvcSet,
+		banSvcSet,
 		repository.NewCachedArticleRepository,
+		dao.NewGORMTagDAO,
 		cache.NewArticleRedisCache,
 		service.NewArticleService,
 		article.NewSaramaSyncProducer,
@@ -98,7 +230,7 @@ func InitArticleHandler(dao dao.ArticleDao) *web.ArticleHandler {
 
 func InitInteractiveService() service.InteractiveService {
 	wire.Build(thirdPartySet, interactiveSvcSet)
-	return service.NewInteractiveService(nil)
+	return service.NewInteractiveService(nil, nil, nil)
 }
 
 func InitJobScheduler() *job.Scheduler {
@@ -9,14 +9,17 @@ package startup
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
+	"webook/internal/events/activity"
 	"webook/internal/events/article"
+	"webook/internal/events/payment"
 	"webook/internal/job"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
+	"webook/internal/service/push"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
+	"webook/internal/web/middleware"
 	"webook/ioc"
 )
 
@@ -24,56 +27,168 @@ import (
 
 func InitWebServer() *gin.Engine {
 	cmdable := InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
 	loggerV1 := InitLogger()
-	v := ioc.InitGinMiddlewares(cmdable, handler, loggerV1)
+	keys := InitJWTKeys()
+	handler := ioc.InitJWTHandler(cmdable, keys, loggerV1)
 	db := InitDB()
+	accessTokenDAO := dao.NewGORMAccessTokenDAO(db)
+	accessTokenRepository := repository.NewGORMAccessTokenRepository(accessTokenDAO)
+	userBanDAO := dao.NewGORMUserBanDAO(db)
+	userBanCache := cache.NewRedisUserBanCache(cmdable)
+	userBanRepository := repository.NewCachedUserBanRepository(userBanDAO, userBanCache, loggerV1)
+	accessTokenService := service.NewAccessTokenService(accessTokenRepository, userBanRepository)
+	v := ioc.InitGinMiddlewares(cmdable, handler, accessTokenService, loggerV1)
 	userDAO := dao.NewUserDao(db)
 	userCache := cache.NewUserCache(cmdable)
-	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
+	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
+	userRepository := repository.NewCachedUserRepository(userDAO, userCache, interactiveCache)
 	userService := service.NewUserService(userRepository)
 	codeCache := cache.NewRedisCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
-	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, handler, codeService)
+	emailService := ioc.InitEmailService()
+	codeService := service.NewCodeService(codeRepository, smsService, emailService)
+	followServiceClient := ioc.InitFollowClient()
+	twoFactorDAO := dao.NewGORMTwoFactorDAO(db)
+	twoFactorRepository := repository.NewGORMTwoFactorRepository(twoFactorDAO)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepository)
+	loginAttemptCache := cache.NewRedisLoginAttemptCache(cmdable)
+	loginAttemptRepository := repository.NewCachedLoginAttemptRepository(loginAttemptCache)
+	captchaService := ioc.InitCaptchaService()
+	loginGuardService := service.NewLoginGuardService(loginAttemptRepository, captchaService, loggerV1)
 	articleDao := dao.NewArticleGORMDAO(db)
+	tagDAO := dao.NewGORMTagDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCachedArticleRepository(articleDao, userRepository, articleCache, loggerV1)
+	articleRepository := repository.NewCachedArticleRepository(articleDao, tagDAO, userRepository, articleCache, loggerV1)
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	banService := service.NewBanService(userBanRepository, articleRepository, producer, loggerV1)
+	mergeTicketKey := ioc.InitMergeTicketKey(loggerV1)
+	twoFactorKey := ioc.InitTwoFactorKey(loggerV1)
+	userHandler := web.NewUserHandler(userService, handler, codeService, followServiceClient, twoFactorService, loginGuardService, banService, mergeTicketKey, twoFactorKey)
+	filter := ioc.InitSensitiveFilter(loggerV1)
+	moderationDAO := dao.NewGORMModerationDAO(db)
+	moderationRepository := repository.NewGORMModerationRepository(moderationDAO)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentCache := cache.NewCommentRedisCache(cmdable)
+	commentRepository := repository.NewCachedCommentRepository(commentDAO, commentCache, loggerV1)
+	activityProducer := activity.NewSaramaSyncProducer(syncProducer)
+	moderationService := service.NewModerationService(filter, moderationRepository, articleRepository, commentRepository, producer, activityProducer, loggerV1)
+	articleService := service.NewArticleService(articleRepository, producer, moderationService, banService, loggerV1)
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
-	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, loggerV1)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
-	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService)
-	wechatService := InitWechatService(loggerV1)
-	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler)
+	interactiveService := service.NewInteractiveService(interactiveRepository, activityProducer, loggerV1)
+	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService, banService)
+	registry := InitOAuth2Registry()
+	oAuth2Handler := ioc.InitOAuth2Handler(registry, handler, userService, banService, twoFactorService, mergeTicketKey, twoFactorKey, loggerV1)
+	rankingCache := cache.NewRankingRedisCache(cmdable)
+	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
+	rankingService := service.NewBatchRankingService(interactiveService, articleService, rankingRepository)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewGORMHistoryRecordRepository(historyRecordDAO)
+	recommendCache := cache.NewRecommendRedisCache(cmdable)
+	recommendRepository := repository.NewCachedRecommendRepository(recommendCache)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewFollowRedisCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
+	recommendService := service.NewBatchRecommendService(articleService, interactiveService, rankingService, historyRecordRepository, followRepository, recommendRepository, loggerV1)
+	feedDAO := dao.NewGORMFeedDAO(db)
+	feedCache := cache.NewFeedRedisCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedDAO, feedCache, loggerV1)
+	followRelationService := service.NewFollowRelationService(followRepository, activityProducer, loggerV1)
+	feedService := service.NewFeedService(feedRepository, followRelationService, articleService)
+	feedHandler := web.NewFeedHandler(recommendService, feedService, interactiveService, loggerV1)
+	commentServiceClient := ioc.InitCommentClient()
+	commentHandler := web.NewCommentHandler(commentServiceClient, banService)
+	userRoleDAO := dao.NewGORMUserRoleDAO(db)
+	userRoleCache := cache.NewRedisUserRoleCache(cmdable)
+	userRoleRepository := repository.NewCachedUserRoleRepository(userRoleDAO, userRoleCache, loggerV1)
+	rbacService := ioc.InitRBACService(userRoleRepository)
+	auditLogDAO := dao.NewGORMAuditLogDAO(db)
+	auditLogRepository := repository.NewGORMAuditLogRepository(auditLogDAO)
+	auditService := service.NewAuditService(auditLogRepository)
+	adminMiddlewareBuilder := middleware.NewAdminMiddlewareBuilder(rbacService, auditService, loggerV1)
+	moderationHandler := web.NewModerationHandler(moderationService, adminMiddlewareBuilder, loggerV1)
+	followHandler := web.NewFollowHandler(followServiceClient)
+	rewardServiceClient := ioc.InitRewardClient()
+	rewardHandler := web.NewRewardHandler(rewardServiceClient, articleService)
+	signer := ioc.InitWechatPaySigner()
+	notifyHandler := ioc.InitWechatNotifyHandler(signer)
+	paymentDAO := dao.NewPaymentGORMDAO(db)
+	paymentRepository := repository.NewGORMPaymentRepository(paymentDAO)
+	paymentProducer := payment.NewSaramaSyncProducer(syncProducer)
+	paymentService := ioc.InitPaymentService(signer, paymentRepository, paymentProducer, loggerV1)
+	wechatPaymentHandler := web.NewWechatPaymentHandler(notifyHandler, paymentService, loggerV1)
+	accountDAO := dao.NewAccountGORMDAO(db)
+	accountRepository := repository.NewGORMAccountRepository(accountDAO)
+	accountService := service.NewAccountService(accountRepository)
+	accountHandler := web.NewAccountHandler(accountService)
+	withdrawalDAO := dao.NewGORMWithdrawalDAO(db)
+	withdrawalRepository := repository.NewGORMWithdrawalRepository(withdrawalDAO)
+	executor := ioc.InitPayoutExecutor()
+	withdrawService := ioc.InitWithdrawService(withdrawalRepository, accountService, executor, loggerV1)
+	withdrawHandler := web.NewWithdrawHandler(withdrawService, adminMiddlewareBuilder, loggerV1)
+	notificationDAO := dao.NewGORMNotificationDAO(db)
+	notificationCache := cache.NewNotificationRedisCache(cmdable)
+	notificationRepository := repository.NewCachedNotificationRepository(notificationDAO, notificationCache)
+	broker := ioc.InitPushBroker(cmdable)
+	notificationService := service.NewNotificationService(notificationRepository, articleService, userService, broker, loggerV1)
+	hub := push.NewHub(broker, loggerV1)
+	notificationHandler := web.NewNotificationHandler(notificationService, userService, hub, handler)
+	adminUserHandler := web.NewAdminUserHandler(handler, userService, loginGuardService, banService, rbacService, adminMiddlewareBuilder)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
+	adminHandler := web.NewAdminHandler(articleService, cronJobService, auditService, adminMiddlewareBuilder)
+	jwksHandler := web.NewJWKSHandler(keys, loggerV1)
+	accessTokenHandler := web.NewAccessTokenHandler(accessTokenService)
+	dataExportDAO := dao.NewGORMDataExportDAO(db)
+	dataExportRepository := repository.NewGORMDataExportRepository(dataExportDAO)
+	storage := ioc.InitExportStorage()
+	dataExportService := service.NewDataExportService(dataExportRepository, userRepository, articleRepository, interactiveRepository, historyRecordRepository, notificationService, storage, loggerV1)
+	accountDeletionDAO := dao.NewGORMAccountDeletionDAO(db)
+	accountDeletionRepository := repository.NewGORMAccountDeletionRepository(accountDeletionDAO)
+	accountDeletionService := ioc.InitAccountDeletionService(accountDeletionRepository, userRepository, articleRepository, producer, loggerV1)
+	privacyHandler := web.NewPrivacyHandler(dataExportService, accountDeletionService, loggerV1)
+	profileService := service.NewProfileService(userRepository, articleRepository, interactiveRepository)
+	profileHandler := web.NewProfileHandler(profileService, userService, followServiceClient, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2Handler, feedHandler, commentHandler, moderationHandler, followHandler, rewardHandler, wechatPaymentHandler, accountHandler, withdrawHandler, notificationHandler, adminUserHandler, adminHandler, jwksHandler, accessTokenHandler, privacyHandler, profileHandler)
 	return engine
 }
 
 // InitArticleHandler 这里采用注入dao的形式方便我们去测试不同的数据存储，例如mysql、mongodb、oss
-func InitArticleHandler(dao2 dao.ArticleDao) *web.ArticleHandler {
+func InitArticleHandler(artDao dao.ArticleDao) *web.ArticleHandler {
 	db := InitDB()
+	tagDAO := dao.NewGORMTagDAO(db)
 	userDAO := dao.NewUserDao(db)
 	cmdable := InitRedis()
 	userCache := cache.NewUserCache(cmdable)
-	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
+	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
+	userRepository := repository.NewCachedUserRepository(userDAO, userCache, interactiveCache)
 	articleCache := cache.NewArticleRedisCache(cmdable)
 	loggerV1 := InitLogger()
-	articleRepository := repository.NewCachedArticleRepository(dao2, userRepository, articleCache, loggerV1)
+	articleRepository := repository.NewCachedArticleRepository(artDao, tagDAO, userRepository, articleCache, loggerV1)
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	filter := ioc.InitSensitiveFilter(loggerV1)
+	moderationDAO := dao.NewGORMModerationDAO(db)
+	moderationRepository := repository.NewGORMModerationRepository(moderationDAO)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentCache := cache.NewCommentRedisCache(cmdable)
+	commentRepository := repository.NewCachedCommentRepository(commentDAO, commentCache, loggerV1)
+	activityProducer := activity.NewSaramaSyncProducer(syncProducer)
+	moderationService := service.NewModerationService(filter, moderationRepository, articleRepository, commentRepository, producer, activityProducer, loggerV1)
+	userBanDAO := dao.NewGORMUserBanDAO(db)
+	userBanCache := cache.NewRedisUserBanCache(cmdable)
+	userBanRepository := repository.NewCachedUserBanRepository(userBanDAO, userBanCache, loggerV1)
+	banService := service.NewBanService(userBanRepository, articleRepository, producer, loggerV1)
+	articleService := service.NewArticleService(articleRepository, producer, moderationService, banService, loggerV1)
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
-	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, loggerV1)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
-	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService)
+	interactiveService := service.NewInteractiveService(interactiveRepository, activityProducer, loggerV1)
+	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService, banService)
 	return articleHandler
 }
 
@@ -84,7 +199,10 @@ func InitInteractiveService() service.InteractiveService {
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	loggerV1 := InitLogger()
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, loggerV1)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
+	client := InitSaramaClient()
+	syncProducer := InitSyncProducer(client)
+	producer := activity.NewSaramaSyncProducer(syncProducer)
+	interactiveService := service.NewInteractiveService(interactiveRepository, producer, loggerV1)
 	return interactiveService
 }
 
@@ -110,6 +228,20 @@ var jobProviderSet = wire.NewSet(service.NewCronJobService, repository.NewPreemp
 
 var userSvcProvider = wire.NewSet(dao.NewUserDao, cache.NewUserCache, repository.NewCachedUserRepository, service.NewUserService)
 
-var articlSvcProvider = wire.NewSet(repository.NewCachedArticleRepository, cache.NewArticleRedisCache, dao.NewArticleGORMDAO, service.NewArticleService)
+var moderationSvcSet = wire.NewSet(dao.NewGORMModerationDAO, repository.NewGORMModerationRepository, ioc.InitSensitiveFilter, service.NewModerationService, dao.NewGORMCommentDAO, cache.NewCommentRedisCache, repository.NewCachedCommentRepository)
+
+var banSvcSet = wire.NewSet(dao.NewGORMUserBanDAO, cache.NewRedisUserBanCache, repository.NewCachedUserBanRepository, service.NewBanService)
+
+var articlSvcProvider = wire.NewSet(repository.NewCachedArticleRepository, dao.NewGORMTagDAO, cache.NewArticleRedisCache, dao.NewArticleGORMDAO, service.NewArticleService)
+
+var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, activity.NewSaramaSyncProducer, service.NewInteractiveService)
+
+var recommendSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, service.NewBatchRankingService, dao.NewGORMHistoryRecordDAO, repository.NewGORMHistoryRecordRepository, cache.NewRecommendRedisCache, repository.NewCachedRecommendRepository, service.NewBatchRecommendService)
+
+var paymentSvcSet = wire.NewSet(dao.NewPaymentGORMDAO, repository.NewGORMPaymentRepository, payment.NewSaramaSyncProducer, ioc.InitWechatPaySigner, ioc.InitWechatNotifyHandler, ioc.InitPaymentService)
+
+var accountSvcSet = wire.NewSet(dao.NewAccountGORMDAO, repository.NewGORMAccountRepository, service.NewAccountService, dao.NewGORMWithdrawalDAO, repository.NewGORMWithdrawalRepository, ioc.InitPayoutExecutor, ioc.InitWithdrawService)
+
+var notificationSvcSet = wire.NewSet(dao.NewGORMNotificationDAO, cache.NewNotificationRedisCache, repository.NewCachedNotificationRepository, service.NewNotificationService, ioc.InitPushBroker, push.NewHub)
 
-var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, service.NewInteractiveService)
+var feedSvcSet = wire.NewSet(dao.NewGORMFollowRelationDAO, cache.NewFollowRedisCache, repository.NewCachedFollowRepository, service.NewFollowRelationService, dao.NewGORMFeedDAO, cache.NewFeedRedisCache, repository.NewCachedFeedRepository, service.NewFeedService)
@@ -0,0 +1,66 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// AccountDeletionJob 冷静期结束之后真正注销账号，注销完所有设备都下线。
+// 注销的每一步都可以重复执行，多个实例同时跑也没关系
+type AccountDeletionJob struct {
+	svc service.AccountDeletionService
+	hdl jwt.Handler
+	l   logger.LoggerV1
+
+	batchSize int
+}
+
+func NewAccountDeletionJob(svc service.AccountDeletionService, hdl jwt.Handler, l logger.LoggerV1) *AccountDeletionJob {
+	return &AccountDeletionJob{
+		svc:       svc,
+		hdl:       hdl,
+		l:         l,
+		batchSize: 100,
+	}
+}
+
+func (a *AccountDeletionJob) Name() string {
+	return "account_deletion_job"
+}
+
+func (a *AccountDeletionJob) Run() error {
+	offset := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		ds, err := a.svc.FindDue(ctx, offset, a.batchSize)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, d := range ds {
+			ctx, cancel = context.WithTimeout(context.Background(), time.Second*30)
+			err = a.execute(ctx, d.Uid)
+			cancel()
+			if err != nil {
+				// 失败的还留在原来的位置，下一次再来
+				offset++
+				a.l.Error("注销账号失败", logger.Int64("uid", d.Uid), logger.Error(err))
+			}
+		}
+		if len(ds) < a.batchSize {
+			return nil
+		}
+	}
+}
+
+// execute 先踢下线再注销，踢下线失败的话申请还在冷静期结束的状态，下一次还会再来
+func (a *AccountDeletionJob) execute(ctx context.Context, uid int64) error {
+	err := a.hdl.ClearUserTokens(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return a.svc.Execute(ctx, uid)
+}
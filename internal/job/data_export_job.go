@@ -0,0 +1,54 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+// DataExportJob 打包用户申请的数据导出，顺便清理过了下载期限的文件。
+// 打包之前会先抢占，多个实例同时跑也不会重复打包
+type DataExportJob struct {
+	svc service.DataExportService
+	l   logger.LoggerV1
+	// 一次最多打包几个，打包比较重，剩下的等下一次
+	batchSize int
+}
+
+func NewDataExportJob(svc service.DataExportService, l logger.LoggerV1) *DataExportJob {
+	return &DataExportJob{
+		svc:       svc,
+		l:         l,
+		batchSize: 10,
+	}
+}
+
+func (d *DataExportJob) Name() string {
+	return "data_export_job"
+}
+
+func (d *DataExportJob) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	es, err := d.svc.FindRunnable(ctx, d.batchSize)
+	cancel()
+	if err != nil {
+		return err
+	}
+	for _, e := range es {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute*5)
+		err = d.svc.Build(ctx, e)
+		cancel()
+		if err != nil {
+			// 已经标记失败并且通知了用户，用户可以重新申请
+			d.l.Error("打包数据导出失败",
+				logger.Int64("id", e.Id),
+				logger.Int64("uid", e.Uid),
+				logger.Error(err))
+		}
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	_, err = d.svc.CleanExpired(ctx, 100)
+	return err
+}
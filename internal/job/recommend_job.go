@@ -0,0 +1,58 @@
+package job
+
+import (
+	"context"
+	rlock "github.com/gotomicro/redis-lock"
+	"time"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+// RecommendJob 定时给活跃用户预先计算推荐结果
+// 计算量比热榜大得多，所以每一次调度都抢一次分布式锁，算完就释放，让别的实例也有机会执行
+type RecommendJob struct {
+	svc     service.RecommendService
+	l       logger.LoggerV1
+	timeout time.Duration
+	client  *rlock.Client
+	key     string
+}
+
+func NewRecommendJob(svc service.RecommendService, l logger.LoggerV1, client *rlock.Client, timeout time.Duration) *RecommendJob {
+	return &RecommendJob{
+		svc:     svc,
+		l:       l,
+		client:  client,
+		key:     "job:recommend",
+		timeout: timeout,
+	}
+}
+
+func (r *RecommendJob) Name() string {
+	return "recommend"
+}
+
+func (r *RecommendJob) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+	defer cancel()
+	lock, err := r.client.Lock(ctx, r.key, r.timeout, &rlock.FixIntervalRetry{
+		Interval: time.Millisecond * 100,
+		Max:      3,
+	}, time.Second)
+	if err != nil {
+		// 别的实例在算
+		r.l.Warn("获取分布式锁失败", logger.Error(err))
+		return nil
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		er := lock.Unlock(ctx)
+		if er != nil {
+			r.l.Error("recommend job 释放分布式锁失败", logger.Error(er))
+		}
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	return r.svc.Compute(ctx)
+}
@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+// SyncWechatOrderJob 对账，支付通知可能丢了，长时间没有结果的支付主动去微信查一下
+// 状态更新本身是幂等的，多个实例同时跑也没关系
+type SyncWechatOrderJob struct {
+	svc service.PaymentService
+	l   logger.LoggerV1
+	// 创建了多久还没有结果才去查
+	minAge    time.Duration
+	batchSize int
+}
+
+func NewSyncWechatOrderJob(svc service.PaymentService, l logger.LoggerV1) *SyncWechatOrderJob {
+	return &SyncWechatOrderJob{
+		svc:       svc,
+		l:         l,
+		minAge:    time.Minute * 30,
+		batchSize: 100,
+	}
+}
+
+func (s *SyncWechatOrderJob) Name() string {
+	return "sync_wechat_order_job"
+}
+
+func (s *SyncWechatOrderJob) Run() error {
+	t := time.Now().Add(-s.minAge)
+	// 同步完的支付不再是等待支付，用 offset 翻页会跳过后面的，所以按照 id 往后翻
+	var minId int64
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		pmts, err := s.svc.FindExpiredPayment(ctx, minId, s.batchSize, t)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, pmt := range pmts {
+			ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+			err = s.svc.SyncWechatInfo(ctx, pmt.BizTradeNO)
+			cancel()
+			if err != nil {
+				// 一个失败了不影响别的，下一次再查
+				s.l.Error("同步微信支付结果失败",
+					logger.String("biz_trade_no", pmt.BizTradeNO),
+					logger.Error(err))
+			}
+		}
+		if len(pmts) < s.batchSize {
+			return nil
+		}
+		minId = pmts[len(pmts)-1].Id
+	}
+}
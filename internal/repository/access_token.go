@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var ErrAccessTokenNotFound = dao.ErrRecordNotFound
+
+type AccessTokenRepository interface {
+	// Create hash 是令牌明文的哈希
+	Create(ctx context.Context, t domain.AccessToken, hash string) (int64, error)
+	FindByHash(ctx context.Context, hash string) (domain.AccessToken, error)
+	FindByUid(ctx context.Context, uid int64) ([]domain.AccessToken, error)
+	CountByUid(ctx context.Context, uid int64) (int64, error)
+	Delete(ctx context.Context, uid int64, id int64) error
+	UpdateLastUsed(ctx context.Context, id int64, lastUsed time.Time) error
+}
+
+type GORMAccessTokenRepository struct {
+	dao dao.AccessTokenDAO
+}
+
+func NewGORMAccessTokenRepository(dao dao.AccessTokenDAO) AccessTokenRepository {
+	return &GORMAccessTokenRepository{dao: dao}
+}
+
+func (repo *GORMAccessTokenRepository) Create(ctx context.Context, t domain.AccessToken, hash string) (int64, error) {
+	return repo.dao.Insert(ctx, dao.AccessToken{
+		Uid:       t.Uid,
+		Name:      t.Name,
+		TokenHash: hash,
+		Prefix:    t.Prefix,
+		Scopes:    strings.Join(t.Scopes, ","),
+		ExpireAt:  t.Expire.UnixMilli(),
+	})
+}
+
+func (repo *GORMAccessTokenRepository) FindByHash(ctx context.Context, hash string) (domain.AccessToken, error) {
+	t, err := repo.dao.FindByHash(ctx, hash)
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	return repo.toDomain(t), nil
+}
+
+func (repo *GORMAccessTokenRepository) FindByUid(ctx context.Context, uid int64) ([]domain.AccessToken, error) {
+	ts, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ts, func(idx int, src dao.AccessToken) domain.AccessToken {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *GORMAccessTokenRepository) CountByUid(ctx context.Context, uid int64) (int64, error) {
+	return repo.dao.CountByUid(ctx, uid)
+}
+
+func (repo *GORMAccessTokenRepository) Delete(ctx context.Context, uid int64, id int64) error {
+	return repo.dao.Delete(ctx, uid, id)
+}
+
+func (repo *GORMAccessTokenRepository) UpdateLastUsed(ctx context.Context, id int64, lastUsed time.Time) error {
+	return repo.dao.UpdateLastUsed(ctx, id, lastUsed.UnixMilli())
+}
+
+func (repo *GORMAccessTokenRepository) toDomain(t dao.AccessToken) domain.AccessToken {
+	var lastUsed time.Time
+	if t.LastUsed > 0 {
+		lastUsed = time.UnixMilli(t.LastUsed)
+	}
+	var scopes []string
+	if t.Scopes != "" {
+		scopes = strings.Split(t.Scopes, ",")
+	}
+	return domain.AccessToken{
+		Id:       t.Id,
+		Uid:      t.Uid,
+		Name:     t.Name,
+		Prefix:   t.Prefix,
+		Scopes:   scopes,
+		Expire:   time.UnixMilli(t.ExpireAt),
+		LastUsed: lastUsed,
+		Ctime:    time.UnixMilli(t.Ctime),
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrDuplicateTransaction = dao.ErrDuplicateTransaction
+	ErrUnbalancedEntries    = dao.ErrUnbalancedEntries
+	ErrInsufficientBalance  = dao.ErrInsufficientBalance
+)
+
+type AccountRepository interface {
+	// AddActivities 同一个 biz + bizId 只能记一次账
+	AddActivities(ctx context.Context, biz string, bizId int64, acts []domain.AccountActivity) error
+	FindAccounts(ctx context.Context, uid int64) ([]domain.Account, error)
+	FindActivities(ctx context.Context, uid int64, offset int, limit int) ([]domain.AccountActivity, error)
+	SumAmt(ctx context.Context) (int64, error)
+}
+
+type GORMAccountRepository struct {
+	dao dao.AccountDAO
+}
+
+func NewGORMAccountRepository(dao dao.AccountDAO) AccountRepository {
+	return &GORMAccountRepository{dao: dao}
+}
+
+func (g *GORMAccountRepository) AddActivities(ctx context.Context, biz string, bizId int64,
+	acts []domain.AccountActivity) error {
+	return g.dao.AddEntries(ctx, dao.AccountTransaction{
+		Biz:   biz,
+		BizId: bizId,
+	}, slice.Map[domain.AccountActivity, dao.AccountActivity](acts,
+		func(idx int, src domain.AccountActivity) dao.AccountActivity {
+			return dao.AccountActivity{
+				Uid:      src.Uid,
+				Type:     src.AccountType.ToUint8(),
+				Amt:      src.Amt,
+				Currency: src.Currency,
+			}
+		}))
+}
+
+func (g *GORMAccountRepository) FindAccounts(ctx context.Context, uid int64) ([]domain.Account, error) {
+	accs, err := g.dao.FindAccounts(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Account, domain.Account](accs, func(idx int, src dao.Account) domain.Account {
+		return domain.Account{
+			Id:       src.Id,
+			Uid:      src.Uid,
+			Type:     domain.AccountType(src.Type),
+			Currency: src.Currency,
+			Balance:  src.Balance,
+			Utime:    time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (g *GORMAccountRepository) FindActivities(ctx context.Context, uid int64, offset int, limit int) ([]domain.AccountActivity, error) {
+	acts, err := g.dao.FindActivities(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.AccountActivity, domain.AccountActivity](acts, func(idx int, src dao.AccountActivity) domain.AccountActivity {
+		return domain.AccountActivity{
+			Id:          src.Id,
+			Uid:         src.Uid,
+			AccountType: domain.AccountType(src.Type),
+			Biz:         src.Biz,
+			BizId:       src.BizId,
+			Amt:         src.Amt,
+			Currency:    src.Currency,
+			Balance:     src.Balance,
+			Ctime:       time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (g *GORMAccountRepository) SumAmt(ctx context.Context) (int64, error) {
+	return g.dao.SumAmt(ctx)
+}
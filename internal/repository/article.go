@@ -12,6 +12,8 @@ import (
 	"webook/pkg/logger"
 )
 
+var ErrArticleNotFound = dao.ErrRecordNotFound
+
 type ArticleRepository interface {
 	Create(ctx context.Context, art domain.Article) (int64, error)
 	Update(ctx context.Context, art domain.Article) error
@@ -21,11 +23,16 @@ type ArticleRepository interface {
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 	GetPubById(ctx context.Context, id int64) (domain.Article, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error)
+	// ListPubByIds 批量查询线上库，会带上标签
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
+	// ListPubByAuthor 作者已经发表的文章，个人主页用，会带上标签
+	ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
 }
 
 type CachedArticleRepository struct {
-	dao   dao.ArticleDao
-	cache cache.ArticleCache
+	dao    dao.ArticleDao
+	tagDAO dao.TagDAO
+	cache  cache.ArticleCache
 	// 如果你直接访问UserDAO，你就绕开了repository
 	// repository 一般都有缓存机制
 	userRepo  UserRepository
@@ -41,9 +48,78 @@ func (c *CachedArticleRepository) ListPub(ctx context.Context, start time.Time,
 	if err != nil {
 		return nil, err
 	}
-	return slice.Map[dao.PublishedArticle, domain.Article](arts, func(idx int, src dao.PublishedArticle) domain.Article {
+	res := slice.Map[dao.PublishedArticle, domain.Article](arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	})
+	return res, c.fillTags(ctx, res)
+}
+
+func (c *CachedArticleRepository) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+	arts, err := c.dao.ListPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	res := slice.Map[dao.PublishedArticle, domain.Article](arts, func(idx int, src dao.PublishedArticle) domain.Article {
 		return c.toDomain(dao.Article(src))
-	}), nil
+	})
+	return res, c.fillTags(ctx, res)
+}
+
+func (c *CachedArticleRepository) ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByAuthor(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := slice.Map[dao.PublishedArticle, domain.Article](arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	})
+	return res, c.fillTags(ctx, res)
+}
+
+// fillTags 批量把标签填进去，标签不是核心数据，查询失败只记录日志
+func (c *CachedArticleRepository) fillTags(ctx context.Context, arts []domain.Article) error {
+	if len(arts) == 0 {
+		return nil
+	}
+	ids := slice.Map[domain.Article, int64](arts, func(idx int, src domain.Article) int64 {
+		return src.Id
+	})
+	tags, err := c.tagDAO.GetByAids(ctx, ids)
+	if err != nil {
+		c.l.Error("查询文章标签失败", logger.Error(err))
+		return nil
+	}
+	tagMap := make(map[int64][]string, len(arts))
+	for _, t := range tags {
+		tagMap[t.Aid] = append(tagMap[t.Aid], t.Tag)
+	}
+	for i := range arts {
+		arts[i].Tags = tagMap[arts[i].Id]
+	}
+	return nil
+}
+
+func (c *CachedArticleRepository) delPub(ctx context.Context, id int64) {
+	er := c.cache.DelPub(ctx, id)
+	if er != nil {
+		c.l.Error("删除线上库文章缓存失败",
+			logger.Int64("aid", id),
+			logger.Error(er))
+	}
+}
+
+// saveTags 只有在调用方传了标签的时候才覆盖
+func (c *CachedArticleRepository) saveTags(ctx context.Context, art domain.Article) {
+	if art.Tags == nil {
+		return
+	}
+	err := c.tagDAO.ReplaceArticleTags(ctx, art.Id, art.Tags)
+	if err != nil {
+		c.l.Error("保存文章标签失败", logger.Int64("aid", art.Id), logger.Error(err))
+	}
 }
 
 func (c *CachedArticleRepository) GetPubById(ctx context.Context, id int64) (domain.Article, error) {
@@ -64,6 +140,9 @@ func (c *CachedArticleRepository) GetPubById(ctx context.Context, id int64) (dom
 		//return res,err
 	}
 	res.Author.Name = author.Nickname
+	arts := []domain.Article{res}
+	_ = c.fillTags(ctx, arts)
+	res = arts[0]
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -147,10 +226,12 @@ func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, of
 	return res, nil
 }
 
-// SyncStatus 同步文章状态接口，这里是将文章设置为不可见
+// SyncStatus 同步文章状态接口，撤回文章、审核文章都会用到
 func (c *CachedArticleRepository) SyncStatus(ctx context.Context, uid int64, id int64, status domain.ArticleStatus) error {
 	err := c.dao.SyncStatus(ctx, uid, id, status.ToUint8())
 	if err == nil {
+		// 线上库的缓存里面有状态，不删掉的话读者会看到旧的状态
+		c.delPub(ctx, id)
 		er := c.cache.DelFirstPage(ctx, uid)
 		if er != nil {
 			// 也要记录日志
@@ -166,6 +247,9 @@ func (c *CachedArticleRepository) SyncStatus(ctx context.Context, uid int64, id
 func (c *CachedArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
 	id, err := c.dao.Sync(ctx, c.toEntity(art))
 	if err == nil {
+		art.Id = id
+		c.saveTags(ctx, art)
+		c.delPub(ctx, id)
 		er := c.cache.DelFirstPage(ctx, art.Author.Id)
 		if er != nil {
 			// 也要记录日志
@@ -207,6 +291,8 @@ func (c *CachedArticleRepository) Sync(ctx context.Context, art domain.Article)
 func (c *CachedArticleRepository) Create(ctx context.Context, art domain.Article) (int64, error) {
 	id, err := c.dao.Insert(ctx, c.toEntity(art))
 	if err == nil {
+		art.Id = id
+		c.saveTags(ctx, art)
 		er := c.cache.DelFirstPage(ctx, art.Author.Id)
 		if er != nil {
 			// 也要记录日志
@@ -223,6 +309,7 @@ func (c *CachedArticleRepository) Create(ctx context.Context, art domain.Article
 func (c *CachedArticleRepository) Update(ctx context.Context, art domain.Article) error {
 	err := c.dao.UpdateById(ctx, c.toEntity(art))
 	if err == nil {
+		c.saveTags(ctx, art)
 		er := c.cache.DelFirstPage(ctx, art.Author.Id)
 		if er != nil {
 			// 也要记录日志
@@ -235,9 +322,11 @@ func (c *CachedArticleRepository) Update(ctx context.Context, art domain.Article
 	return err
 }
 
-func NewCachedArticleRepository(dao dao.ArticleDao, userRepo UserRepository, cache cache.ArticleCache, l logger.LoggerV1) ArticleRepository {
+func NewCachedArticleRepository(dao dao.ArticleDao, tagDAO dao.TagDAO, userRepo UserRepository,
+	cache cache.ArticleCache, l logger.LoggerV1) ArticleRepository {
 	return &CachedArticleRepository{
 		dao:      dao,
+		tagDAO:   tagDAO,
 		cache:    cache,
 		userRepo: userRepo,
 		l:        l,
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, l domain.AuditLog) error
+	List(ctx context.Context, operator int64, offset, limit int) ([]domain.AuditLog, error)
+}
+
+type GORMAuditLogRepository struct {
+	dao dao.AuditLogDAO
+}
+
+func NewGORMAuditLogRepository(dao dao.AuditLogDAO) AuditLogRepository {
+	return &GORMAuditLogRepository{dao: dao}
+}
+
+func (repo *GORMAuditLogRepository) Create(ctx context.Context, l domain.AuditLog) error {
+	return repo.dao.Insert(ctx, dao.AuditLog{
+		Operator: l.Operator,
+		Action:   l.Action,
+		Detail:   l.Detail,
+		IP:       l.IP,
+		Code:     l.Code,
+	})
+}
+
+func (repo *GORMAuditLogRepository) List(ctx context.Context, operator int64, offset, limit int) ([]domain.AuditLog, error) {
+	logs, err := repo.dao.List(ctx, operator, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(logs, func(idx int, src dao.AuditLog) domain.AuditLog {
+		return domain.AuditLog{
+			Id:       src.Id,
+			Operator: src.Operator,
+			Action:   src.Action,
+			Detail:   src.Detail,
+			IP:       src.IP,
+			Code:     src.Code,
+			Ctime:    time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
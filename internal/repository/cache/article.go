@@ -17,6 +17,7 @@ type ArticleCache interface {
 	Set(ctx context.Context, art domain.Article) error
 	GetPub(ctx context.Context, id int64) (domain.Article, error)
 	SetPub(ctx context.Context, res domain.Article) error
+	DelPub(ctx context.Context, id int64) error
 }
 
 type ArticleRedisCache struct {
@@ -59,6 +60,10 @@ func (a *ArticleRedisCache) SetPub(ctx context.Context, art domain.Article) erro
 	return a.client.Set(ctx, a.pubKey(art.Id), val, time.Minute*10).Err()
 }
 
+func (a *ArticleRedisCache) DelPub(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.pubKey(id)).Err()
+}
+
 func (a *ArticleRedisCache) GetFirstPage(ctx context.Context, uid int64) ([]domain.Article, error) {
 	key := a.firstKey(uid)
 	// 返回string
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"webook/internal/domain"
+)
+
+// CommentCache 只缓存根评论的第一页，绝大多数人只看第一页
+type CommentCache interface {
+	GetFirstPage(ctx context.Context, biz string, bizId int64) ([]domain.Comment, error)
+	SetFirstPage(ctx context.Context, biz string, bizId int64, cs []domain.Comment) error
+	DelFirstPage(ctx context.Context, biz string, bizId int64) error
+}
+
+type CommentRedisCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewCommentRedisCache(client redis.Cmdable) CommentCache {
+	return &CommentRedisCache{
+		client:     client,
+		expiration: time.Minute * 10,
+	}
+}
+
+func (c *CommentRedisCache) GetFirstPage(ctx context.Context, biz string, bizId int64) ([]domain.Comment, error) {
+	val, err := c.client.Get(ctx, c.firstKey(biz, bizId)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.Comment
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+func (c *CommentRedisCache) SetFirstPage(ctx context.Context, biz string, bizId int64, cs []domain.Comment) error {
+	val, err := json.Marshal(cs)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.firstKey(biz, bizId), val, c.expiration).Err()
+}
+
+func (c *CommentRedisCache) DelFirstPage(ctx context.Context, biz string, bizId int64) error {
+	return c.client.Del(ctx, c.firstKey(biz, bizId)).Err()
+}
+
+func (c *CommentRedisCache) firstKey(biz string, bizId int64) string {
+	return fmt.Sprintf("comment:first_page:%s:%d", biz, bizId)
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+type FeedCache interface {
+	// AddInbox 把文章放进这些粉丝的收件箱
+	AddInbox(ctx context.Context, uids []int64, item domain.FeedItem) error
+	// GetInbox 收件箱里面排在 cursor 之后的，按照 (Ctime, Aid) 倒序。
+	// 边界上同一时间的文章可能没取全，这个时候会少返回一些，调用方要回到数据库里面查
+	GetInbox(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error)
+}
+
+// FeedRedisCache 只缓存收件箱里面最新的一部分，更早的去数据库里面找
+type FeedRedisCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+	// 每个收件箱最多保留多少条
+	capacity int64
+}
+
+func NewFeedRedisCache(client redis.Cmdable) FeedCache {
+	return &FeedRedisCache{
+		client:     client,
+		expiration: time.Hour * 24 * 7,
+		capacity:   500,
+	}
+}
+
+func (f *FeedRedisCache) AddInbox(ctx context.Context, uids []int64, item domain.FeedItem) error {
+	member := f.member(item)
+	score := float64(item.Ctime.UnixMilli())
+	pipe := f.client.Pipeline()
+	for _, uid := range uids {
+		key := f.key(uid)
+		pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: member})
+		// 只留最新的 capacity 条
+		pipe.ZRemRangeByRank(ctx, key, 0, -f.capacity-1)
+		pipe.Expire(ctx, key, f.expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (f *FeedRedisCache) GetInbox(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	// 包含 cursor.Ctime 本身，同一毫秒里面 aid 更小的还没看过。
+	// 多取一条用来判断最后一个时间点上的文章有没有取全
+	res, err := f.client.ZRevRangeByScoreWithScores(ctx, f.key(uid), &redis.ZRangeBy{
+		Max:   strconv.FormatInt(cursor.Ctime, 10),
+		Min:   "-inf",
+		Count: int64(limit) + 1,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]domain.FeedItem, 0, len(res))
+	for _, z := range res {
+		var item domain.FeedItem
+		_, err = fmt.Sscanf(z.Member.(string), "%d:%d", &item.Aid, &item.Uid)
+		if err != nil {
+			return nil, err
+		}
+		item.Ctime = time.UnixMilli(int64(z.Score))
+		items = append(items, item)
+	}
+	// 同一个分数的成员是按照字符串排序的，要重新按照 aid 排
+	sort.Slice(items, func(i, j int) bool {
+		return feedItemBefore(items[i], items[j])
+	})
+	if len(res) > limit {
+		// 最后一个时间点上可能还有没取到的，整个丢掉，宁可少返回也不能漏
+		last := items[len(items)-1].Ctime
+		for len(items) > 0 && items[len(items)-1].Ctime.Equal(last) {
+			items = items[:len(items)-1]
+		}
+	}
+	unread := make([]domain.FeedItem, 0, len(items))
+	for _, item := range items {
+		// 和 cursor 同一时间的，aid 不小于 cursor 的上一页已经看过了
+		if item.Ctime.UnixMilli() == cursor.Ctime && item.Aid >= cursor.Aid {
+			continue
+		}
+		unread = append(unread, item)
+	}
+	return unread, nil
+}
+
+// feedItemBefore 关注流的顺序，上线时间倒序，同一时间 aid 大的在前面
+func feedItemBefore(a, b domain.FeedItem) bool {
+	if !a.Ctime.Equal(b.Ctime) {
+		return a.Ctime.After(b.Ctime)
+	}
+	return a.Aid > b.Aid
+}
+
+// member 把作者也带上，读的时候可以过滤掉已经取消关注的作者
+func (f *FeedRedisCache) member(item domain.FeedItem) string {
+	return fmt.Sprintf("%d:%d", item.Aid, item.Uid)
+}
+
+func (f *FeedRedisCache) key(uid int64) string {
+	return fmt.Sprintf("feed:inbox:%d", uid)
+}
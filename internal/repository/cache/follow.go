@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+const (
+	fieldFollowerCnt = "follower_cnt"
+	fieldFolloweeCnt = "followee_cnt"
+)
+
+type FollowCache interface {
+	StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error)
+	SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error
+	// Follow 关注关系变化之后，双方的计数都要失效
+	Follow(ctx context.Context, follower, followee int64) error
+	CancelFollow(ctx context.Context, follower, followee int64) error
+}
+
+type FollowRedisCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewFollowRedisCache(client redis.Cmdable) FollowCache {
+	return &FollowRedisCache{
+		client:     client,
+		expiration: time.Minute * 30,
+	}
+}
+
+func (r *FollowRedisCache) StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	data, err := r.client.HGetAll(ctx, r.staticsKey(uid)).Result()
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if len(data) == 0 {
+		return domain.FollowStatics{}, ErrKeyNotExist
+	}
+	var res domain.FollowStatics
+	res.Followers, _ = strconv.ParseInt(data[fieldFollowerCnt], 10, 64)
+	res.Followees, _ = strconv.ParseInt(data[fieldFolloweeCnt], 10, 64)
+	return res, nil
+}
+
+func (r *FollowRedisCache) SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error {
+	key := r.staticsKey(uid)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, fieldFollowerCnt, statics.Followers, fieldFolloweeCnt, statics.Followees)
+	pipe.Expire(ctx, key, r.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *FollowRedisCache) Follow(ctx context.Context, follower, followee int64) error {
+	return r.del(ctx, follower, followee)
+}
+
+func (r *FollowRedisCache) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return r.del(ctx, follower, followee)
+}
+
+// del 计数以数据库为准，缓存直接删掉，下次查询的时候再回写
+func (r *FollowRedisCache) del(ctx context.Context, follower, followee int64) error {
+	return r.client.Del(ctx, r.staticsKey(follower), r.staticsKey(followee)).Err()
+}
+
+func (r *FollowRedisCache) staticsKey(uid int64) string {
+	return fmt.Sprintf("follow:statics:%d", uid)
+}
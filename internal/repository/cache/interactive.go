@@ -34,6 +34,8 @@ type InteractiveCache interface {
 	IncrCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error)
 	Set(ctx context.Context, biz string, bizId int64, res domain.Interactive) error
+	// Del 数据库里面的计数被直接改掉了的时候用，比如说合并账号
+	Del(ctx context.Context, biz string, bizId int64) error
 	// IncrRankingIfPresent 如果排名数据存在就+1
 	IncrRankingIfPresent(ctx context.Context, biz string, bizId int64) error
 	// SetRankingScore 如果排名数据不存在就把数据库中读取到的更新到缓存，如果更新过就+1
@@ -82,6 +84,10 @@ func (r *InteractiveRedisCache) Set(ctx context.Context, biz string, bizId int64
 	return r.client.Expire(ctx, key, time.Minute*15).Err()
 }
 
+func (r *InteractiveRedisCache) Del(ctx context.Context, biz string, bizId int64) error {
+	return r.client.Del(ctx, r.key(biz, bizId)).Err()
+}
+
 func (r *InteractiveRedisCache) IncrCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error {
 	key := r.key(biz, bizId)
 	_, err := r.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCollectCnt, 1).Int()
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"webook/internal/domain"
+)
+
+//go:embed lua/incr_login_fail.lua
+var luaIncrLoginFail string
+
+//go:generate mockgen -source=./login_attempt.go -package=cachemocks -destination=./mocks/login_attempt.mock.go LoginAttemptCache
+type LoginAttemptCache interface {
+	Get(ctx context.Context, account, ip string) (domain.LoginAttempts, error)
+	// Incr 账号和 IP 的失败次数都加一，返回加完之后的结果
+	Incr(ctx context.Context, account, ip string) (domain.LoginAttempts, error)
+	Lock(ctx context.Context, account string, duration time.Duration) error
+	// Clear 登录成功或者管理员解锁，清掉失败次数和锁
+	Clear(ctx context.Context, account string) error
+}
+
+type RedisLoginAttemptCache struct {
+	cmd redis.Cmdable
+	// 失败次数的统计窗口
+	window time.Duration
+}
+
+func NewRedisLoginAttemptCache(cmd redis.Cmdable) LoginAttemptCache {
+	return &RedisLoginAttemptCache{
+		cmd:    cmd,
+		window: time.Minute * 15,
+	}
+}
+
+func (c *RedisLoginAttemptCache) Get(ctx context.Context, account, ip string) (domain.LoginAttempts, error) {
+	pipe := c.cmd.Pipeline()
+	accountCmd := pipe.Get(ctx, c.accountKey(account))
+	ipCmd := pipe.Get(ctx, c.ipKey(ip))
+	lockCmd := pipe.PTTL(ctx, c.lockKey(account))
+	_, err := pipe.Exec(ctx)
+	// 没有失败过的时候 key 不存在
+	if err != nil && err != redis.Nil {
+		return domain.LoginAttempts{}, err
+	}
+	res := domain.LoginAttempts{}
+	res.AccountFails, _ = accountCmd.Int64()
+	res.IPFails, _ = ipCmd.Int64()
+	if ttl := lockCmd.Val(); ttl > 0 {
+		res.Locked = ttl
+	}
+	return res, nil
+}
+
+func (c *RedisLoginAttemptCache) Incr(ctx context.Context, account, ip string) (domain.LoginAttempts, error) {
+	res, err := c.cmd.Eval(ctx, luaIncrLoginFail,
+		[]string{c.accountKey(account), c.ipKey(ip)}, int64(c.window/time.Second)).Int64Slice()
+	if err != nil {
+		return domain.LoginAttempts{}, err
+	}
+	if len(res) != 2 {
+		return domain.LoginAttempts{}, fmt.Errorf("登录失败计数返回值不对 %v", res)
+	}
+	return domain.LoginAttempts{AccountFails: res[0], IPFails: res[1]}, nil
+}
+
+func (c *RedisLoginAttemptCache) Lock(ctx context.Context, account string, duration time.Duration) error {
+	return c.cmd.Set(ctx, c.lockKey(account), "", duration).Err()
+}
+
+func (c *RedisLoginAttemptCache) Clear(ctx context.Context, account string) error {
+	return c.cmd.Del(ctx, c.accountKey(account), c.lockKey(account)).Err()
+}
+
+func (c *RedisLoginAttemptCache) accountKey(account string) string {
+	return fmt.Sprintf("login:fail:account:%s", account)
+}
+
+func (c *RedisLoginAttemptCache) ipKey(ip string) string {
+	return fmt.Sprintf("login:fail:ip:%s", ip)
+}
+
+func (c *RedisLoginAttemptCache) lockKey(account string) string {
+	return fmt.Sprintf("login:lock:%s", account)
+}
@@ -0,0 +1,17 @@
+-- 账号失败次数
+local accountKey = KEYS[1]
+-- IP 失败次数
+local ipKey = KEYS[2]
+-- 统计窗口，秒
+local window = tonumber(ARGV[1])
+
+local accountCnt = redis.call("incr", accountKey)
+if accountCnt == 1 then
+    -- 窗口从第一次失败开始算
+    redis.call("expire", accountKey, window)
+end
+local ipCnt = redis.call("incr", ipKey)
+if ipCnt == 1 then
+    redis.call("expire", ipKey, window)
+end
+return {accountCnt, ipCnt}
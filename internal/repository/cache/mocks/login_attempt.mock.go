@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/cache/login_attempt.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/cache/login_attempt.go -package=cachemocks -destination=./internal/repository/cache/mocks/login_attempt.mock.go
+//
+
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginAttemptCache is a mock of LoginAttemptCache interface.
+type MockLoginAttemptCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAttemptCacheMockRecorder is the mock recorder for MockLoginAttemptCache.
+type MockLoginAttemptCacheMockRecorder struct {
+	mock *MockLoginAttemptCache
+}
+
+// NewMockLoginAttemptCache creates a new mock instance.
+func NewMockLoginAttemptCache(ctrl *gomock.Controller) *MockLoginAttemptCache {
+	mock := &MockLoginAttemptCache{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptCache) EXPECT() *MockLoginAttemptCacheMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockLoginAttemptCache) Clear(ctx context.Context, account string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockLoginAttemptCacheMockRecorder) Clear(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockLoginAttemptCache)(nil).Clear), ctx, account)
+}
+
+// Get mocks base method.
+func (m *MockLoginAttemptCache) Get(ctx context.Context, account, ip string) (domain.LoginAttempts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, account, ip)
+	ret0, _ := ret[0].(domain.LoginAttempts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLoginAttemptCacheMockRecorder) Get(ctx, account, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLoginAttemptCache)(nil).Get), ctx, account, ip)
+}
+
+// Incr mocks base method.
+func (m *MockLoginAttemptCache) Incr(ctx context.Context, account, ip string) (domain.LoginAttempts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, account, ip)
+	ret0, _ := ret[0].(domain.LoginAttempts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockLoginAttemptCacheMockRecorder) Incr(ctx, account, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockLoginAttemptCache)(nil).Incr), ctx, account, ip)
+}
+
+// Lock mocks base method.
+func (m *MockLoginAttemptCache) Lock(ctx context.Context, account string, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, account, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginAttemptCacheMockRecorder) Lock(ctx, account, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginAttemptCache)(nil).Lock), ctx, account, duration)
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+//go:generate mockgen -source=./notification.go -package=cachemocks -destination=./mocks/notification.mock.go NotificationCache
+type NotificationCache interface {
+	// GetUnread 按照类型返回未读数，缓存不存在返回 ErrKeyNotExist
+	GetUnread(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error)
+	// SetUnread 只在缓存不存在的时候用，已有的字段不会被清掉
+	SetUnread(ctx context.Context, uid int64, cnts map[domain.NotificationType]int64) error
+	// IncrUnreadIfPresent 缓存存在才修改，不存在等下次查询的时候从数据库加载
+	IncrUnreadIfPresent(ctx context.Context, uid int64, typ domain.NotificationType, delta int64) error
+	// DelUnread 全部已读之类的，没办法按类型加减的时候直接删掉
+	DelUnread(ctx context.Context, uid int64) error
+}
+
+type NotificationRedisCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewNotificationRedisCache(client redis.Cmdable) NotificationCache {
+	return &NotificationRedisCache{
+		client:     client,
+		expiration: time.Hour * 24,
+	}
+}
+
+func (c *NotificationRedisCache) GetUnread(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error) {
+	res, err := c.client.HGetAll(ctx, c.unreadKey(uid)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, ErrKeyNotExist
+	}
+	cnts := make(map[domain.NotificationType]int64, len(res))
+	for field, val := range res {
+		typ, er := strconv.ParseUint(field, 10, 8)
+		if er != nil || typ == uint64(domain.NotificationTypeUnknown) {
+			continue
+		}
+		cnts[domain.NotificationType(typ)], _ = strconv.ParseInt(val, 10, 64)
+	}
+	return cnts, nil
+}
+
+func (c *NotificationRedisCache) SetUnread(ctx context.Context, uid int64, cnts map[domain.NotificationType]int64) error {
+	key := c.unreadKey(uid)
+	// 一个都没有的时候也要占一个位置，不然每次都会回查数据库
+	vals := []any{strconv.Itoa(int(domain.NotificationTypeUnknown)), 0}
+	for typ, cnt := range cnts {
+		vals = append(vals, strconv.Itoa(int(typ)), cnt)
+	}
+	err := c.client.HSet(ctx, key, vals...).Err()
+	if err != nil {
+		return err
+	}
+	return c.client.Expire(ctx, key, c.expiration).Err()
+}
+
+func (c *NotificationRedisCache) IncrUnreadIfPresent(ctx context.Context, uid int64, typ domain.NotificationType, delta int64) error {
+	return c.client.Eval(ctx, luaIncrCnt, []string{c.unreadKey(uid)}, strconv.Itoa(int(typ)), delta).Err()
+}
+
+func (c *NotificationRedisCache) DelUnread(ctx context.Context, uid int64) error {
+	return c.client.Del(ctx, c.unreadKey(uid)).Err()
+}
+
+func (c *NotificationRedisCache) unreadKey(uid int64) string {
+	return fmt.Sprintf("notification:unread:%d", uid)
+}
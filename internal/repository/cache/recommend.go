@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+type RecommendCache interface {
+	// Replace 用新算出来的推荐结果覆盖旧的
+	Replace(ctx context.Context, uid int64, items []domain.RecommendItem) error
+	// Get 按照分数从高到低分页
+	Get(ctx context.Context, uid int64, offset int, limit int) ([]domain.RecommendItem, error)
+}
+
+type RecommendRedisCache struct {
+	client redis.Cmdable
+	// 推荐结果是定时任务算出来的，过期时间要比任务的间隔长
+	expiration time.Duration
+}
+
+func NewRecommendRedisCache(client redis.Cmdable) RecommendCache {
+	return &RecommendRedisCache{
+		client:     client,
+		expiration: time.Hour * 24,
+	}
+}
+
+func (r *RecommendRedisCache) Replace(ctx context.Context, uid int64, items []domain.RecommendItem) error {
+	key := r.key(uid)
+	members := make([]redis.Z, 0, len(items))
+	for _, item := range items {
+		members = append(members, redis.Z{
+			Score:  item.Score,
+			Member: item.Aid,
+		})
+	}
+	// 用事务保证读者不会读到只写了一半的结果
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, r.expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RecommendRedisCache) Get(ctx context.Context, uid int64, offset int, limit int) ([]domain.RecommendItem, error) {
+	res, err := r.client.ZRevRangeWithScores(ctx, r.key(uid),
+		int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]domain.RecommendItem, 0, len(res))
+	for _, z := range res {
+		aid, _ := strconv.ParseInt(z.Member.(string), 10, 64)
+		items = append(items, domain.RecommendItem{
+			Aid:   aid,
+			Score: z.Score,
+		})
+	}
+	return items, nil
+}
+
+func (r *RecommendRedisCache) key(uid int64) string {
+	return fmt.Sprintf("recommend:feed:%d", uid)
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"webook/internal/domain"
+)
+
+type RewardCache interface {
+	// GetCachedCodeURL 同一个人对同一个东西打赏同样的金额，复用之前的二维码
+	GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
+	CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error
+	// DelCodeURL 打赏有了结果之后二维码就不能再用了
+	DelCodeURL(ctx context.Context, r domain.Reward) error
+}
+
+type RewardRedisCache struct {
+	client redis.Cmdable
+	// 微信的二维码两个小时过期，这里短一点，避免用户扫到过期的码
+	expiration time.Duration
+}
+
+func NewRewardRedisCache(client redis.Cmdable) RewardCache {
+	return &RewardRedisCache{
+		client:     client,
+		expiration: time.Minute * 30,
+	}
+}
+
+func (c *RewardRedisCache) GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	data, err := c.client.Get(ctx, c.codeURLKey(r)).Bytes()
+	if err != nil {
+		return domain.CodeURL{}, err
+	}
+	var res domain.CodeURL
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+func (c *RewardRedisCache) CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error {
+	data, err := json.Marshal(cu)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.codeURLKey(r), data, c.expiration).Err()
+}
+
+func (c *RewardRedisCache) DelCodeURL(ctx context.Context, r domain.Reward) error {
+	return c.client.Del(ctx, c.codeURLKey(r)).Err()
+}
+
+func (c *RewardRedisCache) codeURLKey(r domain.Reward) string {
+	return fmt.Sprintf("reward:code_url:%s:%d:%d:%d", r.Target.Biz, r.Target.BizId, r.Uid, r.Amt)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"webook/internal/domain"
+)
+
+// UserBanCache 点赞、评论都要检查封禁，缓存起来。
+// 没有被封禁的用户也要缓存，存的是零值
+type UserBanCache interface {
+	Get(ctx context.Context, uid int64) (domain.UserBan, error)
+	Set(ctx context.Context, uid int64, b domain.UserBan) error
+	Del(ctx context.Context, uid int64) error
+}
+
+type RedisUserBanCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewRedisUserBanCache(cmd redis.Cmdable) UserBanCache {
+	return &RedisUserBanCache{
+		cmd:        cmd,
+		expiration: time.Minute * 10,
+	}
+}
+
+func (c *RedisUserBanCache) Get(ctx context.Context, uid int64) (domain.UserBan, error) {
+	data, err := c.cmd.Get(ctx, c.key(uid)).Bytes()
+	if err != nil {
+		return domain.UserBan{}, err
+	}
+	var b domain.UserBan
+	err = json.Unmarshal(data, &b)
+	return b, err
+}
+
+func (c *RedisUserBanCache) Set(ctx context.Context, uid int64, b domain.UserBan) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, c.key(uid), data, c.expiration).Err()
+}
+
+func (c *RedisUserBanCache) Del(ctx context.Context, uid int64) error {
+	return c.cmd.Del(ctx, c.key(uid)).Err()
+}
+
+func (c *RedisUserBanCache) key(uid int64) string {
+	return fmt.Sprintf("user:ban:%d", uid)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// UserRoleCache 每个管理后台的请求都要查角色，缓存起来
+type UserRoleCache interface {
+	Get(ctx context.Context, uid int64) ([]string, error)
+	Set(ctx context.Context, uid int64, roles []string) error
+	Del(ctx context.Context, uid int64) error
+}
+
+type RedisUserRoleCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewRedisUserRoleCache(cmd redis.Cmdable) UserRoleCache {
+	return &RedisUserRoleCache{
+		cmd:        cmd,
+		expiration: time.Minute * 10,
+	}
+}
+
+func (c *RedisUserRoleCache) Get(ctx context.Context, uid int64) ([]string, error) {
+	data, err := c.cmd.Get(ctx, c.key(uid)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var roles []string
+	err = json.Unmarshal(data, &roles)
+	return roles, err
+}
+
+// Set 没有角色的用户也要缓存，普通用户访问管理后台的时候不用每次都查数据库
+func (c *RedisUserRoleCache) Set(ctx context.Context, uid int64, roles []string) error {
+	if roles == nil {
+		roles = []string{}
+	}
+	data, err := json.Marshal(roles)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, c.key(uid), data, c.expiration).Err()
+}
+
+func (c *RedisUserRoleCache) Del(ctx context.Context, uid int64) error {
+	return c.cmd.Del(ctx, c.key(uid)).Err()
+}
+
+func (c *RedisUserRoleCache) key(uid int64) string {
+	return fmt.Sprintf("user:roles:%d", uid)
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+	"webook/pkg/logger"
+)
+
+var ErrCommentNotFound = dao.ErrRecordNotFound
+
+type CommentRepository interface {
+	// FindByBiz 根评论，minId 为 0 的时候从最新的开始
+	FindByBiz(ctx context.Context, biz string, bizId, minId, limit int64) ([]domain.Comment, error)
+	// GetMoreReplies 根评论下面的回复，按照时间顺序
+	GetMoreReplies(ctx context.Context, rid, maxId, limit int64) ([]domain.Comment, error)
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	CreateComment(ctx context.Context, c domain.Comment) (int64, error)
+	DeleteComment(ctx context.Context, c domain.Comment) error
+	UpdateStatus(ctx context.Context, c domain.Comment, status domain.CommentStatus) error
+}
+
+type CachedCommentRepository struct {
+	dao   dao.CommentDAO
+	cache cache.CommentCache
+	// 缓存的第一页的大小，超过这个数量的分页查询直接走数据库
+	firstPageSize int64
+	l             logger.LoggerV1
+}
+
+func NewCachedCommentRepository(dao dao.CommentDAO, cache cache.CommentCache, l logger.LoggerV1) CommentRepository {
+	return &CachedCommentRepository{
+		dao:           dao,
+		cache:         cache,
+		firstPageSize: 50,
+		l:             l,
+	}
+}
+
+func (c *CachedCommentRepository) FindByBiz(ctx context.Context, biz string, bizId, minId, limit int64) ([]domain.Comment, error) {
+	if minId > 0 || limit > c.firstPageSize {
+		return c.findByBiz(ctx, biz, bizId, minId, limit)
+	}
+	res, err := c.cache.GetFirstPage(ctx, biz, bizId)
+	if err != nil {
+		// 缓存未命中，直接查一整页出来放进缓存
+		res, err = c.findByBiz(ctx, biz, bizId, 0, c.firstPageSize)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			er := c.cache.SetFirstPage(ctx, biz, bizId, res)
+			if er != nil {
+				c.l.Error("回写评论第一页缓存失败",
+					logger.String("biz", biz),
+					logger.Int64("bizId", bizId),
+					logger.Error(er))
+			}
+		}()
+	}
+	if int64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (c *CachedCommentRepository) findByBiz(ctx context.Context, biz string, bizId, minId, limit int64) ([]domain.Comment, error) {
+	cs, err := c.dao.FindByBiz(ctx, biz, bizId, minId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Comment, domain.Comment](cs, func(idx int, src dao.Comment) domain.Comment {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedCommentRepository) GetMoreReplies(ctx context.Context, rid, maxId, limit int64) ([]domain.Comment, error) {
+	cs, err := c.dao.FindRepliesByRid(ctx, rid, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Comment, domain.Comment](cs, func(idx int, src dao.Comment) domain.Comment {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedCommentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	res, err := c.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return c.toDomain(res), nil
+}
+
+func (c *CachedCommentRepository) CreateComment(ctx context.Context, cmt domain.Comment) (int64, error) {
+	id, err := c.dao.Insert(ctx, c.toEntity(cmt))
+	if err == nil && cmt.RootComment == nil && cmt.Status == domain.CommentStatusNormal {
+		// 只有根评论会影响第一页
+		c.delFirstPage(ctx, cmt.Biz, cmt.BizId)
+	}
+	return id, err
+}
+
+func (c *CachedCommentRepository) DeleteComment(ctx context.Context, cmt domain.Comment) error {
+	err := c.dao.Delete(ctx, cmt.Id)
+	if err == nil && cmt.RootComment == nil {
+		c.delFirstPage(ctx, cmt.Biz, cmt.BizId)
+	}
+	return err
+}
+
+func (c *CachedCommentRepository) UpdateStatus(ctx context.Context, cmt domain.Comment, status domain.CommentStatus) error {
+	err := c.dao.UpdateStatus(ctx, cmt.Id, status.ToUint8())
+	if err == nil && cmt.RootComment == nil {
+		c.delFirstPage(ctx, cmt.Biz, cmt.BizId)
+	}
+	return err
+}
+
+func (c *CachedCommentRepository) delFirstPage(ctx context.Context, biz string, bizId int64) {
+	er := c.cache.DelFirstPage(ctx, biz, bizId)
+	if er != nil {
+		c.l.Error("删除评论第一页缓存失败",
+			logger.String("biz", biz),
+			logger.Int64("bizId", bizId),
+			logger.Error(er))
+	}
+}
+
+func (c *CachedCommentRepository) toDomain(src dao.Comment) domain.Comment {
+	res := domain.Comment{
+		Id: src.Id,
+		Commentator: domain.User{
+			Id: src.Uid,
+		},
+		Biz:     src.Biz,
+		BizId:   src.BizId,
+		Content: src.Content,
+		Status:  domain.CommentStatus(src.Status),
+		Ctime:   time.UnixMilli(src.Ctime),
+		Utime:   time.UnixMilli(src.Utime),
+	}
+	if src.RootId.Valid {
+		res.RootComment = &domain.Comment{Id: src.RootId.Int64}
+	}
+	if src.Pid.Valid {
+		res.ParentComment = &domain.Comment{Id: src.Pid.Int64}
+	}
+	return res
+}
+
+func (c *CachedCommentRepository) toEntity(src domain.Comment) dao.Comment {
+	res := dao.Comment{
+		Id:      src.Id,
+		Uid:     src.Commentator.Id,
+		Biz:     src.Biz,
+		BizId:   src.BizId,
+		Content: src.Content,
+		Status:  src.Status.ToUint8(),
+	}
+	if src.RootComment != nil {
+		res.RootId = sql.NullInt64{Int64: src.RootComment.Id, Valid: true}
+	}
+	if src.ParentComment != nil {
+		res.Pid = sql.NullInt64{Int64: src.ParentComment.Id, Valid: true}
+	}
+	return res
+}
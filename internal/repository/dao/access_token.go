@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AccessTokenDAO interface {
+	Insert(ctx context.Context, t AccessToken) (int64, error)
+	FindByHash(ctx context.Context, hash string) (AccessToken, error)
+	FindByUid(ctx context.Context, uid int64) ([]AccessToken, error)
+	CountByUid(ctx context.Context, uid int64) (int64, error)
+	// Delete 不是这个用户的返回 ErrRecordNotFound
+	Delete(ctx context.Context, uid int64, id int64) error
+	UpdateLastUsed(ctx context.Context, id int64, lastUsed int64) error
+}
+
+type GORMAccessTokenDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAccessTokenDAO(db *gorm.DB) AccessTokenDAO {
+	return &GORMAccessTokenDAO{db: db}
+}
+
+func (dao *GORMAccessTokenDAO) Insert(ctx context.Context, t AccessToken) (int64, error) {
+	now := time.Now().UnixMilli()
+	t.Ctime = now
+	t.Utime = now
+	err := dao.db.WithContext(ctx).Create(&t).Error
+	return t.Id, err
+}
+
+func (dao *GORMAccessTokenDAO) FindByHash(ctx context.Context, hash string) (AccessToken, error) {
+	var res AccessToken
+	err := dao.db.WithContext(ctx).Where("token_hash = ?", hash).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMAccessTokenDAO) FindByUid(ctx context.Context, uid int64) ([]AccessToken, error) {
+	var res []AccessToken
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Order("id DESC").Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMAccessTokenDAO) CountByUid(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := dao.db.WithContext(ctx).Model(&AccessToken{}).Where("uid = ?", uid).Count(&cnt).Error
+	return cnt, err
+}
+
+func (dao *GORMAccessTokenDAO) Delete(ctx context.Context, uid int64, id int64) error {
+	res := dao.db.WithContext(ctx).Where("id = ? AND uid = ?", id, uid).Delete(&AccessToken{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMAccessTokenDAO) UpdateLastUsed(ctx context.Context, id int64, lastUsed int64) error {
+	return dao.db.WithContext(ctx).Model(&AccessToken{}).Where("id = ?", id).
+		Updates(map[string]any{"last_used": lastUsed}).Error
+}
+
+type AccessToken struct {
+	Id   int64  `gorm:"primaryKey,autoIncrement"`
+	Uid  int64  `gorm:"index"`
+	Name string `gorm:"type:varchar(128)"`
+	// 明文的 SHA256
+	TokenHash string `gorm:"type:char(64);unique"`
+	Prefix    string `gorm:"type:varchar(32)"`
+	// 逗号分隔
+	Scopes   string `gorm:"type:varchar(1024)"`
+	ExpireAt int64
+	LastUsed int64
+	Ctime    int64
+	Utime    int64
+}
@@ -0,0 +1,164 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+var (
+	ErrDuplicateTransaction = errors.New("重复入账")
+	ErrUnbalancedEntries    = errors.New("借贷不平")
+	ErrInsufficientBalance  = errors.New("余额不足")
+)
+
+// 清算账户代表跟支付渠道之间的往来，只有它可以是负数
+const accountTypeClearing uint8 = 3
+
+type AccountDAO interface {
+	// AddEntries 在一个事务里面记账并且更新余额，entries 加起来必须是 0，
+	// 除了清算账户，任何账户记完之后余额都不能是负数
+	AddEntries(ctx context.Context, txn AccountTransaction, entries []AccountActivity) error
+	FindAccounts(ctx context.Context, uid int64) ([]Account, error)
+	FindActivities(ctx context.Context, uid int64, offset int, limit int) ([]AccountActivity, error)
+	// SumAmt 所有流水加起来的金额，正常情况下永远是 0
+	SumAmt(ctx context.Context) (int64, error)
+}
+
+type AccountGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewAccountGORMDAO(db *gorm.DB) AccountDAO {
+	return &AccountGORMDAO{db: db}
+}
+
+func (dao *AccountGORMDAO) AddEntries(ctx context.Context, txn AccountTransaction, entries []AccountActivity) error {
+	var sum int64
+	for _, e := range entries {
+		sum = sum + e.Amt
+	}
+	if sum != 0 || len(entries) == 0 {
+		return ErrUnbalancedEntries
+	}
+	// 固定加锁顺序，避免两笔入账互相等待
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Uid < entries[j].Uid
+	})
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txn.Ctime = now
+		err := tx.Create(&txn).Error
+		if me, ok := err.(*mysql.MySQLError); ok {
+			const duplicateErr uint16 = 1062
+			if me.Number == duplicateErr {
+				return ErrDuplicateTransaction
+			}
+		}
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			// 账户不存在就顺便创建
+			err = tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]any{
+					"balance": gorm.Expr("`balance` + ?", e.Amt),
+					"utime":   now,
+				}),
+			}).Create(&Account{
+				Uid:      e.Uid,
+				Type:     e.Type,
+				Currency: e.Currency,
+				Balance:  e.Amt,
+				Ctime:    now,
+				Utime:    now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			var acc Account
+			err = tx.Where("uid = ? AND type = ? AND currency = ?", e.Uid, e.Type, e.Currency).
+				First(&acc).Error
+			if err != nil {
+				return err
+			}
+			if acc.Balance < 0 && acc.Type != accountTypeClearing {
+				return ErrInsufficientBalance
+			}
+			e.TxnId = txn.Id
+			e.AccountId = acc.Id
+			e.Biz = txn.Biz
+			e.BizId = txn.BizId
+			e.Balance = acc.Balance
+			e.Ctime = now
+			err = tx.Create(&e).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (dao *AccountGORMDAO) FindAccounts(ctx context.Context, uid int64) ([]Account, error) {
+	var res []Account
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Order("type").Find(&res).Error
+	return res, err
+}
+
+func (dao *AccountGORMDAO) FindActivities(ctx context.Context, uid int64, offset int, limit int) ([]AccountActivity, error) {
+	var res []AccountActivity
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *AccountGORMDAO) SumAmt(ctx context.Context) (int64, error) {
+	var sum int64
+	err := dao.db.WithContext(ctx).Model(&AccountActivity{}).
+		Select("COALESCE(SUM(`amt`), 0)").Scan(&sum).Error
+	return sum, err
+}
+
+// Account 账户，系统账户的 uid 是 0
+type Account struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"uniqueIndex:uid_type_currency"`
+	Type     uint8  `gorm:"uniqueIndex:uid_type_currency"`
+	Currency string `gorm:"type:varchar(16);uniqueIndex:uid_type_currency"`
+	Balance  int64
+	Ctime    int64
+	Utime    int64
+}
+
+// AccountTransaction 一次入账，靠唯一索引保证同一个业务只入账一次
+type AccountTransaction struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:biz_type_id"`
+	BizId int64  `gorm:"uniqueIndex:biz_type_id"`
+	Ctime int64
+}
+
+// AccountActivity 流水，只增不改
+type AccountActivity struct {
+	Id        int64 `gorm:"primaryKey,autoIncrement"`
+	TxnId     int64 `gorm:"index"`
+	AccountId int64
+	// 冗余下来，按照用户查流水
+	Uid      int64 `gorm:"index"`
+	Type     uint8
+	Biz      string `gorm:"type:varchar(128)"`
+	BizId    int64
+	Amt      int64
+	Currency string `gorm:"type:varchar(16)"`
+	// 记完这一笔之后的余额
+	Balance int64
+	Ctime   int64
+}
@@ -17,6 +17,9 @@ type ArticleDao interface {
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error)
+	ListPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error)
+	// ListPubByAuthor 作者已经发表的文章，最近更新的在前面
+	ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]PublishedArticle, error)
 }
 
 type ArticleGORMDAO struct {
@@ -27,7 +30,25 @@ func (a *ArticleGORMDAO) ListPub(ctx context.Context, start time.Time, offset in
 	var res []PublishedArticle
 	const ArticleStatusPublished = 2
 	err := a.db.WithContext(ctx).Where("utime < ? AND status = ?", start.UnixMilli(), ArticleStatusPublished).
-		Offset(offset).Limit(limit).First(&res).Error
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).Where("author_id = ? AND status = ?", uid, ArticleStatusPublished).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).Where("id IN ? AND status = ?", ids, ArticleStatusPublished).
+		Find(&res).Error
 	return res, err
 }
 
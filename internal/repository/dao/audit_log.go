@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AuditLogDAO interface {
+	Insert(ctx context.Context, l AuditLog) error
+	// List 按照时间倒序，operator 为 0 就是所有管理员的
+	List(ctx context.Context, operator int64, offset, limit int) ([]AuditLog, error)
+}
+
+type GORMAuditLogDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAuditLogDAO(db *gorm.DB) AuditLogDAO {
+	return &GORMAuditLogDAO{db: db}
+}
+
+func (dao *GORMAuditLogDAO) Insert(ctx context.Context, l AuditLog) error {
+	l.Ctime = time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Create(&l).Error
+}
+
+func (dao *GORMAuditLogDAO) List(ctx context.Context, operator int64, offset, limit int) ([]AuditLog, error) {
+	var res []AuditLog
+	db := dao.db.WithContext(ctx)
+	if operator > 0 {
+		db = db.Where("operator = ?", operator)
+	}
+	err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+// AuditLog 只增不改
+type AuditLog struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Operator int64  `gorm:"index"`
+	Action   string `gorm:"type:varchar(256)"`
+	Detail   string `gorm:"type:text"`
+	IP       string `gorm:"type:varchar(64)"`
+	// 业务错误码，HTTP 状态码都是 200，记了没用
+	Code  int
+	Ctime int64
+}
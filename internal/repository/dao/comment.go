@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"gorm.io/gorm"
+	"time"
+)
+
+// CommentStatusNormal 和 domain.CommentStatusNormal 保持一致
+const CommentStatusNormal = 0
+
+type CommentDAO interface {
+	Insert(ctx context.Context, c Comment) (int64, error)
+	// FindByBiz 找根评论，id 降序，minId 为 0 的时候从最新的开始找
+	FindByBiz(ctx context.Context, biz string, bizId, minId, limit int64) ([]Comment, error)
+	// FindRepliesByRid 找某个根评论下面的回复，id 升序，找 id 大于 maxId 的
+	FindRepliesByRid(ctx context.Context, rid, maxId, limit int64) ([]Comment, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	UpdateStatus(ctx context.Context, id int64, status uint8) error
+	// Delete 删除评论，子评论依靠外键级联删除
+	Delete(ctx context.Context, id int64) error
+}
+
+type GORMCommentDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCommentDAO(db *gorm.DB) CommentDAO {
+	return &GORMCommentDAO{db: db}
+}
+
+func (dao *GORMCommentDAO) Insert(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Create(&c).Error
+	return c.Id, err
+}
+
+func (dao *GORMCommentDAO) FindByBiz(ctx context.Context, biz string, bizId, minId, limit int64) ([]Comment, error) {
+	var res []Comment
+	query := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND pid IS NULL AND status = ?", biz, bizId, CommentStatusNormal)
+	if minId > 0 {
+		query = query.Where("id < ?", minId)
+	}
+	err := query.Order("id DESC").Limit(int(limit)).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) FindRepliesByRid(ctx context.Context, rid, maxId, limit int64) ([]Comment, error) {
+	var res []Comment
+	err := dao.db.WithContext(ctx).
+		Where("root_id = ? AND id > ? AND status = ?", rid, maxId, CommentStatusNormal).
+		Order("id ASC").
+		Limit(int(limit)).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var res Comment
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) UpdateStatus(ctx context.Context, id int64, status uint8) error {
+	return dao.db.WithContext(ctx).Model(&Comment{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMCommentDAO) Delete(ctx context.Context, id int64) error {
+	return dao.db.WithContext(ctx).Delete(&Comment{Id: id}).Error
+}
+
+// Comment 评论表
+// 如果是 MongoDB 之类的，可以把回复直接嵌在根评论里面，但是关系型数据库就只能一行一条评论
+type Comment struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 发表评论的人
+	Uid int64 `gorm:"index"`
+	// 被评论的资源
+	Biz   string `gorm:"type:varchar(64);index:biz_type_id"`
+	BizId int64  `gorm:"index:biz_type_id"`
+
+	Content string `gorm:"type:text"`
+
+	// 根评论 ID，NULL 说明自己就是根评论
+	RootId sql.NullInt64 `gorm:"index"`
+	// 父评论 ID，NULL 说明自己就是根评论
+	Pid sql.NullInt64 `gorm:"index"`
+	// 删除父评论的时候，级联把子评论也删掉
+	ParentComment *Comment `gorm:"ForeignKey:Pid;AssociationForeignKey:Id;constraint:OnDelete:CASCADE"`
+
+	// 0 是正常展示，其余状态只有审核的时候会用到
+	Status uint8
+
+	Ctime int64
+	Utime int64
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeedDAO interface {
+	// CreatePushEvents 写到粉丝的收件箱，重复写入会被忽略
+	CreatePushEvents(ctx context.Context, events []FeedPushEvent) error
+	// CreatePullEvent 写到作者自己的发件箱
+	CreatePullEvent(ctx context.Context, event FeedPullEvent) error
+	// FindPushEvents 收件箱里面按照 (ctime, aid) 排在 (maxCtime, maxAid) 之后的
+	FindPushEvents(ctx context.Context, uid int64, maxCtime, maxAid int64, limit int) ([]FeedPushEvent, error)
+	// FindPullEvents 这些作者发件箱里面按照 (ctime, aid) 排在 (maxCtime, maxAid) 之后的
+	FindPullEvents(ctx context.Context, uids []int64, maxCtime, maxAid int64, limit int) ([]FeedPullEvent, error)
+	// DeleteByAid 文章撤回，收件箱和发件箱都要删掉
+	DeleteByAid(ctx context.Context, aid int64) error
+}
+
+type GORMFeedDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMFeedDAO(db *gorm.DB) FeedDAO {
+	return &GORMFeedDAO{db: db}
+}
+
+func (dao *GORMFeedDAO) CreatePushEvents(ctx context.Context, events []FeedPushEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	// 重新发表同一篇文章不会再把它顶上去
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&events).Error
+}
+
+func (dao *GORMFeedDAO) CreatePullEvent(ctx context.Context, event FeedPullEvent) error {
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&event).Error
+}
+
+func (dao *GORMFeedDAO) FindPushEvents(ctx context.Context, uid int64, maxCtime, maxAid int64, limit int) ([]FeedPushEvent, error) {
+	var res []FeedPushEvent
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND (ctime < ? OR (ctime = ? AND aid < ?))", uid, maxCtime, maxCtime, maxAid).
+		Order("ctime DESC, aid DESC").
+		Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFeedDAO) FindPullEvents(ctx context.Context, uids []int64, maxCtime, maxAid int64, limit int) ([]FeedPullEvent, error) {
+	if len(uids) == 0 {
+		return []FeedPullEvent{}, nil
+	}
+	var res []FeedPullEvent
+	err := dao.db.WithContext(ctx).
+		Where("uid IN ? AND (ctime < ? OR (ctime = ? AND aid < ?))", uids, maxCtime, maxCtime, maxAid).
+		Order("ctime DESC, aid DESC").
+		Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFeedDAO) DeleteByAid(ctx context.Context, aid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("aid = ?", aid).Delete(&FeedPushEvent{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("aid = ?", aid).Delete(&FeedPullEvent{}).Error
+	})
+}
+
+// FeedPushEvent 推模型，粉丝的收件箱，一个粉丝一条
+type FeedPushEvent struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 收件人，也就是粉丝
+	Uid int64 `gorm:"uniqueIndex:uid_aid;index:uid_ctime,priority:1"`
+	// 撤回的时候按照文章删
+	Aid int64 `gorm:"uniqueIndex:uid_aid;index"`
+	// 作者
+	AuthorId int64
+	// 文章上线的时间
+	Ctime int64 `gorm:"index:uid_ctime,priority:2"`
+}
+
+// FeedPullEvent 拉模型，大 V 的发件箱，一篇文章一条
+type FeedPullEvent struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 作者
+	Uid   int64 `gorm:"index:uid_ctime,priority:1"`
+	Aid   int64 `gorm:"uniqueIndex"`
+	Ctime int64 `gorm:"index:uid_ctime,priority:2"`
+}
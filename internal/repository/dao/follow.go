@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	FollowRelationStatusUnknown uint8 = iota
+	// FollowRelationStatusActive 关注中
+	FollowRelationStatusActive
+	// FollowRelationStatusInactive 取消关注了
+	FollowRelationStatusInactive
+)
+
+type FollowRelationDAO interface {
+	// CreateFollowRelation 关注，已经关注了就什么也不做
+	CreateFollowRelation(ctx context.Context, r FollowRelation) error
+	// UpdateStatus 取消关注就是把状态改成 inactive
+	UpdateStatus(ctx context.Context, followee, follower int64, status uint8) error
+	// FollowRelationList 某人的关注列表
+	FollowRelationList(ctx context.Context, follower, offset, limit int64) ([]FollowRelation, error)
+	// FollowerRelationList 某人的粉丝列表
+	FollowerRelationList(ctx context.Context, followee, offset, limit int64) ([]FollowRelation, error)
+	FollowRelationDetail(ctx context.Context, follower, followee int64) (FollowRelation, error)
+	GetStatics(ctx context.Context, uid int64) (FollowStatics, error)
+}
+
+type GORMFollowRelationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMFollowRelationDAO(db *gorm.DB) FollowRelationDAO {
+	return &GORMFollowRelationDAO{db: db}
+}
+
+func (dao *GORMFollowRelationDAO) CreateFollowRelation(ctx context.Context, r FollowRelation) error {
+	return dao.updateStatus(ctx, r.Followee, r.Follower, FollowRelationStatusActive)
+}
+
+func (dao *GORMFollowRelationDAO) UpdateStatus(ctx context.Context, followee, follower int64, status uint8) error {
+	return dao.updateStatus(ctx, followee, follower, status)
+}
+
+// updateStatus 关系和计数在同一个事务里面改，并且只有状态真的发生变化的时候才改计数
+// 这样重复关注、重复取消关注都不会把计数搞乱
+func (dao *GORMFollowRelationDAO) updateStatus(ctx context.Context, followee, follower int64, status uint8) error {
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var r FollowRelation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("follower = ? AND followee = ?", follower, followee).
+			First(&r).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if status != FollowRelationStatusActive {
+				// 本来就没有关注
+				return nil
+			}
+			err = tx.Create(&FollowRelation{
+				Follower: follower,
+				Followee: followee,
+				Status:   status,
+				Ctime:    now,
+				Utime:    now,
+			}).Error
+		case err != nil:
+			return err
+		case r.Status == status:
+			return nil
+		default:
+			err = tx.Model(&FollowRelation{}).Where("id = ?", r.Id).
+				Updates(map[string]any{
+					"status": status,
+					"utime":  now,
+				}).Error
+		}
+		if err != nil {
+			return err
+		}
+		var delta int64 = 1
+		if status != FollowRelationStatusActive {
+			delta = -1
+		}
+		err = dao.incrStatics(tx, followee, "followers", delta, now)
+		if err != nil {
+			return err
+		}
+		return dao.incrStatics(tx, follower, "followees", delta, now)
+	})
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == 1062 {
+		// 并发关注，另外一个事务已经插入成功，计数也是它加的
+		return nil
+	}
+	return err
+}
+
+func (dao *GORMFollowRelationDAO) incrStatics(tx *gorm.DB, uid int64, col string, delta int64, now int64) error {
+	s := FollowStatics{
+		Uid:   uid,
+		Ctime: now,
+		Utime: now,
+	}
+	if col == "followers" {
+		s.Followers = delta
+	} else {
+		s.Followees = delta
+	}
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			col:     gorm.Expr(col+" + ?", delta),
+			"utime": now,
+		}),
+	}).Create(&s).Error
+}
+
+func (dao *GORMFollowRelationDAO) FollowRelationList(ctx context.Context, follower, offset, limit int64) ([]FollowRelation, error) {
+	var res []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND status = ?", follower, FollowRelationStatusActive).
+		Order("utime DESC").
+		Offset(int(offset)).Limit(int(limit)).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) FollowerRelationList(ctx context.Context, followee, offset, limit int64) ([]FollowRelation, error) {
+	var res []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("followee = ? AND status = ?", followee, FollowRelationStatusActive).
+		Order("utime DESC").
+		Offset(int(offset)).Limit(int(limit)).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) FollowRelationDetail(ctx context.Context, follower, followee int64) (FollowRelation, error) {
+	var res FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND followee = ? AND status = ?", follower, followee, FollowRelationStatusActive).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) GetStatics(ctx context.Context, uid int64) (FollowStatics, error) {
+	var res FollowStatics
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&res).Error
+	return res, err
+}
+
+// FollowRelation 关注关系表
+type FollowRelation struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 查关注列表，用 follower 开头的联合索引
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	Followee int64 `gorm:"uniqueIndex:follower_followee;index"`
+	// 取消关注不删数据，只改状态
+	Status uint8
+	Ctime  int64
+	Utime  int64
+}
+
+// FollowStatics 关注计数表，和关注关系在同一个事务里面更新
+type FollowStatics struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"unique"`
+	// 有多少粉丝
+	Followers int64
+	// 关注了多少人
+	Followees int64
+	Ctime     int64
+	Utime     int64
+}
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type HistoryRecordDAO interface {
+	// Upsert 同一个用户多次阅读同一篇文章，只更新阅读时间
+	Upsert(ctx context.Context, r ReadHistory) error
+	// FindByUid 按照阅读时间倒序，找出某个用户 start 之后的阅读记录
+	FindByUid(ctx context.Context, uid int64, start time.Time, limit int) ([]ReadHistory, error)
+	// FindActiveUids 找出 start 之后有阅读行为的用户
+	FindActiveUids(ctx context.Context, start time.Time, offset int, limit int) ([]int64, error)
+}
+
+type GORMHistoryRecordDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMHistoryRecordDAO(db *gorm.DB) HistoryRecordDAO {
+	return &GORMHistoryRecordDAO{db: db}
+}
+
+func (dao *GORMHistoryRecordDAO) Upsert(ctx context.Context, r ReadHistory) error {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"utime": now,
+		}),
+	}).Create(&r).Error
+}
+
+func (dao *GORMHistoryRecordDAO) FindByUid(ctx context.Context, uid int64, start time.Time, limit int) ([]ReadHistory, error) {
+	var res []ReadHistory
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND utime > ?", uid, start.UnixMilli()).
+		Order("utime DESC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMHistoryRecordDAO) FindActiveUids(ctx context.Context, start time.Time, offset int, limit int) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&ReadHistory{}).
+		Distinct("uid").
+		Where("utime > ?", start.UnixMilli()).
+		Order("uid").
+		Offset(offset).Limit(limit).
+		Pluck("uid", &res).Error
+	return res, err
+}
+
+// ReadHistory 阅读历史，一个用户对同一个资源只保留一条
+type ReadHistory struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	BizId int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id"`
+	Ctime int64
+	// 最后一次阅读的时间，按照这个来找活跃用户
+	Utime int64 `gorm:"index"`
+}
@@ -11,15 +11,45 @@ import (
 
 func InitTables(db *gorm.DB) error {
 	// 严格来说，这不是优秀的实践
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&User{},
+		&UserIdentity{},
 		&Article{},
 		&PublishedArticle{},
 		&Interactive{},
 		&UserLikeBiz{},
 		&UserCollectionBiz{},
 		&Job{},
+		&ArticleTag{},
+		&ReadHistory{},
+		&Comment{},
+		&ModerationTask{},
+		&FollowRelation{},
+		&FollowStatics{},
+		&FeedPushEvent{},
+		&FeedPullEvent{},
+		&Reward{},
+		&Payment{},
+		&Account{},
+		&AccountTransaction{},
+		&AccountActivity{},
+		&Withdrawal{},
+		&WithdrawalLog{},
+		&Notification{},
+		&NotificationActor{},
+		&UserTwoFactor{},
+		&UserRecoveryCode{},
+		&AccessToken{},
+		&UserRole{},
+		&UserBan{},
+		&AuditLog{},
+		&DataExport{},
+		&AccountDeletion{},
 	)
+	if err != nil {
+		return err
+	}
+	return migrateWechatIdentities(db)
 }
 
 func InitCollection(mdb *mongo.Database) error {
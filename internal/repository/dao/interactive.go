@@ -17,6 +17,12 @@ type InteractiveDAO interface {
 	GetCollectInfo(ctx context.Context, biz string, bizId int64, uid int64) (UserCollectionBiz, error)
 	Get(ctx context.Context, biz string, bizId int64) (Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error)
+	// GetLikedBizIds 用户最近点赞过的资源
+	GetLikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
+	// GetCollectedBizIds 用户最近收藏过的资源
+	GetCollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
+	// SumArticlesByAuthor 作者所有已经发表的文章加起来的阅读、点赞、收藏数
+	SumArticlesByAuthor(ctx context.Context, authorId int64) (Interactive, error)
 }
 
 type GORMInteractiveDAO struct {
@@ -29,6 +35,40 @@ func (dao *GORMInteractiveDAO) GetByIds(ctx context.Context, biz string, ids []i
 	return res, err
 }
 
+func (dao *GORMInteractiveDAO) GetLikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&UserLikeBiz{}).
+		Where("biz = ? AND uid = ? AND status = ?", biz, uid, 1).
+		Order("utime DESC").
+		Limit(limit).
+		Pluck("biz_id", &res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) GetCollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&UserCollectionBiz{}).
+		Where("biz = ? AND uid = ?", biz, uid).
+		Order("utime DESC").
+		Limit(limit).
+		Pluck("biz_id", &res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) SumArticlesByAuthor(ctx context.Context, authorId int64) (Interactive, error) {
+	var res Interactive
+	const articleStatusPublished = 2
+	err := dao.db.WithContext(ctx).Model(&Interactive{}).
+		Select("COALESCE(SUM(interactives.read_cnt), 0) AS read_cnt, "+
+			"COALESCE(SUM(interactives.like_cnt), 0) AS like_cnt, "+
+			"COALESCE(SUM(interactives.collect_cnt), 0) AS collect_cnt").
+		Joins("JOIN published_articles ON published_articles.id = interactives.biz_id").
+		Where("interactives.biz = ? AND published_articles.author_id = ? AND published_articles.status = ?",
+			"article", authorId, articleStatusPublished).
+		Scan(&res).Error
+	return res, err
+}
+
 func (dao *GORMInteractiveDAO) Get(ctx context.Context, biz string, bizId int64) (Interactive, error) {
 	var res Interactive
 	err := dao.db.WithContext(ctx).Where("biz=? AND biz_id=?", biz, bizId).First(&res).Error
@@ -12,6 +12,7 @@ type JobDAO interface {
 	Release(ctx context.Context, jid int64) error
 	UpdateUtime(ctx context.Context, id int64) error
 	UpdateNextTime(ctx context.Context, jid int64, t time.Time) error
+	List(ctx context.Context, offset, limit int) ([]Job, error)
 }
 
 type GORMJobDAO struct {
@@ -135,6 +136,12 @@ func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, jid int64, t time.Tim
 	}).Error
 }
 
+func (dao *GORMJobDAO) List(ctx context.Context, offset, limit int) ([]Job, error) {
+	var res []Job
+	err := dao.db.WithContext(ctx).Order("id").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
 type Job struct {
 	Id         int64  `gorm:"primaryKey,autoIncrement"`
 	Name       string `gorm:"type:varchar(128);unique"`
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./user.go
+// Source: ./internal/repository/dao/user.go
 //
 // Generated by this command:
 //
-//	mockgen -source=./user.go -package=daomocks -destination=./mocks/user.mock.go UserDAO
+//	mockgen -source=./internal/repository/dao/user.go -package=daomocks -destination=./internal/repository/dao/mocks/user.mock.go
 //
 
 // Package daomocks is a generated GoMock package.
@@ -11,6 +11,7 @@ package daomocks
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 	dao "webook/internal/repository/dao"
 
@@ -41,6 +42,34 @@ func (m *MockUserDAO) EXPECT() *MockUserDAOMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockUserDAO) Anonymize(ctx context.Context, uid int64, nickname string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, uid, nickname)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserDAOMockRecorder) Anonymize(ctx, uid, nickname any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserDAO)(nil).Anonymize), ctx, uid, nickname)
+}
+
+// DeleteIdentity mocks base method.
+func (m *MockUserDAO) DeleteIdentity(ctx context.Context, uid int64, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdentity", ctx, uid, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdentity indicates an expected call of DeleteIdentity.
+func (mr *MockUserDAOMockRecorder) DeleteIdentity(ctx, uid, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdentity", reflect.TypeOf((*MockUserDAO)(nil).DeleteIdentity), ctx, uid, provider)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserDAO) FindByEmail(ctx context.Context, email string) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +85,21 @@ func (mr *MockUserDAOMockRecorder) FindByEmail(ctx, email any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserDAO)(nil).FindByEmail), ctx, email)
 }
 
+// FindByHandle mocks base method.
+func (m *MockUserDAO) FindByHandle(ctx context.Context, handle string) (dao.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHandle", ctx, handle)
+	ret0, _ := ret[0].(dao.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHandle indicates an expected call of FindByHandle.
+func (mr *MockUserDAOMockRecorder) FindByHandle(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHandle", reflect.TypeOf((*MockUserDAO)(nil).FindByHandle), ctx, handle)
+}
+
 // FindById mocks base method.
 func (m *MockUserDAO) FindById(ctx context.Context, uid int64) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +115,21 @@ func (mr *MockUserDAOMockRecorder) FindById(ctx, uid any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserDAO)(nil).FindById), ctx, uid)
 }
 
+// FindByIdentity mocks base method.
+func (m *MockUserDAO) FindByIdentity(ctx context.Context, provider, subject string) (dao.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(dao.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIdentity indicates an expected call of FindByIdentity.
+func (mr *MockUserDAOMockRecorder) FindByIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIdentity", reflect.TypeOf((*MockUserDAO)(nil).FindByIdentity), ctx, provider, subject)
+}
+
 // FindByPhone mocks base method.
 func (m *MockUserDAO) FindByPhone(ctx context.Context, phone string) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -86,19 +145,19 @@ func (mr *MockUserDAOMockRecorder) FindByPhone(ctx, phone any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockUserDAO)(nil).FindByPhone), ctx, phone)
 }
 
-// FindByWechat mocks base method.
-func (m *MockUserDAO) FindByWechat(ctx context.Context, openId string) (dao.User, error) {
+// FindIdentities mocks base method.
+func (m *MockUserDAO) FindIdentities(ctx context.Context, uid int64) ([]dao.UserIdentity, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByWechat", ctx, openId)
-	ret0, _ := ret[0].(dao.User)
+	ret := m.ctrl.Call(m, "FindIdentities", ctx, uid)
+	ret0, _ := ret[0].([]dao.UserIdentity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindByWechat indicates an expected call of FindByWechat.
-func (mr *MockUserDAOMockRecorder) FindByWechat(ctx, openId any) *gomock.Call {
+// FindIdentities indicates an expected call of FindIdentities.
+func (mr *MockUserDAOMockRecorder) FindIdentities(ctx, uid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWechat", reflect.TypeOf((*MockUserDAO)(nil).FindByWechat), ctx, openId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentities", reflect.TypeOf((*MockUserDAO)(nil).FindIdentities), ctx, uid)
 }
 
 // Insert mocks base method.
@@ -115,6 +174,64 @@ func (mr *MockUserDAOMockRecorder) Insert(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockUserDAO)(nil).Insert), ctx, user)
 }
 
+// InsertWithIdentity mocks base method.
+func (m *MockUserDAO) InsertWithIdentity(ctx context.Context, user dao.User, identity dao.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWithIdentity", ctx, user, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWithIdentity indicates an expected call of InsertWithIdentity.
+func (mr *MockUserDAOMockRecorder) InsertWithIdentity(ctx, user, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWithIdentity", reflect.TypeOf((*MockUserDAO)(nil).InsertWithIdentity), ctx, user, identity)
+}
+
+// List mocks base method.
+func (m *MockUserDAO) List(ctx context.Context, offset, limit int) ([]dao.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]dao.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserDAOMockRecorder) List(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserDAO)(nil).List), ctx, offset, limit)
+}
+
+// Merge mocks base method.
+func (m *MockUserDAO) Merge(ctx context.Context, primary, duplicate int64) ([]dao.Interactive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, primary, duplicate)
+	ret0, _ := ret[0].([]dao.Interactive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserDAOMockRecorder) Merge(ctx, primary, duplicate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUserDAO)(nil).Merge), ctx, primary, duplicate)
+}
+
+// UpdateAvatar mocks base method.
+func (m *MockUserDAO) UpdateAvatar(ctx context.Context, uid int64, avatar string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvatar", ctx, uid, avatar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAvatar indicates an expected call of UpdateAvatar.
+func (mr *MockUserDAOMockRecorder) UpdateAvatar(ctx, uid, avatar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvatar", reflect.TypeOf((*MockUserDAO)(nil).UpdateAvatar), ctx, uid, avatar)
+}
+
 // UpdateById mocks base method.
 func (m *MockUserDAO) UpdateById(ctx context.Context, entity dao.User) error {
 	m.ctrl.T.Helper()
@@ -128,3 +245,115 @@ func (mr *MockUserDAOMockRecorder) UpdateById(ctx, entity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockUserDAO)(nil).UpdateById), ctx, entity)
 }
+
+// UpdateEmail mocks base method.
+func (m *MockUserDAO) UpdateEmail(ctx context.Context, uid int64, email sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, uid, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockUserDAOMockRecorder) UpdateEmail(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserDAO)(nil).UpdateEmail), ctx, uid, email)
+}
+
+// UpdateEmailVerified mocks base method.
+func (m *MockUserDAO) UpdateEmailVerified(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmailVerified", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmailVerified indicates an expected call of UpdateEmailVerified.
+func (mr *MockUserDAOMockRecorder) UpdateEmailVerified(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmailVerified", reflect.TypeOf((*MockUserDAO)(nil).UpdateEmailVerified), ctx, uid)
+}
+
+// UpdateHandle mocks base method.
+func (m *MockUserDAO) UpdateHandle(ctx context.Context, uid int64, handle string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHandle", ctx, uid, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHandle indicates an expected call of UpdateHandle.
+func (mr *MockUserDAOMockRecorder) UpdateHandle(ctx, uid, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHandle", reflect.TypeOf((*MockUserDAO)(nil).UpdateHandle), ctx, uid, handle)
+}
+
+// UpdateNotificationMute mocks base method.
+func (m *MockUserDAO) UpdateNotificationMute(ctx context.Context, uid int64, mute uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationMute", ctx, uid, mute)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationMute indicates an expected call of UpdateNotificationMute.
+func (mr *MockUserDAOMockRecorder) UpdateNotificationMute(ctx, uid, mute any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationMute", reflect.TypeOf((*MockUserDAO)(nil).UpdateNotificationMute), ctx, uid, mute)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserDAO) UpdatePassword(ctx context.Context, uid int64, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, uid, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserDAOMockRecorder) UpdatePassword(ctx, uid, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserDAO)(nil).UpdatePassword), ctx, uid, password)
+}
+
+// UpdatePhone mocks base method.
+func (m *MockUserDAO) UpdatePhone(ctx context.Context, uid int64, phone sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhone", ctx, uid, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhone indicates an expected call of UpdatePhone.
+func (mr *MockUserDAOMockRecorder) UpdatePhone(ctx, uid, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhone", reflect.TypeOf((*MockUserDAO)(nil).UpdatePhone), ctx, uid, phone)
+}
+
+// UpdateProfilePrivacy mocks base method.
+func (m *MockUserDAO) UpdateProfilePrivacy(ctx context.Context, uid int64, privacy uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfilePrivacy", ctx, uid, privacy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfilePrivacy indicates an expected call of UpdateProfilePrivacy.
+func (mr *MockUserDAOMockRecorder) UpdateProfilePrivacy(ctx, uid, privacy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfilePrivacy", reflect.TypeOf((*MockUserDAO)(nil).UpdateProfilePrivacy), ctx, uid, privacy)
+}
+
+// UpsertIdentity mocks base method.
+func (m *MockUserDAO) UpsertIdentity(ctx context.Context, identity dao.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertIdentity indicates an expected call of UpsertIdentity.
+func (mr *MockUserDAOMockRecorder) UpsertIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertIdentity", reflect.TypeOf((*MockUserDAO)(nil).UpsertIdentity), ctx, identity)
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ModerationDAO interface {
+	Insert(ctx context.Context, t ModerationTask) (int64, error)
+	FindById(ctx context.Context, id int64) (ModerationTask, error)
+	// FindByStatus 先提交的先审核
+	FindByStatus(ctx context.Context, status uint8, offset, limit int) ([]ModerationTask, error)
+	// UpdateStatus 只有处于 from 状态的才会被更新，避免两个人同时审核同一条
+	// 返回是否更新成功
+	UpdateStatus(ctx context.Context, id int64, from, to uint8, reviewer int64, reason string) (bool, error)
+}
+
+type GORMModerationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMModerationDAO(db *gorm.DB) ModerationDAO {
+	return &GORMModerationDAO{db: db}
+}
+
+func (dao *GORMModerationDAO) Insert(ctx context.Context, t ModerationTask) (int64, error) {
+	now := time.Now().UnixMilli()
+	t.Ctime = now
+	t.Utime = now
+	err := dao.db.WithContext(ctx).Create(&t).Error
+	return t.Id, err
+}
+
+func (dao *GORMModerationDAO) FindById(ctx context.Context, id int64) (ModerationTask, error) {
+	var res ModerationTask
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMModerationDAO) FindByStatus(ctx context.Context, status uint8, offset, limit int) ([]ModerationTask, error) {
+	var res []ModerationTask
+	err := dao.db.WithContext(ctx).Where("status = ?", status).
+		Order("id ASC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMModerationDAO) UpdateStatus(ctx context.Context, id int64, from, to uint8, reviewer int64, reason string) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&ModerationTask{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{
+			"status":   to,
+			"reviewer": reviewer,
+			"reason":   reason,
+			"utime":    time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ModerationTask 人工审核队列
+type ModerationTask struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Biz   string `gorm:"type:varchar(64);index:biz_type_id"`
+	BizId int64  `gorm:"index:biz_type_id"`
+	Uid   int64
+	// 内容快照，审核的时候内容可能已经又被改过了
+	Content     string `gorm:"type:text"`
+	ContentHash string `gorm:"type:varchar(64)"`
+	// 命中的敏感词，逗号分隔
+	Hits     string `gorm:"type:varchar(1024)"`
+	Status   uint8  `gorm:"index"`
+	Reviewer int64
+	Reason   string `gorm:"type:varchar(1024)"`
+	Ctime    int64
+	Utime    int64
+}
@@ -28,6 +28,16 @@ func (m *MongoDBArticleDAO) ListPub(ctx context.Context, start time.Time, offset
 	panic("implement me")
 }
 
+func (m *MongoDBArticleDAO) ListPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MongoDBArticleDAO) ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]PublishedArticle, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m *MongoDBArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
 	//TODO implement me
 	panic("implement me")
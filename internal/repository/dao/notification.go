@@ -0,0 +1,165 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// 合并通知的时候最多记住最近几个人
+const notificationRecentActors = 3
+
+//go:generate mockgen -source=./notification.go -package=daomocks -destination=./mocks/notification.mock.go NotificationDAO
+type NotificationDAO interface {
+	// Upsert 同一个人同一种类型同一个资源的未读通知合并成一条，
+	// 返回是否新增了一条未读通知
+	Upsert(ctx context.Context, n Notification, actor int64) (bool, error)
+	FindByUid(ctx context.Context, uid int64, offset, limit int) ([]Notification, error)
+	// MarkRead 返回被标记的那条通知，已经读过了返回 ErrRecordNotFound
+	MarkRead(ctx context.Context, uid, id int64) (Notification, error)
+	MarkAllRead(ctx context.Context, uid int64) error
+	// CountUnread 按照类型统计未读数
+	CountUnread(ctx context.Context, uid int64) (map[uint8]int64, error)
+}
+
+type GORMNotificationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMNotificationDAO(db *gorm.DB) NotificationDAO {
+	return &GORMNotificationDAO{db: db}
+}
+
+func (dao *GORMNotificationDAO) Upsert(ctx context.Context, n Notification, actor int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	created := false
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old Notification
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ? AND agg_key = ?", n.Uid, n.AggKey.String).
+			First(&old).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			actors, _ := json.Marshal([]int64{actor})
+			n.Actors = string(actors)
+			n.ActorCnt = 1
+			n.Ctime = now
+			n.Utime = now
+			err = tx.Create(&n).Error
+			if err != nil {
+				return err
+			}
+			created = true
+			return tx.Create(&NotificationActor{Nid: n.Id, Actor: actor, Ctime: now}).Error
+		case err != nil:
+			return err
+		}
+		// 同一个人重复触发，比如取消点赞之后又点赞，不重复计数
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&NotificationActor{Nid: old.Id, Actor: actor, Ctime: now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		var actors []int64
+		_ = json.Unmarshal([]byte(old.Actors), &actors)
+		actors = append([]int64{actor}, actors...)
+		if len(actors) > notificationRecentActors {
+			actors = actors[:notificationRecentActors]
+		}
+		val, _ := json.Marshal(actors)
+		return tx.Model(&old).Updates(map[string]any{
+			"actors":    string(val),
+			"actor_cnt": gorm.Expr("`actor_cnt` + 1"),
+			"biz_name":  n.BizName,
+			"content":   n.Content,
+			"utime":     now,
+		}).Error
+	})
+	return created, err
+}
+
+func (dao *GORMNotificationDAO) FindByUid(ctx context.Context, uid int64, offset, limit int) ([]Notification, error) {
+	var res []Notification
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMNotificationDAO) MarkRead(ctx context.Context, uid, id int64) (Notification, error) {
+	var n Notification
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND uid = ? AND `read` = ?", id, uid, false).
+			First(&n).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&n).Updates(dao.readUpdates()).Error
+	})
+	return n, err
+}
+
+func (dao *GORMNotificationDAO) MarkAllRead(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Model(&Notification{}).
+		Where("uid = ? AND `read` = ?", uid, false).
+		Updates(dao.readUpdates()).Error
+}
+
+// readUpdates 已读的通知不再参与合并，之后的互动会产生新的通知
+func (dao *GORMNotificationDAO) readUpdates() map[string]any {
+	return map[string]any{
+		"read":    true,
+		"agg_key": sql.NullString{},
+		"utime":   time.Now().UnixMilli(),
+	}
+}
+
+func (dao *GORMNotificationDAO) CountUnread(ctx context.Context, uid int64) (map[uint8]int64, error) {
+	var rows []struct {
+		Type uint8
+		Cnt  int64
+	}
+	err := dao.db.WithContext(ctx).Model(&Notification{}).
+		Select("`type`, COUNT(*) AS cnt").
+		Where("uid = ? AND `read` = ?", uid, false).
+		Group("type").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uint8]int64, len(rows))
+	for _, row := range rows {
+		res[row.Type] = row.Cnt
+	}
+	return res, nil
+}
+
+type Notification struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"uniqueIndex:uid_agg_key;index:uid_utime,priority:1"`
+	// 未读的时候是 类型:biz:bizId，用来合并通知，已读之后置为 NULL
+	AggKey  sql.NullString `gorm:"type:varchar(256);uniqueIndex:uid_agg_key"`
+	Type    uint8
+	Biz     string `gorm:"type:varchar(128)"`
+	BizId   int64
+	BizName string `gorm:"type:varchar(256)"`
+	// 最近几个触发者的 uid，JSON 数组
+	Actors   string `gorm:"type:varchar(256)"`
+	ActorCnt int64
+	Content  string `gorm:"type:varchar(1024)"`
+	Read     bool
+	Ctime    int64
+	Utime    int64 `gorm:"index:uid_utime,priority:2"`
+}
+
+// NotificationActor 一条通知都有谁触发过，用来去重
+type NotificationActor struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	Nid   int64 `gorm:"uniqueIndex:nid_actor"`
+	Actor int64 `gorm:"uniqueIndex:nid_actor"`
+	Ctime int64
+}
@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrDuplicatePayment = errors.New("业务单号已经有支付了")
+
+type PaymentDAO interface {
+	Insert(ctx context.Context, pmt Payment) error
+	GetPayment(ctx context.Context, bizTradeNO string) (Payment, error)
+	// UpdateTxnIDAndStatus 只有当前状态是 from 的时候才更新，返回是否真的更新了
+	UpdateTxnIDAndStatus(ctx context.Context, bizTradeNO string, txnID string, from, to uint8) (bool, error)
+	// FindExpiredPayment 找出 t 之前创建，还在等待支付的，按照 id 升序，只返回 id 大于 minId 的
+	FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]Payment, error)
+}
+
+type PaymentGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewPaymentGORMDAO(db *gorm.DB) PaymentDAO {
+	return &PaymentGORMDAO{db: db}
+}
+
+func (dao *PaymentGORMDAO) Insert(ctx context.Context, pmt Payment) error {
+	now := time.Now().UnixMilli()
+	pmt.Ctime = now
+	pmt.Utime = now
+	err := dao.db.WithContext(ctx).Create(&pmt).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrDuplicatePayment
+		}
+	}
+	return err
+}
+
+func (dao *PaymentGORMDAO) GetPayment(ctx context.Context, bizTradeNO string) (Payment, error) {
+	var res Payment
+	err := dao.db.WithContext(ctx).Where("biz_trade_no = ?", bizTradeNO).First(&res).Error
+	return res, err
+}
+
+func (dao *PaymentGORMDAO) UpdateTxnIDAndStatus(ctx context.Context, bizTradeNO string,
+	txnID string, from, to uint8) (bool, error) {
+	updates := map[string]any{
+		"status": to,
+		"utime":  time.Now().UnixMilli(),
+	}
+	if txnID != "" {
+		updates["txn_id"] = txnID
+	}
+	// 支付通知和对账任务可能同时更新，用状态做乐观锁
+	res := dao.db.WithContext(ctx).Model(&Payment{}).
+		Where("biz_trade_no = ? AND status = ?", bizTradeNO, from).
+		Updates(updates)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *PaymentGORMDAO) FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]Payment, error) {
+	const paymentStatusInit = 1
+	var res []Payment
+	err := dao.db.WithContext(ctx).
+		Where("id > ? AND status = ? AND ctime < ?", minId, paymentStatusInit, t.UnixMilli()).
+		Order("id").
+		Limit(limit).Find(&res).Error
+	return res, err
+}
+
+type Payment struct {
+	Id          int64 `gorm:"primaryKey,autoIncrement"`
+	Amt         int64
+	Currency    string
+	Description string
+	// 业务方的单号，同一个业务单只能有一笔支付
+	BizTradeNO string `gorm:"column:biz_trade_no;type:varchar(256);unique"`
+	// 第三方支付平台的交易号，支付之前是 NULL
+	TxnID sql.NullString `gorm:"column:txn_id;type:varchar(128);unique"`
+	// 对账任务按照状态和创建时间找
+	Status uint8 `gorm:"index:status_ctime,priority:1"`
+	Ctime  int64 `gorm:"index:status_ctime,priority:2"`
+	Utime  int64
+}
@@ -0,0 +1,184 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type DataExportDAO interface {
+	Insert(ctx context.Context, e DataExport) (int64, error)
+	// FindLatest 这个用户最近一次导出
+	FindLatest(ctx context.Context, uid int64) (DataExport, error)
+	// FindRunnable 等着打包的，还有 staleBefore 之前就开始打包但是一直没有结果的
+	FindRunnable(ctx context.Context, staleBefore int64, limit int) ([]DataExport, error)
+	// Claim 抢到了才打包，多个实例同时跑定时任务也只会有一个打包
+	Claim(ctx context.Context, id int64, staleBefore int64) (bool, error)
+	UpdateStatus(ctx context.Context, id int64, status uint8, fileKey string) error
+	// FindDoneBefore 在 utime 之前就打包好的
+	FindDoneBefore(ctx context.Context, utime int64, limit int) ([]DataExport, error)
+}
+
+type GORMDataExportDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMDataExportDAO(db *gorm.DB) DataExportDAO {
+	return &GORMDataExportDAO{db: db}
+}
+
+const (
+	dataExportStatusPending = 1
+	dataExportStatusRunning = 2
+	dataExportStatusDone    = 3
+)
+
+func (dao *GORMDataExportDAO) Insert(ctx context.Context, e DataExport) (int64, error) {
+	now := time.Now().UnixMilli()
+	e.Ctime = now
+	e.Utime = now
+	err := dao.db.WithContext(ctx).Create(&e).Error
+	return e.Id, err
+}
+
+func (dao *GORMDataExportDAO) FindLatest(ctx context.Context, uid int64) (DataExport, error) {
+	var res DataExport
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Order("id DESC").First(&res).Error
+	return res, err
+}
+
+func (dao *GORMDataExportDAO) FindRunnable(ctx context.Context, staleBefore int64, limit int) ([]DataExport, error) {
+	var res []DataExport
+	err := dao.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND utime < ?)",
+			dataExportStatusPending, dataExportStatusRunning, staleBefore).
+		Order("id").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMDataExportDAO) Claim(ctx context.Context, id int64, staleBefore int64) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&DataExport{}).
+		Where("id = ? AND (status = ? OR (status = ? AND utime < ?))",
+			id, dataExportStatusPending, dataExportStatusRunning, staleBefore).
+		Updates(map[string]any{
+			"status": dataExportStatusRunning,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (dao *GORMDataExportDAO) UpdateStatus(ctx context.Context, id int64, status uint8, fileKey string) error {
+	return dao.db.WithContext(ctx).Model(&DataExport{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"status":   status,
+			"file_key": fileKey,
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMDataExportDAO) FindDoneBefore(ctx context.Context, utime int64, limit int) ([]DataExport, error) {
+	var res []DataExport
+	err := dao.db.WithContext(ctx).
+		Where("status = ? AND utime < ?", dataExportStatusDone, utime).
+		Order("id").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+type DataExport struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	Uid    int64 `gorm:"index"`
+	Status uint8 `gorm:"index:status_utime"`
+	// 打包失败或者过期之后是空的
+	FileKey string `gorm:"type:varchar(256)"`
+	Ctime   int64
+	Utime   int64 `gorm:"index:status_utime"`
+}
+
+type AccountDeletionDAO interface {
+	// Upsert 撤销过再申请的话重新开始算冷静期
+	Upsert(ctx context.Context, d AccountDeletion) error
+	FindByUid(ctx context.Context, uid int64) (AccountDeletion, error)
+	// Cancel 没有在冷静期里面的申请返回 ErrRecordNotFound
+	Cancel(ctx context.Context, uid int64) error
+	// FindDue 冷静期在 now 之前就结束了的申请
+	FindDue(ctx context.Context, now int64, offset, limit int) ([]AccountDeletion, error)
+	MarkDone(ctx context.Context, uid int64) error
+}
+
+type GORMAccountDeletionDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAccountDeletionDAO(db *gorm.DB) AccountDeletionDAO {
+	return &GORMAccountDeletionDAO{db: db}
+}
+
+const (
+	accountDeletionStatusPending  = 1
+	accountDeletionStatusCanceled = 2
+	accountDeletionStatusDone     = 3
+)
+
+func (dao *GORMAccountDeletionDAO) Upsert(ctx context.Context, d AccountDeletion) error {
+	now := time.Now().UnixMilli()
+	d.Ctime = now
+	d.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"status":       d.Status,
+			"scheduled_at": d.ScheduledAt,
+			"ctime":        now,
+			"utime":        now,
+		}),
+	}).Create(&d).Error
+}
+
+func (dao *GORMAccountDeletionDAO) FindByUid(ctx context.Context, uid int64) (AccountDeletion, error) {
+	var res AccountDeletion
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMAccountDeletionDAO) Cancel(ctx context.Context, uid int64) error {
+	res := dao.db.WithContext(ctx).Model(&AccountDeletion{}).
+		Where("uid = ? AND status = ?", uid, accountDeletionStatusPending).
+		Updates(map[string]any{
+			"status": accountDeletionStatusCanceled,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMAccountDeletionDAO) FindDue(ctx context.Context, now int64, offset, limit int) ([]AccountDeletion, error) {
+	var res []AccountDeletion
+	err := dao.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", accountDeletionStatusPending, now).
+		Order("scheduled_at").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMAccountDeletionDAO) MarkDone(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Model(&AccountDeletion{}).
+		Where("uid = ? AND status = ?", uid, accountDeletionStatusPending).
+		Updates(map[string]any{
+			"status": accountDeletionStatusDone,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+// AccountDeletion 一个用户只有一条，撤销之后再申请就覆盖掉
+type AccountDeletion struct {
+	Id          int64 `gorm:"primaryKey,autoIncrement"`
+	Uid         int64 `gorm:"unique"`
+	Status      uint8 `gorm:"index:status_scheduled_at"`
+	ScheduledAt int64 `gorm:"index:status_scheduled_at"`
+	Ctime       int64
+	Utime       int64
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RewardDAO interface {
+	Insert(ctx context.Context, r Reward) (int64, error)
+	GetReward(ctx context.Context, rid int64) (Reward, error)
+	// UpdateStatus 只有等待支付的打赏才能改状态，返回是否真的改了
+	UpdateStatus(ctx context.Context, rid int64, status uint8) (bool, error)
+}
+
+type GORMRewardDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMRewardDAO(db *gorm.DB) RewardDAO {
+	return &GORMRewardDAO{db: db}
+}
+
+func (dao *GORMRewardDAO) Insert(ctx context.Context, r Reward) (int64, error) {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	err := dao.db.WithContext(ctx).Create(&r).Error
+	return r.Id, err
+}
+
+func (dao *GORMRewardDAO) GetReward(ctx context.Context, rid int64) (Reward, error) {
+	var r Reward
+	err := dao.db.WithContext(ctx).Where("id = ?", rid).First(&r).Error
+	return r, err
+}
+
+func (dao *GORMRewardDAO) UpdateStatus(ctx context.Context, rid int64, status uint8) (bool, error) {
+	// 支付成功的通知和对账可能同时到，用状态做乐观锁，保证只处理一次
+	const rewardStatusInit = 1
+	res := dao.db.WithContext(ctx).Model(&Reward{}).
+		Where("id = ? AND status = ?", rid, rewardStatusInit).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+type Reward struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Biz     string `gorm:"type:varchar(128);index:biz_biz_id"`
+	BizId   int64  `gorm:"index:biz_biz_id"`
+	BizName string `gorm:"type:varchar(256)"`
+	// 收钱的人
+	TargetUid int64 `gorm:"index"`
+	// 打赏的人
+	Uid int64 `gorm:"index"`
+	// 单位是分
+	Amount int64
+	Status uint8
+	Ctime  int64
+	Utime  int64
+}
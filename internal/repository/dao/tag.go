@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type TagDAO interface {
+	// ReplaceArticleTags 覆盖文章的标签
+	ReplaceArticleTags(ctx context.Context, aid int64, tags []string) error
+	GetByAids(ctx context.Context, aids []int64) ([]ArticleTag, error)
+}
+
+type GORMTagDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMTagDAO(db *gorm.DB) TagDAO {
+	return &GORMTagDAO{db: db}
+}
+
+func (dao *GORMTagDAO) ReplaceArticleTags(ctx context.Context, aid int64, tags []string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("aid = ?", aid).Delete(&ArticleTag{}).Error
+		if err != nil {
+			return err
+		}
+		rows := make([]ArticleTag, 0, len(tags))
+		seen := make(map[string]struct{}, len(tags))
+		for _, tag := range tags {
+			// 重复的标签会违反唯一索引
+			if _, ok := seen[tag]; ok || tag == "" {
+				continue
+			}
+			seen[tag] = struct{}{}
+			rows = append(rows, ArticleTag{
+				Aid:   aid,
+				Tag:   tag,
+				Ctime: now,
+			})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (dao *GORMTagDAO) GetByAids(ctx context.Context, aids []int64) ([]ArticleTag, error) {
+	var res []ArticleTag
+	err := dao.db.WithContext(ctx).Where("aid IN ?", aids).Find(&res).Error
+	return res, err
+}
+
+type ArticleTag struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Aid int64 `gorm:"uniqueIndex:aid_tag"`
+	// 按照标签查文章
+	Tag   string `gorm:"type:varchar(64);uniqueIndex:aid_tag;index"`
+	Ctime int64
+}
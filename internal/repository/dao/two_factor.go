@@ -0,0 +1,130 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TwoFactorDAO interface {
+	// Upsert 重新扫码会换一个新的密钥，调用方要保证还没有开启
+	Upsert(ctx context.Context, tf UserTwoFactor) error
+	FindByUid(ctx context.Context, uid int64) (UserTwoFactor, error)
+	// Enable 开启两步验证，同时换掉所有的恢复码。已经开启了返回 ErrRecordNotFound
+	Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error
+	// UseStep 只有比上次用过的时间步新才会成功
+	UseStep(ctx context.Context, uid int64, step int64) (bool, error)
+	// UseRecoveryCode 每个恢复码只能用一次
+	UseRecoveryCode(ctx context.Context, uid int64, codeHash string) (bool, error)
+	// Delete 关闭两步验证，恢复码也一起删掉
+	Delete(ctx context.Context, uid int64) error
+}
+
+type GORMTwoFactorDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMTwoFactorDAO(db *gorm.DB) TwoFactorDAO {
+	return &GORMTwoFactorDAO{db: db}
+}
+
+func (dao *GORMTwoFactorDAO) Upsert(ctx context.Context, tf UserTwoFactor) error {
+	now := time.Now().UnixMilli()
+	tf.Ctime = now
+	tf.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"secret":    tf.Secret,
+			"enabled":   false,
+			"last_step": 0,
+			"utime":     now,
+		}),
+	}).Create(&tf).Error
+}
+
+func (dao *GORMTwoFactorDAO) FindByUid(ctx context.Context, uid int64) (UserTwoFactor, error) {
+	var res UserTwoFactor
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMTwoFactorDAO) Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UserTwoFactor{}).
+			Where("uid = ? AND enabled = ?", uid, false).
+			Updates(map[string]any{
+				"enabled":   true,
+				"last_step": step,
+				"utime":     now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+		err := tx.Where("uid = ?", uid).Delete(&UserRecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		codes := make([]UserRecoveryCode, 0, len(codeHashes))
+		for _, h := range codeHashes {
+			codes = append(codes, UserRecoveryCode{Uid: uid, CodeHash: h, Ctime: now, Utime: now})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (dao *GORMTwoFactorDAO) UseStep(ctx context.Context, uid int64, step int64) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&UserTwoFactor{}).
+		Where("uid = ? AND last_step < ?", uid, step).
+		Updates(map[string]any{
+			"last_step": step,
+			"utime":     time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *GORMTwoFactorDAO) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&UserRecoveryCode{}).
+		Where("uid = ? AND code_hash = ? AND used = ?", uid, codeHash, false).
+		Updates(map[string]any{
+			"used":  true,
+			"utime": time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *GORMTwoFactorDAO) Delete(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("uid = ?", uid).Delete(&UserRecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("uid = ?", uid).Delete(&UserTwoFactor{}).Error
+	})
+}
+
+// UserTwoFactor 一个用户一条
+type UserTwoFactor struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	Uid    int64  `gorm:"uniqueIndex"`
+	Secret string `gorm:"type:varchar(64)"`
+	// 扫码之后确认过才算开启
+	Enabled  bool
+	LastStep int64
+	Ctime    int64
+	Utime    int64
+}
+
+// UserRecoveryCode 恢复码只存 SHA256，用过就作废
+type UserRecoveryCode struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"index:uid_code"`
+	CodeHash string `gorm:"type:varchar(64);index:uid_code"`
+	Used     bool
+	Ctime    int64
+	Utime    int64
+}
@@ -21,19 +21,39 @@ type UserDAO interface {
 	FindById(ctx context.Context, uid int64) (User, error)
 	UpdateById(ctx context.Context, entity User) error
 	FindByPhone(ctx context.Context, phone string) (User, error)
-	FindByWechat(ctx context.Context, openId string) (User, error)
+	// FindByIdentity 根据绑定的第三方账号找用户
+	FindByIdentity(ctx context.Context, provider, subject string) (User, error)
+	FindIdentities(ctx context.Context, uid int64) ([]UserIdentity, error)
+	// InsertWithIdentity 第三方账号第一次登录，用户和绑定关系一起创建
+	InsertWithIdentity(ctx context.Context, user User, identity UserIdentity) error
+	// UpsertIdentity 第三方账号已经被别人绑定了返回 ErrDuplicateEmail
+	UpsertIdentity(ctx context.Context, identity UserIdentity) error
+	DeleteIdentity(ctx context.Context, uid int64, provider string) error
+	UpdateNotificationMute(ctx context.Context, uid int64, mute uint32) error
+	UpdateEmailVerified(ctx context.Context, uid int64) error
+	UpdatePassword(ctx context.Context, uid int64, password string) error
+	// UpdatePhone phone 无效就是解绑
+	UpdatePhone(ctx context.Context, uid int64, phone sql.NullString) error
+	// UpdateEmail 换绑或者解绑邮箱，换绑的邮箱都是验证过的
+	UpdateEmail(ctx context.Context, uid int64, email sql.NullString) error
+	// Merge 把 duplicate 的文章和点赞收藏挪到 primary 上面，然后把 duplicate 作废。
+	// 返回计数被改过的资源，只有 Biz 和 BizId
+	Merge(ctx context.Context, primary, duplicate int64) ([]Interactive, error)
+	// List 按照 id 倒序，给管理后台用
+	List(ctx context.Context, offset, limit int) ([]User, error)
+	// Anonymize 注销账号，清掉个人信息和登录方式
+	Anonymize(ctx context.Context, uid int64, nickname string) error
+	FindByHandle(ctx context.Context, handle string) (User, error)
+	// UpdateHandle 已经被别人用了返回 ErrDuplicateEmail
+	UpdateHandle(ctx context.Context, uid int64, handle string) error
+	UpdateAvatar(ctx context.Context, uid int64, avatar string) error
+	UpdateProfilePrivacy(ctx context.Context, uid int64, privacy uint8) error
 }
 
 type GORMUserDAO struct {
 	db *gorm.DB
 }
 
-func (dao *GORMUserDAO) FindByWechat(ctx context.Context, openId string) (User, error) {
-	var u User
-	err := dao.db.WithContext(ctx).Where("wechat_open_id = ?", openId).First(&u).Error
-	return u, err
-}
-
 func NewUserDao(db *gorm.DB) UserDAO {
 	return &GORMUserDAO{db: db}
 }
@@ -74,18 +94,100 @@ func (dao *GORMUserDAO) UpdateById(ctx context.Context, entity User) error {
 	}).Error
 }
 
+func (dao *GORMUserDAO) UpdateNotificationMute(ctx context.Context, uid int64, mute uint32) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", uid).Updates(map[string]any{
+		"notification_mute": mute,
+		"utime":             time.Now().UnixMilli(),
+	}).Error
+}
+
+func (dao *GORMUserDAO) UpdateEmailVerified(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", uid).Updates(map[string]any{
+		"email_verified": true,
+		"utime":          time.Now().UnixMilli(),
+	}).Error
+}
+
+func (dao *GORMUserDAO) UpdatePassword(ctx context.Context, uid int64, password string) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", uid).Updates(map[string]any{
+		"password": password,
+		"utime":    time.Now().UnixMilli(),
+	}).Error
+}
+
+func (dao *GORMUserDAO) UpdatePhone(ctx context.Context, uid int64, phone sql.NullString) error {
+	return dao.updateIdentity(ctx, uid, map[string]any{
+		"phone": phone,
+	})
+}
+
+func (dao *GORMUserDAO) UpdateEmail(ctx context.Context, uid int64, email sql.NullString) error {
+	return dao.updateIdentity(ctx, uid, map[string]any{
+		"email":          email,
+		"email_verified": email.Valid,
+	})
+}
+
+// updateIdentity 身份字段上都有唯一索引，被别人抢先绑定了就是冲突
+func (dao *GORMUserDAO) updateIdentity(ctx context.Context, uid int64, fields map[string]any) error {
+	fields["utime"] = time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", uid).Updates(fields).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrDuplicateEmail
+		}
+	}
+	return err
+}
+
 func (dao *GORMUserDAO) FindByPhone(ctx context.Context, phone string) (User, error) {
 	var u User
 	err := dao.db.WithContext(ctx).Where("phone = ?", phone).First(&u).Error
 	return u, err
 }
 
+func (dao *GORMUserDAO) FindByHandle(ctx context.Context, handle string) (User, error) {
+	var u User
+	// 被合并掉的账号不能再出现在个人主页上
+	err := dao.db.WithContext(ctx).Where("handle = ? AND merged_into = ?", handle, 0).First(&u).Error
+	return u, err
+}
+
+func (dao *GORMUserDAO) UpdateHandle(ctx context.Context, uid int64, handle string) error {
+	return dao.updateIdentity(ctx, uid, map[string]any{
+		"handle":       sql.NullString{String: handle, Valid: true},
+		"handle_utime": time.Now().UnixMilli(),
+	})
+}
+
+func (dao *GORMUserDAO) UpdateAvatar(ctx context.Context, uid int64, avatar string) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", uid).Updates(map[string]any{
+		"avatar": avatar,
+		"utime":  time.Now().UnixMilli(),
+	}).Error
+}
+
+func (dao *GORMUserDAO) UpdateProfilePrivacy(ctx context.Context, uid int64, privacy uint8) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", uid).Updates(map[string]any{
+		"profile_privacy": privacy,
+		"utime":           time.Now().UnixMilli(),
+	}).Error
+}
+
+func (dao *GORMUserDAO) List(ctx context.Context, offset, limit int) ([]User, error) {
+	var res []User
+	err := dao.db.WithContext(ctx).Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
 type User struct {
 	Id int64 `gorm:"primaryKey,autoIncrement"`
 	// 代表这是一个可以为NULL的列
 	//Email *string
-	Email    sql.NullString `gorm:"unique"`
-	Password string
+	Email         sql.NullString `gorm:"unique"`
+	EmailVerified bool
+	Password      string
 
 	Nickname string `gorm:"type=varchar(128)"`
 	// YYYY-MM-DD
@@ -95,11 +197,18 @@ type User struct {
 	// 代表这是一个可以为NULL的列
 	Phone sql.NullString `gorm:"unique"`
 
-	// 1 如果查询要求同时使用openid和unionid，就要创建联合唯一索引
-	// 2 如果查询只用openid，那么就openid上创建唯一索引或者 <openid,unionid>联合索引
-	// 3 如果查询只用unionid，那么就在unionid上创建唯一索引或者 <unionid,openid>联合索引
-	WechatOpenId  sql.NullString `gorm:"unique"`
-	WechatUnionId sql.NullString
+	// 关掉了哪些通知，按位记录
+	NotificationMute uint32
+
+	// 个人主页的地址，存的是小写
+	Handle      sql.NullString `gorm:"type:varchar(32);unique"`
+	HandleUtime int64
+	Avatar      string `gorm:"type:varchar(1024)"`
+	// 个人主页上隐藏了哪些内容，按位记录
+	ProfilePrivacy uint8
+
+	// 被合并到了哪个账号，合并之后这个账号就不能再用了
+	MergedInto int64
 
 	// 时区 UTC 0 的毫秒数
 	// 创建时间
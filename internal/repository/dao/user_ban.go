@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type UserBanDAO interface {
+	// Upsert 已经封禁了就覆盖原来的范围、期限和原因
+	Upsert(ctx context.Context, b UserBan) error
+	FindByUid(ctx context.Context, uid int64) (UserBan, error)
+	Delete(ctx context.Context, uid int64) error
+}
+
+type GORMUserBanDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMUserBanDAO(db *gorm.DB) UserBanDAO {
+	return &GORMUserBanDAO{db: db}
+}
+
+func (dao *GORMUserBanDAO) Upsert(ctx context.Context, b UserBan) error {
+	now := time.Now().UnixMilli()
+	b.Ctime = now
+	b.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"reason":    b.Reason,
+			"scope":     b.Scope,
+			"expire_at": b.ExpireAt,
+			"operator":  b.Operator,
+			"utime":     now,
+		}),
+	}).Create(&b).Error
+}
+
+func (dao *GORMUserBanDAO) FindByUid(ctx context.Context, uid int64) (UserBan, error) {
+	var res UserBan
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMUserBanDAO) Delete(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Where("uid = ?", uid).Delete(&UserBan{}).Error
+}
+
+type UserBan struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	Uid    int64  `gorm:"unique"`
+	Reason string `gorm:"type:varchar(1024)"`
+	// domain.BanScope，之前的封禁都是全部封禁
+	Scope uint8 `gorm:"default:7"`
+	// 0 就是永久封禁
+	ExpireAt int64
+	Operator int64
+	Ctime    int64
+	Utime    int64
+}
@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Anonymize 注销账号，整个在一个事务里面。
+// 用户这一行留着，文章、评论、点赞收藏都还指向它，只是清掉了能认出是谁的信息；
+// 登录方式、阅读历史这些只跟本人有关的数据直接删掉
+func (dao *GORMUserDAO) Anonymize(ctx context.Context, uid int64, nickname string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&User{}).Where("id = ?", uid).Updates(map[string]any{
+			"email":             sql.NullString{},
+			"email_verified":    false,
+			"password":          "",
+			"phone":             sql.NullString{},
+			"nickname":          nickname,
+			"birthday":          0,
+			"description":       "",
+			"notification_mute": 0,
+			"handle":            sql.NullString{},
+			"avatar":            "",
+			"profile_privacy":   0,
+			"utime":             now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		for _, model := range []any{&UserIdentity{}, &ReadHistory{}, &AccessToken{},
+			&UserTwoFactor{}, &UserRecoveryCode{}, &UserRole{}} {
+			err = tx.Where("uid = ?", uid).Delete(model).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"context"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserIdentity 用户绑定的第三方账号
+type UserIdentity struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"uniqueIndex:uid_provider"`
+	// 每个提供方一个用户只能绑定一个，一个第三方账号也只能绑定一个用户
+	Provider string `gorm:"type:varchar(64);uniqueIndex:uid_provider;uniqueIndex:provider_subject"`
+	Subject  string `gorm:"type:varchar(255);uniqueIndex:provider_subject"`
+	UnionId  string `gorm:"type:varchar(255)"`
+	Email    string `gorm:"type:varchar(255)"`
+	Name     string `gorm:"type:varchar(255)"`
+	Ctime    int64
+	Utime    int64
+}
+
+func (dao *GORMUserDAO) FindByIdentity(ctx context.Context, provider, subject string) (User, error) {
+	var ui UserIdentity
+	err := dao.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).
+		First(&ui).Error
+	if err != nil {
+		return User{}, err
+	}
+	return dao.FindById(ctx, ui.Uid)
+}
+
+func (dao *GORMUserDAO) FindIdentities(ctx context.Context, uid int64) ([]UserIdentity, error) {
+	var res []UserIdentity
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Order("id").Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMUserDAO) InsertWithIdentity(ctx context.Context, user User, identity UserIdentity) error {
+	now := time.Now().UnixMilli()
+	user.Ctime, user.Utime = now, now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&user).Error
+		if err != nil {
+			return err
+		}
+		identity.Uid = user.Id
+		identity.Ctime, identity.Utime = now, now
+		return tx.Create(&identity).Error
+	})
+	return dao.duplicateErr(err)
+}
+
+// UpsertIdentity 同一个提供方已经绑定过的话直接换掉
+func (dao *GORMUserDAO) UpsertIdentity(ctx context.Context, identity UserIdentity) error {
+	now := time.Now().UnixMilli()
+	identity.Ctime, identity.Utime = now, now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("uid = ? AND provider = ?", identity.Uid, identity.Provider).
+			Delete(&UserIdentity{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&identity).Error
+	})
+	return dao.duplicateErr(err)
+}
+
+func (dao *GORMUserDAO) DeleteIdentity(ctx context.Context, uid int64, provider string) error {
+	return dao.db.WithContext(ctx).Where("uid = ? AND provider = ?", uid, provider).
+		Delete(&UserIdentity{}).Error
+}
+
+// mergeUserIdentities 主账号没有绑定的提供方才挪过去，剩下的删掉
+func (dao *GORMUserDAO) mergeUserIdentities(tx *gorm.DB, primary, duplicate int64, now int64) error {
+	var providers []string
+	err := tx.Model(&UserIdentity{}).Where("uid = ?", primary).Pluck("provider", &providers).Error
+	if err != nil {
+		return err
+	}
+	move := tx.Model(&UserIdentity{}).Where("uid = ?", duplicate)
+	if len(providers) > 0 {
+		move = move.Where("provider NOT IN ?", providers)
+	}
+	err = move.Updates(map[string]any{"uid": primary, "utime": now}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("uid = ?", duplicate).Delete(&UserIdentity{}).Error
+}
+
+func (dao *GORMUserDAO) duplicateErr(err error) error {
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrDuplicateEmail
+		}
+	}
+	return err
+}
+
+// migrateWechatIdentities 以前微信的 openid 直接放在 users 上面，挪到 user_identities 之后把列删掉。
+// 列已经删掉了就什么都不做
+func migrateWechatIdentities(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&User{}, "wechat_open_id") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("INSERT IGNORE INTO user_identities (uid, provider, subject, union_id, ctime, utime) " +
+			"SELECT id, 'wechat', wechat_open_id, COALESCE(wechat_union_id, ''), ctime, utime " +
+			"FROM users WHERE wechat_open_id IS NOT NULL AND wechat_open_id != ''").Error
+		if err != nil {
+			return err
+		}
+		err = tx.Migrator().DropColumn(&User{}, "wechat_open_id")
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&User{}, "wechat_union_id")
+	})
+}
@@ -0,0 +1,191 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Merge 整个合并在一个事务里面，要么全挪过去，要么都不动。
+// 评论、关注、打赏这些数据还留在 duplicate 上面。
+// duplicate 的个人访问令牌直接删掉，不然合并之后还能拿它登录。
+// 合并很少发生，所以点赞收藏是一条一条处理的
+func (dao *GORMUserDAO) Merge(ctx context.Context, primary, duplicate int64) ([]Interactive, error) {
+	now := time.Now().UnixMilli()
+	var changed []Interactive
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pu, du User
+		err := tx.Where("id = ? AND merged_into = ?", primary, 0).First(&pu).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("id = ? AND merged_into = ?", duplicate, 0).First(&du).Error
+		if err != nil {
+			return err
+		}
+		err = dao.mergeIdentities(tx, pu, du, now)
+		if err != nil {
+			return err
+		}
+		err = tx.Where("uid = ?", duplicate).Delete(&AccessToken{}).Error
+		if err != nil {
+			return err
+		}
+		for _, art := range []any{&Article{}, &PublishedArticle{}} {
+			err = tx.Model(art).Where("author_id = ?", duplicate).
+				Updates(map[string]any{"author_id": primary}).Error
+			if err != nil {
+				return err
+			}
+		}
+		likes, err := dao.mergeLikes(tx, primary, duplicate, now)
+		if err != nil {
+			return err
+		}
+		collections, err := dao.mergeCollections(tx, primary, duplicate, now)
+		if err != nil {
+			return err
+		}
+		changed = append(likes, collections...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// mergeIdentities 主账号没有的身份才挪过去，主账号已经有的就保留主账号的。
+// handle 也一样，主账号已经有了就把 duplicate 的释放掉，别人可以用。
+// 唯一索引的关系，要先把 duplicate 上面的清掉
+func (dao *GORMUserDAO) mergeIdentities(tx *gorm.DB, pu, du User, now int64) error {
+	err := tx.Model(&User{}).Where("id = ?", du.Id).Updates(map[string]any{
+		"email":          sql.NullString{},
+		"email_verified": false,
+		"password":       "",
+		"phone":          sql.NullString{},
+		"handle":         sql.NullString{},
+		"handle_utime":   0,
+		"merged_into":    pu.Id,
+		"utime":          now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	fields := map[string]any{"utime": now}
+	if !pu.Email.Valid && du.Email.Valid {
+		fields["email"] = du.Email
+		fields["email_verified"] = du.EmailVerified
+		// 密码跟着邮箱走，不然邮箱登录不了
+		if pu.Password == "" {
+			fields["password"] = du.Password
+		}
+	}
+	if !pu.Phone.Valid && du.Phone.Valid {
+		fields["phone"] = du.Phone
+	}
+	if !pu.Handle.Valid && du.Handle.Valid {
+		// 修改时间也带过去，不然合并一下就能绕过修改间隔
+		fields["handle"] = du.Handle
+		fields["handle_utime"] = du.HandleUtime
+	}
+	err = tx.Model(&User{}).Where("id = ?", pu.Id).Updates(fields).Error
+	if err != nil {
+		return err
+	}
+	return dao.mergeUserIdentities(tx, pu.Id, du.Id, now)
+}
+
+// mergeLikes 两个账号都点赞过的资源只能算一次，返回点赞数被改过的资源
+func (dao *GORMUserDAO) mergeLikes(tx *gorm.DB, primary, duplicate int64, now int64) ([]Interactive, error) {
+	var likes []UserLikeBiz
+	err := tx.Where("uid = ?", duplicate).Find(&likes).Error
+	if err != nil {
+		return nil, err
+	}
+	var changed []Interactive
+	for _, like := range likes {
+		var exist UserLikeBiz
+		err = tx.Where("uid = ? AND biz = ? AND biz_id = ?", primary, like.Biz, like.BizId).
+			First(&exist).Error
+		switch err {
+		case gorm.ErrRecordNotFound:
+			err = tx.Model(&UserLikeBiz{}).Where("id = ?", like.Id).
+				Updates(map[string]any{"uid": primary, "utime": now}).Error
+		case nil:
+			var decr bool
+			decr, err = dao.mergeLike(tx, exist, like, now)
+			if err == nil && decr {
+				changed = append(changed, Interactive{Biz: like.Biz, BizId: like.BizId})
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// mergeLike 返回 true 代表点赞数减了一
+func (dao *GORMUserDAO) mergeLike(tx *gorm.DB, exist, like UserLikeBiz, now int64) (bool, error) {
+	err := tx.Delete(&UserLikeBiz{}, like.Id).Error
+	if err != nil || like.Status != 1 {
+		return false, err
+	}
+	if exist.Status == 1 {
+		// 点赞数多算了一次
+		err = tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ?", like.Biz, like.BizId).
+			Updates(map[string]any{
+				"like_cnt": gorm.Expr("`like_cnt` - 1"),
+				"utime":    now,
+			}).Error
+		return err == nil, err
+	}
+	// 主账号取消过点赞，以 duplicate 的为准
+	return false, tx.Model(&UserLikeBiz{}).Where("id = ?", exist.Id).
+		Updates(map[string]any{"status": 1, "utime": now}).Error
+}
+
+// mergeCollections 两个账号都收藏过的资源只保留主账号的，返回收藏数被改过的资源
+func (dao *GORMUserDAO) mergeCollections(tx *gorm.DB, primary, duplicate int64, now int64) ([]Interactive, error) {
+	var cbs []UserCollectionBiz
+	err := tx.Where("uid = ?", duplicate).Find(&cbs).Error
+	if err != nil {
+		return nil, err
+	}
+	var changed []Interactive
+	for _, cb := range cbs {
+		var cnt int64
+		err = tx.Model(&UserCollectionBiz{}).
+			Where("uid = ? AND biz = ? AND biz_id = ?", primary, cb.Biz, cb.BizId).
+			Count(&cnt).Error
+		if err != nil {
+			return nil, err
+		}
+		if cnt == 0 {
+			err = tx.Model(&UserCollectionBiz{}).Where("id = ?", cb.Id).
+				Updates(map[string]any{"uid": primary, "utime": now}).Error
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = tx.Delete(&UserCollectionBiz{}, cb.Id).Error
+		if err != nil {
+			return nil, err
+		}
+		err = tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ?", cb.Biz, cb.BizId).
+			Updates(map[string]any{
+				"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+				"utime":       now,
+			}).Error
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, Interactive{Biz: cb.Biz, BizId: cb.BizId})
+	}
+	return changed, nil
+}
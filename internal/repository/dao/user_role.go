@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type UserRoleDAO interface {
+	FindByUid(ctx context.Context, uid int64) ([]UserRole, error)
+	// Insert 已经有这个角色了就什么都不做
+	Insert(ctx context.Context, r UserRole) error
+	Delete(ctx context.Context, uid int64, role string) error
+}
+
+type GORMUserRoleDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMUserRoleDAO(db *gorm.DB) UserRoleDAO {
+	return &GORMUserRoleDAO{db: db}
+}
+
+func (dao *GORMUserRoleDAO) FindByUid(ctx context.Context, uid int64) ([]UserRole, error) {
+	var res []UserRole
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMUserRoleDAO) Insert(ctx context.Context, r UserRole) error {
+	r.Ctime = time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&r).Error
+}
+
+func (dao *GORMUserRoleDAO) Delete(ctx context.Context, uid int64, role string) error {
+	return dao.db.WithContext(ctx).Where("uid = ? AND role = ?", uid, role).Delete(&UserRole{}).Error
+}
+
+type UserRole struct {
+	Id   int64  `gorm:"primaryKey,autoIncrement"`
+	Uid  int64  `gorm:"uniqueIndex:uid_role"`
+	Role string `gorm:"type:varchar(32);uniqueIndex:uid_role"`
+	// 哪个管理员授予的
+	Operator int64
+	Ctime    int64
+}
@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type WithdrawalDAO interface {
+	// Insert 创建提现申请，同时记一条审计日志
+	Insert(ctx context.Context, w Withdrawal, log WithdrawalLog) (int64, error)
+	FindById(ctx context.Context, id int64) (Withdrawal, error)
+	FindByUid(ctx context.Context, uid int64, offset, limit int) ([]Withdrawal, error)
+	// FindByStatus 先申请的先处理
+	FindByStatus(ctx context.Context, status uint8, offset, limit int) ([]Withdrawal, error)
+	// UpdateStatus 只有处于 log.From 状态的才会被更新，更新成功的同时记一条审计日志，
+	// 返回是否更新成功
+	UpdateStatus(ctx context.Context, id int64, txnID string, log WithdrawalLog) (bool, error)
+	FindLogs(ctx context.Context, wid int64) ([]WithdrawalLog, error)
+}
+
+type GORMWithdrawalDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMWithdrawalDAO(db *gorm.DB) WithdrawalDAO {
+	return &GORMWithdrawalDAO{db: db}
+}
+
+func (dao *GORMWithdrawalDAO) Insert(ctx context.Context, w Withdrawal, log WithdrawalLog) (int64, error) {
+	now := time.Now().UnixMilli()
+	w.Ctime = now
+	w.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&w).Error
+		if err != nil {
+			return err
+		}
+		log.Wid = w.Id
+		log.Ctime = now
+		return tx.Create(&log).Error
+	})
+	return w.Id, err
+}
+
+func (dao *GORMWithdrawalDAO) FindById(ctx context.Context, id int64) (Withdrawal, error) {
+	var res Withdrawal
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMWithdrawalDAO) FindByUid(ctx context.Context, uid int64, offset, limit int) ([]Withdrawal, error) {
+	var res []Withdrawal
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMWithdrawalDAO) FindByStatus(ctx context.Context, status uint8, offset, limit int) ([]Withdrawal, error) {
+	var res []Withdrawal
+	err := dao.db.WithContext(ctx).Where("status = ?", status).
+		Order("id ASC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMWithdrawalDAO) UpdateStatus(ctx context.Context, id int64, txnID string, log WithdrawalLog) (bool, error) {
+	now := time.Now().UnixMilli()
+	var ok bool
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]any{
+			"status": log.To,
+			"utime":  now,
+		}
+		if txnID != "" {
+			updates["txn_id"] = txnID
+		}
+		res := tx.Model(&Withdrawal{}).
+			Where("id = ? AND status = ?", id, log.From).
+			Updates(updates)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		ok = true
+		log.Wid = id
+		log.Ctime = now
+		return tx.Create(&log).Error
+	})
+	return ok && err == nil, err
+}
+
+func (dao *GORMWithdrawalDAO) FindLogs(ctx context.Context, wid int64) ([]WithdrawalLog, error) {
+	var res []WithdrawalLog
+	err := dao.db.WithContext(ctx).Where("wid = ?", wid).Order("id ASC").Find(&res).Error
+	return res, err
+}
+
+type Withdrawal struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	Uid      int64 `gorm:"index"`
+	Amt      int64
+	Fee      int64
+	Currency string `gorm:"type:varchar(16)"`
+	Status   uint8  `gorm:"index"`
+	TxnID    string `gorm:"column:txn_id;type:varchar(128)"`
+	Ctime    int64
+	Utime    int64
+}
+
+// WithdrawalLog 审计日志，只增不改
+type WithdrawalLog struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	Wid      int64 `gorm:"index"`
+	Operator int64
+	From     uint8  `gorm:"column:from_status"`
+	To       uint8  `gorm:"column:to_status"`
+	Remark   string `gorm:"type:varchar(1024)"`
+	Ctime    int64
+}
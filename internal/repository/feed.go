@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+	"webook/pkg/logger"
+)
+
+type FeedRepository interface {
+	// PushItem 推模型，写到这些粉丝的收件箱
+	PushItem(ctx context.Context, uids []int64, item domain.FeedItem) error
+	// PullItem 拉模型，写到作者的发件箱
+	PullItem(ctx context.Context, item domain.FeedItem) error
+	// FindPushItems 收件箱里面排在 cursor 之后的，按照 (Ctime, Aid) 倒序
+	FindPushItems(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error)
+	// FindPullItems 这些作者发件箱里面排在 cursor 之后的，按照 (Ctime, Aid) 倒序
+	FindPullItems(ctx context.Context, uids []int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error)
+	DeleteByAid(ctx context.Context, aid int64) error
+}
+
+type CachedFeedRepository struct {
+	dao   dao.FeedDAO
+	cache cache.FeedCache
+	l     logger.LoggerV1
+}
+
+func NewCachedFeedRepository(dao dao.FeedDAO, cache cache.FeedCache, l logger.LoggerV1) FeedRepository {
+	return &CachedFeedRepository{
+		dao:   dao,
+		cache: cache,
+		l:     l,
+	}
+}
+
+func (c *CachedFeedRepository) PushItem(ctx context.Context, uids []int64, item domain.FeedItem) error {
+	events := slice.Map[int64, dao.FeedPushEvent](uids, func(idx int, uid int64) dao.FeedPushEvent {
+		return dao.FeedPushEvent{
+			Uid:      uid,
+			Aid:      item.Aid,
+			AuthorId: item.Uid,
+			Ctime:    item.Ctime.UnixMilli(),
+		}
+	})
+	err := c.dao.CreatePushEvents(ctx, events)
+	if err != nil {
+		return err
+	}
+	err = c.cache.AddInbox(ctx, uids, item)
+	if err != nil {
+		// 缓存没写进去，读的时候会退回到数据库
+		c.l.Error("写入收件箱缓存失败",
+			logger.Int64("aid", item.Aid),
+			logger.Error(err))
+	}
+	return nil
+}
+
+func (c *CachedFeedRepository) PullItem(ctx context.Context, item domain.FeedItem) error {
+	return c.dao.CreatePullEvent(ctx, dao.FeedPullEvent{
+		Uid:   item.Uid,
+		Aid:   item.Aid,
+		Ctime: item.Ctime.UnixMilli(),
+	})
+}
+
+func (c *CachedFeedRepository) FindPushItems(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	items, err := c.cache.GetInbox(ctx, uid, cursor, limit)
+	// 缓存只有最新的一部分，不够一页说明可能翻到了缓存之外
+	if err == nil && len(items) >= limit {
+		return items, nil
+	}
+	if err != nil {
+		c.l.Error("读取收件箱缓存失败",
+			logger.Int64("uid", uid),
+			logger.Error(err))
+	}
+	events, err := c.dao.FindPushEvents(ctx, uid, cursor.Ctime, cursor.Aid, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.FeedPushEvent, domain.FeedItem](events, func(idx int, src dao.FeedPushEvent) domain.FeedItem {
+		return domain.FeedItem{
+			Aid:   src.Aid,
+			Uid:   src.AuthorId,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (c *CachedFeedRepository) FindPullItems(ctx context.Context, uids []int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	events, err := c.dao.FindPullEvents(ctx, uids, cursor.Ctime, cursor.Aid, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.FeedPullEvent, domain.FeedItem](events, func(idx int, src dao.FeedPullEvent) domain.FeedItem {
+		return domain.FeedItem{
+			Aid:   src.Aid,
+			Uid:   src.Uid,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+// DeleteByAid 缓存里面的不删，读的时候查不到上线的文章自然会过滤掉
+func (c *CachedFeedRepository) DeleteByAid(ctx context.Context, aid int64) error {
+	return c.dao.DeleteByAid(ctx, aid)
+}
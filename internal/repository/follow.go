@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+	"webook/pkg/logger"
+)
+
+var ErrFollowRelationNotFound = dao.ErrRecordNotFound
+
+type FollowRepository interface {
+	// GetFollowee 关注列表
+	GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error)
+	// GetFollower 粉丝列表
+	GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error)
+	FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error)
+	AddFollowRelation(ctx context.Context, r domain.FollowRelation) error
+	InactiveFollowRelation(ctx context.Context, follower, followee int64) error
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type CachedFollowRepository struct {
+	dao   dao.FollowRelationDAO
+	cache cache.FollowCache
+	l     logger.LoggerV1
+}
+
+func NewCachedFollowRepository(dao dao.FollowRelationDAO, cache cache.FollowCache, l logger.LoggerV1) FollowRepository {
+	return &CachedFollowRepository{
+		dao:   dao,
+		cache: cache,
+		l:     l,
+	}
+}
+
+func (c *CachedFollowRepository) GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error) {
+	rs, err := c.dao.FollowRelationList(ctx, follower, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.toDomains(rs), nil
+}
+
+func (c *CachedFollowRepository) GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error) {
+	rs, err := c.dao.FollowerRelationList(ctx, followee, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.toDomains(rs), nil
+}
+
+func (c *CachedFollowRepository) FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error) {
+	r, err := c.dao.FollowRelationDetail(ctx, follower, followee)
+	if err != nil {
+		return domain.FollowRelation{}, err
+	}
+	return c.toDomain(r), nil
+}
+
+func (c *CachedFollowRepository) AddFollowRelation(ctx context.Context, r domain.FollowRelation) error {
+	err := c.dao.CreateFollowRelation(ctx, dao.FollowRelation{
+		Follower: r.Follower,
+		Followee: r.Followee,
+	})
+	if err != nil {
+		return err
+	}
+	return c.cache.Follow(ctx, r.Follower, r.Followee)
+}
+
+func (c *CachedFollowRepository) InactiveFollowRelation(ctx context.Context, follower, followee int64) error {
+	err := c.dao.UpdateStatus(ctx, followee, follower, dao.FollowRelationStatusInactive)
+	if err != nil {
+		return err
+	}
+	return c.cache.CancelFollow(ctx, follower, followee)
+}
+
+func (c *CachedFollowRepository) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	res, err := c.cache.StaticsInfo(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+	s, err := c.dao.GetStatics(ctx, uid)
+	switch err {
+	case nil:
+		res = domain.FollowStatics{
+			Followers: s.Followers,
+			Followees: s.Followees,
+		}
+	case dao.ErrRecordNotFound:
+		// 没有关注过别人，也没有被关注过
+		res = domain.FollowStatics{}
+	default:
+		return domain.FollowStatics{}, err
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		er := c.cache.SetStaticsInfo(ctx, uid, res)
+		if er != nil {
+			c.l.Error("回写关注计数缓存失败", logger.Int64("uid", uid), logger.Error(er))
+		}
+	}()
+	return res, nil
+}
+
+func (c *CachedFollowRepository) toDomains(rs []dao.FollowRelation) []domain.FollowRelation {
+	return slice.Map[dao.FollowRelation, domain.FollowRelation](rs, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return c.toDomain(src)
+	})
+}
+
+func (c *CachedFollowRepository) toDomain(r dao.FollowRelation) domain.FollowRelation {
+	return domain.FollowRelation{
+		Followee: r.Followee,
+		Follower: r.Follower,
+	}
+}
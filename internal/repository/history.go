@@ -2,10 +2,52 @@ package repository
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
 	"webook/internal/domain"
+	"webook/internal/repository/dao"
 )
 
 // HistoryRecordRepository 阅读历史
 type HistoryRecordRepository interface {
 	AddRecord(ctx context.Context, record domain.HistoryRecord) error
+	// FindByUid 某个用户在 start 之后的阅读记录，最近的在前面
+	FindByUid(ctx context.Context, uid int64, start time.Time, limit int) ([]domain.HistoryRecord, error)
+	// FindActiveUids 在 start 之后有过阅读行为的用户
+	FindActiveUids(ctx context.Context, start time.Time, offset int, limit int) ([]int64, error)
+}
+
+type GORMHistoryRecordRepository struct {
+	dao dao.HistoryRecordDAO
+}
+
+func NewGORMHistoryRecordRepository(dao dao.HistoryRecordDAO) HistoryRecordRepository {
+	return &GORMHistoryRecordRepository{dao: dao}
+}
+
+func (g *GORMHistoryRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	return g.dao.Upsert(ctx, dao.ReadHistory{
+		Uid:   record.Uid,
+		Biz:   record.Biz,
+		BizId: record.BizId,
+	})
+}
+
+func (g *GORMHistoryRecordRepository) FindByUid(ctx context.Context, uid int64, start time.Time, limit int) ([]domain.HistoryRecord, error) {
+	rs, err := g.dao.FindByUid(ctx, uid, start, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ReadHistory, domain.HistoryRecord](rs, func(idx int, src dao.ReadHistory) domain.HistoryRecord {
+		return domain.HistoryRecord{
+			Uid:   src.Uid,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Utime: time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (g *GORMHistoryRecordRepository) FindActiveUids(ctx context.Context, start time.Time, offset int, limit int) ([]int64, error) {
+	return g.dao.FindActiveUids(ctx, start, offset, limit)
 }
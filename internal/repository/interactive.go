@@ -21,6 +21,10 @@ type InteractiveRepository interface {
 	Liked(ctx context.Context, biz string, bizId int64, uid int64) (bool, error)
 	Collected(ctx context.Context, biz string, bizId int64, uid int64) (bool, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]domain.Interactive, error)
+	LikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
+	CollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
+	// SumArticlesByAuthor 作者所有已经发表的文章加起来的互动数据
+	SumArticlesByAuthor(ctx context.Context, authorId int64) (domain.Interactive, error)
 }
 
 type CachedInteractiveRepository struct {
@@ -39,6 +43,22 @@ func (c *CachedInteractiveRepository) GetByIds(ctx context.Context, biz string,
 	}), nil
 }
 
+func (c *CachedInteractiveRepository) LikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	return c.dao.GetLikedBizIds(ctx, biz, uid, limit)
+}
+
+func (c *CachedInteractiveRepository) CollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	return c.dao.GetCollectedBizIds(ctx, biz, uid, limit)
+}
+
+func (c *CachedInteractiveRepository) SumArticlesByAuthor(ctx context.Context, authorId int64) (domain.Interactive, error) {
+	ie, err := c.dao.SumArticlesByAuthor(ctx, authorId)
+	if err != nil {
+		return domain.Interactive{}, err
+	}
+	return c.toDomain(ie), nil
+}
+
 func (c *CachedInteractiveRepository) Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error) {
 	intr, err := c.cache.Get(ctx, biz, bizId)
 	if err == nil {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/slice"
 	"time"
 	"webook/internal/domain"
 	"webook/internal/repository/dao"
@@ -12,6 +13,7 @@ type CronJobRepository interface {
 	Release(ctx context.Context, jid int64) error
 	UpdateUtime(ctx context.Context, id int64) error
 	UpdateNextTime(ctx context.Context, id int64, time time.Time) error
+	List(ctx context.Context, offset, limit int) ([]domain.Job, error)
 }
 
 type PreemptJobRepository struct {
@@ -44,3 +46,22 @@ func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64) error
 func (p *PreemptJobRepository) UpdateNextTime(ctx context.Context, id int64, time time.Time) error {
 	return p.dao.UpdateNextTime(ctx, id, time)
 }
+
+func (p *PreemptJobRepository) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	jobs, err := p.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(jobs, func(idx int, src dao.Job) domain.Job {
+		return domain.Job{
+			Id:           src.Id,
+			Name:         src.Name,
+			Expression:   src.Expression,
+			Executor:     src.Executor,
+			Cfg:          src.Cfg,
+			Status:       domain.JobStatus(src.Status),
+			NextExecTime: time.UnixMilli(src.NextTime),
+			Utime:        time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
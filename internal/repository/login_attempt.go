@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, account, ip string) (domain.LoginAttempts, error)
+	IncrFail(ctx context.Context, account, ip string) (domain.LoginAttempts, error)
+	Lock(ctx context.Context, account string, duration time.Duration) error
+	Clear(ctx context.Context, account string) error
+}
+
+type CachedLoginAttemptRepository struct {
+	cache cache.LoginAttemptCache
+}
+
+func NewCachedLoginAttemptRepository(c cache.LoginAttemptCache) LoginAttemptRepository {
+	return &CachedLoginAttemptRepository{cache: c}
+}
+
+func (repo *CachedLoginAttemptRepository) Get(ctx context.Context, account, ip string) (domain.LoginAttempts, error) {
+	return repo.cache.Get(ctx, account, ip)
+}
+
+func (repo *CachedLoginAttemptRepository) IncrFail(ctx context.Context, account, ip string) (domain.LoginAttempts, error) {
+	return repo.cache.Incr(ctx, account, ip)
+}
+
+func (repo *CachedLoginAttemptRepository) Lock(ctx context.Context, account string, duration time.Duration) error {
+	return repo.cache.Lock(ctx, account, duration)
+}
+
+func (repo *CachedLoginAttemptRepository) Clear(ctx context.Context, account string) error {
+	return repo.cache.Clear(ctx, account)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/account.go -package=repomocks -destination=./internal/repository/mocks/account.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountRepository is a mock of AccountRepository interface.
+type MockAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountRepositoryMockRecorder is the mock recorder for MockAccountRepository.
+type MockAccountRepositoryMockRecorder struct {
+	mock *MockAccountRepository
+}
+
+// NewMockAccountRepository creates a new mock instance.
+func NewMockAccountRepository(ctrl *gomock.Controller) *MockAccountRepository {
+	mock := &MockAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddActivities mocks base method.
+func (m *MockAccountRepository) AddActivities(ctx context.Context, biz string, bizId int64, acts []domain.AccountActivity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActivities", ctx, biz, bizId, acts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddActivities indicates an expected call of AddActivities.
+func (mr *MockAccountRepositoryMockRecorder) AddActivities(ctx, biz, bizId, acts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActivities", reflect.TypeOf((*MockAccountRepository)(nil).AddActivities), ctx, biz, bizId, acts)
+}
+
+// FindAccounts mocks base method.
+func (m *MockAccountRepository) FindAccounts(ctx context.Context, uid int64) ([]domain.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccounts", ctx, uid)
+	ret0, _ := ret[0].([]domain.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAccounts indicates an expected call of FindAccounts.
+func (mr *MockAccountRepositoryMockRecorder) FindAccounts(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccounts", reflect.TypeOf((*MockAccountRepository)(nil).FindAccounts), ctx, uid)
+}
+
+// FindActivities mocks base method.
+func (m *MockAccountRepository) FindActivities(ctx context.Context, uid int64, offset, limit int) ([]domain.AccountActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActivities", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.AccountActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActivities indicates an expected call of FindActivities.
+func (mr *MockAccountRepositoryMockRecorder) FindActivities(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActivities", reflect.TypeOf((*MockAccountRepository)(nil).FindActivities), ctx, uid, offset, limit)
+}
+
+// SumAmt mocks base method.
+func (m *MockAccountRepository) SumAmt(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumAmt", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumAmt indicates an expected call of SumAmt.
+func (mr *MockAccountRepositoryMockRecorder) SumAmt(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumAmt", reflect.TypeOf((*MockAccountRepository)(nil).SumAmt), ctx)
+}
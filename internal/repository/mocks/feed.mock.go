@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/feed.go -package=repomocks -destination=./internal/repository/mocks/feed.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedRepository is a mock of FeedRepository interface.
+type MockFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedRepositoryMockRecorder is the mock recorder for MockFeedRepository.
+type MockFeedRepositoryMockRecorder struct {
+	mock *MockFeedRepository
+}
+
+// NewMockFeedRepository creates a new mock instance.
+func NewMockFeedRepository(ctrl *gomock.Controller) *MockFeedRepository {
+	mock := &MockFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByAid mocks base method.
+func (m *MockFeedRepository) DeleteByAid(ctx context.Context, aid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAid", ctx, aid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAid indicates an expected call of DeleteByAid.
+func (mr *MockFeedRepositoryMockRecorder) DeleteByAid(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAid", reflect.TypeOf((*MockFeedRepository)(nil).DeleteByAid), ctx, aid)
+}
+
+// FindPullItems mocks base method.
+func (m *MockFeedRepository) FindPullItems(ctx context.Context, uids []int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPullItems", ctx, uids, cursor, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPullItems indicates an expected call of FindPullItems.
+func (mr *MockFeedRepositoryMockRecorder) FindPullItems(ctx, uids, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPullItems", reflect.TypeOf((*MockFeedRepository)(nil).FindPullItems), ctx, uids, cursor, limit)
+}
+
+// FindPushItems mocks base method.
+func (m *MockFeedRepository) FindPushItems(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPushItems", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPushItems indicates an expected call of FindPushItems.
+func (mr *MockFeedRepositoryMockRecorder) FindPushItems(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPushItems", reflect.TypeOf((*MockFeedRepository)(nil).FindPushItems), ctx, uid, cursor, limit)
+}
+
+// PullItem mocks base method.
+func (m *MockFeedRepository) PullItem(ctx context.Context, item domain.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PullItem indicates an expected call of PullItem.
+func (mr *MockFeedRepositoryMockRecorder) PullItem(ctx, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullItem", reflect.TypeOf((*MockFeedRepository)(nil).PullItem), ctx, item)
+}
+
+// PushItem mocks base method.
+func (m *MockFeedRepository) PushItem(ctx context.Context, uids []int64, item domain.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushItem", ctx, uids, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushItem indicates an expected call of PushItem.
+func (mr *MockFeedRepositoryMockRecorder) PushItem(ctx, uids, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushItem", reflect.TypeOf((*MockFeedRepository)(nil).PushItem), ctx, uids, item)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/history.go -package=repomocks -destination=./internal/repository/mocks/history.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecordRepository is a mock of HistoryRecordRepository interface.
+type MockHistoryRecordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecordRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHistoryRecordRepositoryMockRecorder is the mock recorder for MockHistoryRecordRepository.
+type MockHistoryRecordRepositoryMockRecorder struct {
+	mock *MockHistoryRecordRepository
+}
+
+// NewMockHistoryRecordRepository creates a new mock instance.
+func NewMockHistoryRecordRepository(ctrl *gomock.Controller) *MockHistoryRecordRepository {
+	mock := &MockHistoryRecordRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecordRepository) EXPECT() *MockHistoryRecordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRecord mocks base method.
+func (m *MockHistoryRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRecord indicates an expected call of AddRecord.
+func (mr *MockHistoryRecordRepositoryMockRecorder) AddRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockHistoryRecordRepository)(nil).AddRecord), ctx, record)
+}
+
+// FindActiveUids mocks base method.
+func (m *MockHistoryRecordRepository) FindActiveUids(ctx context.Context, start time.Time, offset, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveUids", ctx, start, offset, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveUids indicates an expected call of FindActiveUids.
+func (mr *MockHistoryRecordRepositoryMockRecorder) FindActiveUids(ctx, start, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveUids", reflect.TypeOf((*MockHistoryRecordRepository)(nil).FindActiveUids), ctx, start, offset, limit)
+}
+
+// FindByUid mocks base method.
+func (m *MockHistoryRecordRepository) FindByUid(ctx context.Context, uid int64, start time.Time, limit int) ([]domain.HistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid, start, limit)
+	ret0, _ := ret[0].([]domain.HistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockHistoryRecordRepositoryMockRecorder) FindByUid(ctx, uid, start, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockHistoryRecordRepository)(nil).FindByUid), ctx, uid, start, limit)
+}
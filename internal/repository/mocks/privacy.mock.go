@@ -0,0 +1,228 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/privacy.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/privacy.go -package=repomocks -destination=./internal/repository/mocks/privacy.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExportRepository is a mock of DataExportRepository interface.
+type MockDataExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDataExportRepositoryMockRecorder is the mock recorder for MockDataExportRepository.
+type MockDataExportRepositoryMockRecorder struct {
+	mock *MockDataExportRepository
+}
+
+// NewMockDataExportRepository creates a new mock instance.
+func NewMockDataExportRepository(ctrl *gomock.Controller) *MockDataExportRepository {
+	mock := &MockDataExportRepository{ctrl: ctrl}
+	mock.recorder = &MockDataExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportRepository) EXPECT() *MockDataExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockDataExportRepository) Claim(ctx context.Context, id int64, staleBefore time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, id, staleBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockDataExportRepositoryMockRecorder) Claim(ctx, id, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockDataExportRepository)(nil).Claim), ctx, id, staleBefore)
+}
+
+// Create mocks base method.
+func (m *MockDataExportRepository) Create(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportRepositoryMockRecorder) Create(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExportRepository)(nil).Create), ctx, uid)
+}
+
+// FindDoneBefore mocks base method.
+func (m *MockDataExportRepository) FindDoneBefore(ctx context.Context, t time.Time, limit int) ([]domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDoneBefore", ctx, t, limit)
+	ret0, _ := ret[0].([]domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDoneBefore indicates an expected call of FindDoneBefore.
+func (mr *MockDataExportRepositoryMockRecorder) FindDoneBefore(ctx, t, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDoneBefore", reflect.TypeOf((*MockDataExportRepository)(nil).FindDoneBefore), ctx, t, limit)
+}
+
+// FindLatest mocks base method.
+func (m *MockDataExportRepository) FindLatest(ctx context.Context, uid int64) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatest", ctx, uid)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatest indicates an expected call of FindLatest.
+func (mr *MockDataExportRepositoryMockRecorder) FindLatest(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatest", reflect.TypeOf((*MockDataExportRepository)(nil).FindLatest), ctx, uid)
+}
+
+// FindRunnable mocks base method.
+func (m *MockDataExportRepository) FindRunnable(ctx context.Context, staleBefore time.Time, limit int) ([]domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRunnable", ctx, staleBefore, limit)
+	ret0, _ := ret[0].([]domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRunnable indicates an expected call of FindRunnable.
+func (mr *MockDataExportRepositoryMockRecorder) FindRunnable(ctx, staleBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRunnable", reflect.TypeOf((*MockDataExportRepository)(nil).FindRunnable), ctx, staleBefore, limit)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockDataExportRepository) UpdateStatus(ctx context.Context, id int64, status domain.DataExportStatus, fileKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status, fileKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockDataExportRepositoryMockRecorder) UpdateStatus(ctx, id, status, fileKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockDataExportRepository)(nil).UpdateStatus), ctx, id, status, fileKey)
+}
+
+// MockAccountDeletionRepository is a mock of AccountDeletionRepository interface.
+type MockAccountDeletionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountDeletionRepositoryMockRecorder is the mock recorder for MockAccountDeletionRepository.
+type MockAccountDeletionRepositoryMockRecorder struct {
+	mock *MockAccountDeletionRepository
+}
+
+// NewMockAccountDeletionRepository creates a new mock instance.
+func NewMockAccountDeletionRepository(ctrl *gomock.Controller) *MockAccountDeletionRepository {
+	mock := &MockAccountDeletionRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionRepository) EXPECT() *MockAccountDeletionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockAccountDeletionRepository) Cancel(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Cancel(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Cancel), ctx, uid)
+}
+
+// Find mocks base method.
+func (m *MockAccountDeletionRepository) Find(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, uid)
+	ret0, _ := ret[0].(domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Find(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Find), ctx, uid)
+}
+
+// FindDue mocks base method.
+func (m *MockAccountDeletionRepository) FindDue(ctx context.Context, now time.Time, offset, limit int) ([]domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, now, offset, limit)
+	ret0, _ := ret[0].([]domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockAccountDeletionRepositoryMockRecorder) FindDue(ctx, now, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockAccountDeletionRepository)(nil).FindDue), ctx, now, offset, limit)
+}
+
+// MarkDone mocks base method.
+func (m *MockAccountDeletionRepository) MarkDone(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDone", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDone indicates an expected call of MarkDone.
+func (mr *MockAccountDeletionRepositoryMockRecorder) MarkDone(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDone", reflect.TypeOf((*MockAccountDeletionRepository)(nil).MarkDone), ctx, uid)
+}
+
+// Save mocks base method.
+func (m *MockAccountDeletionRepository) Save(ctx context.Context, d domain.AccountDeletion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Save(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Save), ctx, d)
+}
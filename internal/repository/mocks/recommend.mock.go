@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/recommend.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/recommend.go -package=repomocks -destination=./internal/repository/mocks/recommend.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecommendRepository is a mock of RecommendRepository interface.
+type MockRecommendRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRecommendRepositoryMockRecorder is the mock recorder for MockRecommendRepository.
+type MockRecommendRepositoryMockRecorder struct {
+	mock *MockRecommendRepository
+}
+
+// NewMockRecommendRepository creates a new mock instance.
+func NewMockRecommendRepository(ctrl *gomock.Controller) *MockRecommendRepository {
+	mock := &MockRecommendRepository{ctrl: ctrl}
+	mock.recorder = &MockRecommendRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendRepository) EXPECT() *MockRecommendRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetRecommend mocks base method.
+func (m *MockRecommendRepository) GetRecommend(ctx context.Context, uid int64, offset, limit int) ([]domain.RecommendItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommend", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.RecommendItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommend indicates an expected call of GetRecommend.
+func (mr *MockRecommendRepositoryMockRecorder) GetRecommend(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommend", reflect.TypeOf((*MockRecommendRepository)(nil).GetRecommend), ctx, uid, offset, limit)
+}
+
+// ReplaceRecommend mocks base method.
+func (m *MockRecommendRepository) ReplaceRecommend(ctx context.Context, uid int64, items []domain.RecommendItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecommend", ctx, uid, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecommend indicates an expected call of ReplaceRecommend.
+func (mr *MockRecommendRepositoryMockRecorder) ReplaceRecommend(ctx, uid, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecommend", reflect.TypeOf((*MockRecommendRepository)(nil).ReplaceRecommend), ctx, uid, items)
+}
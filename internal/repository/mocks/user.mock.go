@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./user.go
+// Source: ./internal/repository/user.go
 //
 // Generated by this command:
 //
-//	mockgen -source=./user.go -package=repomocks -destination=./mocks/user.mock.go UserRepository
+//	mockgen -source=./internal/repository/user.go -package=repomocks -destination=./internal/repository/mocks/user.mock.go
 //
 
 // Package repomocks is a generated GoMock package.
@@ -41,6 +41,34 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockUserRepository) Anonymize(ctx context.Context, uid int64, nickname string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, uid, nickname)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserRepositoryMockRecorder) Anonymize(ctx, uid, nickname any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserRepository)(nil).Anonymize), ctx, uid, nickname)
+}
+
+// BindIdentity mocks base method.
+func (m *MockUserRepository) BindIdentity(ctx context.Context, identity domain.OAuthIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindIdentity indicates an expected call of BindIdentity.
+func (mr *MockUserRepositoryMockRecorder) BindIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindIdentity", reflect.TypeOf((*MockUserRepository)(nil).BindIdentity), ctx, identity)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, user domain.User) error {
 	m.ctrl.T.Helper()
@@ -55,6 +83,20 @@ func (mr *MockUserRepositoryMockRecorder) Create(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
 }
 
+// CreateWithIdentity mocks base method.
+func (m *MockUserRepository) CreateWithIdentity(ctx context.Context, user domain.User, identity domain.OAuthIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithIdentity", ctx, user, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWithIdentity indicates an expected call of CreateWithIdentity.
+func (mr *MockUserRepositoryMockRecorder) CreateWithIdentity(ctx, user, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithIdentity", reflect.TypeOf((*MockUserRepository)(nil).CreateWithIdentity), ctx, user, identity)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +112,21 @@ func (mr *MockUserRepositoryMockRecorder) FindByEmail(ctx, email any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepository)(nil).FindByEmail), ctx, email)
 }
 
+// FindByHandle mocks base method.
+func (m *MockUserRepository) FindByHandle(ctx context.Context, handle string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHandle", ctx, handle)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHandle indicates an expected call of FindByHandle.
+func (mr *MockUserRepositoryMockRecorder) FindByHandle(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHandle", reflect.TypeOf((*MockUserRepository)(nil).FindByHandle), ctx, handle)
+}
+
 // FindById mocks base method.
 func (m *MockUserRepository) FindById(ctx context.Context, uid int64) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +142,21 @@ func (mr *MockUserRepositoryMockRecorder) FindById(ctx, uid any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserRepository)(nil).FindById), ctx, uid)
 }
 
+// FindByIdentity mocks base method.
+func (m *MockUserRepository) FindByIdentity(ctx context.Context, provider, subject string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIdentity indicates an expected call of FindByIdentity.
+func (mr *MockUserRepositoryMockRecorder) FindByIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIdentity", reflect.TypeOf((*MockUserRepository)(nil).FindByIdentity), ctx, provider, subject)
+}
+
 // FindByPhone mocks base method.
 func (m *MockUserRepository) FindByPhone(ctx context.Context, phone string) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -100,19 +172,118 @@ func (mr *MockUserRepositoryMockRecorder) FindByPhone(ctx, phone any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockUserRepository)(nil).FindByPhone), ctx, phone)
 }
 
-// FindByWechat mocks base method.
-func (m *MockUserRepository) FindByWechat(ctx context.Context, openId string) (domain.User, error) {
+// FindIdentities mocks base method.
+func (m *MockUserRepository) FindIdentities(ctx context.Context, uid int64) ([]domain.OAuthIdentity, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByWechat", ctx, openId)
-	ret0, _ := ret[0].(domain.User)
+	ret := m.ctrl.Call(m, "FindIdentities", ctx, uid)
+	ret0, _ := ret[0].([]domain.OAuthIdentity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindByWechat indicates an expected call of FindByWechat.
-func (mr *MockUserRepositoryMockRecorder) FindByWechat(ctx, openId any) *gomock.Call {
+// FindIdentities indicates an expected call of FindIdentities.
+func (mr *MockUserRepositoryMockRecorder) FindIdentities(ctx, uid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWechat", reflect.TypeOf((*MockUserRepository)(nil).FindByWechat), ctx, openId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentities", reflect.TypeOf((*MockUserRepository)(nil).FindIdentities), ctx, uid)
+}
+
+// List mocks base method.
+func (m *MockUserRepository) List(ctx context.Context, offset, limit int) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserRepositoryMockRecorder) List(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, offset, limit)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepositoryMockRecorder) MarkEmailVerified(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepository)(nil).MarkEmailVerified), ctx, uid)
+}
+
+// Merge mocks base method.
+func (m *MockUserRepository) Merge(ctx context.Context, primary, duplicate int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, primary, duplicate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserRepositoryMockRecorder) Merge(ctx, primary, duplicate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUserRepository)(nil).Merge), ctx, primary, duplicate)
+}
+
+// UnbindIdentity mocks base method.
+func (m *MockUserRepository) UnbindIdentity(ctx context.Context, uid int64, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbindIdentity", ctx, uid, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbindIdentity indicates an expected call of UnbindIdentity.
+func (mr *MockUserRepositoryMockRecorder) UnbindIdentity(ctx, uid, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbindIdentity", reflect.TypeOf((*MockUserRepository)(nil).UnbindIdentity), ctx, uid, provider)
+}
+
+// UpdateAvatar mocks base method.
+func (m *MockUserRepository) UpdateAvatar(ctx context.Context, uid int64, avatar string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvatar", ctx, uid, avatar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAvatar indicates an expected call of UpdateAvatar.
+func (mr *MockUserRepositoryMockRecorder) UpdateAvatar(ctx, uid, avatar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvatar", reflect.TypeOf((*MockUserRepository)(nil).UpdateAvatar), ctx, uid, avatar)
+}
+
+// UpdateEmail mocks base method.
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, uid int64, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, uid, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockUserRepositoryMockRecorder) UpdateEmail(ctx, uid, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserRepository)(nil).UpdateEmail), ctx, uid, email)
+}
+
+// UpdateHandle mocks base method.
+func (m *MockUserRepository) UpdateHandle(ctx context.Context, uid int64, handle string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHandle", ctx, uid, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHandle indicates an expected call of UpdateHandle.
+func (mr *MockUserRepositoryMockRecorder) UpdateHandle(ctx, uid, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHandle", reflect.TypeOf((*MockUserRepository)(nil).UpdateHandle), ctx, uid, handle)
 }
 
 // UpdateNonSensitiveInfo mocks base method.
@@ -128,3 +299,59 @@ func (mr *MockUserRepositoryMockRecorder) UpdateNonSensitiveInfo(ctx, user any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNonSensitiveInfo", reflect.TypeOf((*MockUserRepository)(nil).UpdateNonSensitiveInfo), ctx, user)
 }
+
+// UpdateNotificationMute mocks base method.
+func (m *MockUserRepository) UpdateNotificationMute(ctx context.Context, uid int64, mute domain.NotificationMute) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationMute", ctx, uid, mute)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationMute indicates an expected call of UpdateNotificationMute.
+func (mr *MockUserRepositoryMockRecorder) UpdateNotificationMute(ctx, uid, mute any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationMute", reflect.TypeOf((*MockUserRepository)(nil).UpdateNotificationMute), ctx, uid, mute)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, uid int64, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, uid, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, uid, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, uid, password)
+}
+
+// UpdatePhone mocks base method.
+func (m *MockUserRepository) UpdatePhone(ctx context.Context, uid int64, phone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhone", ctx, uid, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhone indicates an expected call of UpdatePhone.
+func (mr *MockUserRepositoryMockRecorder) UpdatePhone(ctx, uid, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhone", reflect.TypeOf((*MockUserRepository)(nil).UpdatePhone), ctx, uid, phone)
+}
+
+// UpdateProfilePrivacy mocks base method.
+func (m *MockUserRepository) UpdateProfilePrivacy(ctx context.Context, uid int64, privacy domain.ProfilePrivacy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfilePrivacy", ctx, uid, privacy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfilePrivacy indicates an expected call of UpdateProfilePrivacy.
+func (mr *MockUserRepositoryMockRecorder) UpdateProfilePrivacy(ctx, uid, privacy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfilePrivacy", reflect.TypeOf((*MockUserRepository)(nil).UpdateProfilePrivacy), ctx, uid, privacy)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/user_ban.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/user_ban.go -package=repomocks -destination=./internal/repository/mocks/user_ban.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserBanRepository is a mock of UserBanRepository interface.
+type MockUserBanRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserBanRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserBanRepositoryMockRecorder is the mock recorder for MockUserBanRepository.
+type MockUserBanRepositoryMockRecorder struct {
+	mock *MockUserBanRepository
+}
+
+// NewMockUserBanRepository creates a new mock instance.
+func NewMockUserBanRepository(ctrl *gomock.Controller) *MockUserBanRepository {
+	mock := &MockUserBanRepository{ctrl: ctrl}
+	mock.recorder = &MockUserBanRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserBanRepository) EXPECT() *MockUserBanRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockUserBanRepository) Delete(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserBanRepositoryMockRecorder) Delete(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserBanRepository)(nil).Delete), ctx, uid)
+}
+
+// Find mocks base method.
+func (m *MockUserBanRepository) Find(ctx context.Context, uid int64) (domain.UserBan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, uid)
+	ret0, _ := ret[0].(domain.UserBan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockUserBanRepositoryMockRecorder) Find(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockUserBanRepository)(nil).Find), ctx, uid)
+}
+
+// Save mocks base method.
+func (m *MockUserBanRepository) Save(ctx context.Context, b domain.UserBan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockUserBanRepositoryMockRecorder) Save(ctx, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserBanRepository)(nil).Save), ctx, b)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/user_role.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/user_role.go -package=repomocks -destination=./internal/repository/mocks/user_role.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserRoleRepository is a mock of UserRoleRepository interface.
+type MockUserRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRoleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserRoleRepositoryMockRecorder is the mock recorder for MockUserRoleRepository.
+type MockUserRoleRepositoryMockRecorder struct {
+	mock *MockUserRoleRepository
+}
+
+// NewMockUserRoleRepository creates a new mock instance.
+func NewMockUserRoleRepository(ctrl *gomock.Controller) *MockUserRoleRepository {
+	mock := &MockUserRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRoleRepository) EXPECT() *MockUserRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRole mocks base method.
+func (m *MockUserRoleRepository) AddRole(ctx context.Context, uid int64, role string, operator int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRole", ctx, uid, role, operator)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRole indicates an expected call of AddRole.
+func (mr *MockUserRoleRepositoryMockRecorder) AddRole(ctx, uid, role, operator any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRole", reflect.TypeOf((*MockUserRoleRepository)(nil).AddRole), ctx, uid, role, operator)
+}
+
+// FindRoles mocks base method.
+func (m *MockUserRoleRepository) FindRoles(ctx context.Context, uid int64) (domain.Roles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRoles", ctx, uid)
+	ret0, _ := ret[0].(domain.Roles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRoles indicates an expected call of FindRoles.
+func (mr *MockUserRoleRepositoryMockRecorder) FindRoles(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRoles", reflect.TypeOf((*MockUserRoleRepository)(nil).FindRoles), ctx, uid)
+}
+
+// RemoveRole mocks base method.
+func (m *MockUserRoleRepository) RemoveRole(ctx context.Context, uid int64, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRole", ctx, uid, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRole indicates an expected call of RemoveRole.
+func (mr *MockUserRoleRepositoryMockRecorder) RemoveRole(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRole", reflect.TypeOf((*MockUserRoleRepository)(nil).RemoveRole), ctx, uid, role)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/withdrawal.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/withdrawal.go -package=repomocks -destination=./internal/repository/mocks/withdrawal.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWithdrawalRepository is a mock of WithdrawalRepository interface.
+type MockWithdrawalRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWithdrawalRepositoryMockRecorder is the mock recorder for MockWithdrawalRepository.
+type MockWithdrawalRepositoryMockRecorder struct {
+	mock *MockWithdrawalRepository
+}
+
+// NewMockWithdrawalRepository creates a new mock instance.
+func NewMockWithdrawalRepository(ctrl *gomock.Controller) *MockWithdrawalRepository {
+	mock := &MockWithdrawalRepository{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalRepository) EXPECT() *MockWithdrawalRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWithdrawalRepository) Create(ctx context.Context, w domain.Withdrawal) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, w)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWithdrawalRepositoryMockRecorder) Create(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWithdrawalRepository)(nil).Create), ctx, w)
+}
+
+// FindById mocks base method.
+func (m *MockWithdrawalRepository) FindById(ctx context.Context, id int64) (domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockWithdrawalRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockWithdrawalRepository)(nil).FindById), ctx, id)
+}
+
+// FindLogs mocks base method.
+func (m *MockWithdrawalRepository) FindLogs(ctx context.Context, wid int64) ([]domain.WithdrawalLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLogs", ctx, wid)
+	ret0, _ := ret[0].([]domain.WithdrawalLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLogs indicates an expected call of FindLogs.
+func (mr *MockWithdrawalRepositoryMockRecorder) FindLogs(ctx, wid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLogs", reflect.TypeOf((*MockWithdrawalRepository)(nil).FindLogs), ctx, wid)
+}
+
+// ListByStatus mocks base method.
+func (m *MockWithdrawalRepository) ListByStatus(ctx context.Context, status domain.WithdrawalStatus, offset, limit int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, offset, limit)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockWithdrawalRepositoryMockRecorder) ListByStatus(ctx, status, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockWithdrawalRepository)(nil).ListByStatus), ctx, status, offset, limit)
+}
+
+// ListByUid mocks base method.
+func (m *MockWithdrawalRepository) ListByUid(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUid", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUid indicates an expected call of ListByUid.
+func (mr *MockWithdrawalRepositoryMockRecorder) ListByUid(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUid", reflect.TypeOf((*MockWithdrawalRepository)(nil).ListByUid), ctx, uid, offset, limit)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockWithdrawalRepository) UpdateStatus(ctx context.Context, w domain.Withdrawal, from domain.WithdrawalStatus, operator int64, remark string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, w, from, operator, remark)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockWithdrawalRepositoryMockRecorder) UpdateStatus(ctx, w, from, operator, remark any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockWithdrawalRepository)(nil).UpdateStatus), ctx, w, from, operator, remark)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrModerationTaskNotFound = dao.ErrRecordNotFound
+	// ErrModerationTaskReviewed 已经被别人审核过了
+	ErrModerationTaskReviewed = errors.New("审核任务已经处理过了")
+)
+
+type ModerationRepository interface {
+	Create(ctx context.Context, t domain.ModerationTask) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.ModerationTask, error)
+	ListByStatus(ctx context.Context, status domain.ModerationStatus, offset, limit int) ([]domain.ModerationTask, error)
+	// UpdateStatus 只能处理还在等待审核的任务
+	UpdateStatus(ctx context.Context, t domain.ModerationTask) error
+}
+
+type GORMModerationRepository struct {
+	dao dao.ModerationDAO
+}
+
+func NewGORMModerationRepository(dao dao.ModerationDAO) ModerationRepository {
+	return &GORMModerationRepository{dao: dao}
+}
+
+func (g *GORMModerationRepository) Create(ctx context.Context, t domain.ModerationTask) (int64, error) {
+	return g.dao.Insert(ctx, g.toEntity(t))
+}
+
+func (g *GORMModerationRepository) FindById(ctx context.Context, id int64) (domain.ModerationTask, error) {
+	t, err := g.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.ModerationTask{}, err
+	}
+	return g.toDomain(t), nil
+}
+
+func (g *GORMModerationRepository) ListByStatus(ctx context.Context, status domain.ModerationStatus, offset, limit int) ([]domain.ModerationTask, error) {
+	ts, err := g.dao.FindByStatus(ctx, status.ToUint8(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.ModerationTask, domain.ModerationTask](ts, func(idx int, src dao.ModerationTask) domain.ModerationTask {
+		return g.toDomain(src)
+	}), nil
+}
+
+func (g *GORMModerationRepository) UpdateStatus(ctx context.Context, t domain.ModerationTask) error {
+	ok, err := g.dao.UpdateStatus(ctx, t.Id, domain.ModerationStatusPending.ToUint8(),
+		t.Status.ToUint8(), t.Reviewer, t.Reason)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrModerationTaskReviewed
+	}
+	return nil
+}
+
+func (g *GORMModerationRepository) toDomain(src dao.ModerationTask) domain.ModerationTask {
+	var hits []string
+	if src.Hits != "" {
+		hits = strings.Split(src.Hits, ",")
+	}
+	return domain.ModerationTask{
+		Id:          src.Id,
+		Biz:         src.Biz,
+		BizId:       src.BizId,
+		Uid:         src.Uid,
+		Content:     src.Content,
+		ContentHash: src.ContentHash,
+		Hits:        hits,
+		Status:      domain.ModerationStatus(src.Status),
+		Reviewer:    src.Reviewer,
+		Reason:      src.Reason,
+		Ctime:       time.UnixMilli(src.Ctime),
+		Utime:       time.UnixMilli(src.Utime),
+	}
+}
+
+func (g *GORMModerationRepository) toEntity(src domain.ModerationTask) dao.ModerationTask {
+	return dao.ModerationTask{
+		Id:          src.Id,
+		Biz:         src.Biz,
+		BizId:       src.BizId,
+		Uid:         src.Uid,
+		Content:     src.Content,
+		ContentHash: src.ContentHash,
+		Hits:        strings.Join(src.Hits, ","),
+		Status:      src.Status.ToUint8(),
+		Reviewer:    src.Reviewer,
+		Reason:      src.Reason,
+	}
+}
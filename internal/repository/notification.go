@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/ecodeclub/ekit/slice"
+	"log"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+)
+
+var ErrNotificationNotFound = dao.ErrRecordNotFound
+
+type NotificationRepository interface {
+	// AddNotification 合并到还没读的同类通知里面，actor 是这次的触发者
+	AddNotification(ctx context.Context, n domain.Notification, actor int64) error
+	List(ctx context.Context, uid int64, offset, limit int) ([]domain.Notification, error)
+	MarkRead(ctx context.Context, uid, id int64) error
+	MarkAllRead(ctx context.Context, uid int64) error
+	UnreadCnt(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error)
+}
+
+type CachedNotificationRepository struct {
+	dao   dao.NotificationDAO
+	cache cache.NotificationCache
+}
+
+func NewCachedNotificationRepository(dao dao.NotificationDAO, cache cache.NotificationCache) NotificationRepository {
+	return &CachedNotificationRepository{dao: dao, cache: cache}
+}
+
+func (c *CachedNotificationRepository) AddNotification(ctx context.Context, n domain.Notification, actor int64) error {
+	created, err := c.dao.Upsert(ctx, c.toEntity(n), actor)
+	// 合并进已有的未读通知的时候，未读数不变
+	if err != nil || !created {
+		return err
+	}
+	err = c.cache.IncrUnreadIfPresent(ctx, n.Uid, n.Type, 1)
+	if err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func (c *CachedNotificationRepository) List(ctx context.Context, uid int64, offset, limit int) ([]domain.Notification, error) {
+	ns, err := c.dao.FindByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Notification, domain.Notification](ns, func(idx int, src dao.Notification) domain.Notification {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedNotificationRepository) MarkRead(ctx context.Context, uid, id int64) error {
+	n, err := c.dao.MarkRead(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	err = c.cache.IncrUnreadIfPresent(ctx, uid, domain.NotificationType(n.Type), -1)
+	if err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func (c *CachedNotificationRepository) MarkAllRead(ctx context.Context, uid int64) error {
+	err := c.dao.MarkAllRead(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// SetUnread 不会清掉已有的按类型的计数，只能删掉等下次查询的时候重新加载
+	return c.cache.DelUnread(ctx, uid)
+}
+
+func (c *CachedNotificationRepository) UnreadCnt(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error) {
+	res, err := c.cache.GetUnread(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+	cnts, err := c.dao.CountUnread(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	res = make(map[domain.NotificationType]int64, len(cnts))
+	for typ, cnt := range cnts {
+		res[domain.NotificationType(typ)] = cnt
+	}
+	err = c.cache.SetUnread(ctx, uid, res)
+	if err != nil {
+		log.Println(err)
+	}
+	return res, nil
+}
+
+func (c *CachedNotificationRepository) toEntity(n domain.Notification) dao.Notification {
+	return dao.Notification{
+		Uid: n.Uid,
+		AggKey: sql.NullString{
+			String: fmt.Sprintf("%d:%s:%d", n.Type, n.Biz, n.BizId),
+			Valid:  true,
+		},
+		Type:    n.Type.ToUint8(),
+		Biz:     n.Biz,
+		BizId:   n.BizId,
+		BizName: n.BizName,
+		Content: n.Content,
+	}
+}
+
+func (c *CachedNotificationRepository) toDomain(n dao.Notification) domain.Notification {
+	var actors []int64
+	_ = json.Unmarshal([]byte(n.Actors), &actors)
+	return domain.Notification{
+		Id:       n.Id,
+		Uid:      n.Uid,
+		Type:     domain.NotificationType(n.Type),
+		Biz:      n.Biz,
+		BizId:    n.BizId,
+		BizName:  n.BizName,
+		Actors:   actors,
+		ActorCnt: n.ActorCnt,
+		Content:  n.Content,
+		Read:     n.Read,
+		Ctime:    time.UnixMilli(n.Ctime),
+		Utime:    time.UnixMilli(n.Utime),
+	}
+}
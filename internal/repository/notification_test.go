@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	cachemocks "webook/internal/repository/cache/mocks"
+	"webook/internal/repository/dao"
+	daomocks "webook/internal/repository/dao/mocks"
+)
+
+func TestCachedNotificationRepository_MarkAllRead(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.NotificationDAO, cache.NotificationCache)
+
+		wantErr error
+		// 全部已读之后再查一次未读数
+		wantCnts map[domain.NotificationType]int64
+	}{
+		{
+			name: "已经缓存了未读数，全部已读之后重新加载",
+			mock: func(ctrl *gomock.Controller) (dao.NotificationDAO, cache.NotificationCache) {
+				d := daomocks.NewMockNotificationDAO(ctrl)
+				c := cachemocks.NewMockNotificationCache(ctrl)
+				gomock.InOrder(
+					d.EXPECT().MarkAllRead(gomock.Any(), int64(123)).Return(nil),
+					// 不能用 SetUnread，缓存里面原本的 "1"=5 还会留着
+					c.EXPECT().DelUnread(gomock.Any(), int64(123)).Return(nil),
+					c.EXPECT().GetUnread(gomock.Any(), int64(123)).Return(nil, cache.ErrKeyNotExist),
+					d.EXPECT().CountUnread(gomock.Any(), int64(123)).Return(map[uint8]int64{}, nil),
+					c.EXPECT().SetUnread(gomock.Any(), int64(123), map[domain.NotificationType]int64{}).Return(nil),
+				)
+				return d, c
+			},
+			wantCnts: map[domain.NotificationType]int64{},
+		},
+		{
+			name: "数据库失败，不动缓存",
+			mock: func(ctrl *gomock.Controller) (dao.NotificationDAO, cache.NotificationCache) {
+				d := daomocks.NewMockNotificationDAO(ctrl)
+				c := cachemocks.NewMockNotificationCache(ctrl)
+				d.EXPECT().MarkAllRead(gomock.Any(), int64(123)).Return(errors.New("db错误"))
+				return d, c
+			},
+			wantErr: errors.New("db错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewCachedNotificationRepository(tc.mock(ctrl))
+			err := repo.MarkAllRead(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			cnts, err := repo.UnreadCnt(context.Background(), 123)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantCnts, cnts)
+		})
+	}
+}
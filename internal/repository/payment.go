@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrPaymentNotFound  = dao.ErrRecordNotFound
+	ErrDuplicatePayment = dao.ErrDuplicatePayment
+)
+
+type PaymentRepository interface {
+	AddPayment(ctx context.Context, pmt domain.Payment) error
+	GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error)
+	// UpdatePayment 只有当前状态是 from 的时候才更新，返回是否真的更新了
+	UpdatePayment(ctx context.Context, pmt domain.Payment, from domain.PaymentStatus) (bool, error)
+	// FindExpiredPayment 按照 id 升序翻页，minId 是上一批最大的 id
+	FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]domain.Payment, error)
+}
+
+type GORMPaymentRepository struct {
+	dao dao.PaymentDAO
+}
+
+func NewGORMPaymentRepository(dao dao.PaymentDAO) PaymentRepository {
+	return &GORMPaymentRepository{dao: dao}
+}
+
+func (g *GORMPaymentRepository) AddPayment(ctx context.Context, pmt domain.Payment) error {
+	return g.dao.Insert(ctx, g.toEntity(pmt))
+}
+
+func (g *GORMPaymentRepository) GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	pmt, err := g.dao.GetPayment(ctx, bizTradeNO)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	return g.toDomain(pmt), nil
+}
+
+func (g *GORMPaymentRepository) UpdatePayment(ctx context.Context, pmt domain.Payment, from domain.PaymentStatus) (bool, error) {
+	return g.dao.UpdateTxnIDAndStatus(ctx, pmt.BizTradeNO, pmt.TxnID, from.ToUint8(), pmt.Status.ToUint8())
+}
+
+func (g *GORMPaymentRepository) FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]domain.Payment, error) {
+	pmts, err := g.dao.FindExpiredPayment(ctx, minId, limit, t)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Payment, domain.Payment](pmts, func(idx int, src dao.Payment) domain.Payment {
+		return g.toDomain(src)
+	}), nil
+}
+
+func (g *GORMPaymentRepository) toEntity(pmt domain.Payment) dao.Payment {
+	return dao.Payment{
+		Amt:         pmt.Amt.Total,
+		Currency:    pmt.Amt.Currency,
+		Description: pmt.Description,
+		BizTradeNO:  pmt.BizTradeNO,
+		TxnID: sql.NullString{
+			String: pmt.TxnID,
+			Valid:  pmt.TxnID != "",
+		},
+		Status: pmt.Status.ToUint8(),
+	}
+}
+
+func (g *GORMPaymentRepository) toDomain(pmt dao.Payment) domain.Payment {
+	return domain.Payment{
+		Id: pmt.Id,
+		Amt: domain.Amount{
+			Currency: pmt.Currency,
+			Total:    pmt.Amt,
+		},
+		BizTradeNO:  pmt.BizTradeNO,
+		Description: pmt.Description,
+		Status:      domain.PaymentStatus(pmt.Status),
+		TxnID:       pmt.TxnID.String,
+		Utime:       time.UnixMilli(pmt.Utime),
+	}
+}
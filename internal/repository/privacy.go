@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrDataExportNotFound      = dao.ErrRecordNotFound
+	ErrAccountDeletionNotFound = dao.ErrRecordNotFound
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, uid int64) (int64, error)
+	// FindLatest 没有导出过返回 ErrDataExportNotFound
+	FindLatest(ctx context.Context, uid int64) (domain.DataExport, error)
+	// FindRunnable 等着打包的，包括 staleBefore 之前开始打包之后就没了下文的
+	FindRunnable(ctx context.Context, staleBefore time.Time, limit int) ([]domain.DataExport, error)
+	Claim(ctx context.Context, id int64, staleBefore time.Time) (bool, error)
+	UpdateStatus(ctx context.Context, id int64, status domain.DataExportStatus, fileKey string) error
+	FindDoneBefore(ctx context.Context, t time.Time, limit int) ([]domain.DataExport, error)
+}
+
+type GORMDataExportRepository struct {
+	dao dao.DataExportDAO
+}
+
+func NewGORMDataExportRepository(dao dao.DataExportDAO) DataExportRepository {
+	return &GORMDataExportRepository{dao: dao}
+}
+
+func (repo *GORMDataExportRepository) Create(ctx context.Context, uid int64) (int64, error) {
+	return repo.dao.Insert(ctx, dao.DataExport{
+		Uid:    uid,
+		Status: domain.DataExportStatusPending.ToUint8(),
+	})
+}
+
+func (repo *GORMDataExportRepository) FindLatest(ctx context.Context, uid int64) (domain.DataExport, error) {
+	e, err := repo.dao.FindLatest(ctx, uid)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	return repo.toDomain(e), nil
+}
+
+func (repo *GORMDataExportRepository) FindRunnable(ctx context.Context, staleBefore time.Time, limit int) ([]domain.DataExport, error) {
+	es, err := repo.dao.FindRunnable(ctx, staleBefore.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(es, func(idx int, src dao.DataExport) domain.DataExport {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *GORMDataExportRepository) Claim(ctx context.Context, id int64, staleBefore time.Time) (bool, error) {
+	return repo.dao.Claim(ctx, id, staleBefore.UnixMilli())
+}
+
+func (repo *GORMDataExportRepository) UpdateStatus(ctx context.Context, id int64,
+	status domain.DataExportStatus, fileKey string) error {
+	return repo.dao.UpdateStatus(ctx, id, status.ToUint8(), fileKey)
+}
+
+func (repo *GORMDataExportRepository) FindDoneBefore(ctx context.Context, t time.Time, limit int) ([]domain.DataExport, error) {
+	es, err := repo.dao.FindDoneBefore(ctx, t.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(es, func(idx int, src dao.DataExport) domain.DataExport {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *GORMDataExportRepository) toDomain(e dao.DataExport) domain.DataExport {
+	return domain.DataExport{
+		Id:      e.Id,
+		Uid:     e.Uid,
+		Status:  domain.DataExportStatus(e.Status),
+		FileKey: e.FileKey,
+		Ctime:   time.UnixMilli(e.Ctime),
+		Utime:   time.UnixMilli(e.Utime),
+	}
+}
+
+type AccountDeletionRepository interface {
+	// Save 重新申请的话冷静期重新算
+	Save(ctx context.Context, d domain.AccountDeletion) error
+	// Find 没有申请过返回 ErrAccountDeletionNotFound
+	Find(ctx context.Context, uid int64) (domain.AccountDeletion, error)
+	// Cancel 不在冷静期里面返回 ErrAccountDeletionNotFound
+	Cancel(ctx context.Context, uid int64) error
+	FindDue(ctx context.Context, now time.Time, offset, limit int) ([]domain.AccountDeletion, error)
+	MarkDone(ctx context.Context, uid int64) error
+}
+
+type GORMAccountDeletionRepository struct {
+	dao dao.AccountDeletionDAO
+}
+
+func NewGORMAccountDeletionRepository(dao dao.AccountDeletionDAO) AccountDeletionRepository {
+	return &GORMAccountDeletionRepository{dao: dao}
+}
+
+func (repo *GORMAccountDeletionRepository) Save(ctx context.Context, d domain.AccountDeletion) error {
+	return repo.dao.Upsert(ctx, dao.AccountDeletion{
+		Uid:         d.Uid,
+		Status:      d.Status.ToUint8(),
+		ScheduledAt: d.ScheduledAt.UnixMilli(),
+	})
+}
+
+func (repo *GORMAccountDeletionRepository) Find(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	d, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return domain.AccountDeletion{}, err
+	}
+	return repo.toDomain(d), nil
+}
+
+func (repo *GORMAccountDeletionRepository) Cancel(ctx context.Context, uid int64) error {
+	return repo.dao.Cancel(ctx, uid)
+}
+
+func (repo *GORMAccountDeletionRepository) FindDue(ctx context.Context, now time.Time,
+	offset, limit int) ([]domain.AccountDeletion, error) {
+	ds, err := repo.dao.FindDue(ctx, now.UnixMilli(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ds, func(idx int, src dao.AccountDeletion) domain.AccountDeletion {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *GORMAccountDeletionRepository) MarkDone(ctx context.Context, uid int64) error {
+	return repo.dao.MarkDone(ctx, uid)
+}
+
+func (repo *GORMAccountDeletionRepository) toDomain(d dao.AccountDeletion) domain.AccountDeletion {
+	return domain.AccountDeletion{
+		Uid:         d.Uid,
+		Status:      domain.AccountDeletionStatus(d.Status),
+		ScheduledAt: time.UnixMilli(d.ScheduledAt),
+		Ctime:       time.UnixMilli(d.Ctime),
+	}
+}
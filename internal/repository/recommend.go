@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+type RecommendRepository interface {
+	ReplaceRecommend(ctx context.Context, uid int64, items []domain.RecommendItem) error
+	GetRecommend(ctx context.Context, uid int64, offset int, limit int) ([]domain.RecommendItem, error)
+}
+
+// CachedRecommendRepository 推荐结果随时可以重新算，所以只放在缓存里面
+type CachedRecommendRepository struct {
+	cache cache.RecommendCache
+}
+
+func NewCachedRecommendRepository(cache cache.RecommendCache) RecommendRepository {
+	return &CachedRecommendRepository{cache: cache}
+}
+
+func (c *CachedRecommendRepository) ReplaceRecommend(ctx context.Context, uid int64, items []domain.RecommendItem) error {
+	return c.cache.Replace(ctx, uid, items)
+}
+
+func (c *CachedRecommendRepository) GetRecommend(ctx context.Context, uid int64, offset int, limit int) ([]domain.RecommendItem, error) {
+	return c.cache.Get(ctx, uid, offset, limit)
+}
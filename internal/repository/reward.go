@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+)
+
+var ErrRewardNotFound = dao.ErrRecordNotFound
+
+type RewardRepository interface {
+	CreateReward(ctx context.Context, r domain.Reward) (int64, error)
+	GetReward(ctx context.Context, rid int64) (domain.Reward, error)
+	// UpdateStatus 只有等待支付的打赏才能改状态，返回是否真的改了。
+	// 改成终态之后缓存的二维码也会删掉
+	UpdateStatus(ctx context.Context, r domain.Reward, status domain.RewardStatus) (bool, error)
+	GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
+	CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error
+}
+
+type CachedRewardRepository struct {
+	dao   dao.RewardDAO
+	cache cache.RewardCache
+}
+
+func NewCachedRewardRepository(dao dao.RewardDAO, cache cache.RewardCache) RewardRepository {
+	return &CachedRewardRepository{dao: dao, cache: cache}
+}
+
+func (c *CachedRewardRepository) CreateReward(ctx context.Context, r domain.Reward) (int64, error) {
+	return c.dao.Insert(ctx, c.toEntity(r))
+}
+
+func (c *CachedRewardRepository) GetReward(ctx context.Context, rid int64) (domain.Reward, error) {
+	r, err := c.dao.GetReward(ctx, rid)
+	if err != nil {
+		return domain.Reward{}, err
+	}
+	return c.toDomain(r), nil
+}
+
+func (c *CachedRewardRepository) UpdateStatus(ctx context.Context, r domain.Reward, status domain.RewardStatus) (bool, error) {
+	ok, err := c.dao.UpdateStatus(ctx, r.Id, status.ToUint8())
+	if err != nil {
+		return false, err
+	}
+	if status != domain.RewardStatusInit {
+		// 不管是不是这一次改的，二维码都不能再给别的打赏用了。
+		// 删除失败的话 PreReward 还会再检查一次打赏的状态
+		_ = c.cache.DelCodeURL(ctx, r)
+	}
+	return ok, nil
+}
+
+func (c *CachedRewardRepository) GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	return c.cache.GetCachedCodeURL(ctx, r)
+}
+
+func (c *CachedRewardRepository) CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error {
+	return c.cache.CachedCodeURL(ctx, cu, r)
+}
+
+func (c *CachedRewardRepository) toEntity(r domain.Reward) dao.Reward {
+	return dao.Reward{
+		Id:        r.Id,
+		Biz:       r.Target.Biz,
+		BizId:     r.Target.BizId,
+		BizName:   r.Target.BizName,
+		TargetUid: r.Target.Uid,
+		Uid:       r.Uid,
+		Amount:    r.Amt,
+		Status:    r.Status.ToUint8(),
+	}
+}
+
+func (c *CachedRewardRepository) toDomain(r dao.Reward) domain.Reward {
+	return domain.Reward{
+		Id:  r.Id,
+		Uid: r.Uid,
+		Target: domain.Target{
+			Biz:     r.Biz,
+			BizId:   r.BizId,
+			BizName: r.BizName,
+			Uid:     r.TargetUid,
+		},
+		Amt:    r.Amount,
+		Status: domain.RewardStatus(r.Status),
+	}
+}
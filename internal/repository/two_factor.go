@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var ErrTwoFactorNotFound = dao.ErrRecordNotFound
+
+type TwoFactorRepository interface {
+	// SaveSecret 开始绑定，还没有开启
+	SaveSecret(ctx context.Context, uid int64, secret string) error
+	Find(ctx context.Context, uid int64) (domain.TwoFactor, error)
+	// Enable 确认开启，codeHashes 是恢复码的哈希
+	Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error
+	// UseStep 记下用掉的时间步，返回 false 说明这个验证码已经用过了
+	UseStep(ctx context.Context, uid int64, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, uid int64, codeHash string) (bool, error)
+	Delete(ctx context.Context, uid int64) error
+}
+
+type GORMTwoFactorRepository struct {
+	dao dao.TwoFactorDAO
+}
+
+func NewGORMTwoFactorRepository(dao dao.TwoFactorDAO) TwoFactorRepository {
+	return &GORMTwoFactorRepository{dao: dao}
+}
+
+func (repo *GORMTwoFactorRepository) SaveSecret(ctx context.Context, uid int64, secret string) error {
+	return repo.dao.Upsert(ctx, dao.UserTwoFactor{Uid: uid, Secret: secret})
+}
+
+func (repo *GORMTwoFactorRepository) Find(ctx context.Context, uid int64) (domain.TwoFactor, error) {
+	tf, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return domain.TwoFactor{}, err
+	}
+	return domain.TwoFactor{
+		Uid:      tf.Uid,
+		Secret:   tf.Secret,
+		Enabled:  tf.Enabled,
+		LastStep: tf.LastStep,
+	}, nil
+}
+
+func (repo *GORMTwoFactorRepository) Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error {
+	return repo.dao.Enable(ctx, uid, step, codeHashes)
+}
+
+func (repo *GORMTwoFactorRepository) UseStep(ctx context.Context, uid int64, step int64) (bool, error) {
+	return repo.dao.UseStep(ctx, uid, step)
+}
+
+func (repo *GORMTwoFactorRepository) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) (bool, error) {
+	return repo.dao.UseRecoveryCode(ctx, uid, codeHash)
+}
+
+func (repo *GORMTwoFactorRepository) Delete(ctx context.Context, uid int64) error {
+	return repo.dao.Delete(ctx, uid)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"github.com/ecodeclub/ekit/slice"
 	"log"
 	"time"
 	"webook/internal/domain"
@@ -22,22 +23,69 @@ type UserRepository interface {
 	FindById(ctx context.Context, uid int64) (domain.User, error)
 	FindByPhone(ctx context.Context, phone string) (domain.User, error)
 	UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error
-	FindByWechat(ctx context.Context, openId string) (domain.User, error)
+	FindByIdentity(ctx context.Context, provider, subject string) (domain.User, error)
+	FindIdentities(ctx context.Context, uid int64) ([]domain.OAuthIdentity, error)
+	// CreateWithIdentity 第三方账号第一次登录，第三方账号已经有用户了返回 ErrDuplicateUser
+	CreateWithIdentity(ctx context.Context, user domain.User, identity domain.OAuthIdentity) error
+	// BindIdentity 同一个提供方已经绑定过的话直接换掉
+	BindIdentity(ctx context.Context, identity domain.OAuthIdentity) error
+	UnbindIdentity(ctx context.Context, uid int64, provider string) error
+	UpdateNotificationMute(ctx context.Context, uid int64, mute domain.NotificationMute) error
+	MarkEmailVerified(ctx context.Context, uid int64) error
+	// UpdatePassword password 是已经加密过的
+	UpdatePassword(ctx context.Context, uid int64, password string) error
+	// UpdatePhone 传空字符串就是解绑，下面两个也一样
+	UpdatePhone(ctx context.Context, uid int64, phone string) error
+	UpdateEmail(ctx context.Context, uid int64, email string) error
+	Merge(ctx context.Context, primary, duplicate int64) error
+	List(ctx context.Context, offset, limit int) ([]domain.User, error)
+	// Anonymize 注销账号，用户还在但是认不出是谁了
+	Anonymize(ctx context.Context, uid int64, nickname string) error
+	FindByHandle(ctx context.Context, handle string) (domain.User, error)
+	// UpdateHandle 已经被别人用了返回 ErrDuplicateUser
+	UpdateHandle(ctx context.Context, uid int64, handle string) error
+	UpdateAvatar(ctx context.Context, uid int64, avatar string) error
+	UpdateProfilePrivacy(ctx context.Context, uid int64, privacy domain.ProfilePrivacy) error
 }
 
 type CachedUserRepository struct {
 	dao   dao.UserDAO
 	cache cache.UserCache
+	// intrCache 合并账号会直接改数据库里面的点赞收藏数
+	intrCache cache.InteractiveCache
 }
 
-func (repo *CachedUserRepository) FindByWechat(ctx context.Context, openId string) (domain.User, error) {
-	ue, err := repo.dao.FindByWechat(ctx, openId)
+func (repo *CachedUserRepository) FindByIdentity(ctx context.Context, provider, subject string) (domain.User, error) {
+	ue, err := repo.dao.FindByIdentity(ctx, provider, subject)
 	if err != nil {
 		return domain.User{}, err
 	}
 	return repo.toDomain(ue), nil
 }
 
+func (repo *CachedUserRepository) FindIdentities(ctx context.Context, uid int64) ([]domain.OAuthIdentity, error) {
+	res, err := repo.dao.FindIdentities(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.UserIdentity) domain.OAuthIdentity {
+		return repo.identityToDomain(src)
+	}), nil
+}
+
+func (repo *CachedUserRepository) CreateWithIdentity(ctx context.Context, user domain.User,
+	identity domain.OAuthIdentity) error {
+	return repo.dao.InsertWithIdentity(ctx, repo.toEntity(user), repo.identityToEntity(identity))
+}
+
+func (repo *CachedUserRepository) BindIdentity(ctx context.Context, identity domain.OAuthIdentity) error {
+	return repo.dao.UpsertIdentity(ctx, repo.identityToEntity(identity))
+}
+
+func (repo *CachedUserRepository) UnbindIdentity(ctx context.Context, uid int64, provider string) error {
+	return repo.dao.DeleteIdentity(ctx, uid, provider)
+}
+
 //type DBConfig struct {
 //	DSN string
 //}
@@ -60,10 +108,11 @@ func (repo *CachedUserRepository) FindByWechat(ctx context.Context, openId strin
 //	}, nil
 //}
 
-func NewCachedUserRepository(dao dao.UserDAO, c cache.UserCache) UserRepository {
+func NewCachedUserRepository(dao dao.UserDAO, c cache.UserCache, intrCache cache.InteractiveCache) UserRepository {
 	return &CachedUserRepository{
-		dao:   dao,
-		cache: c,
+		dao:       dao,
+		cache:     c,
+		intrCache: intrCache,
 	}
 }
 
@@ -80,19 +129,49 @@ func (repo *CachedUserRepository) FindByEmail(ctx context.Context, email string)
 }
 
 func (repo *CachedUserRepository) toDomain(u dao.User) domain.User {
+	var handleUtime time.Time
+	// 没设置过 handle 就保持零值，不然会变成 1970 年
+	if u.HandleUtime > 0 {
+		handleUtime = time.UnixMilli(u.HandleUtime)
+	}
 	return domain.User{
-		Id:          u.Id,
-		Email:       u.Email.String,
-		Phone:       u.Phone.String,
-		Password:    u.Password,
-		Nickname:    u.Nickname,
-		Birthday:    time.UnixMilli(u.Birthday),
-		Description: u.Description,
-		Ctime:       time.UnixMilli(u.Ctime),
-		WechatInfo: domain.WechatInfo{
-			OpenId:  u.WechatOpenId.String,
-			UnionId: u.WechatUnionId.String,
-		},
+		Id:               u.Id,
+		Email:            u.Email.String,
+		EmailVerified:    u.EmailVerified,
+		Phone:            u.Phone.String,
+		Password:         u.Password,
+		Nickname:         u.Nickname,
+		Birthday:         time.UnixMilli(u.Birthday),
+		Description:      u.Description,
+		Ctime:            time.UnixMilli(u.Ctime),
+		NotificationMute: domain.NotificationMute(u.NotificationMute),
+		Handle:           u.Handle.String,
+		HandleUtime:      handleUtime,
+		Avatar:           u.Avatar,
+		ProfilePrivacy:   domain.ProfilePrivacy(u.ProfilePrivacy),
+	}
+}
+
+func (repo *CachedUserRepository) identityToDomain(ui dao.UserIdentity) domain.OAuthIdentity {
+	return domain.OAuthIdentity{
+		Uid:      ui.Uid,
+		Provider: ui.Provider,
+		Subject:  ui.Subject,
+		UnionId:  ui.UnionId,
+		Email:    ui.Email,
+		Name:     ui.Name,
+		Ctime:    time.UnixMilli(ui.Ctime),
+	}
+}
+
+func (repo *CachedUserRepository) identityToEntity(identity domain.OAuthIdentity) dao.UserIdentity {
+	return dao.UserIdentity{
+		Uid:      identity.Uid,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		UnionId:  identity.UnionId,
+		Email:    identity.Email,
+		Name:     identity.Name,
 	}
 }
 
@@ -107,18 +186,18 @@ func (repo *CachedUserRepository) toEntity(u domain.User) dao.User {
 			String: u.Phone,
 			Valid:  u.Phone != "",
 		},
-		Password: u.Password,
-		WechatUnionId: sql.NullString{
-			String: u.WechatInfo.UnionId,
-			Valid:  u.WechatInfo.UnionId != "",
-		},
-		WechatOpenId: sql.NullString{
-			String: u.WechatInfo.OpenId,
-			Valid:  u.WechatInfo.OpenId != "",
+		EmailVerified:    u.EmailVerified,
+		Password:         u.Password,
+		Nickname:         u.Nickname,
+		Birthday:         u.Birthday.UnixMilli(),
+		Description:      u.Description,
+		NotificationMute: uint32(u.NotificationMute),
+		Handle: sql.NullString{
+			String: u.Handle,
+			Valid:  u.Handle != "",
 		},
-		Nickname:    u.Nickname,
-		Birthday:    u.Birthday.UnixMilli(),
-		Description: u.Description,
+		Avatar:         u.Avatar,
+		ProfilePrivacy: uint8(u.ProfilePrivacy),
 	}
 }
 
@@ -201,6 +280,66 @@ func (repo *CachedUserRepository) UpdateNonSensitiveInfo(ctx context.Context, us
 	return repo.cache.Del(ctx, user.Id)
 }
 
+func (repo *CachedUserRepository) UpdateNotificationMute(ctx context.Context, uid int64, mute domain.NotificationMute) error {
+	err := repo.dao.UpdateNotificationMute(ctx, uid, uint32(mute))
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) MarkEmailVerified(ctx context.Context, uid int64) error {
+	err := repo.dao.UpdateEmailVerified(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdatePassword(ctx context.Context, uid int64, password string) error {
+	err := repo.dao.UpdatePassword(ctx, uid, password)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdatePhone(ctx context.Context, uid int64, phone string) error {
+	err := repo.dao.UpdatePhone(ctx, uid, sql.NullString{String: phone, Valid: phone != ""})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdateEmail(ctx context.Context, uid int64, email string) error {
+	err := repo.dao.UpdateEmail(ctx, uid, sql.NullString{String: email, Valid: email != ""})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) Merge(ctx context.Context, primary, duplicate int64) error {
+	changed, err := repo.dao.Merge(ctx, primary, duplicate)
+	if err != nil {
+		return err
+	}
+	// 不删的话缓存里面的点赞收藏数要等过期了才对得上
+	for _, intr := range changed {
+		err = repo.intrCache.Del(ctx, intr.Biz, intr.BizId)
+		if err != nil {
+			return err
+		}
+	}
+	// 两个账号的身份信息都变了
+	err = repo.cache.Del(ctx, primary)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, duplicate)
+}
+
 func (repo *CachedUserRepository) FindByPhone(ctx context.Context, phone string) (domain.User, error) {
 	u, err := repo.dao.FindByPhone(ctx, phone)
 	if err != nil {
@@ -208,3 +347,54 @@ func (repo *CachedUserRepository) FindByPhone(ctx context.Context, phone string)
 	}
 	return repo.toDomain(u), nil
 }
+
+func (repo *CachedUserRepository) Anonymize(ctx context.Context, uid int64, nickname string) error {
+	err := repo.dao.Anonymize(ctx, uid, nickname)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+// FindByHandle 个人主页用，handle 改了的话按 id 的缓存不好维护，直接查数据库
+func (repo *CachedUserRepository) FindByHandle(ctx context.Context, handle string) (domain.User, error) {
+	u, err := repo.dao.FindByHandle(ctx, handle)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return repo.toDomain(u), nil
+}
+
+func (repo *CachedUserRepository) UpdateHandle(ctx context.Context, uid int64, handle string) error {
+	err := repo.dao.UpdateHandle(ctx, uid, handle)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdateAvatar(ctx context.Context, uid int64, avatar string) error {
+	err := repo.dao.UpdateAvatar(ctx, uid, avatar)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) UpdateProfilePrivacy(ctx context.Context, uid int64, privacy domain.ProfilePrivacy) error {
+	err := repo.dao.UpdateProfilePrivacy(ctx, uid, uint8(privacy))
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRepository) List(ctx context.Context, offset, limit int) ([]domain.User, error) {
+	users, err := repo.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(users, func(idx int, src dao.User) domain.User {
+		return repo.toDomain(src)
+	}), nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+	"webook/pkg/logger"
+)
+
+var ErrUserBanNotFound = dao.ErrRecordNotFound
+
+type UserBanRepository interface {
+	Save(ctx context.Context, b domain.UserBan) error
+	// Find 没有被封禁返回 ErrUserBanNotFound，过期了的还是会返回
+	Find(ctx context.Context, uid int64) (domain.UserBan, error)
+	Delete(ctx context.Context, uid int64) error
+}
+
+type CachedUserBanRepository struct {
+	dao   dao.UserBanDAO
+	cache cache.UserBanCache
+	l     logger.LoggerV1
+}
+
+func NewCachedUserBanRepository(dao dao.UserBanDAO, cache cache.UserBanCache,
+	l logger.LoggerV1) UserBanRepository {
+	return &CachedUserBanRepository{dao: dao, cache: cache, l: l}
+}
+
+func (repo *CachedUserBanRepository) Save(ctx context.Context, b domain.UserBan) error {
+	var expireAt int64
+	if !b.Expire.IsZero() {
+		expireAt = b.Expire.UnixMilli()
+	}
+	err := repo.dao.Upsert(ctx, dao.UserBan{
+		Uid:      b.Uid,
+		Reason:   b.Reason,
+		Scope:    uint8(b.Scope),
+		ExpireAt: expireAt,
+		Operator: b.Operator,
+	})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, b.Uid)
+}
+
+func (repo *CachedUserBanRepository) Find(ctx context.Context, uid int64) (domain.UserBan, error) {
+	b, err := repo.cache.Get(ctx, uid)
+	if err == nil {
+		if b.Uid == 0 {
+			return domain.UserBan{}, ErrUserBanNotFound
+		}
+		return b, nil
+	}
+	entity, err := repo.dao.FindByUid(ctx, uid)
+	switch err {
+	case nil:
+		b = repo.toDomain(entity)
+	case dao.ErrRecordNotFound:
+		// 没有被封禁也缓存起来
+		b = domain.UserBan{}
+	default:
+		return domain.UserBan{}, err
+	}
+	er := repo.cache.Set(ctx, uid, b)
+	if er != nil {
+		repo.l.Error("回写封禁缓存失败", logger.Int64("uid", uid), logger.Error(er))
+	}
+	return b, err
+}
+
+func (repo *CachedUserBanRepository) Delete(ctx context.Context, uid int64) error {
+	err := repo.dao.Delete(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserBanRepository) toDomain(b dao.UserBan) domain.UserBan {
+	res := domain.UserBan{
+		Uid:      b.Uid,
+		Reason:   b.Reason,
+		Scope:    domain.BanScope(b.Scope),
+		Operator: b.Operator,
+		Ctime:    time.UnixMilli(b.Ctime),
+	}
+	if b.ExpireAt > 0 {
+		res.Expire = time.UnixMilli(b.ExpireAt)
+	}
+	return res
+}
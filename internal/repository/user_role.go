@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+	"webook/pkg/logger"
+)
+
+type UserRoleRepository interface {
+	FindRoles(ctx context.Context, uid int64) (domain.Roles, error)
+	AddRole(ctx context.Context, uid int64, role string, operator int64) error
+	RemoveRole(ctx context.Context, uid int64, role string) error
+}
+
+type CachedUserRoleRepository struct {
+	dao   dao.UserRoleDAO
+	cache cache.UserRoleCache
+	l     logger.LoggerV1
+}
+
+func NewCachedUserRoleRepository(dao dao.UserRoleDAO, cache cache.UserRoleCache,
+	l logger.LoggerV1) UserRoleRepository {
+	return &CachedUserRoleRepository{dao: dao, cache: cache, l: l}
+}
+
+func (repo *CachedUserRoleRepository) FindRoles(ctx context.Context, uid int64) (domain.Roles, error) {
+	roles, err := repo.cache.Get(ctx, uid)
+	if err == nil {
+		return roles, nil
+	}
+	rs, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	roles = slice.Map(rs, func(idx int, src dao.UserRole) string {
+		return src.Role
+	})
+	err = repo.cache.Set(ctx, uid, roles)
+	if err != nil {
+		repo.l.Error("回写角色缓存失败", logger.Int64("uid", uid), logger.Error(err))
+	}
+	return roles, nil
+}
+
+func (repo *CachedUserRoleRepository) AddRole(ctx context.Context, uid int64, role string, operator int64) error {
+	err := repo.dao.Insert(ctx, dao.UserRole{Uid: uid, Role: role, Operator: operator})
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
+
+func (repo *CachedUserRoleRepository) RemoveRole(ctx context.Context, uid int64, role string) error {
+	err := repo.dao.Delete(ctx, uid, role)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, uid)
+}
@@ -175,7 +175,7 @@ func TestCachedUserRepository_FindById(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			uc, ud := tc.mock(ctrl)
-			svc := NewCachedUserRepository(ud, uc)
+			svc := NewCachedUserRepository(ud, uc, nil)
 			user, err := svc.FindById(tc.ctx, tc.uid)
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.wantUser, user)
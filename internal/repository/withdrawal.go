@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrWithdrawalNotFound = dao.ErrRecordNotFound
+	// ErrWithdrawalStatusChanged 状态已经被别人改掉了
+	ErrWithdrawalStatusChanged = errors.New("提现状态已经变了")
+)
+
+type WithdrawalRepository interface {
+	// Create 创建提现申请，审计日志的操作人就是申请人自己
+	Create(ctx context.Context, w domain.Withdrawal) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.Withdrawal, error)
+	ListByUid(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error)
+	ListByStatus(ctx context.Context, status domain.WithdrawalStatus, offset, limit int) ([]domain.Withdrawal, error)
+	// UpdateStatus 只有当前是 from 状态的时候才能更新，并且会记审计日志
+	UpdateStatus(ctx context.Context, w domain.Withdrawal, from domain.WithdrawalStatus,
+		operator int64, remark string) error
+	FindLogs(ctx context.Context, wid int64) ([]domain.WithdrawalLog, error)
+}
+
+type GORMWithdrawalRepository struct {
+	dao dao.WithdrawalDAO
+}
+
+func NewGORMWithdrawalRepository(dao dao.WithdrawalDAO) WithdrawalRepository {
+	return &GORMWithdrawalRepository{dao: dao}
+}
+
+func (g *GORMWithdrawalRepository) Create(ctx context.Context, w domain.Withdrawal) (int64, error) {
+	return g.dao.Insert(ctx, g.toEntity(w), dao.WithdrawalLog{
+		Operator: w.Uid,
+		To:       w.Status.ToUint8(),
+		Remark:   "申请提现",
+	})
+}
+
+func (g *GORMWithdrawalRepository) FindById(ctx context.Context, id int64) (domain.Withdrawal, error) {
+	w, err := g.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Withdrawal{}, err
+	}
+	return g.toDomain(w), nil
+}
+
+func (g *GORMWithdrawalRepository) ListByUid(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error) {
+	ws, err := g.dao.FindByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Withdrawal, domain.Withdrawal](ws, func(idx int, src dao.Withdrawal) domain.Withdrawal {
+		return g.toDomain(src)
+	}), nil
+}
+
+func (g *GORMWithdrawalRepository) ListByStatus(ctx context.Context, status domain.WithdrawalStatus, offset, limit int) ([]domain.Withdrawal, error) {
+	ws, err := g.dao.FindByStatus(ctx, status.ToUint8(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.Withdrawal, domain.Withdrawal](ws, func(idx int, src dao.Withdrawal) domain.Withdrawal {
+		return g.toDomain(src)
+	}), nil
+}
+
+func (g *GORMWithdrawalRepository) UpdateStatus(ctx context.Context, w domain.Withdrawal,
+	from domain.WithdrawalStatus, operator int64, remark string) error {
+	ok, err := g.dao.UpdateStatus(ctx, w.Id, w.TxnID, dao.WithdrawalLog{
+		Operator: operator,
+		From:     from.ToUint8(),
+		To:       w.Status.ToUint8(),
+		Remark:   remark,
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWithdrawalStatusChanged
+	}
+	return nil
+}
+
+func (g *GORMWithdrawalRepository) FindLogs(ctx context.Context, wid int64) ([]domain.WithdrawalLog, error) {
+	logs, err := g.dao.FindLogs(ctx, wid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.WithdrawalLog, domain.WithdrawalLog](logs, func(idx int, src dao.WithdrawalLog) domain.WithdrawalLog {
+		return domain.WithdrawalLog{
+			Id:       src.Id,
+			Wid:      src.Wid,
+			Operator: src.Operator,
+			From:     domain.WithdrawalStatus(src.From),
+			To:       domain.WithdrawalStatus(src.To),
+			Remark:   src.Remark,
+			Ctime:    time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (g *GORMWithdrawalRepository) toEntity(w domain.Withdrawal) dao.Withdrawal {
+	return dao.Withdrawal{
+		Id:       w.Id,
+		Uid:      w.Uid,
+		Amt:      w.Amt,
+		Fee:      w.Fee,
+		Currency: w.Currency,
+		Status:   w.Status.ToUint8(),
+		TxnID:    w.TxnID,
+	}
+}
+
+func (g *GORMWithdrawalRepository) toDomain(w dao.Withdrawal) domain.Withdrawal {
+	return domain.Withdrawal{
+		Id:       w.Id,
+		Uid:      w.Uid,
+		Amt:      w.Amt,
+		Fee:      w.Fee,
+		Currency: w.Currency,
+		Status:   domain.WithdrawalStatus(w.Status),
+		TxnID:    w.TxnID,
+		Ctime:    time.UnixMilli(w.Ctime),
+		Utime:    time.UnixMilli(w.Utime),
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+const (
+	// 每个用户最多这么多个令牌
+	maxAccessTokens = 20
+	// 令牌一定要有过期时间，最长一年
+	maxAccessTokenTTL = time.Hour * 24 * 365
+	// 最近使用时间不用记得太准，省得每次调用都写库
+	accessTokenLastUsedGap = time.Minute
+)
+
+var (
+	ErrInvalidAccessToken  = errors.New("个人访问令牌无效")
+	ErrAccessTokenNotFound = repository.ErrAccessTokenNotFound
+	ErrInvalidScope        = errors.New("不支持的权限")
+	ErrInvalidTokenTTL     = errors.New("有效期不合法")
+	ErrTooManyAccessTokens = errors.New("个人访问令牌太多了")
+)
+
+//go:generate mockgen -source=./access_token.go -package=svcmocks -destination=./mocks/access_token.mock.go AccessTokenService
+type AccessTokenService interface {
+	// Create 返回令牌明文，只有这一次能看到
+	Create(ctx context.Context, uid int64, name string, scopes []string, ttl time.Duration) (string, domain.AccessToken, error)
+	List(ctx context.Context, uid int64) ([]domain.AccessToken, error)
+	// Revoke 不是这个用户的返回 ErrAccessTokenNotFound
+	Revoke(ctx context.Context, uid int64, id int64) error
+	// Verify 校验令牌明文，过期了或者被撤销了都返回 ErrInvalidAccessToken，
+	// 用户被封禁了登录返回 ErrUserBanned
+	Verify(ctx context.Context, token string) (domain.AccessToken, error)
+}
+
+type accessTokenService struct {
+	repo repository.AccessTokenRepository
+	// banRepo 令牌不走登录流程，封禁了登录的用户要在这里拦住
+	banRepo repository.UserBanRepository
+}
+
+func NewAccessTokenService(repo repository.AccessTokenRepository,
+	banRepo repository.UserBanRepository) AccessTokenService {
+	return &accessTokenService{repo: repo, banRepo: banRepo}
+}
+
+func (svc *accessTokenService) Create(ctx context.Context, uid int64, name string,
+	scopes []string, ttl time.Duration) (string, domain.AccessToken, error) {
+	if len(scopes) == 0 {
+		return "", domain.AccessToken{}, ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(domain.AccessTokenScopes, scope) {
+			return "", domain.AccessToken{}, ErrInvalidScope
+		}
+	}
+	if ttl <= 0 || ttl > maxAccessTokenTTL {
+		return "", domain.AccessToken{}, ErrInvalidTokenTTL
+	}
+	cnt, err := svc.repo.CountByUid(ctx, uid)
+	if err != nil {
+		return "", domain.AccessToken{}, err
+	}
+	if cnt >= maxAccessTokens {
+		return "", domain.AccessToken{}, ErrTooManyAccessTokens
+	}
+	token, err := svc.generate()
+	if err != nil {
+		return "", domain.AccessToken{}, err
+	}
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	now := time.Now()
+	t := domain.AccessToken{
+		Uid:    uid,
+		Name:   name,
+		Prefix: token[:len(domain.AccessTokenPrefix)+4],
+		Scopes: slices.Compact(scopes),
+		Expire: now.Add(ttl),
+		Ctime:  now,
+	}
+	t.Id, err = svc.repo.Create(ctx, t, svc.hash(token))
+	if err != nil {
+		return "", domain.AccessToken{}, err
+	}
+	return token, t, nil
+}
+
+func (svc *accessTokenService) List(ctx context.Context, uid int64) ([]domain.AccessToken, error) {
+	return svc.repo.FindByUid(ctx, uid)
+}
+
+func (svc *accessTokenService) Revoke(ctx context.Context, uid int64, id int64) error {
+	return svc.repo.Delete(ctx, uid, id)
+}
+
+func (svc *accessTokenService) Verify(ctx context.Context, token string) (domain.AccessToken, error) {
+	if !strings.HasPrefix(token, domain.AccessTokenPrefix) {
+		return domain.AccessToken{}, ErrInvalidAccessToken
+	}
+	t, err := svc.repo.FindByHash(ctx, svc.hash(token))
+	switch {
+	case err == repository.ErrAccessTokenNotFound:
+		return domain.AccessToken{}, ErrInvalidAccessToken
+	case err != nil:
+		return domain.AccessToken{}, err
+	}
+	now := time.Now()
+	if t.Expired(now) {
+		return domain.AccessToken{}, ErrInvalidAccessToken
+	}
+	b, err := svc.banRepo.Find(ctx, t.Uid)
+	switch {
+	case err == nil && b.Restricts(domain.BanScopeLogin, now):
+		return domain.AccessToken{}, ErrUserBanned
+	case err != nil && err != repository.ErrUserBanNotFound:
+		return domain.AccessToken{}, err
+	}
+	if now.Sub(t.LastUsed) >= accessTokenLastUsedGap {
+		// 记不上也不影响这次调用
+		_ = svc.repo.UpdateLastUsed(ctx, t.Id, now)
+		t.LastUsed = now
+	}
+	return t, nil
+}
+
+// generate 256 位的随机数
+func (svc *accessTokenService) generate() (string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return domain.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hash 令牌是随机生成的，熵足够，用 SHA256 就行了
+func (svc *accessTokenService) hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
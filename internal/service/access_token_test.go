@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestAccessTokenService_Create(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.AccessTokenRepository
+
+		scopes     []string
+		ttl        time.Duration
+		wantScopes []string
+		wantErr    error
+	}{
+		{
+			name: "创建成功，权限去重",
+			mock: func(ctrl *gomock.Controller) repository.AccessTokenRepository {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				repo.EXPECT().CountByUid(gomock.Any(), int64(123)).Return(int64(1), nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(10), nil)
+				return repo
+			},
+			scopes:     []string{domain.ScopeArticlesWrite, domain.ScopeArticlesRead, domain.ScopeArticlesWrite},
+			ttl:        time.Hour * 24,
+			wantScopes: []string{domain.ScopeArticlesRead, domain.ScopeArticlesWrite},
+		},
+		{
+			name: "不支持的权限",
+			mock: func(ctrl *gomock.Controller) repository.AccessTokenRepository {
+				return repomocks.NewMockAccessTokenRepository(ctrl)
+			},
+			scopes:  []string{"users:write"},
+			ttl:     time.Hour * 24,
+			wantErr: ErrInvalidScope,
+		},
+		{
+			name: "有效期太长",
+			mock: func(ctrl *gomock.Controller) repository.AccessTokenRepository {
+				return repomocks.NewMockAccessTokenRepository(ctrl)
+			},
+			scopes:  []string{domain.ScopeArticlesRead},
+			ttl:     maxAccessTokenTTL + time.Hour,
+			wantErr: ErrInvalidTokenTTL,
+		},
+		{
+			name: "令牌太多",
+			mock: func(ctrl *gomock.Controller) repository.AccessTokenRepository {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				repo.EXPECT().CountByUid(gomock.Any(), int64(123)).Return(int64(maxAccessTokens), nil)
+				return repo
+			},
+			scopes:  []string{domain.ScopeArticlesRead},
+			ttl:     time.Hour * 24,
+			wantErr: ErrTooManyAccessTokens,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAccessTokenService(tc.mock(ctrl), nil)
+			token, at, err := svc.Create(context.Background(), 123, "ci", tc.scopes, tc.ttl)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.True(t, strings.HasPrefix(token, domain.AccessTokenPrefix))
+			assert.True(t, strings.HasPrefix(token, at.Prefix))
+			assert.Equal(t, int64(10), at.Id)
+			assert.Equal(t, tc.wantScopes, at.Scopes)
+		})
+	}
+}
+
+func TestAccessTokenService_Verify(t *testing.T) {
+	svc := &accessTokenService{}
+	token, err := svc.generate()
+	require.NoError(t, err)
+	now := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository)
+
+		token   string
+		wantErr error
+	}{
+		{
+			name: "校验成功，记录最近使用时间",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				banRepo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), svc.hash(token)).
+					Return(domain.AccessToken{Id: 10, Uid: 123, Expire: now.Add(time.Hour)}, nil)
+				banRepo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.UserBan{}, repository.ErrUserBanNotFound)
+				repo.EXPECT().UpdateLastUsed(gomock.Any(), int64(10), gomock.Any()).Return(nil)
+				return repo, banRepo
+			},
+			token: token,
+		},
+		{
+			name: "刚用过，不用再记",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				banRepo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), svc.hash(token)).
+					Return(domain.AccessToken{Id: 10, Uid: 123, Expire: now.Add(time.Hour), LastUsed: now}, nil)
+				banRepo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.UserBan{}, repository.ErrUserBanNotFound)
+				return repo, banRepo
+			},
+			token: token,
+		},
+		{
+			name: "已经过期",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				banRepo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), svc.hash(token)).
+					Return(domain.AccessToken{Id: 10, Uid: 123, Expire: now.Add(-time.Hour)}, nil)
+				return repo, banRepo
+			},
+			token:   token,
+			wantErr: ErrInvalidAccessToken,
+		},
+		{
+			name: "用户被封禁了登录",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				banRepo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), svc.hash(token)).
+					Return(domain.AccessToken{Id: 10, Uid: 123, Expire: now.Add(time.Hour)}, nil)
+				banRepo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.UserBan{Uid: 123, Scope: domain.BanScopeLogin}, nil)
+				return repo, banRepo
+			},
+			token:   token,
+			wantErr: ErrUserBanned,
+		},
+		{
+			name: "封禁已经到期",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				banRepo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), svc.hash(token)).
+					Return(domain.AccessToken{Id: 10, Uid: 123, Expire: now.Add(time.Hour), LastUsed: now}, nil)
+				banRepo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.UserBan{Uid: 123, Scope: domain.BanScopeLogin, Expire: now.Add(-time.Hour)}, nil)
+				return repo, banRepo
+			},
+			token: token,
+		},
+		{
+			name: "已经撤销",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				repo := repomocks.NewMockAccessTokenRepository(ctrl)
+				banRepo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), svc.hash(token)).
+					Return(domain.AccessToken{}, repository.ErrAccessTokenNotFound)
+				return repo, banRepo
+			},
+			token:   token,
+			wantErr: ErrInvalidAccessToken,
+		},
+		{
+			name: "不是个人访问令牌",
+			mock: func(ctrl *gomock.Controller) (repository.AccessTokenRepository, repository.UserBanRepository) {
+				return repomocks.NewMockAccessTokenRepository(ctrl), repomocks.NewMockUserBanRepository(ctrl)
+			},
+			token:   "abc",
+			wantErr: ErrInvalidAccessToken,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, banRepo := tc.mock(ctrl)
+			svc := NewAccessTokenService(repo, banRepo)
+			at, err := svc.Verify(context.Background(), tc.token)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, int64(123), at.Uid)
+		})
+	}
+}
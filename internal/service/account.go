@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	// ErrDuplicateCredit 同一个业务已经入过账了
+	ErrDuplicateCredit     = repository.ErrDuplicateTransaction
+	ErrInvalidCredit       = errors.New("入账参数不合法")
+	ErrInsufficientBalance = repository.ErrInsufficientBalance
+)
+
+// 提现在账本里面的三个环节，bizId 都是提现 id
+const (
+	bizWithdrawFreeze   = "withdraw_freeze"
+	bizWithdrawUnfreeze = "withdraw_unfreeze"
+	bizWithdrawSettle   = "withdraw_settle"
+)
+
+//go:generate mockgen -source=./account.go -package=svcmocks -destination=./mocks/account.mock.go AccountService
+type AccountService interface {
+	// Credit 入账，钱都是从支付渠道的清算账户转过来的
+	Credit(ctx context.Context, c domain.Credit) error
+	// Balance 用户所有账户的余额
+	Balance(ctx context.Context, uid int64) ([]domain.Account, error)
+	// Statement 用户的流水，最新的在前面
+	Statement(ctx context.Context, uid int64, offset, limit int) ([]domain.AccountActivity, error)
+	// Freeze 提现申请的时候，把钱从打赏账户转到冻结账户
+	Freeze(ctx context.Context, w domain.Withdrawal) error
+	// Unfreeze 审核拒绝或者打款失败，把冻结的钱还回去
+	Unfreeze(ctx context.Context, w domain.Withdrawal) error
+	// Settle 打款成功，冻结的钱扣掉手续费之后出账，手续费归平台
+	Settle(ctx context.Context, w domain.Withdrawal) error
+	// CheckBalanced 对账用，所有流水加起来必须是 0
+	CheckBalanced(ctx context.Context) (bool, error)
+}
+
+type accountService struct {
+	repo repository.AccountRepository
+}
+
+func NewAccountService(repo repository.AccountRepository) AccountService {
+	return &accountService{repo: repo}
+}
+
+func (a *accountService) Credit(ctx context.Context, c domain.Credit) error {
+	if c.Biz == "" || c.BizId <= 0 {
+		return ErrInvalidCredit
+	}
+	acts := make([]domain.AccountActivity, 0, len(c.Items)+1)
+	// 每一种币种单独配平
+	sums := make(map[string]int64, 1)
+	currencies := make([]string, 0, 1)
+	for _, item := range c.Items {
+		if item.Amt < 0 {
+			return ErrInvalidCredit
+		}
+		// 比如平台不抽成的时候，分成就是 0
+		if item.Amt == 0 {
+			continue
+		}
+		uid := item.Uid
+		switch item.AccountType {
+		case domain.AccountTypeReward:
+			if uid <= 0 {
+				return ErrInvalidCredit
+			}
+		case domain.AccountTypeSystem:
+			uid = 0
+		default:
+			// 清算账户只能由这里自己记
+			return ErrInvalidCredit
+		}
+		currency := item.Currency
+		if currency == "" {
+			currency = "CNY"
+		}
+		if _, ok := sums[currency]; !ok {
+			currencies = append(currencies, currency)
+		}
+		sums[currency] += item.Amt
+		acts = append(acts, domain.AccountActivity{
+			Uid:         uid,
+			AccountType: item.AccountType,
+			Amt:         item.Amt,
+			Currency:    currency,
+		})
+	}
+	if len(acts) == 0 {
+		return ErrInvalidCredit
+	}
+	for _, currency := range currencies {
+		acts = append(acts, domain.AccountActivity{
+			AccountType: domain.AccountTypeClearing,
+			Amt:         -sums[currency],
+			Currency:    currency,
+		})
+	}
+	return a.repo.AddActivities(ctx, c.Biz, c.BizId, acts)
+}
+
+func (a *accountService) Freeze(ctx context.Context, w domain.Withdrawal) error {
+	return a.repo.AddActivities(ctx, bizWithdrawFreeze, w.Id, []domain.AccountActivity{
+		{Uid: w.Uid, AccountType: domain.AccountTypeReward, Amt: -w.Amt, Currency: w.Currency},
+		{Uid: w.Uid, AccountType: domain.AccountTypeFrozen, Amt: w.Amt, Currency: w.Currency},
+	})
+}
+
+func (a *accountService) Unfreeze(ctx context.Context, w domain.Withdrawal) error {
+	return a.repo.AddActivities(ctx, bizWithdrawUnfreeze, w.Id, []domain.AccountActivity{
+		{Uid: w.Uid, AccountType: domain.AccountTypeFrozen, Amt: -w.Amt, Currency: w.Currency},
+		{Uid: w.Uid, AccountType: domain.AccountTypeReward, Amt: w.Amt, Currency: w.Currency},
+	})
+}
+
+func (a *accountService) Settle(ctx context.Context, w domain.Withdrawal) error {
+	acts := []domain.AccountActivity{
+		{Uid: w.Uid, AccountType: domain.AccountTypeFrozen, Amt: -w.Amt, Currency: w.Currency},
+		// 打给用户的钱从支付渠道出去了，清算账户的欠款减少
+		{AccountType: domain.AccountTypeClearing, Amt: w.Paid(), Currency: w.Currency},
+	}
+	if w.Fee > 0 {
+		acts = append(acts, domain.AccountActivity{
+			AccountType: domain.AccountTypeSystem, Amt: w.Fee, Currency: w.Currency,
+		})
+	}
+	return a.repo.AddActivities(ctx, bizWithdrawSettle, w.Id, acts)
+}
+
+func (a *accountService) Balance(ctx context.Context, uid int64) ([]domain.Account, error) {
+	return a.repo.FindAccounts(ctx, uid)
+}
+
+func (a *accountService) Statement(ctx context.Context, uid int64, offset, limit int) ([]domain.AccountActivity, error) {
+	return a.repo.FindActivities(ctx, uid, offset, limit)
+}
+
+func (a *accountService) CheckBalanced(ctx context.Context) (bool, error) {
+	sum, err := a.repo.SumAmt(ctx)
+	return sum == 0, err
+}
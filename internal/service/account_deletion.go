@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var ErrAccountDeletionNotFound = repository.ErrAccountDeletionNotFound
+
+//go:generate mockgen -source=./account_deletion.go -package=svcmocks -destination=./mocks/account_deletion.mock.go AccountDeletionService
+type AccountDeletionService interface {
+	// Request 申请注销，冷静期过了才真正注销。已经在冷静期里面的话返回原来的申请
+	Request(ctx context.Context, uid int64) (domain.AccountDeletion, error)
+	// Cancel 冷静期里面可以撤销，不在冷静期里面返回 ErrAccountDeletionNotFound
+	Cancel(ctx context.Context, uid int64) error
+	// Find 没有申请过返回 ErrAccountDeletionNotFound
+	Find(ctx context.Context, uid int64) (domain.AccountDeletion, error)
+	// FindDue 冷静期已经结束的申请，给定时任务用
+	FindDue(ctx context.Context, offset, limit int) ([]domain.AccountDeletion, error)
+	// Execute 真正注销：文章下线，个人信息匿名化，登录方式和阅读历史删掉。
+	// 点赞收藏留着，不然文章上面的计数对不上
+	Execute(ctx context.Context, uid int64) error
+}
+
+type accountDeletionService struct {
+	repo     repository.AccountDeletionRepository
+	userRepo repository.UserRepository
+	artRepo  repository.ArticleRepository
+	producer article.Producer
+	// 冷静期
+	grace time.Duration
+	l     logger.LoggerV1
+}
+
+func NewAccountDeletionService(repo repository.AccountDeletionRepository, userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository, producer article.Producer,
+	grace time.Duration, l logger.LoggerV1) AccountDeletionService {
+	return &accountDeletionService{
+		repo:     repo,
+		userRepo: userRepo,
+		artRepo:  artRepo,
+		producer: producer,
+		grace:    grace,
+		l:        l,
+	}
+}
+
+func (svc *accountDeletionService) Request(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	d, err := svc.repo.Find(ctx, uid)
+	switch err {
+	case nil:
+		if d.Status == domain.AccountDeletionStatusPending {
+			return d, nil
+		}
+	case repository.ErrAccountDeletionNotFound:
+	default:
+		return domain.AccountDeletion{}, err
+	}
+	now := time.Now()
+	d = domain.AccountDeletion{
+		Uid:         uid,
+		Status:      domain.AccountDeletionStatusPending,
+		ScheduledAt: now.Add(svc.grace),
+		Ctime:       now,
+	}
+	return d, svc.repo.Save(ctx, d)
+}
+
+func (svc *accountDeletionService) Cancel(ctx context.Context, uid int64) error {
+	return svc.repo.Cancel(ctx, uid)
+}
+
+func (svc *accountDeletionService) Find(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	return svc.repo.Find(ctx, uid)
+}
+
+func (svc *accountDeletionService) FindDue(ctx context.Context, offset, limit int) ([]domain.AccountDeletion, error) {
+	return svc.repo.FindDue(ctx, time.Now(), offset, limit)
+}
+
+// Execute 每一步都可以重复执行，中间失败了下次定时任务再来一遍
+func (svc *accountDeletionService) Execute(ctx context.Context, uid int64) error {
+	err := takeDownArticles(ctx, svc.artRepo, svc.producer, svc.l, uid)
+	if err != nil {
+		return err
+	}
+	err = svc.userRepo.Anonymize(ctx, uid, domain.DeletedUserNickname)
+	if err != nil {
+		return err
+	}
+	return svc.repo.MarkDone(ctx, uid)
+}
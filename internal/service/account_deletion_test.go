@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestAccountDeletionService_Request(t *testing.T) {
+	scheduled := time.Now().Add(time.Hour)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.AccountDeletionRepository
+
+		wantStatus domain.AccountDeletionStatus
+		// 是不是重新算了冷静期
+		wantNew bool
+	}{
+		{
+			name: "第一次申请",
+			mock: func(ctrl *gomock.Controller) repository.AccountDeletionRepository {
+				repo := repomocks.NewMockAccountDeletionRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.AccountDeletion{}, repository.ErrAccountDeletionNotFound)
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+				return repo
+			},
+			wantStatus: domain.AccountDeletionStatusPending,
+			wantNew:    true,
+		},
+		{
+			name: "已经在冷静期里面",
+			mock: func(ctrl *gomock.Controller) repository.AccountDeletionRepository {
+				repo := repomocks.NewMockAccountDeletionRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).Return(domain.AccountDeletion{
+					Uid:         123,
+					Status:      domain.AccountDeletionStatusPending,
+					ScheduledAt: scheduled,
+				}, nil)
+				return repo
+			},
+			wantStatus: domain.AccountDeletionStatusPending,
+		},
+		{
+			name: "撤销过再申请",
+			mock: func(ctrl *gomock.Controller) repository.AccountDeletionRepository {
+				repo := repomocks.NewMockAccountDeletionRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).Return(domain.AccountDeletion{
+					Uid:         123,
+					Status:      domain.AccountDeletionStatusCanceled,
+					ScheduledAt: scheduled,
+				}, nil)
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+				return repo
+			},
+			wantStatus: domain.AccountDeletionStatusPending,
+			wantNew:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAccountDeletionService(tc.mock(ctrl), nil, nil, nil,
+				time.Hour*24*15, logger.NewNoOpLogger())
+			d, err := svc.Request(context.Background(), 123)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, d.Status)
+			if tc.wantNew {
+				assert.True(t, d.ScheduledAt.After(time.Now().Add(time.Hour*24*14)))
+			} else {
+				assert.Equal(t, scheduled, d.ScheduledAt)
+			}
+		})
+	}
+}
+
+func TestAccountDeletionService_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockAccountDeletionRepository(ctrl)
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	artRepo.EXPECT().GetByAuthor(gomock.Any(), int64(123), 0, 100).Return([]domain.Article{
+		{Id: 1, Status: domain.ArticleStatusUnpublished},
+	}, nil)
+	userRepo.EXPECT().Anonymize(gomock.Any(), int64(123), domain.DeletedUserNickname).Return(nil)
+	repo.EXPECT().MarkDone(gomock.Any(), int64(123)).Return(nil)
+	svc := NewAccountDeletionService(repo, userRepo, artRepo, nil,
+		time.Hour*24*15, logger.NewNoOpLogger())
+	assert.NoError(t, svc.Execute(context.Background(), 123))
+}
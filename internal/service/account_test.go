@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestAccountService_Credit(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.AccountRepository
+
+		credit  domain.Credit
+		wantErr error
+	}{
+		{
+			name: "打赏分账，清算账户出钱，借贷平衡",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().AddActivities(gomock.Any(), "reward", int64(1), []domain.AccountActivity{
+					{AccountType: domain.AccountTypeSystem, Amt: 10, Currency: "CNY"},
+					{Uid: 123, AccountType: domain.AccountTypeReward, Amt: 90, Currency: "CNY"},
+					{AccountType: domain.AccountTypeClearing, Amt: -100, Currency: "CNY"},
+				}).Return(nil)
+				return repo
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					// 系统账户传了 uid 也不管
+					{Uid: 456, AccountType: domain.AccountTypeSystem, Amt: 10, Currency: "CNY"},
+					{Uid: 123, AccountType: domain.AccountTypeReward, Amt: 90},
+				},
+			},
+		},
+		{
+			name: "平台不抽成，跳过金额为 0 的",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().AddActivities(gomock.Any(), "reward", int64(1), []domain.AccountActivity{
+					{Uid: 123, AccountType: domain.AccountTypeReward, Amt: 100, Currency: "CNY"},
+					{AccountType: domain.AccountTypeClearing, Amt: -100, Currency: "CNY"},
+				}).Return(nil)
+				return repo
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					{AccountType: domain.AccountTypeSystem, Amt: 0},
+					{Uid: 123, AccountType: domain.AccountTypeReward, Amt: 100},
+				},
+			},
+		},
+		{
+			name: "重复入账",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().AddActivities(gomock.Any(), "reward", int64(1), gomock.Any()).
+					Return(repository.ErrDuplicateTransaction)
+				return repo
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					{Uid: 123, AccountType: domain.AccountTypeReward, Amt: 100},
+				},
+			},
+			wantErr: ErrDuplicateCredit,
+		},
+		{
+			name: "不能直接操作清算账户",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				return repomocks.NewMockAccountRepository(ctrl)
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					{AccountType: domain.AccountTypeClearing, Amt: 100},
+				},
+			},
+			wantErr: ErrInvalidCredit,
+		},
+		{
+			name: "个人账户没有 uid",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				return repomocks.NewMockAccountRepository(ctrl)
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					{AccountType: domain.AccountTypeReward, Amt: 100},
+				},
+			},
+			wantErr: ErrInvalidCredit,
+		},
+		{
+			name: "负数金额",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				return repomocks.NewMockAccountRepository(ctrl)
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					{Uid: 123, AccountType: domain.AccountTypeReward, Amt: -100},
+				},
+			},
+			wantErr: ErrInvalidCredit,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAccountService(tc.mock(ctrl))
+			err := svc.Credit(context.Background(), tc.credit)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
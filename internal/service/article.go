@@ -10,9 +10,12 @@ import (
 	"webook/pkg/logger"
 )
 
+var ErrArticleNotFound = repository.ErrArticleNotFound
+
 //go:generate mockgen -source=./article.go -package=svcmocks -destination=./mocks/article.mock.go ArticleService
 type ArticleService interface {
 	Save(ctx context.Context, art domain.Article) (int64, error)
+	// Publish 作者被禁止发表文章的话返回 ErrUserBanned
 	Publish(ctx context.Context, art domain.Article) (int64, error)
 	Withdraw(ctx context.Context, uid int64, id int64) error
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
@@ -20,11 +23,15 @@ type ArticleService interface {
 	GetPubById(ctx context.Context, id, uid int64) (domain.Article, error)
 	// ListPub 找线上库的数据
 	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error)
+	// ListPubByIds 按照 id 批量找线上库的数据
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
 }
 
 type articleService struct {
 	repo     repository.ArticleRepository
 	producer article.Producer
+	modSvc   ModerationService
+	banSvc   BanService
 
 	// V1写法专用
 	readerRepo repository.ArticleReaderRepository
@@ -43,20 +50,87 @@ func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, e
 }
 
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
+	err := a.banSvc.Check(ctx, art.Author.Id, domain.BanScopePublish)
+	if err != nil {
+		return art.Id, err
+	}
 	art.Status = domain.ArticleStatusPublished
-	return a.repo.Sync(ctx, art)
+	content := art.Title + "\n" + art.Content
+	hits := a.modSvc.Check(content)
+	if len(hits) > 0 {
+		// 命中敏感词，先不上线，等人工审核
+		art.Status = domain.ArticleStatusPending
+	}
+	id, err := a.repo.Sync(ctx, art)
+	if err != nil {
+		return id, err
+	}
+	if len(hits) == 0 {
+		a.producePublished(id, art.Author.Id)
+		return id, nil
+	}
+	err = a.modSvc.Submit(ctx, domain.ModerationTask{
+		Biz:     "article",
+		BizId:   id,
+		Uid:     art.Author.Id,
+		Content: content,
+		Hits:    hits,
+	})
+	if err != nil {
+		// 文章已经保存了，只是进审核队列失败，不影响作者
+		a.l.Error("提交文章审核失败",
+			logger.Int64("aid", id),
+			logger.Error(err))
+	}
+	return id, nil
 }
 
 func (a *articleService) Withdraw(ctx context.Context, uid int64, id int64) error {
-	return a.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	err := a.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	if err != nil {
+		return err
+	}
+	er := a.producer.ProduceWithdrawnEvent(article.WithdrawnEvent{
+		Aid: id,
+		Uid: uid,
+	})
+	if er != nil {
+		// 文章已经撤回了，信息流里面读的时候还会再过滤一遍
+		a.l.Error("发送 WithdrawnEvent 失败",
+			logger.Int64("aid", id),
+			logger.Error(er))
+	}
+	return nil
+}
+
+// producePublished 通知下游文章上线了，失败了也不影响发表
+func (a *articleService) producePublished(id, uid int64) {
+	err := a.producer.ProducePublishedEvent(article.PublishedEvent{
+		Aid:   id,
+		Uid:   uid,
+		Ctime: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		a.l.Error("发送 PublishedEvent 失败",
+			logger.Int64("aid", id),
+			logger.Error(err))
+	}
 }
 
 func (a *articleService) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error) {
 	return a.repo.ListPub(ctx, start, offset, limit)
 }
 
+func (a *articleService) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	return a.repo.ListPubByIds(ctx, ids)
+}
+
 func (a *articleService) GetPubById(ctx context.Context, id, uid int64) (domain.Article, error) {
 	res, err := a.repo.GetPubById(ctx, id)
+	if err == nil && res.Status != domain.ArticleStatusPublished {
+		// 撤回的、等待审核的、审核没通过的，读者都看不到
+		return domain.Article{}, ErrArticleNotFound
+	}
 	go func() {
 		if err == nil {
 			// 在这里发一个消息
@@ -84,10 +158,14 @@ func (a *articleService) GetByAuthor(ctx context.Context, uid int64, offset int,
 	return a.repo.GetByAuthor(ctx, uid, offset, limit)
 }
 
-func NewArticleService(repo repository.ArticleRepository, producer article.Producer) ArticleService {
+func NewArticleService(repo repository.ArticleRepository, producer article.Producer,
+	modSvc ModerationService, banSvc BanService, l logger.LoggerV1) ArticleService {
 	return &articleService{
 		repo:     repo,
 		producer: producer,
+		modSvc:   modSvc,
+		banSvc:   banSvc,
+		l:        l,
 	}
 }
 
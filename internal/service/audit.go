@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+// AuditService 管理员的每一个操作都要记下来，方便事后追查
+//
+//go:generate mockgen -source=./audit.go -package=svcmocks -destination=./mocks/audit.mock.go AuditService
+type AuditService interface {
+	Record(ctx context.Context, l domain.AuditLog) error
+	// List operator 为 0 就是所有管理员的
+	List(ctx context.Context, operator int64, offset, limit int) ([]domain.AuditLog, error)
+}
+
+type auditService struct {
+	repo repository.AuditLogRepository
+}
+
+func NewAuditService(repo repository.AuditLogRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (svc *auditService) Record(ctx context.Context, l domain.AuditLog) error {
+	return svc.repo.Create(ctx, l)
+}
+
+func (svc *auditService) List(ctx context.Context, operator int64, offset, limit int) ([]domain.AuditLog, error) {
+	return svc.repo.List(ctx, operator, offset, limit)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrUserBanned      = errors.New("账号已经被封禁")
+	ErrInvalidBanScope = errors.New("封禁范围不合法")
+	ErrUserBanNotFound = repository.ErrUserBanNotFound
+)
+
+//go:generate mockgen -source=./ban.go -package=svcmocks -destination=./mocks/ban.mock.go BanService
+type BanService interface {
+	// Ban 已经封禁了就覆盖原来的封禁。全部封禁的时候已经发表的文章都会下线
+	Ban(ctx context.Context, b domain.UserBan) error
+	Unban(ctx context.Context, uid int64) error
+	// Find 没有被封禁返回 ErrUserBanNotFound
+	Find(ctx context.Context, uid int64) (domain.UserBan, error)
+	// Check 禁止了 scope 这种操作的话返回 ErrUserBanned
+	Check(ctx context.Context, uid int64, scope domain.BanScope) error
+}
+
+type banService struct {
+	repo     repository.UserBanRepository
+	artRepo  repository.ArticleRepository
+	producer article.Producer
+	l        logger.LoggerV1
+}
+
+func NewBanService(repo repository.UserBanRepository, artRepo repository.ArticleRepository,
+	producer article.Producer, l logger.LoggerV1) BanService {
+	return &banService{
+		repo:     repo,
+		artRepo:  artRepo,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (svc *banService) Ban(ctx context.Context, b domain.UserBan) error {
+	if !b.Scope.Valid() {
+		return ErrInvalidBanScope
+	}
+	err := svc.repo.Save(ctx, b)
+	if err != nil {
+		return err
+	}
+	if b.Scope != domain.BanScopeAll {
+		return nil
+	}
+	return takeDownArticles(ctx, svc.artRepo, svc.producer, svc.l, b.Uid)
+}
+
+// takeDownArticles 把作者已经发表的文章都下线，封禁和注销账号都会用到。
+// 先把已经发表的文章都找出来再下线，
+// 边翻页边改状态的话，改过的文章顺序会变，可能漏掉
+func takeDownArticles(ctx context.Context, artRepo repository.ArticleRepository,
+	producer article.Producer, l logger.LoggerV1, uid int64) error {
+	const batchSize = 100
+	var ids []int64
+	for offset := 0; ; offset += batchSize {
+		arts, err := artRepo.GetByAuthor(ctx, uid, offset, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, art := range arts {
+			if art.Status == domain.ArticleStatusPublished {
+				ids = append(ids, art.Id)
+			}
+		}
+		if len(arts) < batchSize {
+			break
+		}
+	}
+	for _, id := range ids {
+		err := artRepo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+		if err != nil {
+			return err
+		}
+		err = producer.ProduceWithdrawnEvent(article.WithdrawnEvent{Aid: id, Uid: uid})
+		if err != nil {
+			// 跟作者自己撤回一样，信息流读的时候还会再过滤一遍
+			l.Error("发送 WithdrawnEvent 失败",
+				logger.Int64("aid", id),
+				logger.Error(err))
+		}
+	}
+	return nil
+}
+
+func (svc *banService) Unban(ctx context.Context, uid int64) error {
+	return svc.repo.Delete(ctx, uid)
+}
+
+func (svc *banService) Find(ctx context.Context, uid int64) (domain.UserBan, error) {
+	return svc.repo.Find(ctx, uid)
+}
+
+func (svc *banService) Check(ctx context.Context, uid int64, scope domain.BanScope) error {
+	b, err := svc.repo.Find(ctx, uid)
+	switch err {
+	case nil:
+		if b.Restricts(scope, time.Now()) {
+			return ErrUserBanned
+		}
+		return nil
+	case repository.ErrUserBanNotFound:
+		return nil
+	default:
+		return err
+	}
+}
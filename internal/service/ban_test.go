@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	evtmocks "webook/internal/events/article/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestBanService_Check(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		ban  domain.UserBan
+		err  error
+
+		scope   domain.BanScope
+		wantErr error
+	}{
+		{
+			name:    "没有被封禁",
+			err:     repository.ErrUserBanNotFound,
+			scope:   domain.BanScopeLogin,
+			wantErr: nil,
+		},
+		{
+			name:    "永久禁止发表",
+			ban:     domain.UserBan{Uid: 123, Scope: domain.BanScopePublish},
+			scope:   domain.BanScopePublish,
+			wantErr: ErrUserBanned,
+		},
+		{
+			name:  "禁止发表不影响登录",
+			ban:   domain.UserBan{Uid: 123, Scope: domain.BanScopePublish},
+			scope: domain.BanScopeLogin,
+		},
+		{
+			name:    "全部封禁",
+			ban:     domain.UserBan{Uid: 123, Scope: domain.BanScopeAll, Expire: now.Add(time.Hour)},
+			scope:   domain.BanScopeComment,
+			wantErr: ErrUserBanned,
+		},
+		{
+			name:  "已经过期",
+			ban:   domain.UserBan{Uid: 123, Scope: domain.BanScopeAll, Expire: now.Add(-time.Hour)},
+			scope: domain.BanScopeLogin,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockUserBanRepository(ctrl)
+			repo.EXPECT().Find(gomock.Any(), int64(123)).Return(tc.ban, tc.err)
+			svc := NewBanService(repo, nil, nil, logger.NewNoOpLogger())
+			err := svc.Check(context.Background(), 123, tc.scope)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestBanService_Ban(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserBanRepository,
+			repository.ArticleRepository, article.Producer)
+
+		ban     domain.UserBan
+		wantErr error
+	}{
+		{
+			name: "全部封禁，已经发表的文章下线",
+			mock: func(ctrl *gomock.Controller) (repository.UserBanRepository,
+				repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockUserBanRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+				artRepo.EXPECT().GetByAuthor(gomock.Any(), int64(123), 0, 100).Return([]domain.Article{
+					{Id: 1, Status: domain.ArticleStatusPublished},
+					{Id: 2, Status: domain.ArticleStatusUnpublished},
+					{Id: 3, Status: domain.ArticleStatusPublished},
+				}, nil)
+				artRepo.EXPECT().SyncStatus(gomock.Any(), int64(123), int64(1), domain.ArticleStatus(domain.ArticleStatusPrivate)).Return(nil)
+				artRepo.EXPECT().SyncStatus(gomock.Any(), int64(123), int64(3), domain.ArticleStatus(domain.ArticleStatusPrivate)).Return(nil)
+				producer.EXPECT().ProduceWithdrawnEvent(article.WithdrawnEvent{Aid: 1, Uid: 123}).Return(nil)
+				producer.EXPECT().ProduceWithdrawnEvent(article.WithdrawnEvent{Aid: 3, Uid: 123}).Return(nil)
+				return repo, artRepo, producer
+			},
+			ban: domain.UserBan{Uid: 123, Reason: "广告", Scope: domain.BanScopeAll},
+		},
+		{
+			name: "只禁止评论，文章不动",
+			mock: func(ctrl *gomock.Controller) (repository.UserBanRepository,
+				repository.ArticleRepository, article.Producer) {
+				repo := repomocks.NewMockUserBanRepository(ctrl)
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			ban: domain.UserBan{Uid: 123, Reason: "骂人", Scope: domain.BanScopeComment},
+		},
+		{
+			name: "范围不合法",
+			mock: func(ctrl *gomock.Controller) (repository.UserBanRepository,
+				repository.ArticleRepository, article.Producer) {
+				return repomocks.NewMockUserBanRepository(ctrl),
+					repomocks.NewMockArticleRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			ban:     domain.UserBan{Uid: 123, Reason: "骂人"},
+			wantErr: ErrInvalidBanScope,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo, producer := tc.mock(ctrl)
+			svc := NewBanService(repo, artRepo, producer, logger.NewNoOpLogger())
+			err := svc.Ban(context.Background(), tc.ban)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
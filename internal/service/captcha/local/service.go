@@ -0,0 +1,18 @@
+package local
+
+import (
+	"context"
+)
+
+// Service 本地开发用，token 跟配置的一样就算通过
+type Service struct {
+	token string
+}
+
+func NewService(token string) *Service {
+	return &Service{token: token}
+}
+
+func (s *Service) Verify(ctx context.Context, token string, ip string) (bool, error) {
+	return token != "" && token == s.token, nil
+}
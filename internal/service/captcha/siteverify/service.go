@@ -0,0 +1,59 @@
+package siteverify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Service reCAPTCHA、hCaptcha 和 Turnstile 的服务端校验接口都是一样的：
+// 表单提交 secret、response 和 remoteip，返回的 JSON 里面有 success
+type Service struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+func NewService(verifyURL, secret string) *Service {
+	return &Service{
+		verifyURL: verifyURL,
+		secret:    secret,
+		client:    &http.Client{Timeout: time.Second * 3},
+	}
+}
+
+func (s *Service) Verify(ctx context.Context, token string, ip string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	form := url.Values{}
+	form.Set("secret", s.secret)
+	form.Set("response", token)
+	form.Set("remoteip", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.verifyURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("人机验证服务返回了 %d", resp.StatusCode)
+	}
+	var res struct {
+		Success bool `json:"success"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return false, err
+	}
+	return res.Success, nil
+}
@@ -0,0 +1,10 @@
+package captcha
+
+import "context"
+
+// Service 校验前端人机验证拿到的 token，屏蔽具体是哪家的验证码
+//
+//go:generate mockgen -source=./types.go -package=captchamocks -destination=./mocks/captcha.mock.go Service
+type Service interface {
+	Verify(ctx context.Context, token string, ip string) (bool, error)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"webook/internal/repository"
+	"webook/internal/service/email"
 	"webook/internal/service/sms"
 )
 
@@ -14,17 +15,22 @@ var ErrCodeSendTooMany = repository.ErrCodeSendTooMany
 type CodeService interface {
 	Send(ctx context.Context, biz, phone string) error
 	Verify(ctx context.Context, biz, phone, inputCode string) (bool, error)
+	// SendEmail 通过邮件发验证码，跟短信验证码一样有频率和次数限制
+	SendEmail(ctx context.Context, biz, email string) error
+	VerifyEmail(ctx context.Context, biz, email, inputCode string) (bool, error)
 }
 
 type codeService struct {
-	repo repository.CodeRepository
-	sms  sms.Service
+	repo  repository.CodeRepository
+	sms   sms.Service
+	email email.Service
 }
 
-func NewCodeService(repo repository.CodeRepository, sms sms.Service) CodeService {
+func NewCodeService(repo repository.CodeRepository, sms sms.Service, emailSvc email.Service) CodeService {
 	return &codeService{
-		repo: repo,
-		sms:  sms,
+		repo:  repo,
+		sms:   sms,
+		email: emailSvc,
 	}
 }
 
@@ -50,6 +56,45 @@ func (svc *codeService) Verify(ctx context.Context, biz, phone, inputCode string
 	return ok, err
 }
 
+func (svc *codeService) SendEmail(ctx context.Context, biz, addr string) error {
+	code := svc.generate()
+	// 邮箱里面有 @，跟手机号的 key 不会冲突
+	err := svc.repo.Set(ctx, biz, addr, code)
+	if err != nil {
+		return err
+	}
+	mail, err := email.Render(email.TplVerifyCode, map[string]any{
+		"Code":    code,
+		"Purpose": codePurpose(biz),
+		"Minutes": 10,
+	})
+	if err != nil {
+		return err
+	}
+	mail.To = []string{addr}
+	return svc.email.Send(ctx, mail)
+}
+
+func (svc *codeService) VerifyEmail(ctx context.Context, biz, addr, inputCode string) (bool, error) {
+	return svc.Verify(ctx, biz, addr, inputCode)
+}
+
+// codePurpose 邮件里面告诉用户这个验证码是干什么的
+func codePurpose(biz string) string {
+	switch biz {
+	case "email_verify":
+		return "验证邮箱"
+	case "login":
+		return "登录"
+	case "reset_password":
+		return "重置密码"
+	case "bind":
+		return "绑定账号"
+	default:
+		return "进行安全验证"
+	}
+}
+
 func (svc *codeService) generate() string {
 	// 0-999999
 	code := rand.Intn(1000000)
@@ -1,10 +1,82 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"strings"
 	"testing"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/internal/service/email"
+	emailmocks "webook/internal/service/email/mocks"
+	smsmocks "webook/internal/service/sms/mocks"
 )
 
 func TestCodeGenerate(t *testing.T) {
 	t.Log(fmt.Sprintf("%06d", 1))
 }
+
+func TestCodeService_SendEmail(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CodeRepository, email.Service)
+
+		wantErr error
+	}{
+		{
+			name: "发送成功，邮件里面的验证码跟存起来的一样",
+			mock: func(ctrl *gomock.Controller) (repository.CodeRepository, email.Service) {
+				repo := repomocks.NewMockCodeRepository(ctrl)
+				emailSvc := emailmocks.NewMockService(ctrl)
+				var code string
+				repo.EXPECT().Set(gomock.Any(), "email_verify", "123@qq.com", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, biz, addr, c string) error {
+						code = c
+						return nil
+					})
+				emailSvc.EXPECT().Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, mail email.Mail) error {
+						assert.Equal(t, []string{"123@qq.com"}, mail.To)
+						assert.Contains(t, mail.Subject, code)
+						assert.True(t, strings.Contains(mail.Body, "验证邮箱"))
+						return nil
+					})
+				return repo, emailSvc
+			},
+		},
+		{
+			name: "发送太频繁",
+			mock: func(ctrl *gomock.Controller) (repository.CodeRepository, email.Service) {
+				repo := repomocks.NewMockCodeRepository(ctrl)
+				repo.EXPECT().Set(gomock.Any(), "email_verify", "123@qq.com", gomock.Any()).
+					Return(ErrCodeSendTooMany)
+				return repo, emailmocks.NewMockService(ctrl)
+			},
+			wantErr: ErrCodeSendTooMany,
+		},
+		{
+			name: "邮件发送失败",
+			mock: func(ctrl *gomock.Controller) (repository.CodeRepository, email.Service) {
+				repo := repomocks.NewMockCodeRepository(ctrl)
+				emailSvc := emailmocks.NewMockService(ctrl)
+				repo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				emailSvc.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("mock smtp 错误"))
+				return repo, emailSvc
+			},
+			wantErr: errors.New("mock smtp 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, emailSvc := tc.mock(ctrl)
+			svc := NewCodeService(repo, smsmocks.NewMockService(ctrl), emailSvc)
+			err := svc.SendEmail(context.Background(), "email_verify", "123@qq.com")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrCommentNotFound    = repository.ErrCommentNotFound
+	ErrCommentPermission  = errors.New("只能删除自己的评论")
+	ErrInvalidCommentTree = errors.New("回复的评论不属于同一个资源")
+)
+
+//go:generate mockgen -source=./comment.go -package=svcmocks -destination=./mocks/comment.mock.go CommentService
+type CommentService interface {
+	// GetCommentList 根评论，按照 id 降序，minId 是上一批的最小 ID
+	GetCommentList(ctx context.Context, biz string, bizId, minId, limit int64) ([]domain.Comment, error)
+	// GetMoreReplies 某个根评论下的回复，按照 id 升序，maxId 是上一批的最大 ID
+	GetMoreReplies(ctx context.Context, rid, maxId, limit int64) ([]domain.Comment, error)
+	CreateComment(ctx context.Context, c domain.Comment) (int64, error)
+	// DeleteComment 删除评论，连带子评论一起删除，只能删除自己的评论
+	DeleteComment(ctx context.Context, uid, id int64) error
+}
+
+type commentService struct {
+	repo     repository.CommentRepository
+	modSvc   ModerationService
+	producer activity.Producer
+	l        logger.LoggerV1
+}
+
+func NewCommentService(repo repository.CommentRepository, modSvc ModerationService,
+	producer activity.Producer, l logger.LoggerV1) CommentService {
+	return &commentService{
+		repo:     repo,
+		modSvc:   modSvc,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (c *commentService) GetCommentList(ctx context.Context, biz string, bizId, minId, limit int64) ([]domain.Comment, error) {
+	return c.repo.FindByBiz(ctx, biz, bizId, minId, limit)
+}
+
+func (c *commentService) GetMoreReplies(ctx context.Context, rid, maxId, limit int64) ([]domain.Comment, error) {
+	return c.repo.GetMoreReplies(ctx, rid, maxId, limit)
+}
+
+func (c *commentService) CreateComment(ctx context.Context, cmt domain.Comment) (int64, error) {
+	hits := c.modSvc.Check(cmt.Content)
+	cmt.Status = domain.CommentStatusNormal
+	if len(hits) > 0 {
+		// 命中敏感词，先不展示，等人工审核
+		cmt.Status = domain.CommentStatusPending
+	}
+	id, err := c.create(ctx, &cmt)
+	if err != nil {
+		return 0, err
+	}
+	if len(hits) == 0 {
+		// 等待审核的评论不通知
+		c.produceCommentEvent(id, cmt)
+		return id, nil
+	}
+	err = c.modSvc.Submit(ctx, domain.ModerationTask{
+		Biz:     "comment",
+		BizId:   id,
+		Uid:     cmt.Commentator.Id,
+		Content: cmt.Content,
+		Hits:    hits,
+	})
+	if err != nil {
+		c.l.Error("提交评论审核失败",
+			logger.Int64("cid", id),
+			logger.Error(err))
+	}
+	return id, nil
+}
+
+func (c *commentService) produceCommentEvent(id int64, cmt domain.Comment) {
+	cmt.Id = id
+	err := c.producer.ProduceCommentEvent(newCommentEvent(cmt))
+	if err != nil {
+		c.l.Error("发送评论消息失败",
+			logger.Int64("cid", id),
+			logger.Error(err))
+	}
+}
+
+// newCommentEvent 回复的话 cmt.ParentComment 里面要带上父评论的作者
+func newCommentEvent(cmt domain.Comment) activity.CommentEvent {
+	evt := activity.CommentEvent{
+		Cid:     cmt.Id,
+		Biz:     cmt.Biz,
+		BizId:   cmt.BizId,
+		Uid:     cmt.Commentator.Id,
+		Content: cmt.Content,
+	}
+	if cmt.ParentComment != nil {
+		evt.ParentUid = cmt.ParentComment.Commentator.Id
+	}
+	return evt
+}
+
+// create 会把父评论的作者填到 cmt 里面
+func (c *commentService) create(ctx context.Context, cmt *domain.Comment) (int64, error) {
+	if cmt.ParentComment == nil || cmt.ParentComment.Id == 0 {
+		// 根评论
+		cmt.ParentComment = nil
+		cmt.RootComment = nil
+		return c.repo.CreateComment(ctx, *cmt)
+	}
+	// 回复，根评论以父评论为准，不相信调用方
+	parent, err := c.repo.FindById(ctx, cmt.ParentComment.Id)
+	if err != nil {
+		return 0, err
+	}
+	if parent.Biz != cmt.Biz || parent.BizId != cmt.BizId {
+		return 0, ErrInvalidCommentTree
+	}
+	cmt.ParentComment = &domain.Comment{Id: parent.Id, Commentator: parent.Commentator}
+	if parent.RootComment != nil {
+		cmt.RootComment = &domain.Comment{Id: parent.RootComment.Id}
+	} else {
+		cmt.RootComment = &domain.Comment{Id: parent.Id}
+	}
+	return c.repo.CreateComment(ctx, *cmt)
+}
+
+func (c *commentService) DeleteComment(ctx context.Context, uid, id int64) error {
+	cmt, err := c.repo.FindById(ctx, id)
+	if err == ErrCommentNotFound {
+		// 已经删掉了
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cmt.Commentator.Id != uid {
+		return ErrCommentPermission
+	}
+	return c.repo.DeleteComment(ctx, cmt)
+}
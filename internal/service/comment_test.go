@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	evtmocks "webook/internal/events/activity/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestCommentService_CreateComment(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository, ModerationService)
+
+		cmt domain.Comment
+		// 等待审核的评论不发消息
+		wantEvt *activity.CommentEvent
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "根评论，忽略调用方传的根评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, ModerationService) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				modSvc := svcmocks.NewMockModerationService(ctrl)
+				modSvc.EXPECT().Check("评论").Return(nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator: domain.User{Id: 123},
+					Biz:         "article",
+					BizId:       1,
+					Content:     "评论",
+				}).Return(int64(10), nil)
+				return repo, modSvc
+			},
+			cmt: domain.Comment{
+				Commentator: domain.User{Id: 123},
+				Biz:         "article",
+				BizId:       1,
+				Content:     "评论",
+				RootComment: &domain.Comment{Id: 3},
+			},
+			wantEvt: &activity.CommentEvent{Cid: 10, Biz: "article", BizId: 1, Uid: 123, Content: "评论"},
+			wantId:  10,
+		},
+		{
+			name: "回复根评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, ModerationService) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				modSvc := svcmocks.NewMockModerationService(ctrl)
+				modSvc.EXPECT().Check("回复").Return(nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Comment{Id: 1, Biz: "article", BizId: 1,
+						Commentator: domain.User{Id: 456}}, nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator:   domain.User{Id: 123},
+					Biz:           "article",
+					BizId:         1,
+					Content:       "回复",
+					RootComment:   &domain.Comment{Id: 1},
+					ParentComment: &domain.Comment{Id: 1, Commentator: domain.User{Id: 456}},
+				}).Return(int64(11), nil)
+				return repo, modSvc
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "回复",
+				ParentComment: &domain.Comment{Id: 1},
+			},
+			wantEvt: &activity.CommentEvent{Cid: 11, Biz: "article", BizId: 1, Uid: 123,
+				ParentUid: 456, Content: "回复"},
+			wantId: 11,
+		},
+		{
+			name: "回复别人的回复，根评论沿用父评论的",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, ModerationService) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				modSvc := svcmocks.NewMockModerationService(ctrl)
+				modSvc.EXPECT().Check("回复").Return(nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).
+					Return(domain.Comment{Id: 11, Biz: "article", BizId: 1,
+						RootComment: &domain.Comment{Id: 1}}, nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator:   domain.User{Id: 123},
+					Biz:           "article",
+					BizId:         1,
+					Content:       "回复",
+					RootComment:   &domain.Comment{Id: 1},
+					ParentComment: &domain.Comment{Id: 11},
+				}).Return(int64(12), nil)
+				return repo, modSvc
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "回复",
+				RootComment:   &domain.Comment{Id: 11},
+				ParentComment: &domain.Comment{Id: 11},
+			},
+			wantEvt: &activity.CommentEvent{Cid: 12, Biz: "article", BizId: 1, Uid: 123, Content: "回复"},
+			wantId:  12,
+		},
+		{
+			name: "父评论属于别的资源",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, ModerationService) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				modSvc := svcmocks.NewMockModerationService(ctrl)
+				modSvc.EXPECT().Check("回复").Return(nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Comment{Id: 1, Biz: "article", BizId: 2}, nil)
+				return repo, modSvc
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "回复",
+				ParentComment: &domain.Comment{Id: 1},
+			},
+			wantErr: ErrInvalidCommentTree,
+		},
+		{
+			name: "命中敏感词，等待审核",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, ModerationService) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				modSvc := svcmocks.NewMockModerationService(ctrl)
+				modSvc.EXPECT().Check("赌博").Return([]string{"赌博"})
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator: domain.User{Id: 123},
+					Biz:         "article",
+					BizId:       1,
+					Content:     "赌博",
+					Status:      domain.CommentStatusPending,
+				}).Return(int64(13), nil)
+				modSvc.EXPECT().Submit(gomock.Any(), domain.ModerationTask{
+					Biz:     "comment",
+					BizId:   13,
+					Uid:     123,
+					Content: "赌博",
+					Hits:    []string{"赌博"},
+				}).Return(nil)
+				return repo, modSvc
+			},
+			cmt: domain.Comment{
+				Commentator: domain.User{Id: 123},
+				Biz:         "article",
+				BizId:       1,
+				Content:     "赌博",
+			},
+			wantId: 13,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, modSvc := tc.mock(ctrl)
+			producer := evtmocks.NewMockProducer(ctrl)
+			if tc.wantEvt != nil {
+				producer.EXPECT().ProduceCommentEvent(*tc.wantEvt).Return(nil)
+			}
+			svc := NewCommentService(repo, modSvc, producer, logger.NewNoOpLogger())
+			id, err := svc.CreateComment(context.Background(), tc.cmt)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CommentRepository
+
+		uid     int64
+		id      int64
+		wantErr error
+	}{
+		{
+			name: "删除自己的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				cmt := domain.Comment{Id: 1, Commentator: domain.User{Id: 123}}
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(cmt, nil)
+				repo.EXPECT().DeleteComment(gomock.Any(), cmt).Return(nil)
+				return repo
+			},
+			uid: 123,
+			id:  1,
+		},
+		{
+			name: "删除别人的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Comment{Id: 1, Commentator: domain.User{Id: 456}}, nil)
+				return repo
+			},
+			uid:     123,
+			id:      1,
+			wantErr: ErrCommentPermission,
+		},
+		{
+			name: "没有 uid 不能删除别人的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Comment{Id: 1, Commentator: domain.User{Id: 456}}, nil)
+				return repo
+			},
+			id:      1,
+			wantErr: ErrCommentPermission,
+		},
+		{
+			name: "评论已经不存在",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Comment{}, repository.ErrCommentNotFound)
+				return repo
+			},
+			uid: 123,
+			id:  1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCommentService(tc.mock(ctrl), svcmocks.NewMockModerationService(ctrl),
+				evtmocks.NewMockProducer(ctrl), logger.NewNoOpLogger())
+			err := svc.DeleteComment(context.Background(), tc.uid, tc.id)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/storage"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrDataExportInProgress  = errors.New("上一次导出还没有完成")
+	ErrDataExportTooFrequent = errors.New("导出太频繁")
+	ErrDataExportNotFound    = repository.ErrDataExportNotFound
+	// ErrDataExportNotReady 还没打包好，或者已经过了下载期限
+	ErrDataExportNotReady = errors.New("导出文件不能下载")
+)
+
+const (
+	// 两次导出至少隔这么久
+	dataExportInterval = time.Hour * 24
+	// 打包好之后多久可以下载，过了就删掉
+	dataExportRetention = time.Hour * 24 * 7
+	// 开始打包之后这么久还没有结果，就当作实例挂了，重新打包
+	dataExportTimeout = time.Minute * 30
+	// 点赞、收藏、阅读历史各自最多导出这么多条
+	dataExportMaxRecords = 10000
+)
+
+//go:generate mockgen -source=./data_export.go -package=svcmocks -destination=./mocks/data_export.mock.go DataExportService
+type DataExportService interface {
+	// Request 申请导出，打包是定时任务异步做的，好了之后发系统通知
+	Request(ctx context.Context, uid int64) (domain.DataExport, error)
+	// Latest 最近一次导出，没有导出过返回 ErrDataExportNotFound
+	Latest(ctx context.Context, uid int64) (domain.DataExport, error)
+	// Download 最近一次导出的 zip，不能下载返回 ErrDataExportNotReady
+	Download(ctx context.Context, uid int64) ([]byte, error)
+	// FindRunnable 下面这几个是给定时任务用的
+	FindRunnable(ctx context.Context, limit int) ([]domain.DataExport, error)
+	// Build 打包一个导出，别的实例已经在打包了就什么都不做
+	Build(ctx context.Context, e domain.DataExport) error
+	// CleanExpired 删掉过了下载期限的文件，返回清理了几个
+	CleanExpired(ctx context.Context, limit int) (int, error)
+}
+
+type dataExportService struct {
+	repo        repository.DataExportRepository
+	userRepo    repository.UserRepository
+	artRepo     repository.ArticleRepository
+	intrRepo    repository.InteractiveRepository
+	historyRepo repository.HistoryRecordRepository
+	notifySvc   NotificationService
+	storage     storage.Storage
+	l           logger.LoggerV1
+}
+
+func NewDataExportService(repo repository.DataExportRepository, userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository, intrRepo repository.InteractiveRepository,
+	historyRepo repository.HistoryRecordRepository, notifySvc NotificationService,
+	storage storage.Storage, l logger.LoggerV1) DataExportService {
+	return &dataExportService{
+		repo:        repo,
+		userRepo:    userRepo,
+		artRepo:     artRepo,
+		intrRepo:    intrRepo,
+		historyRepo: historyRepo,
+		notifySvc:   notifySvc,
+		storage:     storage,
+		l:           l,
+	}
+}
+
+func (svc *dataExportService) Request(ctx context.Context, uid int64) (domain.DataExport, error) {
+	latest, err := svc.repo.FindLatest(ctx, uid)
+	switch err {
+	case nil:
+		switch latest.Status {
+		case domain.DataExportStatusPending, domain.DataExportStatusRunning:
+			return latest, ErrDataExportInProgress
+		case domain.DataExportStatusFailed:
+			// 失败了可以马上重试
+		default:
+			if time.Since(latest.Ctime) < dataExportInterval {
+				return latest, ErrDataExportTooFrequent
+			}
+		}
+	case repository.ErrDataExportNotFound:
+	default:
+		return domain.DataExport{}, err
+	}
+	id, err := svc.repo.Create(ctx, uid)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	now := time.Now()
+	return domain.DataExport{
+		Id:     id,
+		Uid:    uid,
+		Status: domain.DataExportStatusPending,
+		Ctime:  now,
+		Utime:  now,
+	}, nil
+}
+
+func (svc *dataExportService) Latest(ctx context.Context, uid int64) (domain.DataExport, error) {
+	return svc.repo.FindLatest(ctx, uid)
+}
+
+func (svc *dataExportService) Download(ctx context.Context, uid int64) ([]byte, error) {
+	e, err := svc.repo.FindLatest(ctx, uid)
+	switch err {
+	case nil:
+	case repository.ErrDataExportNotFound:
+		return nil, ErrDataExportNotReady
+	default:
+		return nil, err
+	}
+	if e.Status != domain.DataExportStatusDone || time.Since(e.Utime) > dataExportRetention {
+		return nil, ErrDataExportNotReady
+	}
+	data, err := svc.storage.Get(ctx, e.FileKey)
+	if err == storage.ErrObjectNotFound {
+		return nil, ErrDataExportNotReady
+	}
+	return data, err
+}
+
+func (svc *dataExportService) FindRunnable(ctx context.Context, limit int) ([]domain.DataExport, error) {
+	return svc.repo.FindRunnable(ctx, time.Now().Add(-dataExportTimeout), limit)
+}
+
+func (svc *dataExportService) Build(ctx context.Context, e domain.DataExport) error {
+	ok, err := svc.repo.Claim(ctx, e.Id, time.Now().Add(-dataExportTimeout))
+	if err != nil || !ok {
+		return err
+	}
+	data, err := svc.pack(ctx, e.Uid)
+	if err == nil {
+		key := fmt.Sprintf("exports/%d/%d.zip", e.Uid, e.Id)
+		err = svc.storage.Put(ctx, key, data)
+		if err == nil {
+			err = svc.repo.UpdateStatus(ctx, e.Id, domain.DataExportStatusDone, key)
+		}
+	}
+	if err != nil {
+		er := svc.repo.UpdateStatus(ctx, e.Id, domain.DataExportStatusFailed, "")
+		if er != nil {
+			// 超时之后定时任务还会再捡起来
+			svc.l.Error("标记导出失败出错", logger.Int64("id", e.Id), logger.Error(er))
+		}
+		svc.notify(ctx, e, "你的数据导出失败了，请稍后重新申请")
+		return err
+	}
+	svc.notify(ctx, e, "你的数据导出已经完成，7 天之内可以下载")
+	return nil
+}
+
+// notify 通知失败不影响导出，用户自己也能查到进度
+func (svc *dataExportService) notify(ctx context.Context, e domain.DataExport, content string) {
+	err := svc.notifySvc.Notify(ctx, domain.Notification{
+		Uid:     e.Uid,
+		Type:    domain.NotificationTypeSystem,
+		Biz:     "data_export",
+		BizId:   e.Id,
+		Content: content,
+	}, 0)
+	if err != nil {
+		svc.l.Error("发送数据导出通知失败", logger.Int64("id", e.Id), logger.Error(err))
+	}
+}
+
+func (svc *dataExportService) CleanExpired(ctx context.Context, limit int) (int, error) {
+	es, err := svc.repo.FindDoneBefore(ctx, time.Now().Add(-dataExportRetention), limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range es {
+		err = svc.storage.Delete(ctx, e.FileKey)
+		if err != nil {
+			return 0, err
+		}
+		err = svc.repo.UpdateStatus(ctx, e.Id, domain.DataExportStatusExpired, "")
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(es), nil
+}
+
+// pack 每一类数据一个 JSON 文件，打成一个 zip
+func (svc *dataExportService) pack(ctx context.Context, uid int64) ([]byte, error) {
+	u, err := svc.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := svc.articles(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	likes, err := svc.intrRepo.LikedBizIds(ctx, "article", uid, dataExportMaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := svc.intrRepo.CollectedBizIds(ctx, "article", uid, dataExportMaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	history, err := svc.historyRepo.FindByUid(ctx, uid, time.UnixMilli(0), dataExportMaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	files := []struct {
+		name string
+		data any
+	}{
+		{name: "profile.json", data: exportProfile{
+			Id:          u.Id,
+			Email:       u.Email,
+			Phone:       u.Phone,
+			Nickname:    u.Nickname,
+			Birthday:    u.Birthday.Format(time.DateOnly),
+			Description: u.Description,
+			Ctime:       u.Ctime,
+		}},
+		{name: "articles.json", data: arts},
+		{name: "likes.json", data: likes},
+		{name: "collections.json", data: collections},
+		{name: "history.json", data: exportHistory(history)},
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := w.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(f.data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = w.Close()
+	return buf.Bytes(), err
+}
+
+// articles 制作库里面的草稿和线上库里面发表的版本都导出来
+func (svc *dataExportService) articles(ctx context.Context, uid int64) ([]exportArticle, error) {
+	const batchSize = 100
+	var res []exportArticle
+	for offset := 0; ; offset += batchSize {
+		arts, err := svc.artRepo.GetByAuthor(ctx, uid, offset, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, art := range arts {
+			ea := exportArticle{
+				Id:     art.Id,
+				Status: art.Status.ToUint8(),
+				Draft:  newExportRevision(art),
+			}
+			if art.Status != domain.ArticleStatusUnpublished {
+				pub, err := svc.artRepo.GetPubById(ctx, art.Id)
+				switch err {
+				case nil:
+					rev := newExportRevision(pub)
+					ea.Published = &rev
+				case repository.ErrArticleNotFound:
+				default:
+					return nil, err
+				}
+			}
+			res = append(res, ea)
+		}
+		if len(arts) < batchSize {
+			return res, nil
+		}
+	}
+}
+
+type exportProfile struct {
+	Id          int64     `json:"id"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	Nickname    string    `json:"nickname"`
+	Birthday    string    `json:"birthday"`
+	Description string    `json:"description"`
+	Ctime       time.Time `json:"ctime"`
+}
+
+type exportArticle struct {
+	Id     int64 `json:"id"`
+	Status uint8 `json:"status"`
+	// Draft 作者最新编辑的版本
+	Draft exportRevision `json:"draft"`
+	// Published 读者看到的版本，没有发表过就没有
+	Published *exportRevision `json:"published,omitempty"`
+}
+
+type exportRevision struct {
+	Title   string    `json:"title"`
+	Content string    `json:"content"`
+	Utime   time.Time `json:"utime"`
+}
+
+func newExportRevision(art domain.Article) exportRevision {
+	return exportRevision{Title: art.Title, Content: art.Content, Utime: art.Utime}
+}
+
+type exportHistoryRecord struct {
+	Biz   string    `json:"biz"`
+	BizId int64     `json:"biz_id"`
+	Utime time.Time `json:"utime"`
+}
+
+func exportHistory(rs []domain.HistoryRecord) []exportHistoryRecord {
+	res := make([]exportHistoryRecord, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, exportHistoryRecord{Biz: r.Biz, BizId: r.BizId, Utime: r.Utime})
+	}
+	return res
+}
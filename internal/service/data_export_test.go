@@ -0,0 +1,131 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	storagemocks "webook/internal/service/storage/mocks"
+	"webook/pkg/logger"
+)
+
+func TestDataExportService_Request(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.DataExportRepository
+
+		wantErr error
+	}{
+		{
+			name: "第一次导出",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				repo := repomocks.NewMockDataExportRepository(ctrl)
+				repo.EXPECT().FindLatest(gomock.Any(), int64(123)).
+					Return(domain.DataExport{}, repository.ErrDataExportNotFound)
+				repo.EXPECT().Create(gomock.Any(), int64(123)).Return(int64(1), nil)
+				return repo
+			},
+		},
+		{
+			name: "上一次还没打包完",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				repo := repomocks.NewMockDataExportRepository(ctrl)
+				repo.EXPECT().FindLatest(gomock.Any(), int64(123)).Return(domain.DataExport{
+					Id: 1, Uid: 123, Status: domain.DataExportStatusRunning, Ctime: time.Now(),
+				}, nil)
+				return repo
+			},
+			wantErr: ErrDataExportInProgress,
+		},
+		{
+			name: "一天之内导出过",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				repo := repomocks.NewMockDataExportRepository(ctrl)
+				repo.EXPECT().FindLatest(gomock.Any(), int64(123)).Return(domain.DataExport{
+					Id: 1, Uid: 123, Status: domain.DataExportStatusDone, Ctime: time.Now().Add(-time.Hour),
+				}, nil)
+				return repo
+			},
+			wantErr: ErrDataExportTooFrequent,
+		},
+		{
+			name: "上一次失败了可以马上重试",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				repo := repomocks.NewMockDataExportRepository(ctrl)
+				repo.EXPECT().FindLatest(gomock.Any(), int64(123)).Return(domain.DataExport{
+					Id: 1, Uid: 123, Status: domain.DataExportStatusFailed, Ctime: time.Now(),
+				}, nil)
+				repo.EXPECT().Create(gomock.Any(), int64(123)).Return(int64(2), nil)
+				return repo
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewDataExportService(tc.mock(ctrl), nil, nil, nil, nil, nil, nil, logger.NewNoOpLogger())
+			_, err := svc.Request(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestDataExportService_Build(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockDataExportRepository(ctrl)
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	intrRepo := repomocks.NewMockInteractiveRepository(ctrl)
+	historyRepo := repomocks.NewMockHistoryRecordRepository(ctrl)
+	notifySvc := svcmocks.NewMockNotificationService(ctrl)
+	store := storagemocks.NewMockStorage(ctrl)
+
+	e := domain.DataExport{Id: 1, Uid: 123, Status: domain.DataExportStatusPending}
+	repo.EXPECT().Claim(gomock.Any(), int64(1), gomock.Any()).Return(true, nil)
+	userRepo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{Id: 123, Nickname: "Tom"}, nil)
+	artRepo.EXPECT().GetByAuthor(gomock.Any(), int64(123), 0, 100).Return([]domain.Article{
+		{Id: 1, Title: "草稿", Status: domain.ArticleStatusUnpublished},
+		{Id: 2, Title: "改过的标题", Status: domain.ArticleStatusPublished},
+	}, nil)
+	artRepo.EXPECT().GetPubById(gomock.Any(), int64(2)).Return(domain.Article{Id: 2, Title: "发表的标题"}, nil)
+	intrRepo.EXPECT().LikedBizIds(gomock.Any(), "article", int64(123), gomock.Any()).Return([]int64{3}, nil)
+	intrRepo.EXPECT().CollectedBizIds(gomock.Any(), "article", int64(123), gomock.Any()).Return([]int64{4}, nil)
+	historyRepo.EXPECT().FindByUid(gomock.Any(), int64(123), gomock.Any(), gomock.Any()).
+		Return([]domain.HistoryRecord{{Biz: "article", BizId: 5, Uid: 123}}, nil)
+	var data []byte
+	store.EXPECT().Put(gomock.Any(), "exports/123/1.zip", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, d []byte) error {
+			data = d
+			return nil
+		})
+	repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.DataExportStatusDone, "exports/123/1.zip").Return(nil)
+	notifySvc.EXPECT().Notify(gomock.Any(), gomock.Any(), int64(0)).
+		DoAndReturn(func(ctx context.Context, n domain.Notification, actor int64) error {
+			assert.Equal(t, int64(123), n.Uid)
+			assert.Equal(t, domain.NotificationTypeSystem, n.Type)
+			return nil
+		})
+
+	svc := NewDataExportService(repo, userRepo, artRepo, intrRepo, historyRepo, notifySvc, store, logger.NewNoOpLogger())
+	err := svc.Build(context.Background(), e)
+	require.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"profile.json", "articles.json", "likes.json",
+		"collections.json", "history.json"}, names)
+}
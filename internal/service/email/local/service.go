@@ -0,0 +1,37 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"webook/internal/service/email"
+)
+
+// Service 本地开发用，不真的发邮件，打日志或者写到目录里面方便打开看
+type Service struct {
+	// 为空就只打日志
+	dir string
+}
+
+func NewService(dir string) *Service {
+	return &Service{dir: dir}
+}
+
+func (s *Service) Send(ctx context.Context, mail email.Mail) error {
+	log.Println("发送邮件", mail.To, mail.Subject)
+	if s.dir == "" {
+		return nil
+	}
+	err := os.MkdirAll(s.dir, 0o755)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.html", time.Now().UnixNano(), strings.Join(mail.To, "_"))
+	content := fmt.Sprintf("<!-- To: %s -->\n<!-- Subject: %s -->\n%s",
+		strings.Join(mail.To, ", "), mail.Subject, mail.Body)
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(content), 0o644)
+}
@@ -0,0 +1,142 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+	"webook/internal/service/email"
+)
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// 发件人，比如 webook <noreply@webook.com>
+	From string
+	// 465 端口一般是直接 TLS，587 端口是先明文再 STARTTLS
+	ImplicitTLS bool
+}
+
+type Service struct {
+	cfg  Config
+	auth smtp.Auth
+}
+
+func NewService(cfg Config) *Service {
+	return &Service{
+		cfg:  cfg,
+		auth: smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host),
+	}
+}
+
+func (s *Service) Send(ctx context.Context, mail email.Mail) error {
+	if len(mail.To) == 0 {
+		return fmt.Errorf("邮件没有收件人")
+	}
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok && !s.cfg.ImplicitTLS {
+		err = client.StartTLS(&tls.Config{ServerName: s.cfg.Host})
+		if err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		err = client.Auth(s.auth)
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(s.address(s.cfg.From))
+	if err != nil {
+		return err
+	}
+	for _, to := range mail.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(s.message(mail))
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *Service) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, fmt.Sprintf("%d", s.cfg.Port))
+	dialer := &net.Dialer{Timeout: time.Second * 5}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.cfg.ImplicitTLS {
+		conn, err = (&tls.Dialer{
+			NetDialer: dialer,
+			Config:    &tls.Config{ServerName: s.cfg.Host},
+		}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// address 从 "名字 <地址>" 里面拿出地址
+func (s *Service) address(from string) string {
+	start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">")
+	if start >= 0 && end > start {
+		return from[start+1 : end]
+	}
+	return from
+}
+
+func (s *Service) message(mail email.Mail) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + s.cfg.From + "\r\n")
+	buf.WriteString("To: " + strings.Join(mail.To, ", ") + "\r\n")
+	// 标题里面有中文，要编码
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", mail.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(mail.Body))
+	// 每行不能超过 76 个字符
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,32 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+)
+
+// 模板的名字，每个模板文件里面定义 名字.subject 和 名字.body 两部分
+const (
+	TplVerifyCode = "verify_code"
+)
+
+var (
+	//go:embed templates/*.html
+	templateFS embed.FS
+	templates  = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+)
+
+// Render 用模板生成标题和正文，收件人由调用方自己填
+func Render(tplName string, data any) (Mail, error) {
+	var subject, body bytes.Buffer
+	err := templates.ExecuteTemplate(&subject, tplName+".subject", data)
+	if err != nil {
+		return Mail{}, err
+	}
+	err = templates.ExecuteTemplate(&body, tplName+".body", data)
+	if err != nil {
+		return Mail{}, err
+	}
+	return Mail{Subject: subject.String(), Body: body.String()}, nil
+}
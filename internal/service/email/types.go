@@ -0,0 +1,17 @@
+package email
+
+import "context"
+
+// Mail 一封邮件，正文是 HTML
+type Mail struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Service 发送邮件的抽象，屏蔽 SMTP 还是别的渠道
+//
+//go:generate mockgen -source=./types.go -package=emailmocks -destination=./mocks/email.mock.go Service
+type Service interface {
+	Send(ctx context.Context, mail Mail) error
+}
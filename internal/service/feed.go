@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"math"
+	"sort"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+//go:generate mockgen -source=./feed.go -package=svcmocks -destination=./mocks/feed.mock.go FeedService
+type FeedService interface {
+	// PublishItem 文章上线，粉丝少的作者推到粉丝的收件箱，粉丝多的作者只写自己的发件箱
+	PublishItem(ctx context.Context, item domain.FeedItem) error
+	// WithdrawItem 文章撤回，从关注流里面拿掉
+	WithdrawItem(ctx context.Context, aid int64) error
+	// Following 关注流，cursor 是上一页返回的游标，第一页传零值。
+	// 返回下一页的游标，零值表示没有更多了
+	Following(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.Article, domain.FeedCursor, error)
+}
+
+type feedService struct {
+	repo      repository.FeedRepository
+	followSvc FollowRelationService
+	artSvc    ArticleService
+
+	// 粉丝数达到这个值就不推了，读的时候去拉
+	pullThreshold int64
+	// 推的时候每批处理多少个粉丝
+	batchSize int64
+	// 读的时候最多看多少个关注的人
+	maxFollowees int64
+}
+
+func NewFeedService(repo repository.FeedRepository, followSvc FollowRelationService,
+	artSvc ArticleService) FeedService {
+	return &feedService{
+		repo:          repo,
+		followSvc:     followSvc,
+		artSvc:        artSvc,
+		pullThreshold: 1000,
+		batchSize:     200,
+		maxFollowees:  2000,
+	}
+}
+
+func (f *feedService) PublishItem(ctx context.Context, item domain.FeedItem) error {
+	statics, err := f.followSvc.GetFollowStatics(ctx, item.Uid)
+	if err != nil {
+		return err
+	}
+	if statics.Followers >= f.pullThreshold {
+		// 大 V 推一次要写太多收件箱，只写发件箱
+		return f.repo.PullItem(ctx, item)
+	}
+	var offset int64
+	for {
+		relations, err := f.followSvc.GetFollower(ctx, item.Uid, offset, f.batchSize)
+		if err != nil {
+			return err
+		}
+		uids := slice.Map[domain.FollowRelation, int64](relations, func(idx int, src domain.FollowRelation) int64 {
+			return src.Follower
+		})
+		if len(uids) > 0 {
+			err = f.repo.PushItem(ctx, uids, item)
+			if err != nil {
+				return err
+			}
+		}
+		if int64(len(relations)) < f.batchSize {
+			return nil
+		}
+		offset = offset + int64(len(relations))
+	}
+}
+
+func (f *feedService) WithdrawItem(ctx context.Context, aid int64) error {
+	return f.repo.DeleteByAid(ctx, aid)
+}
+
+func (f *feedService) Following(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.Article, domain.FeedCursor, error) {
+	if cursor.IsZero() {
+		// 第一页，现在这一毫秒上线的也要包含进来
+		cursor = domain.FeedCursor{Ctime: time.Now().UnixMilli(), Aid: math.MaxInt64}
+	}
+	followees, err := f.followees(ctx, uid)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	pushItems, err := f.repo.FindPushItems(ctx, uid, cursor, limit)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	// 有些作者粉丝多了之后才改成拉的，所以所有关注的人都要拉一遍
+	followeeIds := make([]int64, 0, len(followees))
+	for id := range followees {
+		followeeIds = append(followeeIds, id)
+	}
+	pullItems, err := f.repo.FindPullItems(ctx, followeeIds, cursor, limit)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+
+	items := f.merge(pushItems, pullItems, limit)
+	var next domain.FeedCursor
+	if len(items) == limit {
+		last := items[len(items)-1]
+		next = domain.FeedCursor{Ctime: last.Ctime.UnixMilli(), Aid: last.Aid}
+	}
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		// 推进来之后又取消关注了
+		if _, ok := followees[item.Uid]; ok {
+			ids = append(ids, item.Aid)
+		}
+	}
+	if len(ids) == 0 {
+		return []domain.Article{}, next, nil
+	}
+	arts, err := f.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	// 撤回的文章查不到，顺便就过滤掉了
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	res := make([]domain.Article, 0, len(ids))
+	for _, id := range ids {
+		if art, ok := artMap[id]; ok {
+			res = append(res, art)
+		}
+	}
+	return res, next, nil
+}
+
+// followees 关注的人，key 是 uid
+func (f *feedService) followees(ctx context.Context, uid int64) (map[int64]struct{}, error) {
+	res := make(map[int64]struct{})
+	var offset int64
+	for offset < f.maxFollowees {
+		relations, err := f.followSvc.GetFollowee(ctx, uid, offset, f.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range relations {
+			res[r.Followee] = struct{}{}
+		}
+		if int64(len(relations)) < f.batchSize {
+			break
+		}
+		offset = offset + int64(len(relations))
+	}
+	return res, nil
+}
+
+// merge 按照 (上线时间, aid) 倒序合并推和拉的结果，同一篇文章只留一条
+func (f *feedService) merge(pushItems, pullItems []domain.FeedItem, limit int) []domain.FeedItem {
+	items := make([]domain.FeedItem, 0, len(pushItems)+len(pullItems))
+	seen := make(map[int64]struct{}, cap(items))
+	for _, src := range [][]domain.FeedItem{pushItems, pullItems} {
+		for _, item := range src {
+			if _, ok := seen[item.Aid]; ok {
+				continue
+			}
+			seen[item.Aid] = struct{}{}
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Ctime.Equal(items[j].Ctime) {
+			return items[i].Ctime.After(items[j].Ctime)
+		}
+		return items[i].Aid > items[j].Aid
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+)
+
+func TestFeedService_PublishItem(t *testing.T) {
+	item := domain.FeedItem{Aid: 1, Uid: 123, Ctime: time.UnixMilli(100)}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository, FollowRelationService)
+
+		wantErr error
+	}{
+		{
+			name: "粉丝少，分批推到收件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, FollowRelationService) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followSvc := svcmocks.NewMockFollowRelationService(ctrl)
+				followSvc.EXPECT().GetFollowStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 3}, nil)
+				followSvc.EXPECT().GetFollower(gomock.Any(), int64(123), int64(0), int64(2)).
+					Return([]domain.FollowRelation{{Follower: 1}, {Follower: 2}}, nil)
+				followSvc.EXPECT().GetFollower(gomock.Any(), int64(123), int64(2), int64(2)).
+					Return([]domain.FollowRelation{{Follower: 3}}, nil)
+				repo.EXPECT().PushItem(gomock.Any(), []int64{1, 2}, item).Return(nil)
+				repo.EXPECT().PushItem(gomock.Any(), []int64{3}, item).Return(nil)
+				return repo, followSvc
+			},
+		},
+		{
+			name: "大 V 只写发件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, FollowRelationService) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followSvc := svcmocks.NewMockFollowRelationService(ctrl)
+				followSvc.EXPECT().GetFollowStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 10}, nil)
+				repo.EXPECT().PullItem(gomock.Any(), item).Return(nil)
+				return repo, followSvc
+			},
+		},
+		{
+			name: "查询粉丝失败",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, FollowRelationService) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followSvc := svcmocks.NewMockFollowRelationService(ctrl)
+				followSvc.EXPECT().GetFollowStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 3}, nil)
+				followSvc.EXPECT().GetFollower(gomock.Any(), int64(123), int64(0), int64(2)).
+					Return(nil, errors.New("mock db 错误"))
+				return repo, followSvc
+			},
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, followSvc := tc.mock(ctrl)
+			svc := &feedService{
+				repo:          repo,
+				followSvc:     followSvc,
+				pullThreshold: 10,
+				batchSize:     2,
+			}
+			err := svc.PublishItem(context.Background(), item)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestFeedService_Following(t *testing.T) {
+	const uid = 123
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockFeedRepository(ctrl)
+	followSvc := svcmocks.NewMockFollowRelationService(ctrl)
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+
+	// 关注了 1 和 2，其中 2 是大 V
+	followSvc.EXPECT().GetFollowee(gomock.Any(), int64(uid), int64(0), int64(10)).
+		Return([]domain.FollowRelation{{Followee: 1}, {Followee: 2}}, nil)
+	cursor := domain.FeedCursor{Ctime: 1000, Aid: 50}
+	repo.EXPECT().FindPushItems(gomock.Any(), int64(uid), cursor, 3).
+		Return([]domain.FeedItem{
+			{Aid: 11, Uid: 1, Ctime: time.UnixMilli(900)},
+			// 已经取消关注了
+			{Aid: 31, Uid: 3, Ctime: time.UnixMilli(800)},
+			{Aid: 12, Uid: 1, Ctime: time.UnixMilli(500)},
+		}, nil)
+	// 21 和 22 是同一毫秒上线的
+	repo.EXPECT().FindPullItems(gomock.Any(), gomock.Any(), cursor, 3).
+		Return([]domain.FeedItem{
+			{Aid: 22, Uid: 2, Ctime: time.UnixMilli(700)},
+			{Aid: 21, Uid: 2, Ctime: time.UnixMilli(700)},
+		}, nil)
+	// 22 已经撤回了
+	artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{11, 22}).
+		Return([]domain.Article{{Id: 11}}, nil)
+
+	svc := &feedService{
+		repo:         repo,
+		followSvc:    followSvc,
+		artSvc:       artSvc,
+		batchSize:    10,
+		maxFollowees: 100,
+	}
+	arts, next, err := svc.Following(context.Background(), uid, cursor, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Article{{Id: 11}}, arts)
+	// 下一页从 22 之后开始，同一毫秒的 21 不会丢
+	assert.Equal(t, domain.FeedCursor{Ctime: 700, Aid: 22}, next)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrFollowRelationNotFound = repository.ErrFollowRelationNotFound
+	ErrFollowSelf             = errors.New("不能关注自己")
+)
+
+//go:generate mockgen -source=./follow.go -package=svcmocks -destination=./mocks/follow.mock.go FollowRelationService
+type FollowRelationService interface {
+	// GetFollowee 关注列表
+	GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error)
+	// GetFollower 粉丝列表
+	GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error)
+	// FollowInfo 没有关注返回 ErrFollowRelationNotFound
+	FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error)
+	Follow(ctx context.Context, follower, followee int64) error
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type followRelationService struct {
+	repo     repository.FollowRepository
+	producer activity.Producer
+	l        logger.LoggerV1
+}
+
+func NewFollowRelationService(repo repository.FollowRepository, producer activity.Producer,
+	l logger.LoggerV1) FollowRelationService {
+	return &followRelationService{
+		repo:     repo,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (f *followRelationService) GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error) {
+	return f.repo.GetFollowee(ctx, follower, offset, limit)
+}
+
+func (f *followRelationService) GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error) {
+	return f.repo.GetFollower(ctx, followee, offset, limit)
+}
+
+func (f *followRelationService) FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error) {
+	return f.repo.FollowInfo(ctx, follower, followee)
+}
+
+func (f *followRelationService) Follow(ctx context.Context, follower, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	err := f.repo.AddFollowRelation(ctx, domain.FollowRelation{
+		Follower: follower,
+		Followee: followee,
+	})
+	if err != nil {
+		return err
+	}
+	er := f.producer.ProduceFollowEvent(activity.FollowEvent{
+		Follower: follower,
+		Followee: followee,
+	})
+	if er != nil {
+		f.l.Error("发送关注消息失败",
+			logger.Int64("follower", follower),
+			logger.Int64("followee", followee),
+			logger.Error(er))
+	}
+	return nil
+}
+
+func (f *followRelationService) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return f.repo.InactiveFollowRelation(ctx, follower, followee)
+}
+
+func (f *followRelationService) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return f.repo.GetFollowStatics(ctx, uid)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	evtmocks "webook/internal/events/activity/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestFollowRelationService_Follow(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.FollowRepository
+
+		follower  int64
+		followee  int64
+		wantEvent bool
+		wantErr   error
+	}{
+		{
+			name: "关注成功",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), domain.FollowRelation{
+					Follower: 123,
+					Followee: 456,
+				}).Return(nil)
+				return repo
+			},
+			follower:  123,
+			followee:  456,
+			wantEvent: true,
+		},
+		{
+			name: "不能关注自己",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				return repomocks.NewMockFollowRepository(ctrl)
+			},
+			follower: 123,
+			followee: 123,
+			wantErr:  ErrFollowSelf,
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), gomock.Any()).
+					Return(errors.New("mock db 错误"))
+				return repo
+			},
+			follower: 123,
+			followee: 456,
+			wantErr:  errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			producer := evtmocks.NewMockProducer(ctrl)
+			if tc.wantEvent {
+				producer.EXPECT().ProduceFollowEvent(activity.FollowEvent{
+					Follower: tc.follower,
+					Followee: tc.followee,
+				}).Return(nil)
+			}
+			svc := NewFollowRelationService(tc.mock(ctrl), producer, logger.NewNoOpLogger())
+			err := svc.Follow(context.Background(), tc.follower, tc.followee)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
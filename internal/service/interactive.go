@@ -4,7 +4,9 @@ import (
 	"context"
 	"golang.org/x/sync/errgroup"
 	"webook/internal/domain"
+	"webook/internal/events/activity"
 	"webook/internal/repository"
+	"webook/pkg/logger"
 )
 
 //go:generate mockgen -source=./interactive.go -package=svcmocks -destination=./mocks/interactive.mock.go InteractiveService
@@ -19,10 +21,16 @@ type InteractiveService interface {
 	// Get 获取文章点赞数收藏数阅读数
 	Get(ctx context.Context, biz string, bizId int64, uid int64) (domain.Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error)
+	// LikedBizIds 用户最近点赞过的资源
+	LikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
+	// CollectedBizIds 用户最近收藏过的资源
+	CollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error)
 }
 
 type interactiveService struct {
-	repo repository.InteractiveRepository
+	repo     repository.InteractiveRepository
+	producer activity.Producer
+	l        logger.LoggerV1
 }
 
 func (i *interactiveService) GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error) {
@@ -37,6 +45,14 @@ func (i *interactiveService) GetByIds(ctx context.Context, biz string, ids []int
 	return res, nil
 }
 
+func (i *interactiveService) LikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	return i.repo.LikedBizIds(ctx, biz, uid, limit)
+}
+
+func (i *interactiveService) CollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	return i.repo.CollectedBizIds(ctx, biz, uid, limit)
+}
+
 func (i *interactiveService) Get(ctx context.Context, biz string, bizId int64, uid int64) (domain.Interactive, error) {
 	intr, err := i.repo.Get(ctx, biz, bizId)
 	if err != nil {
@@ -63,15 +79,36 @@ func (i *interactiveService) Collect(ctx context.Context, biz string, bizId int6
 }
 
 func (i *interactiveService) Like(ctx context.Context, biz string, id int64, uid int64) error {
-	return i.repo.IncrLike(ctx, biz, id, uid)
+	err := i.repo.IncrLike(ctx, biz, id, uid)
+	if err != nil {
+		return err
+	}
+	// 通知发不出去不影响点赞
+	er := i.producer.ProduceLikeEvent(activity.LikeEvent{
+		Biz:   biz,
+		BizId: id,
+		Uid:   uid,
+	})
+	if er != nil {
+		i.l.Error("发送点赞消息失败",
+			logger.String("biz", biz),
+			logger.Int64("bizId", id),
+			logger.Error(er))
+	}
+	return nil
 }
 
 func (i *interactiveService) CancelLike(ctx context.Context, biz string, id int64, uid int64) error {
 	return i.repo.DecrLike(ctx, biz, id, uid)
 }
 
-func NewInteractiveService(repo repository.InteractiveRepository) InteractiveService {
-	return &interactiveService{repo: repo}
+func NewInteractiveService(repo repository.InteractiveRepository, producer activity.Producer,
+	l logger.LoggerV1) InteractiveService {
+	return &interactiveService{
+		repo:     repo,
+		producer: producer,
+		l:        l,
+	}
 }
 
 func (i *interactiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
@@ -13,6 +13,8 @@ type CronJobService interface {
 	Preempt(ctx context.Context) (domain.Job, error)
 	ResetNextTime(ctx context.Context, j domain.Job) error
 	// 这里也可以暴露Job整个的增删改查方法，让用户可以通过http接口对Job进行操作
+	// List 管理后台查看任务的调度情况
+	List(ctx context.Context, offset, limit int) ([]domain.Job, error)
 }
 
 type cronJobService struct {
@@ -68,3 +70,7 @@ func (c *cronJobService) refresh(id int64) {
 		c.l.Error("续约失败", logger.Error(err), logger.Int64("jid", id))
 	}
 }
+
+func (c *cronJobService) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	return c.repo.List(ctx, offset, limit)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/captcha"
+	"webook/pkg/logger"
+)
+
+const (
+	// 账号失败这么多次之后要人机验证，并且每次失败都要等一会儿才能再试
+	captchaAccountFails = 3
+	// 账号失败这么多次之后直接锁住
+	lockAccountFails = 10
+	lockDuration     = time.Minute * 15
+	// 同一个 IP 撞了很多账号
+	captchaIPFails = 20
+	blockIPFails   = 100
+	// 逐步加长的等待时间的上限
+	maxLoginDelay = time.Minute
+)
+
+var (
+	ErrAccountLocked    = errors.New("账号已经被临时锁定")
+	ErrLoginTooFrequent = errors.New("登录太频繁")
+	ErrCaptchaRequired  = errors.New("需要人机验证")
+)
+
+// LoginGuardService 防止暴力破解。account 用 EmailLoginAccount 这几个方法生成，
+// 不同的登录方式分开计数
+//
+//go:generate mockgen -source=./login_guard.go -package=svcmocks -destination=./mocks/login_guard.mock.go LoginGuardService
+type LoginGuardService interface {
+	// Check 在校验密码或者验证码之前调用，返回 ErrAccountLocked 或者 ErrLoginTooFrequent 的时候，
+	// 同时返回还要等多久
+	Check(ctx context.Context, account, ip, captchaToken string) (time.Duration, error)
+	// Fail 记一次失败，失败多了会锁账号
+	Fail(ctx context.Context, account, ip string) error
+	// Succeed 登录成功，之前的失败次数都不算了
+	Succeed(ctx context.Context, account string) error
+	// Unlock 管理员解锁这个用户所有的登录方式
+	Unlock(ctx context.Context, u domain.User, operator int64) error
+}
+
+func EmailLoginAccount(email string) string {
+	return "email:" + email
+}
+
+func PhoneLoginAccount(phone string) string {
+	return "phone:" + phone
+}
+
+// TwoFactorLoginAccount 两步验证的验证码也要防止被暴力破解
+func TwoFactorLoginAccount(uid int64) string {
+	return fmt.Sprintf("2fa:%d", uid)
+}
+
+type loginGuardService struct {
+	repo    repository.LoginAttemptRepository
+	captcha captcha.Service
+	l       logger.LoggerV1
+}
+
+func NewLoginGuardService(repo repository.LoginAttemptRepository, captcha captcha.Service,
+	l logger.LoggerV1) LoginGuardService {
+	return &loginGuardService{repo: repo, captcha: captcha, l: l}
+}
+
+func (svc *loginGuardService) Check(ctx context.Context, account, ip, captchaToken string) (time.Duration, error) {
+	a, err := svc.repo.Get(ctx, account, ip)
+	if err != nil {
+		return 0, err
+	}
+	if a.Locked > 0 {
+		if a.AccountFails >= lockAccountFails {
+			return a.Locked, ErrAccountLocked
+		}
+		return a.Locked, ErrLoginTooFrequent
+	}
+	if a.IPFails >= blockIPFails {
+		return 0, ErrLoginTooFrequent
+	}
+	if a.AccountFails < captchaAccountFails && a.IPFails < captchaIPFails {
+		return 0, nil
+	}
+	if captchaToken == "" {
+		return 0, ErrCaptchaRequired
+	}
+	ok, err := svc.captcha.Verify(ctx, captchaToken, ip)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrCaptchaRequired
+	}
+	return 0, nil
+}
+
+func (svc *loginGuardService) Fail(ctx context.Context, account, ip string) error {
+	a, err := svc.repo.IncrFail(ctx, account, ip)
+	if err != nil {
+		return err
+	}
+	svc.l.Warn("安全事件：登录失败",
+		logger.String("event", "login_failed"),
+		logger.String("account", account),
+		logger.String("ip", ip),
+		logger.Int64("accountFails", a.AccountFails),
+		logger.Int64("ipFails", a.IPFails))
+	if a.AccountFails < captchaAccountFails {
+		return nil
+	}
+	d := svc.lockDuration(a.AccountFails)
+	if a.AccountFails >= lockAccountFails {
+		svc.l.Warn("安全事件：账号被临时锁定",
+			logger.String("event", "account_locked"),
+			logger.String("account", account),
+			logger.String("ip", ip),
+			logger.Int64("accountFails", a.AccountFails))
+	}
+	return svc.repo.Lock(ctx, account, d)
+}
+
+// lockDuration 从 1 秒开始每次翻倍，失败太多次直接锁 15 分钟
+func (svc *loginGuardService) lockDuration(fails int64) time.Duration {
+	if fails >= lockAccountFails {
+		return lockDuration
+	}
+	d := time.Second << (fails - captchaAccountFails)
+	if d > maxLoginDelay {
+		return maxLoginDelay
+	}
+	return d
+}
+
+func (svc *loginGuardService) Succeed(ctx context.Context, account string) error {
+	return svc.repo.Clear(ctx, account)
+}
+
+func (svc *loginGuardService) Unlock(ctx context.Context, u domain.User, operator int64) error {
+	accounts := []string{TwoFactorLoginAccount(u.Id)}
+	if u.Email != "" {
+		accounts = append(accounts, EmailLoginAccount(u.Email))
+	}
+	if u.Phone != "" {
+		accounts = append(accounts, PhoneLoginAccount(u.Phone))
+	}
+	for _, account := range accounts {
+		err := svc.repo.Clear(ctx, account)
+		if err != nil {
+			return err
+		}
+	}
+	svc.l.Warn("安全事件：管理员解锁账号",
+		logger.String("event", "account_unlocked"),
+		logger.Int64("uid", u.Id),
+		logger.Int64("operator", operator))
+	return nil
+}
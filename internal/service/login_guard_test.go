@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/internal/service/captcha"
+	captchamocks "webook/internal/service/captcha/mocks"
+	"webook/pkg/logger"
+)
+
+func TestLoginGuardService_Check(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, captcha.Service)
+
+		captcha  string
+		wantWait time.Duration
+		wantErr  error
+	}{
+		{
+			name: "没有失败过",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, captcha.Service) {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), "email:123@qq.com", "127.0.0.1").
+					Return(domain.LoginAttempts{}, nil)
+				return repo, captchamocks.NewMockService(ctrl)
+			},
+		},
+		{
+			name: "账号被锁住了",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, captcha.Service) {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), "email:123@qq.com", "127.0.0.1").
+					Return(domain.LoginAttempts{AccountFails: 10, Locked: time.Minute * 10}, nil)
+				return repo, captchamocks.NewMockService(ctrl)
+			},
+			wantWait: time.Minute * 10,
+			wantErr:  ErrAccountLocked,
+		},
+		{
+			name: "还在等待时间里面",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, captcha.Service) {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), "email:123@qq.com", "127.0.0.1").
+					Return(domain.LoginAttempts{AccountFails: 4, Locked: time.Second * 2}, nil)
+				return repo, captchamocks.NewMockService(ctrl)
+			},
+			wantWait: time.Second * 2,
+			wantErr:  ErrLoginTooFrequent,
+		},
+		{
+			name: "失败多了没带人机验证",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, captcha.Service) {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), "email:123@qq.com", "127.0.0.1").
+					Return(domain.LoginAttempts{AccountFails: 3}, nil)
+				return repo, captchamocks.NewMockService(ctrl)
+			},
+			wantErr: ErrCaptchaRequired,
+		},
+		{
+			name: "同一个 IP 撞了很多账号，人机验证通过",
+			mock: func(ctrl *gomock.Controller) (repository.LoginAttemptRepository, captcha.Service) {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Get(gomock.Any(), "email:123@qq.com", "127.0.0.1").
+					Return(domain.LoginAttempts{IPFails: 30}, nil)
+				c := captchamocks.NewMockService(ctrl)
+				c.EXPECT().Verify(gomock.Any(), "token", "127.0.0.1").Return(true, nil)
+				return repo, c
+			},
+			captcha: "token",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, c := tc.mock(ctrl)
+			svc := NewLoginGuardService(repo, c, logger.NewNoOpLogger())
+			wait, err := svc.Check(context.Background(), EmailLoginAccount("123@qq.com"), "127.0.0.1", tc.captcha)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantWait, wait)
+		})
+	}
+}
+
+func TestLoginGuardService_Fail(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.LoginAttemptRepository
+	}{
+		{
+			name: "前几次不用等",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().IncrFail(gomock.Any(), "phone:15212345678", "127.0.0.1").
+					Return(domain.LoginAttempts{AccountFails: 2, IPFails: 2}, nil)
+				return repo
+			},
+		},
+		{
+			name: "等待时间翻倍",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().IncrFail(gomock.Any(), "phone:15212345678", "127.0.0.1").
+					Return(domain.LoginAttempts{AccountFails: 5, IPFails: 5}, nil)
+				repo.EXPECT().Lock(gomock.Any(), "phone:15212345678", time.Second*4).Return(nil)
+				return repo
+			},
+		},
+		{
+			name: "失败太多次直接锁住",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().IncrFail(gomock.Any(), "phone:15212345678", "127.0.0.1").
+					Return(domain.LoginAttempts{AccountFails: 10, IPFails: 10}, nil)
+				repo.EXPECT().Lock(gomock.Any(), "phone:15212345678", time.Minute*15).Return(nil)
+				return repo
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewLoginGuardService(tc.mock(ctrl), captchamocks.NewMockService(ctrl), logger.NewNoOpLogger())
+			err := svc.Fail(context.Background(), PhoneLoginAccount("15212345678"), "127.0.0.1")
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./account.go -package=svcmocks -destination=./mocks/account.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountService is a mock of AccountService interface.
+type MockAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountServiceMockRecorder is the mock recorder for MockAccountService.
+type MockAccountServiceMockRecorder struct {
+	mock *MockAccountService
+}
+
+// NewMockAccountService creates a new mock instance.
+func NewMockAccountService(ctrl *gomock.Controller) *MockAccountService {
+	mock := &MockAccountService{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Balance mocks base method.
+func (m *MockAccountService) Balance(ctx context.Context, uid int64) ([]domain.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balance", ctx, uid)
+	ret0, _ := ret[0].([]domain.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Balance indicates an expected call of Balance.
+func (mr *MockAccountServiceMockRecorder) Balance(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balance", reflect.TypeOf((*MockAccountService)(nil).Balance), ctx, uid)
+}
+
+// CheckBalanced mocks base method.
+func (m *MockAccountService) CheckBalanced(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckBalanced", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckBalanced indicates an expected call of CheckBalanced.
+func (mr *MockAccountServiceMockRecorder) CheckBalanced(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBalanced", reflect.TypeOf((*MockAccountService)(nil).CheckBalanced), ctx)
+}
+
+// Credit mocks base method.
+func (m *MockAccountService) Credit(ctx context.Context, c domain.Credit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockAccountServiceMockRecorder) Credit(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockAccountService)(nil).Credit), ctx, c)
+}
+
+// Freeze mocks base method.
+func (m *MockAccountService) Freeze(ctx context.Context, w domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Freeze", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Freeze indicates an expected call of Freeze.
+func (mr *MockAccountServiceMockRecorder) Freeze(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Freeze", reflect.TypeOf((*MockAccountService)(nil).Freeze), ctx, w)
+}
+
+// Settle mocks base method.
+func (m *MockAccountService) Settle(ctx context.Context, w domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Settle", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Settle indicates an expected call of Settle.
+func (mr *MockAccountServiceMockRecorder) Settle(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Settle", reflect.TypeOf((*MockAccountService)(nil).Settle), ctx, w)
+}
+
+// Statement mocks base method.
+func (m *MockAccountService) Statement(ctx context.Context, uid int64, offset, limit int) ([]domain.AccountActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statement", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.AccountActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockAccountServiceMockRecorder) Statement(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockAccountService)(nil).Statement), ctx, uid, offset, limit)
+}
+
+// Unfreeze mocks base method.
+func (m *MockAccountService) Unfreeze(ctx context.Context, w domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfreeze", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfreeze indicates an expected call of Unfreeze.
+func (mr *MockAccountServiceMockRecorder) Unfreeze(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfreeze", reflect.TypeOf((*MockAccountService)(nil).Unfreeze), ctx, w)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./account_deletion.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./account_deletion.go -package=svcmocks -destination=./mocks/account_deletion.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountDeletionService is a mock of AccountDeletionService interface.
+type MockAccountDeletionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountDeletionServiceMockRecorder is the mock recorder for MockAccountDeletionService.
+type MockAccountDeletionServiceMockRecorder struct {
+	mock *MockAccountDeletionService
+}
+
+// NewMockAccountDeletionService creates a new mock instance.
+func NewMockAccountDeletionService(ctrl *gomock.Controller) *MockAccountDeletionService {
+	mock := &MockAccountDeletionService{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionService) EXPECT() *MockAccountDeletionServiceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockAccountDeletionService) Cancel(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAccountDeletionServiceMockRecorder) Cancel(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAccountDeletionService)(nil).Cancel), ctx, uid)
+}
+
+// Execute mocks base method.
+func (m *MockAccountDeletionService) Execute(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockAccountDeletionServiceMockRecorder) Execute(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockAccountDeletionService)(nil).Execute), ctx, uid)
+}
+
+// Find mocks base method.
+func (m *MockAccountDeletionService) Find(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, uid)
+	ret0, _ := ret[0].(domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockAccountDeletionServiceMockRecorder) Find(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAccountDeletionService)(nil).Find), ctx, uid)
+}
+
+// FindDue mocks base method.
+func (m *MockAccountDeletionService) FindDue(ctx context.Context, offset, limit int) ([]domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockAccountDeletionServiceMockRecorder) FindDue(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockAccountDeletionService)(nil).FindDue), ctx, offset, limit)
+}
+
+// Request mocks base method.
+func (m *MockAccountDeletionService) Request(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, uid)
+	ret0, _ := ret[0].(domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockAccountDeletionServiceMockRecorder) Request(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockAccountDeletionService)(nil).Request), ctx, uid)
+}
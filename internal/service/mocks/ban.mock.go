@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./ban.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./ban.go -package=svcmocks -destination=./mocks/ban.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBanService is a mock of BanService interface.
+type MockBanService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBanServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBanServiceMockRecorder is the mock recorder for MockBanService.
+type MockBanServiceMockRecorder struct {
+	mock *MockBanService
+}
+
+// NewMockBanService creates a new mock instance.
+func NewMockBanService(ctrl *gomock.Controller) *MockBanService {
+	mock := &MockBanService{ctrl: ctrl}
+	mock.recorder = &MockBanServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBanService) EXPECT() *MockBanServiceMockRecorder {
+	return m.recorder
+}
+
+// Ban mocks base method.
+func (m *MockBanService) Ban(ctx context.Context, b domain.UserBan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ban", ctx, b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ban indicates an expected call of Ban.
+func (mr *MockBanServiceMockRecorder) Ban(ctx, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ban", reflect.TypeOf((*MockBanService)(nil).Ban), ctx, b)
+}
+
+// Check mocks base method.
+func (m *MockBanService) Check(ctx context.Context, uid int64, scope domain.BanScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, uid, scope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockBanServiceMockRecorder) Check(ctx, uid, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockBanService)(nil).Check), ctx, uid, scope)
+}
+
+// Find mocks base method.
+func (m *MockBanService) Find(ctx context.Context, uid int64) (domain.UserBan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, uid)
+	ret0, _ := ret[0].(domain.UserBan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockBanServiceMockRecorder) Find(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockBanService)(nil).Find), ctx, uid)
+}
+
+// Unban mocks base method.
+func (m *MockBanService) Unban(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unban", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unban indicates an expected call of Unban.
+func (mr *MockBanServiceMockRecorder) Unban(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unban", reflect.TypeOf((*MockBanService)(nil).Unban), ctx, uid)
+}
@@ -54,6 +54,20 @@ func (mr *MockCodeServiceMockRecorder) Send(ctx, biz, phone any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCodeService)(nil).Send), ctx, biz, phone)
 }
 
+// SendEmail mocks base method.
+func (m *MockCodeService) SendEmail(ctx context.Context, biz, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendEmail", ctx, biz, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendEmail indicates an expected call of SendEmail.
+func (mr *MockCodeServiceMockRecorder) SendEmail(ctx, biz, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockCodeService)(nil).SendEmail), ctx, biz, email)
+}
+
 // Verify mocks base method.
 func (m *MockCodeService) Verify(ctx context.Context, biz, phone, inputCode string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -68,3 +82,18 @@ func (mr *MockCodeServiceMockRecorder) Verify(ctx, biz, phone, inputCode any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockCodeService)(nil).Verify), ctx, biz, phone, inputCode)
 }
+
+// VerifyEmail mocks base method.
+func (m *MockCodeService) VerifyEmail(ctx context.Context, biz, email, inputCode string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, biz, email, inputCode)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockCodeServiceMockRecorder) VerifyEmail(ctx, biz, email, inputCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockCodeService)(nil).VerifyEmail), ctx, biz, email, inputCode)
+}
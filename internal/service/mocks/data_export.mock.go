@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./data_export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./data_export.go -package=svcmocks -destination=./mocks/data_export.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExportService is a mock of DataExportService interface.
+type MockDataExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDataExportServiceMockRecorder is the mock recorder for MockDataExportService.
+type MockDataExportServiceMockRecorder struct {
+	mock *MockDataExportService
+}
+
+// NewMockDataExportService creates a new mock instance.
+func NewMockDataExportService(ctrl *gomock.Controller) *MockDataExportService {
+	mock := &MockDataExportService{ctrl: ctrl}
+	mock.recorder = &MockDataExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportService) EXPECT() *MockDataExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Build mocks base method.
+func (m *MockDataExportService) Build(ctx context.Context, e domain.DataExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Build", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Build indicates an expected call of Build.
+func (mr *MockDataExportServiceMockRecorder) Build(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockDataExportService)(nil).Build), ctx, e)
+}
+
+// CleanExpired mocks base method.
+func (m *MockDataExportService) CleanExpired(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanExpired", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CleanExpired indicates an expected call of CleanExpired.
+func (mr *MockDataExportServiceMockRecorder) CleanExpired(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanExpired", reflect.TypeOf((*MockDataExportService)(nil).CleanExpired), ctx, limit)
+}
+
+// Download mocks base method.
+func (m *MockDataExportService) Download(ctx context.Context, uid int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx, uid)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockDataExportServiceMockRecorder) Download(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockDataExportService)(nil).Download), ctx, uid)
+}
+
+// FindRunnable mocks base method.
+func (m *MockDataExportService) FindRunnable(ctx context.Context, limit int) ([]domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRunnable", ctx, limit)
+	ret0, _ := ret[0].([]domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRunnable indicates an expected call of FindRunnable.
+func (mr *MockDataExportServiceMockRecorder) FindRunnable(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRunnable", reflect.TypeOf((*MockDataExportService)(nil).FindRunnable), ctx, limit)
+}
+
+// Latest mocks base method.
+func (m *MockDataExportService) Latest(ctx context.Context, uid int64) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", ctx, uid)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockDataExportServiceMockRecorder) Latest(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockDataExportService)(nil).Latest), ctx, uid)
+}
+
+// Request mocks base method.
+func (m *MockDataExportService) Request(ctx context.Context, uid int64) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, uid)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockDataExportServiceMockRecorder) Request(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockDataExportService)(nil).Request), ctx, uid)
+}
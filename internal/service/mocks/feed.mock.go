@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./feed.go -package=svcmocks -destination=./mocks/feed.mock.go FeedService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedService is a mock of FeedService interface.
+type MockFeedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedServiceMockRecorder is the mock recorder for MockFeedService.
+type MockFeedServiceMockRecorder struct {
+	mock *MockFeedService
+}
+
+// NewMockFeedService creates a new mock instance.
+func NewMockFeedService(ctrl *gomock.Controller) *MockFeedService {
+	mock := &MockFeedService{ctrl: ctrl}
+	mock.recorder = &MockFeedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedService) EXPECT() *MockFeedServiceMockRecorder {
+	return m.recorder
+}
+
+// Following mocks base method.
+func (m *MockFeedService) Following(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.Article, domain.FeedCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Following", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(domain.FeedCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Following indicates an expected call of Following.
+func (mr *MockFeedServiceMockRecorder) Following(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Following", reflect.TypeOf((*MockFeedService)(nil).Following), ctx, uid, cursor, limit)
+}
+
+// PublishItem mocks base method.
+func (m *MockFeedService) PublishItem(ctx context.Context, item domain.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishItem indicates an expected call of PublishItem.
+func (mr *MockFeedServiceMockRecorder) PublishItem(ctx, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishItem", reflect.TypeOf((*MockFeedService)(nil).PublishItem), ctx, item)
+}
+
+// WithdrawItem mocks base method.
+func (m *MockFeedService) WithdrawItem(ctx context.Context, aid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawItem", ctx, aid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawItem indicates an expected call of WithdrawItem.
+func (mr *MockFeedServiceMockRecorder) WithdrawItem(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawItem", reflect.TypeOf((*MockFeedService)(nil).WithdrawItem), ctx, aid)
+}
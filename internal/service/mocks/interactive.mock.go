@@ -69,6 +69,21 @@ func (mr *MockInteractiveServiceMockRecorder) Collect(ctx, biz, bizId, cid, uid
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockInteractiveService)(nil).Collect), ctx, biz, bizId, cid, uid)
 }
 
+// CollectedBizIds mocks base method.
+func (m *MockInteractiveService) CollectedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectedBizIds", ctx, biz, uid, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectedBizIds indicates an expected call of CollectedBizIds.
+func (mr *MockInteractiveServiceMockRecorder) CollectedBizIds(ctx, biz, uid, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectedBizIds", reflect.TypeOf((*MockInteractiveService)(nil).CollectedBizIds), ctx, biz, uid, limit)
+}
+
 // Get mocks base method.
 func (m *MockInteractiveService) Get(ctx context.Context, biz string, bizId, uid int64) (domain.Interactive, error) {
 	m.ctrl.T.Helper()
@@ -126,3 +141,18 @@ func (mr *MockInteractiveServiceMockRecorder) Like(ctx, biz, id, uid any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockInteractiveService)(nil).Like), ctx, biz, id, uid)
 }
+
+// LikedBizIds mocks base method.
+func (m *MockInteractiveService) LikedBizIds(ctx context.Context, biz string, uid int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LikedBizIds", ctx, biz, uid, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LikedBizIds indicates an expected call of LikedBizIds.
+func (mr *MockInteractiveServiceMockRecorder) LikedBizIds(ctx, biz, uid, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikedBizIds", reflect.TypeOf((*MockInteractiveService)(nil).LikedBizIds), ctx, biz, uid, limit)
+}
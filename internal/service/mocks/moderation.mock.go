@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./moderation.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./moderation.go -package=svcmocks -destination=./mocks/moderation.mock.go ModerationService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModerationService is a mock of ModerationService interface.
+type MockModerationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockModerationServiceMockRecorder is the mock recorder for MockModerationService.
+type MockModerationServiceMockRecorder struct {
+	mock *MockModerationService
+}
+
+// NewMockModerationService creates a new mock instance.
+func NewMockModerationService(ctrl *gomock.Controller) *MockModerationService {
+	mock := &MockModerationService{ctrl: ctrl}
+	mock.recorder = &MockModerationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationService) EXPECT() *MockModerationServiceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockModerationService) Approve(ctx context.Context, id, reviewer int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, id, reviewer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockModerationServiceMockRecorder) Approve(ctx, id, reviewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockModerationService)(nil).Approve), ctx, id, reviewer)
+}
+
+// Check mocks base method.
+func (m *MockModerationService) Check(text string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", text)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockModerationServiceMockRecorder) Check(text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockModerationService)(nil).Check), text)
+}
+
+// ListPending mocks base method.
+func (m *MockModerationService) ListPending(ctx context.Context, offset, limit int) ([]domain.ModerationTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.ModerationTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockModerationServiceMockRecorder) ListPending(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockModerationService)(nil).ListPending), ctx, offset, limit)
+}
+
+// Reject mocks base method.
+func (m *MockModerationService) Reject(ctx context.Context, id, reviewer int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, id, reviewer, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockModerationServiceMockRecorder) Reject(ctx, id, reviewer, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockModerationService)(nil).Reject), ctx, id, reviewer, reason)
+}
+
+// Submit mocks base method.
+func (m *MockModerationService) Submit(ctx context.Context, t domain.ModerationTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockModerationServiceMockRecorder) Submit(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockModerationService)(nil).Submit), ctx, t)
+}
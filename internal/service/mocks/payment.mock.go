@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./payment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./payment.go -package=svcmocks -destination=./mocks/payment.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+	wechatpay "webook/pkg/wechatpay"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentService is a mock of PaymentService interface.
+type MockPaymentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentServiceMockRecorder is the mock recorder for MockPaymentService.
+type MockPaymentServiceMockRecorder struct {
+	mock *MockPaymentService
+}
+
+// NewMockPaymentService creates a new mock instance.
+func NewMockPaymentService(ctrl *gomock.Controller) *MockPaymentService {
+	mock := &MockPaymentService{ctrl: ctrl}
+	mock.recorder = &MockPaymentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentService) EXPECT() *MockPaymentServiceMockRecorder {
+	return m.recorder
+}
+
+// FindExpiredPayment mocks base method.
+func (m *MockPaymentService) FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredPayment", ctx, minId, limit, t)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredPayment indicates an expected call of FindExpiredPayment.
+func (mr *MockPaymentServiceMockRecorder) FindExpiredPayment(ctx, minId, limit, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredPayment", reflect.TypeOf((*MockPaymentService)(nil).FindExpiredPayment), ctx, minId, limit, t)
+}
+
+// GetPayment mocks base method.
+func (m *MockPaymentService) GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayment", ctx, bizTradeNO)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayment indicates an expected call of GetPayment.
+func (mr *MockPaymentServiceMockRecorder) GetPayment(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockPaymentService)(nil).GetPayment), ctx, bizTradeNO)
+}
+
+// HandleCallback mocks base method.
+func (m *MockPaymentService) HandleCallback(ctx context.Context, txn wechatpay.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleCallback", ctx, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleCallback indicates an expected call of HandleCallback.
+func (mr *MockPaymentServiceMockRecorder) HandleCallback(ctx, txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCallback", reflect.TypeOf((*MockPaymentService)(nil).HandleCallback), ctx, txn)
+}
+
+// Prepay mocks base method.
+func (m *MockPaymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepay", ctx, pmt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prepay indicates an expected call of Prepay.
+func (mr *MockPaymentServiceMockRecorder) Prepay(ctx, pmt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepay", reflect.TypeOf((*MockPaymentService)(nil).Prepay), ctx, pmt)
+}
+
+// SyncWechatInfo mocks base method.
+func (m *MockPaymentService) SyncWechatInfo(ctx context.Context, bizTradeNO string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncWechatInfo", ctx, bizTradeNO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncWechatInfo indicates an expected call of SyncWechatInfo.
+func (mr *MockPaymentServiceMockRecorder) SyncWechatInfo(ctx, bizTradeNO any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncWechatInfo", reflect.TypeOf((*MockPaymentService)(nil).SyncWechatInfo), ctx, bizTradeNO)
+}
+
+// MockNativeGateway is a mock of NativeGateway interface.
+type MockNativeGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockNativeGatewayMockRecorder
+	isgomock struct{}
+}
+
+// MockNativeGatewayMockRecorder is the mock recorder for MockNativeGateway.
+type MockNativeGatewayMockRecorder struct {
+	mock *MockNativeGateway
+}
+
+// NewMockNativeGateway creates a new mock instance.
+func NewMockNativeGateway(ctrl *gomock.Controller) *MockNativeGateway {
+	mock := &MockNativeGateway{ctrl: ctrl}
+	mock.recorder = &MockNativeGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNativeGateway) EXPECT() *MockNativeGatewayMockRecorder {
+	return m.recorder
+}
+
+// NativePrepay mocks base method.
+func (m *MockNativeGateway) NativePrepay(ctx context.Context, req wechatpay.PrepayRequest) (wechatpay.PrepayResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NativePrepay", ctx, req)
+	ret0, _ := ret[0].(wechatpay.PrepayResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NativePrepay indicates an expected call of NativePrepay.
+func (mr *MockNativeGatewayMockRecorder) NativePrepay(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NativePrepay", reflect.TypeOf((*MockNativeGateway)(nil).NativePrepay), ctx, req)
+}
+
+// QueryOrderByOutTradeNo mocks base method.
+func (m *MockNativeGateway) QueryOrderByOutTradeNo(ctx context.Context, outTradeNo string) (wechatpay.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryOrderByOutTradeNo", ctx, outTradeNo)
+	ret0, _ := ret[0].(wechatpay.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryOrderByOutTradeNo indicates an expected call of QueryOrderByOutTradeNo.
+func (mr *MockNativeGatewayMockRecorder) QueryOrderByOutTradeNo(ctx, outTradeNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryOrderByOutTradeNo", reflect.TypeOf((*MockNativeGateway)(nil).QueryOrderByOutTradeNo), ctx, outTradeNo)
+}
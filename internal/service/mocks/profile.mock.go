@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./profile.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./profile.go -package=svcmocks -destination=./mocks/profile.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProfileService is a mock of ProfileService interface.
+type MockProfileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProfileServiceMockRecorder is the mock recorder for MockProfileService.
+type MockProfileServiceMockRecorder struct {
+	mock *MockProfileService
+}
+
+// NewMockProfileService creates a new mock instance.
+func NewMockProfileService(ctrl *gomock.Controller) *MockProfileService {
+	mock := &MockProfileService{ctrl: ctrl}
+	mock.recorder = &MockProfileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileService) EXPECT() *MockProfileServiceMockRecorder {
+	return m.recorder
+}
+
+// PublicProfile mocks base method.
+func (m *MockProfileService) PublicProfile(ctx context.Context, handle string, offset, limit int) (domain.PublicProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicProfile", ctx, handle, offset, limit)
+	ret0, _ := ret[0].(domain.PublicProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublicProfile indicates an expected call of PublicProfile.
+func (mr *MockProfileServiceMockRecorder) PublicProfile(ctx, handle, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicProfile", reflect.TypeOf((*MockProfileService)(nil).PublicProfile), ctx, handle, offset, limit)
+}
+
+// SetAvatar mocks base method.
+func (m *MockProfileService) SetAvatar(ctx context.Context, uid int64, avatar string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAvatar", ctx, uid, avatar)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAvatar indicates an expected call of SetAvatar.
+func (mr *MockProfileServiceMockRecorder) SetAvatar(ctx, uid, avatar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAvatar", reflect.TypeOf((*MockProfileService)(nil).SetAvatar), ctx, uid, avatar)
+}
+
+// SetHandle mocks base method.
+func (m *MockProfileService) SetHandle(ctx context.Context, uid int64, handle string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHandle", ctx, uid, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHandle indicates an expected call of SetHandle.
+func (mr *MockProfileServiceMockRecorder) SetHandle(ctx, uid, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHandle", reflect.TypeOf((*MockProfileService)(nil).SetHandle), ctx, uid, handle)
+}
+
+// SetPrivacy mocks base method.
+func (m *MockProfileService) SetPrivacy(ctx context.Context, uid int64, privacy domain.ProfilePrivacy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrivacy", ctx, uid, privacy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrivacy indicates an expected call of SetPrivacy.
+func (mr *MockProfileServiceMockRecorder) SetPrivacy(ctx, uid, privacy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrivacy", reflect.TypeOf((*MockProfileService)(nil).SetPrivacy), ctx, uid, privacy)
+}
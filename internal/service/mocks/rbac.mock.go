@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./rbac.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./rbac.go -package=svcmocks -destination=./mocks/rbac.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRBACService is a mock of RBACService interface.
+type MockRBACService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRBACServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRBACServiceMockRecorder is the mock recorder for MockRBACService.
+type MockRBACServiceMockRecorder struct {
+	mock *MockRBACService
+}
+
+// NewMockRBACService creates a new mock instance.
+func NewMockRBACService(ctrl *gomock.Controller) *MockRBACService {
+	mock := &MockRBACService{ctrl: ctrl}
+	mock.recorder = &MockRBACServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRBACService) EXPECT() *MockRBACServiceMockRecorder {
+	return m.recorder
+}
+
+// Grant mocks base method.
+func (m *MockRBACService) Grant(ctx context.Context, uid int64, role string, operator int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", ctx, uid, role, operator)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockRBACServiceMockRecorder) Grant(ctx, uid, role, operator any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockRBACService)(nil).Grant), ctx, uid, role, operator)
+}
+
+// HasPermission mocks base method.
+func (m *MockRBACService) HasPermission(ctx context.Context, uid int64, perm string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPermission", ctx, uid, perm)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPermission indicates an expected call of HasPermission.
+func (mr *MockRBACServiceMockRecorder) HasPermission(ctx, uid, perm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPermission", reflect.TypeOf((*MockRBACService)(nil).HasPermission), ctx, uid, perm)
+}
+
+// Revoke mocks base method.
+func (m *MockRBACService) Revoke(ctx context.Context, uid int64, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, uid, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRBACServiceMockRecorder) Revoke(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRBACService)(nil).Revoke), ctx, uid, role)
+}
+
+// Roles mocks base method.
+func (m *MockRBACService) Roles(ctx context.Context, uid int64) (domain.Roles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Roles", ctx, uid)
+	ret0, _ := ret[0].(domain.Roles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Roles indicates an expected call of Roles.
+func (mr *MockRBACServiceMockRecorder) Roles(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*MockRBACService)(nil).Roles), ctx, uid)
+}
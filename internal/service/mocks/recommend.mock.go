@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./recommend.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./recommend.go -package=svcmocks -destination=./mocks/recommend.mock.go RecommendService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecommendService is a mock of RecommendService interface.
+type MockRecommendService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRecommendServiceMockRecorder is the mock recorder for MockRecommendService.
+type MockRecommendServiceMockRecorder struct {
+	mock *MockRecommendService
+}
+
+// NewMockRecommendService creates a new mock instance.
+func NewMockRecommendService(ctrl *gomock.Controller) *MockRecommendService {
+	mock := &MockRecommendService{ctrl: ctrl}
+	mock.recorder = &MockRecommendServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendService) EXPECT() *MockRecommendServiceMockRecorder {
+	return m.recorder
+}
+
+// Compute mocks base method.
+func (m *MockRecommendService) Compute(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compute", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compute indicates an expected call of Compute.
+func (mr *MockRecommendServiceMockRecorder) Compute(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compute", reflect.TypeOf((*MockRecommendService)(nil).Compute), ctx)
+}
+
+// Recommend mocks base method.
+func (m *MockRecommendService) Recommend(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recommend", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recommend indicates an expected call of Recommend.
+func (mr *MockRecommendServiceMockRecorder) Recommend(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommend", reflect.TypeOf((*MockRecommendService)(nil).Recommend), ctx, uid, offset, limit)
+}
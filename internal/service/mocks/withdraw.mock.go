@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./withdraw.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./withdraw.go -package=svcmocks -destination=./mocks/withdraw.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWithdrawService is a mock of WithdrawService interface.
+type MockWithdrawService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWithdrawServiceMockRecorder is the mock recorder for MockWithdrawService.
+type MockWithdrawServiceMockRecorder struct {
+	mock *MockWithdrawService
+}
+
+// NewMockWithdrawService creates a new mock instance.
+func NewMockWithdrawService(ctrl *gomock.Controller) *MockWithdrawService {
+	mock := &MockWithdrawService{ctrl: ctrl}
+	mock.recorder = &MockWithdrawServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawService) EXPECT() *MockWithdrawServiceMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockWithdrawService) Apply(ctx context.Context, uid, amt int64) (domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, uid, amt)
+	ret0, _ := ret[0].(domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockWithdrawServiceMockRecorder) Apply(ctx, uid, amt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockWithdrawService)(nil).Apply), ctx, uid, amt)
+}
+
+// Approve mocks base method.
+func (m *MockWithdrawService) Approve(ctx context.Context, wid, operator int64) (domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, wid, operator)
+	ret0, _ := ret[0].(domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockWithdrawServiceMockRecorder) Approve(ctx, wid, operator any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockWithdrawService)(nil).Approve), ctx, wid, operator)
+}
+
+// List mocks base method.
+func (m *MockWithdrawService) List(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWithdrawServiceMockRecorder) List(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWithdrawService)(nil).List), ctx, uid, offset, limit)
+}
+
+// ListPending mocks base method.
+func (m *MockWithdrawService) ListPending(ctx context.Context, offset, limit int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockWithdrawServiceMockRecorder) ListPending(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockWithdrawService)(nil).ListPending), ctx, offset, limit)
+}
+
+// Logs mocks base method.
+func (m *MockWithdrawService) Logs(ctx context.Context, wid int64) ([]domain.WithdrawalLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logs", ctx, wid)
+	ret0, _ := ret[0].([]domain.WithdrawalLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logs indicates an expected call of Logs.
+func (mr *MockWithdrawServiceMockRecorder) Logs(ctx, wid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockWithdrawService)(nil).Logs), ctx, wid)
+}
+
+// Reject mocks base method.
+func (m *MockWithdrawService) Reject(ctx context.Context, wid, operator int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, wid, operator, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockWithdrawServiceMockRecorder) Reject(ctx, wid, operator, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockWithdrawService)(nil).Reject), ctx, wid, operator, reason)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	"webook/internal/events/article"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+	"webook/pkg/sensitive"
+)
+
+var (
+	ErrModerationTaskNotFound = repository.ErrModerationTaskNotFound
+	ErrModerationTaskReviewed = repository.ErrModerationTaskReviewed
+	// ErrModerationContentChanged 提交审核之后内容又被改过了，审核人看到的不是线上的内容
+	ErrModerationContentChanged = errors.New("内容已经被修改，请审核新的内容")
+	errUnknownModerationBiz     = errors.New("未知的审核业务")
+)
+
+//go:generate mockgen -source=./moderation.go -package=svcmocks -destination=./mocks/moderation.mock.go ModerationService
+type ModerationService interface {
+	// Check 返回命中的敏感词，没有命中返回 nil
+	Check(text string) []string
+	// Submit 把命中了敏感词的内容放进人工审核队列
+	Submit(ctx context.Context, t domain.ModerationTask) error
+	// ListPending 等待审核的任务，先提交的在前面
+	ListPending(ctx context.Context, offset, limit int) ([]domain.ModerationTask, error)
+	// Approve 审核通过，内容上线
+	// 提交审核之后内容被改过的话返回 ErrModerationContentChanged，任务作废
+	Approve(ctx context.Context, id int64, reviewer int64) error
+	// Reject 审核拒绝，和 Approve 一样只针对提交审核时候的内容
+	Reject(ctx context.Context, id int64, reviewer int64, reason string) error
+}
+
+type moderationService struct {
+	filter *sensitive.Filter
+	repo   repository.ModerationRepository
+	// 审核结果要落到具体的业务上
+	artRepo     repository.ArticleRepository
+	commentRepo repository.CommentRepository
+	// 文章审核通过相当于发表
+	producer article.Producer
+	// 评论审核通过之后才通知作者和被回复的人
+	activityProducer activity.Producer
+	l                logger.LoggerV1
+}
+
+func NewModerationService(filter *sensitive.Filter, repo repository.ModerationRepository,
+	artRepo repository.ArticleRepository, commentRepo repository.CommentRepository,
+	producer article.Producer, activityProducer activity.Producer, l logger.LoggerV1) ModerationService {
+	return &moderationService{
+		filter:           filter,
+		repo:             repo,
+		artRepo:          artRepo,
+		commentRepo:      commentRepo,
+		producer:         producer,
+		activityProducer: activityProducer,
+		l:                l,
+	}
+}
+
+func (m *moderationService) Check(text string) []string {
+	return m.filter.Match(text)
+}
+
+func (m *moderationService) Submit(ctx context.Context, t domain.ModerationTask) error {
+	t.Status = domain.ModerationStatusPending
+	t.ContentHash = contentHash(t.Content)
+	_, err := m.repo.Create(ctx, t)
+	return err
+}
+
+func (m *moderationService) ListPending(ctx context.Context, offset, limit int) ([]domain.ModerationTask, error) {
+	return m.repo.ListByStatus(ctx, domain.ModerationStatusPending, offset, limit)
+}
+
+func (m *moderationService) Approve(ctx context.Context, id int64, reviewer int64) error {
+	return m.review(ctx, id, reviewer, domain.ModerationStatusApproved, "")
+}
+
+func (m *moderationService) Reject(ctx context.Context, id int64, reviewer int64, reason string) error {
+	return m.review(ctx, id, reviewer, domain.ModerationStatusRejected, reason)
+}
+
+func (m *moderationService) review(ctx context.Context, id int64, reviewer int64,
+	status domain.ModerationStatus, reason string) error {
+	t, err := m.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != domain.ModerationStatusPending {
+		return ErrModerationTaskReviewed
+	}
+	// 审核结果是按照 id 落到业务上的，要确认线上的内容还是审核人看到的那一份
+	changed, err := m.contentChanged(ctx, t)
+	if err != nil {
+		return err
+	}
+	if changed {
+		t.Status = domain.ModerationStatusOutdated
+		t.Reviewer = reviewer
+		err = m.repo.UpdateStatus(ctx, t)
+		if err != nil {
+			return err
+		}
+		return ErrModerationContentChanged
+	}
+	// 先改业务的状态，再标记任务处理完毕
+	// 如果中间失败了，任务还在队列里面，可以重新审核
+	err = m.apply(ctx, t, status)
+	if err != nil {
+		return err
+	}
+	t.Status = status
+	t.Reviewer = reviewer
+	t.Reason = reason
+	return m.repo.UpdateStatus(ctx, t)
+}
+
+// contentChanged 对比业务当前的内容和提交审核时候的快照
+func (m *moderationService) contentChanged(ctx context.Context, t domain.ModerationTask) (bool, error) {
+	var content string
+	switch t.Biz {
+	case "article":
+		// 等待审核的文章已经同步到了线上库，只是状态不对
+		art, err := m.artRepo.GetPubById(ctx, t.BizId)
+		if err != nil {
+			return false, err
+		}
+		// 和提交审核的时候保持一致
+		content = art.Title + "\n" + art.Content
+	case "comment":
+		cmt, err := m.commentRepo.FindById(ctx, t.BizId)
+		if err == repository.ErrCommentNotFound {
+			// 评论已经被删掉了，交给后面处理
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		content = cmt.Content
+	default:
+		return false, errUnknownModerationBiz
+	}
+	want := t.ContentHash
+	if want == "" {
+		// 老的任务没有摘要，用快照算一个
+		want = contentHash(t.Content)
+	}
+	return contentHash(content) != want, nil
+}
+
+func (m *moderationService) produceCommentEvent(ctx context.Context, cmt domain.Comment) {
+	if cmt.ParentComment != nil {
+		parent, err := m.commentRepo.FindById(ctx, cmt.ParentComment.Id)
+		if err != nil {
+			// 父评论查不到就只通知文章作者
+			m.l.Error("查询父评论失败",
+				logger.Int64("cid", cmt.Id),
+				logger.Error(err))
+			cmt.ParentComment = nil
+		} else {
+			cmt.ParentComment = &domain.Comment{Id: parent.Id, Commentator: parent.Commentator}
+		}
+	}
+	err := m.activityProducer.ProduceCommentEvent(newCommentEvent(cmt))
+	if err != nil {
+		m.l.Error("发送评论消息失败",
+			logger.Int64("cid", cmt.Id),
+			logger.Error(err))
+	}
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *moderationService) apply(ctx context.Context, t domain.ModerationTask, status domain.ModerationStatus) error {
+	approved := status == domain.ModerationStatusApproved
+	switch t.Biz {
+	case "article":
+		artStatus := domain.ArticleStatus(domain.ArticleStatusRejected)
+		if approved {
+			artStatus = domain.ArticleStatusPublished
+		}
+		err := m.artRepo.SyncStatus(ctx, t.Uid, t.BizId, artStatus)
+		if err != nil || !approved {
+			return err
+		}
+		er := m.producer.ProducePublishedEvent(article.PublishedEvent{
+			Aid:   t.BizId,
+			Uid:   t.Uid,
+			Ctime: time.Now().UnixMilli(),
+		})
+		if er != nil {
+			m.l.Error("发送 PublishedEvent 失败",
+				logger.Int64("aid", t.BizId),
+				logger.Error(er))
+		}
+		return nil
+	case "comment":
+		cmt, err := m.commentRepo.FindById(ctx, t.BizId)
+		if err == repository.ErrCommentNotFound {
+			// 评论已经被作者删掉了，没什么好审的
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cmtStatus := domain.CommentStatusRejected
+		if approved {
+			cmtStatus = domain.CommentStatusNormal
+		}
+		err = m.commentRepo.UpdateStatus(ctx, cmt, cmtStatus)
+		if err != nil || !approved {
+			return err
+		}
+		// 提交的时候等待审核没有通知，审核通过之后补上
+		m.produceCommentEvent(ctx, cmt)
+		return nil
+	default:
+		return errUnknownModerationBiz
+	}
+}
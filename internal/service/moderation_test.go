@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	activitymocks "webook/internal/events/activity/mocks"
+	"webook/internal/events/article"
+	evtmocks "webook/internal/events/article/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestModerationService_Review(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ModerationRepository,
+			repository.ArticleRepository, repository.CommentRepository)
+
+		approve bool
+		// 文章审核通过要发上线的消息
+		wantPublished bool
+		// 评论审核通过要补发评论的消息
+		wantCommentEvt *activity.CommentEvent
+		wantErr        error
+	}{
+		{
+			name: "文章审核通过",
+			mock: func(ctrl *gomock.Controller) (repository.ModerationRepository,
+				repository.ArticleRepository, repository.CommentRepository) {
+				repo := repomocks.NewMockModerationRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				commentRepo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.ModerationTask{
+					Id: 1, Biz: "article", BizId: 2, Uid: 3,
+					ContentHash: contentHash("标题\n内容"),
+					Status:      domain.ModerationStatusPending,
+				}, nil)
+				artRepo.EXPECT().GetPubById(gomock.Any(), int64(2)).
+					Return(domain.Article{Id: 2, Title: "标题", Content: "内容"}, nil)
+				artRepo.EXPECT().SyncStatus(gomock.Any(), int64(3), int64(2),
+					domain.ArticleStatus(domain.ArticleStatusPublished)).Return(nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), domain.ModerationTask{
+					Id: 1, Biz: "article", BizId: 2, Uid: 3,
+					ContentHash: contentHash("标题\n内容"),
+					Status:      domain.ModerationStatusApproved,
+					Reviewer:    100,
+				}).Return(nil)
+				return repo, artRepo, commentRepo
+			},
+			approve:       true,
+			wantPublished: true,
+		},
+		{
+			name: "提交审核之后文章又被改过了",
+			mock: func(ctrl *gomock.Controller) (repository.ModerationRepository,
+				repository.ArticleRepository, repository.CommentRepository) {
+				repo := repomocks.NewMockModerationRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				commentRepo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.ModerationTask{
+					Id: 1, Biz: "article", BizId: 2, Uid: 3,
+					ContentHash: contentHash("标题\n内容"),
+					Status:      domain.ModerationStatusPending,
+				}, nil)
+				artRepo.EXPECT().GetPubById(gomock.Any(), int64(2)).
+					Return(domain.Article{Id: 2, Title: "标题", Content: "偷偷换掉的内容"}, nil)
+				// 不会改文章的状态，任务直接作废
+				repo.EXPECT().UpdateStatus(gomock.Any(), domain.ModerationTask{
+					Id: 1, Biz: "article", BizId: 2, Uid: 3,
+					ContentHash: contentHash("标题\n内容"),
+					Status:      domain.ModerationStatusOutdated,
+					Reviewer:    100,
+				}).Return(nil)
+				return repo, artRepo, commentRepo
+			},
+			approve: true,
+			wantErr: ErrModerationContentChanged,
+		},
+		{
+			name: "评论审核通过，补发通知",
+			mock: func(ctrl *gomock.Controller) (repository.ModerationRepository,
+				repository.ArticleRepository, repository.CommentRepository) {
+				repo := repomocks.NewMockModerationRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				commentRepo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.ModerationTask{
+					Id: 1, Biz: "comment", BizId: 2, Uid: 3,
+					Content: "违规评论",
+					Status:  domain.ModerationStatusPending,
+				}, nil)
+				// 回复了评论 5
+				cmt := domain.Comment{Id: 2, Commentator: domain.User{Id: 3},
+					Biz: "article", BizId: 9, Content: "违规评论",
+					ParentComment: &domain.Comment{Id: 5},
+					Status:        domain.CommentStatusPending}
+				commentRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(cmt, nil).Times(2)
+				commentRepo.EXPECT().UpdateStatus(gomock.Any(), cmt, domain.CommentStatusNormal).Return(nil)
+				commentRepo.EXPECT().FindById(gomock.Any(), int64(5)).
+					Return(domain.Comment{Id: 5, Commentator: domain.User{Id: 7}}, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), domain.ModerationTask{
+					Id: 1, Biz: "comment", BizId: 2, Uid: 3,
+					Content:  "违规评论",
+					Status:   domain.ModerationStatusApproved,
+					Reviewer: 100,
+				}).Return(nil)
+				return repo, artRepo, commentRepo
+			},
+			approve: true,
+			wantCommentEvt: &activity.CommentEvent{
+				Cid: 2, Biz: "article", BizId: 9, Uid: 3,
+				Content: "违规评论", ParentUid: 7,
+			},
+		},
+		{
+			name: "评论审核拒绝",
+			mock: func(ctrl *gomock.Controller) (repository.ModerationRepository,
+				repository.ArticleRepository, repository.CommentRepository) {
+				repo := repomocks.NewMockModerationRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				commentRepo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.ModerationTask{
+					Id: 1, Biz: "comment", BizId: 2, Uid: 3,
+					Content: "违规评论",
+					Status:  domain.ModerationStatusPending,
+				}, nil)
+				cmt := domain.Comment{Id: 2, Content: "违规评论", Status: domain.CommentStatusPending}
+				commentRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(cmt, nil).Times(2)
+				commentRepo.EXPECT().UpdateStatus(gomock.Any(), cmt, domain.CommentStatusRejected).Return(nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), domain.ModerationTask{
+					Id: 1, Biz: "comment", BizId: 2, Uid: 3,
+					Content:  "违规评论",
+					Status:   domain.ModerationStatusRejected,
+					Reviewer: 100,
+					Reason:   "违规",
+				}).Return(nil)
+				return repo, artRepo, commentRepo
+			},
+		},
+		{
+			name: "已经审核过了",
+			mock: func(ctrl *gomock.Controller) (repository.ModerationRepository,
+				repository.ArticleRepository, repository.CommentRepository) {
+				repo := repomocks.NewMockModerationRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.ModerationTask{
+					Id: 1, Biz: "article", BizId: 2, Uid: 3,
+					Status: domain.ModerationStatusApproved,
+				}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl), repomocks.NewMockCommentRepository(ctrl)
+			},
+			approve: true,
+			wantErr: ErrModerationTaskReviewed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo, commentRepo := tc.mock(ctrl)
+			producer := evtmocks.NewMockProducer(ctrl)
+			if tc.wantPublished {
+				producer.EXPECT().ProducePublishedEvent(gomock.Any()).
+					DoAndReturn(func(evt article.PublishedEvent) error {
+						assert.Equal(t, int64(2), evt.Aid)
+						assert.Equal(t, int64(3), evt.Uid)
+						return nil
+					})
+			}
+			activityProducer := activitymocks.NewMockProducer(ctrl)
+			if tc.wantCommentEvt != nil {
+				activityProducer.EXPECT().ProduceCommentEvent(*tc.wantCommentEvt).Return(nil)
+			}
+			svc := NewModerationService(nil, repo, artRepo, commentRepo, producer, activityProducer,
+				logger.NewNoOpLogger())
+			var err error
+			if tc.approve {
+				err = svc.Approve(context.Background(), 1, 100)
+			} else {
+				err = svc.Reject(context.Background(), 1, 100, "违规")
+			}
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
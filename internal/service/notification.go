@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/push"
+	"webook/pkg/logger"
+)
+
+var ErrNotificationNotFound = repository.ErrNotificationNotFound
+
+//go:generate mockgen -source=./notification.go -package=svcmocks -destination=./mocks/notification.mock.go NotificationService
+type NotificationService interface {
+	// Notify 给 n.Uid 发一条通知，actor 是触发的人。
+	// 文章相关的通知 n.Uid 可以不填，会用文章作者来补上
+	Notify(ctx context.Context, n domain.Notification, actor int64) error
+	List(ctx context.Context, uid int64, offset, limit int) ([]domain.Notification, error)
+	MarkRead(ctx context.Context, uid, id int64) error
+	MarkAllRead(ctx context.Context, uid int64) error
+	// UnreadCnt 按照类型统计的未读数
+	UnreadCnt(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error)
+}
+
+type notificationService struct {
+	repo    repository.NotificationRepository
+	artSvc  ArticleService
+	userSvc UserService
+	// 推给在线的用户
+	broker push.Broker
+	l      logger.LoggerV1
+}
+
+func NewNotificationService(repo repository.NotificationRepository, artSvc ArticleService,
+	userSvc UserService, broker push.Broker, l logger.LoggerV1) NotificationService {
+	return &notificationService{
+		repo:    repo,
+		artSvc:  artSvc,
+		userSvc: userSvc,
+		broker:  broker,
+		l:       l,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, n domain.Notification, actor int64) error {
+	if n.Biz == "article" && (n.Uid == 0 || n.BizName == "") {
+		arts, err := s.artSvc.ListPubByIds(ctx, []int64{n.BizId})
+		if err != nil {
+			return err
+		}
+		if len(arts) == 0 {
+			// 文章已经下线了，不用通知
+			return nil
+		}
+		if n.Uid == 0 {
+			n.Uid = arts[0].Author.Id
+		}
+		n.BizName = arts[0].Title
+	}
+	// 自己给自己点赞、评论，不用通知
+	if n.Uid <= 0 || n.Uid == actor {
+		return nil
+	}
+	u, err := s.userSvc.FindById(ctx, n.Uid)
+	if err != nil {
+		return err
+	}
+	if u.NotificationMute.Muted(n.Type) {
+		return nil
+	}
+	err = s.repo.AddNotification(ctx, n, actor)
+	if err != nil {
+		return err
+	}
+	// 推的是这一次的动作，合并之后的结果客户端自己来拉
+	n.Actors = []int64{actor}
+	n.ActorCnt = 1
+	n.Utime = time.Now()
+	s.push(ctx, n.Uid, &n)
+	return nil
+}
+
+func (s *notificationService) List(ctx context.Context, uid int64, offset, limit int) ([]domain.Notification, error) {
+	return s.repo.List(ctx, uid, offset, limit)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, uid, id int64) error {
+	err := s.repo.MarkRead(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	// 同步给用户的其他设备
+	s.push(ctx, uid, nil)
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, uid int64) error {
+	err := s.repo.MarkAllRead(ctx, uid)
+	if err != nil {
+		return err
+	}
+	s.push(ctx, uid, nil)
+	return nil
+}
+
+func (s *notificationService) UnreadCnt(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error) {
+	return s.repo.UnreadCnt(ctx, uid)
+}
+
+// push 推送失败不影响业务，用户下次打开的时候会拉到
+func (s *notificationService) push(ctx context.Context, uid int64, n *domain.Notification) {
+	cnts, err := s.repo.UnreadCnt(ctx, uid)
+	if err != nil {
+		s.l.Error("推送通知查询未读数失败", logger.Int64("uid", uid), logger.Error(err))
+		return
+	}
+	err = s.broker.Publish(ctx, domain.NotificationPush{
+		Uid:          uid,
+		Notification: n,
+		Unread:       cnts,
+	})
+	if err != nil {
+		s.l.Error("推送通知失败", logger.Int64("uid", uid), logger.Error(err))
+	}
+}
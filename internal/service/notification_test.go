@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/internal/service/push"
+	pushmocks "webook/internal/service/push/mocks"
+	"webook/pkg/logger"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.NotificationRepository, ArticleService, UserService, push.Broker)
+
+		n       domain.Notification
+		actor   int64
+		wantErr error
+	}{
+		{
+			name: "点赞通知文章作者",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, ArticleService, UserService, push.Broker) {
+				repo := repomocks.NewMockNotificationRepository(ctrl)
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{1}).
+					Return([]domain.Article{{Id: 1, Title: "标题", Author: domain.Author{Id: 456}}}, nil)
+				userSvc.EXPECT().FindById(gomock.Any(), int64(456)).Return(domain.User{Id: 456}, nil)
+				repo.EXPECT().AddNotification(gomock.Any(), domain.Notification{
+					Uid:     456,
+					Type:    domain.NotificationTypeLike,
+					Biz:     "article",
+					BizId:   1,
+					BizName: "标题",
+				}, int64(123)).Return(nil)
+				// 推给在线的作者，带上最新的未读数
+				repo.EXPECT().UnreadCnt(gomock.Any(), int64(456)).
+					Return(map[domain.NotificationType]int64{domain.NotificationTypeLike: 1}, nil)
+				broker := pushmocks.NewMockBroker(ctrl)
+				broker.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, msg domain.NotificationPush) error {
+						assert.Equal(t, int64(456), msg.Uid)
+						assert.Equal(t, []int64{123}, msg.Notification.Actors)
+						assert.Equal(t, int64(1), msg.Unread[domain.NotificationTypeLike])
+						return nil
+					})
+				return repo, artSvc, userSvc, broker
+			},
+			n:     domain.Notification{Type: domain.NotificationTypeLike, Biz: "article", BizId: 1},
+			actor: 123,
+		},
+		{
+			name: "给自己点赞",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, ArticleService, UserService, push.Broker) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{1}).
+					Return([]domain.Article{{Id: 1, Author: domain.Author{Id: 123}}}, nil)
+				return repomocks.NewMockNotificationRepository(ctrl), artSvc, svcmocks.NewMockUserService(ctrl),
+					pushmocks.NewMockBroker(ctrl)
+			},
+			n:     domain.Notification{Type: domain.NotificationTypeLike, Biz: "article", BizId: 1},
+			actor: 123,
+		},
+		{
+			name: "文章已经下线",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, ArticleService, UserService, push.Broker) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{1}).Return(nil, nil)
+				return repomocks.NewMockNotificationRepository(ctrl), artSvc, svcmocks.NewMockUserService(ctrl),
+					pushmocks.NewMockBroker(ctrl)
+			},
+			n:     domain.Notification{Type: domain.NotificationTypeLike, Biz: "article", BizId: 1},
+			actor: 123,
+		},
+		{
+			name: "用户关掉了关注通知",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, ArticleService, UserService, push.Broker) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindById(gomock.Any(), int64(456)).Return(domain.User{
+					Id:               456,
+					NotificationMute: domain.NotificationMute(0).Set(domain.NotificationTypeFollow, true),
+				}, nil)
+				return repomocks.NewMockNotificationRepository(ctrl), svcmocks.NewMockArticleService(ctrl), userSvc,
+					pushmocks.NewMockBroker(ctrl)
+			},
+			n: domain.Notification{Uid: 456, Type: domain.NotificationTypeFollow,
+				Biz: "user", BizId: 456},
+			actor: 123,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artSvc, userSvc, broker := tc.mock(ctrl)
+			svc := NewNotificationService(repo, artSvc, userSvc, broker, logger.NewNoOpLogger())
+			err := svc.Notify(context.Background(), tc.n, tc.actor)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
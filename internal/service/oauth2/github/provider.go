@@ -0,0 +1,176 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service/oauth2"
+)
+
+const providerName = "github"
+
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// 下面几个留空就是 github.com 的，GitHub Enterprise 要改
+	AuthURL  string
+	TokenURL string
+	APIURL   string
+}
+
+type provider struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewProvider GitHub 的 OAuth App，不是 OIDC，用户信息要另外调接口拿
+func NewProvider(cfg Config) oauth2.Provider {
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = "https://github.com/login/oauth/authorize"
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = "https://github.com/login/oauth/access_token"
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.github.com"
+	}
+	return &provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+func (p *provider) Name() string {
+	return providerName
+}
+
+func (p *provider) AuthURL(ctx context.Context, req oauth2.AuthRequest) (string, error) {
+	params := url.Values{}
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", "read:user user:email")
+	params.Set("state", req.State)
+	params.Set("code_challenge", req.CodeChallenge())
+	params.Set("code_challenge_method", "S256")
+	params.Set("allow_signup", "false")
+	return p.cfg.AuthURL + "?" + params.Encode(), nil
+}
+
+func (p *provider) VerifyCode(ctx context.Context, code string, req oauth2.AuthRequest) (domain.OAuthIdentity, error) {
+	token, err := p.exchange(ctx, code, req)
+	if err != nil {
+		return domain.OAuthIdentity{}, err
+	}
+	var u userResp
+	err = p.get(ctx, token, "/user", &u)
+	if err != nil {
+		return domain.OAuthIdentity{}, err
+	}
+	if u.Id == 0 {
+		return domain.OAuthIdentity{}, fmt.Errorf("GitHub 没有返回用户 ID")
+	}
+	email, err := p.primaryEmail(ctx, token)
+	if err != nil {
+		return domain.OAuthIdentity{}, err
+	}
+	name := u.Name
+	if name == "" {
+		name = u.Login
+	}
+	return domain.OAuthIdentity{
+		Provider: providerName,
+		// login 可以改，id 不会变
+		Subject: strconv.FormatInt(u.Id, 10),
+		Email:   email,
+		Name:    name,
+	}, nil
+}
+
+func (p *provider) exchange(ctx context.Context, code string, req oauth2.AuthRequest) (string, error) {
+	form := url.Values{}
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", req.CodeVerifier)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// 不加的话返回的是表单格式
+	httpReq.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var res tokenResp
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return "", err
+	}
+	// 授权码不对的时候也是 200，错误放在 error 里面
+	if res.Error != "" || res.AccessToken == "" {
+		return "", fmt.Errorf("%w %s %s", oauth2.ErrInvalidCode, res.Error, res.ErrorDescription)
+	}
+	return res.AccessToken, nil
+}
+
+// primaryEmail 只要验证过的主邮箱，没有就算了
+func (p *provider) primaryEmail(ctx context.Context, token string) (string, error) {
+	var emails []emailResp
+	err := p.get(ctx, token, "/user/emails", &emails)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, nil
+		}
+	}
+	return "", nil
+}
+
+func (p *provider) get(ctx context.Context, token, path string, val any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.APIURL+path, nil)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("调用 GitHub 接口 %s 失败 %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(val)
+}
+
+type tokenResp struct {
+	AccessToken      string `json:"access_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type userResp struct {
+	Id    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type emailResp struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/service/oauth2"
+)
+
+func TestProvider_VerifyCode(t *testing.T) {
+	req, err := oauth2.NewAuthRequest()
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("code") != "good" || r.PostFormValue("code_verifier") != req.CodeVerifier {
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "token"})
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 123, "login": "octocat"})
+	})
+	mux.HandleFunc("GET /user/emails", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "other@example.com", "primary": false, "verified": true},
+			{"email": "octocat@example.com", "primary": true, "verified": true},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	p := NewProvider(Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     server.URL + "/login/oauth/access_token",
+		APIURL:       server.URL,
+	})
+
+	identity, err := p.VerifyCode(context.Background(), "good", req)
+	require.NoError(t, err)
+	assert.Equal(t, "123", identity.Subject)
+	assert.Equal(t, "octocat", identity.Name)
+	assert.Equal(t, "octocat@example.com", identity.Email)
+
+	_, err = p.VerifyCode(context.Background(), "bad", req)
+	assert.ErrorIs(t, err, oauth2.ErrInvalidCode)
+}
@@ -0,0 +1,225 @@
+// Package oidc 通用的 OpenID Connect 登录，用授权码模式加 PKCE，
+// 端点都从 issuer 的 /.well-known/openid-configuration 里面拿
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service/oauth2"
+	"webook/pkg/jwks"
+)
+
+type Config struct {
+	// 路由里面的名字，比如 google
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// 留空就是 openid email profile
+	Scopes []string
+}
+
+// 只接受非对称签名，不接受 none 和 HMAC
+var validMethods = []string{"RS256", "RS384", "RS512", "PS256", "EdDSA"}
+
+type provider struct {
+	cfg    Config
+	client *http.Client
+
+	mutex     sync.Mutex
+	discovery *discovery
+	keys      *jwks.RemoteKeySet
+}
+
+func NewProvider(cfg Config) oauth2.Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+func (p *provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *provider) AuthURL(ctx context.Context, req oauth2.AuthRequest) (string, error) {
+	d, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	params.Set("state", req.State)
+	params.Set("nonce", req.Nonce)
+	params.Set("code_challenge", req.CodeChallenge())
+	params.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+func (p *provider) VerifyCode(ctx context.Context, code string, req oauth2.AuthRequest) (domain.OAuthIdentity, error) {
+	d, keys, err := p.discover(ctx)
+	if err != nil {
+		return domain.OAuthIdentity{}, err
+	}
+	idToken, err := p.exchange(ctx, d, code, req)
+	if err != nil {
+		return domain.OAuthIdentity{}, err
+	}
+	claims, err := p.verifyIDToken(idToken, keys, req)
+	if err != nil {
+		return domain.OAuthIdentity{}, err
+	}
+	identity := domain.OAuthIdentity{
+		Provider: p.cfg.Name,
+		Subject:  claims.Subject,
+		Name:     claims.Name,
+	}
+	if identity.Name == "" {
+		identity.Name = claims.PreferredUsername
+	}
+	// 没验证过的邮箱不能信
+	if claims.EmailVerified {
+		identity.Email = claims.Email
+	}
+	return identity, nil
+}
+
+func (p *provider) exchange(ctx context.Context, d *discovery, code string, req oauth2.AuthRequest) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", req.CodeVerifier)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	// client_secret_basic，所有提供方都必须支持
+	httpReq.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var res tokenResp
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK || res.Error != "" {
+		return "", fmt.Errorf("%w %s %s", oauth2.ErrInvalidCode, res.Error, res.ErrorDescription)
+	}
+	if res.IDToken == "" {
+		return "", fmt.Errorf("%s 没有返回 id_token", p.cfg.Name)
+	}
+	return res.IDToken, nil
+}
+
+// verifyIDToken 按照 OIDC Core 3.1.3.7 校验，access token 直接从 token 端点拿到，
+// 所以不用再校验 at_hash
+func (p *provider) verifyIDToken(idToken string, keys *jwks.RemoteKeySet, req oauth2.AuthRequest) (idTokenClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, keys.Keyfunc,
+		jwt.WithValidMethods(validMethods),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute))
+	if err != nil {
+		return idTokenClaims{}, fmt.Errorf("id_token 无效 %w", err)
+	}
+	if claims.Nonce == "" || claims.Nonce != req.Nonce {
+		return idTokenClaims{}, fmt.Errorf("id_token 的 nonce 不匹配")
+	}
+	if len(claims.Audience) > 1 && claims.Azp != p.cfg.ClientID {
+		return idTokenClaims{}, fmt.Errorf("id_token 的 azp 不匹配")
+	}
+	if claims.Subject == "" {
+		return idTokenClaims{}, fmt.Errorf("id_token 没有 sub")
+	}
+	return claims, nil
+}
+
+// discover 第一次用的时候才去拉，拉失败了下次再试
+func (p *provider) discover(ctx context.Context) (*discovery, *jwks.RemoteKeySet, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.discovery != nil {
+		return p.discovery, p.keys, nil
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("拉取 %s 的 OIDC 配置失败 %d", p.cfg.Name, resp.StatusCode)
+	}
+	var d discovery
+	err = json.NewDecoder(resp.Body).Decode(&d)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 防止配置被冒充
+	if strings.TrimSuffix(d.Issuer, "/") != p.cfg.Issuer {
+		return nil, nil, fmt.Errorf("%s 的 issuer 不一致 %s", p.cfg.Name, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, nil, fmt.Errorf("%s 的 OIDC 配置不完整", p.cfg.Name)
+	}
+	p.discovery = &d
+	p.keys = jwks.NewRemoteKeySet(d.JWKSURI, p.client)
+	return p.discovery, p.keys, nil
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResp struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	Azp               string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
@@ -0,0 +1,105 @@
+package oidc
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"webook/internal/service/oauth2"
+	"webook/internal/service/oauth2/oidc/simulator"
+)
+
+// TestProvider 跟本地的模拟器走一遍完整的授权码流程
+func TestProvider(t *testing.T) {
+	server := httptest.NewServer(simulator.NewServer("webook", "webook-secret").Handler())
+	defer server.Close()
+	newProvider := func(secret string) oauth2.Provider {
+		return NewProvider(Config{
+			Name:         "local",
+			Issuer:       server.URL,
+			ClientID:     "webook",
+			ClientSecret: secret,
+			RedirectURL:  "http://localhost:8080/oauth2/local/callback",
+		})
+	}
+	// 模拟浏览器跳到授权页，拿到跳回来的授权码
+	authorize := func(t *testing.T, p oauth2.Provider, req oauth2.AuthRequest, user string) string {
+		authURL, err := p.AuthURL(context.Background(), req)
+		require.NoError(t, err)
+		client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+		resp, err := client.Get(authURL + "&login_hint=" + user)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		loc, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, req.State, loc.Query().Get("state"))
+		return loc.Query().Get("code")
+	}
+
+	testCases := []struct {
+		name string
+		// 回调的时候拿到的 AuthRequest
+		callbackReq func(req oauth2.AuthRequest) oauth2.AuthRequest
+		secret      string
+
+		wantSubject string
+		wantErr     bool
+	}{
+		{
+			name:        "登录成功",
+			callbackReq: func(req oauth2.AuthRequest) oauth2.AuthRequest { return req },
+			secret:      "webook-secret",
+			wantSubject: "bob",
+		},
+		{
+			name: "code_verifier 不对",
+			callbackReq: func(req oauth2.AuthRequest) oauth2.AuthRequest {
+				req.CodeVerifier = "wrong"
+				return req
+			},
+			secret:  "webook-secret",
+			wantErr: true,
+		},
+		{
+			name: "nonce 不对",
+			callbackReq: func(req oauth2.AuthRequest) oauth2.AuthRequest {
+				req.Nonce = "wrong"
+				return req
+			},
+			secret:  "webook-secret",
+			wantErr: true,
+		},
+		{
+			name:        "client 密钥不对",
+			callbackReq: func(req oauth2.AuthRequest) oauth2.AuthRequest { return req },
+			secret:      "wrong",
+			wantErr:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newProvider(tc.secret)
+			req, err := oauth2.NewAuthRequest()
+			require.NoError(t, err)
+			code := authorize(t, p, req, "bob")
+			identity, err := p.VerifyCode(context.Background(), code, tc.callbackReq(req))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "local", identity.Provider)
+			assert.Equal(t, tc.wantSubject, identity.Subject)
+			assert.Equal(t, "bob@example.com", identity.Email)
+			// 授权码只能用一次
+			_, err = p.VerifyCode(context.Background(), code, req)
+			assert.ErrorIs(t, err, oauth2.ErrInvalidCode)
+		})
+	}
+}
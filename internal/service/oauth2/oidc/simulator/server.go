@@ -0,0 +1,199 @@
+// Package simulator 本地的 OIDC 提供方，不用真的去 Google 之类的地方注册应用就能跑通登录流程。
+// 授权的时候不需要用户确认，直接带着授权码跳回去
+package simulator
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+	"webook/pkg/jwks"
+)
+
+const kid = "simulator"
+
+type authCode struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	subject       string
+	expire        time.Time
+}
+
+// Server 授权码只放在内存里面。issuer 就是请求里面的 Host
+type Server struct {
+	clientID     string
+	clientSecret string
+	priv         ed25519.PrivateKey
+	pub          ed25519.PublicKey
+
+	mu    sync.Mutex
+	codes map[string]authCode
+}
+
+func NewServer(clientID, clientSecret string) *Server {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return &Server{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		priv:         priv,
+		pub:          pub,
+		codes:        make(map[string]authCode),
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("GET /jwks", s.jwks)
+	// login_hint 参数就是登录的用户，不传就是 alice
+	mux.HandleFunc("GET /authorize", s.authorize)
+	mux.HandleFunc("POST /token", s.token)
+	return mux
+}
+
+func (s *Server) issuer(r *http.Request) string {
+	if r.TLS != nil {
+		return "https://" + r.Host
+	}
+	return "http://" + r.Host
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := s.issuer(r)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"EdDSA"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	key, err := jwks.FromPublicKey(kid, "EdDSA", s.pub)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, jwks.Set{Keys: []jwks.JWK{key}})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || q.Get("redirect_uri") == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if q.Get("client_id") != s.clientID || q.Get("response_type") != "code" {
+		writeError(w, http.StatusBadRequest, "unauthorized_client")
+		return
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	subject := q.Get("login_hint")
+	if subject == "" {
+		subject = "alice"
+	}
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = authCode{
+		clientID:      s.clientID,
+		redirectURI:   q.Get("redirect_uri"),
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		subject:       subject,
+		expire:        time.Now().Add(time.Minute),
+	}
+	s.mu.Unlock()
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if clientID != s.clientID || clientSecret != s.clientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+	s.mu.Lock()
+	code, ok := s.codes[r.PostFormValue("code")]
+	// 授权码只能用一次
+	delete(s.codes, r.PostFormValue("code"))
+	s.mu.Unlock()
+	if !ok || time.Now().After(code.expire) || code.redirectURI != r.PostFormValue("redirect_uri") {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != code.codeChallenge {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"iss":            s.issuer(r),
+		"sub":            code.subject,
+		"aud":            code.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          code.nonce,
+		"email":          code.subject + "@example.com",
+		"email_verified": true,
+		"name":           code.subject,
+	})
+	token.Header["kid"] = kid
+	idToken, err := token.SignedString(s.priv)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func randomString() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, val any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(val)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
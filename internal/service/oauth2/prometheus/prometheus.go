@@ -5,27 +5,27 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"time"
 	"webook/internal/domain"
-	"webook/internal/service/oauth2/wechat"
+	"webook/internal/service/oauth2"
 )
 
-// Decorator 通过装饰器监控微信扫码验证响应时间
+// Decorator 通过装饰器监控第三方登录验证授权码的响应时间
 type Decorator struct {
-	wechat.Service
+	oauth2.Provider
 	sum prometheus.Summary
 }
 
-func NewDecorator(svc wechat.Service, sum prometheus.Summary) *Decorator {
+func NewDecorator(svc oauth2.Provider, sum prometheus.Summary) *Decorator {
 	return &Decorator{
-		Service: svc,
-		sum:     sum,
+		Provider: svc,
+		sum:      sum,
 	}
 }
 
-func (d *Decorator) VerifyCode(ctx context.Context, code string) (domain.WechatInfo, error) {
+func (d *Decorator) VerifyCode(ctx context.Context, code string, req oauth2.AuthRequest) (domain.OAuthIdentity, error) {
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start).Milliseconds()
 		d.sum.Observe(float64(duration))
 	}()
-	return d.Service.VerifyCode(ctx, code)
+	return d.Provider.VerifyCode(ctx, code, req)
 }
@@ -0,0 +1,94 @@
+// Package oauth2 第三方登录。每个提供方实现 Provider，注册到 Registry 里面，
+// 回调地址统一是 /oauth2/{name}/callback
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"webook/internal/domain"
+)
+
+var ErrInvalidCode = errors.New("授权码无效")
+
+//go:generate mockgen -source=./types.go -package=oauth2mocks -destination=./mocks/oauth2.mock.go Provider
+type Provider interface {
+	// Name 也是路由里面的名字，比如 wechat、github
+	Name() string
+	AuthURL(ctx context.Context, req AuthRequest) (string, error)
+	// VerifyCode 用授权码换第三方账号，req 要跟 AuthURL 的时候是同一个
+	VerifyCode(ctx context.Context, code string, req AuthRequest) (domain.OAuthIdentity, error)
+}
+
+// AuthRequest 一次授权要用到的随机值，跳转之前放在 cookie 里面，回调的时候取出来。
+// 不支持 PKCE 或者不是 OIDC 的提供方忽略用不上的字段
+type AuthRequest struct {
+	// 防止 CSRF
+	State string
+	// PKCE，跳转的时候只带上它的哈希
+	CodeVerifier string
+	// OIDC 的 ID token 里面要带回来，防止重放
+	Nonce string
+}
+
+func NewAuthRequest() (AuthRequest, error) {
+	state, err := randomString(16)
+	if err != nil {
+		return AuthRequest{}, err
+	}
+	verifier, err := randomString(32)
+	if err != nil {
+		return AuthRequest{}, err
+	}
+	nonce, err := randomString(16)
+	if err != nil {
+		return AuthRequest{}, err
+	}
+	return AuthRequest{State: state, CodeVerifier: verifier, Nonce: nonce}, nil
+}
+
+// CodeChallenge PKCE 的 S256
+func (r AuthRequest) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(r.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Registry 启动的时候根据配置注册，之后只读
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names 给前端展示有哪些登录方式
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -7,63 +7,68 @@ import (
 	"net/http"
 	"net/url"
 	"webook/internal/domain"
+	"webook/internal/service/oauth2"
 	"webook/pkg/logger"
 )
 
-type Service interface {
-	AuthURL(ctx context.Context, state string) (string, error)
-	VerifyCode(ctx context.Context, code string) (domain.WechatInfo, error)
-}
-
-var redirectURL = url.PathEscape("https://meoying.com/oauth2/wechat/callback")
+const providerName = "wechat"
 
 type service struct {
-	appID     string
-	appSecret string
-	client    *http.Client
-	l         logger.LoggerV1
+	appID       string
+	appSecret   string
+	redirectURL string
+	client      *http.Client
+	l           logger.LoggerV1
 }
 
-func NewService(appID string, appSecret string, l logger.LoggerV1) Service {
+// NewService 微信扫码登录，不支持 PKCE，只用 state 防 CSRF
+func NewService(appID string, appSecret string, redirectURL string, l logger.LoggerV1) oauth2.Provider {
 	return &service{
-		appID:     appID,
-		appSecret: appSecret,
-		client:    http.DefaultClient,
-		l:         l,
+		appID:       appID,
+		appSecret:   appSecret,
+		redirectURL: redirectURL,
+		client:      http.DefaultClient,
+		l:           l,
 	}
 }
 
+func (s *service) Name() string {
+	return providerName
+}
+
 // VerifyCode 给微信发一个http请求
-func (s *service) VerifyCode(ctx context.Context, code string) (domain.WechatInfo, error) {
+func (s *service) VerifyCode(ctx context.Context, code string, req oauth2.AuthRequest) (domain.OAuthIdentity, error) {
 	accessTokenUrl := fmt.Sprintf(`https://api.weixin.qq.com/sns/oauth2/access_token?appid=%s&secret=%s&code=%s&grant_type=authorization_code`,
-		s.appID, s.appSecret, code)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, accessTokenUrl, nil)
+		s.appID, s.appSecret, url.QueryEscape(code))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, accessTokenUrl, nil)
 	if err != nil {
-		return domain.WechatInfo{}, err
+		return domain.OAuthIdentity{}, err
 	}
-	httpResp, err := s.client.Do(req)
+	httpResp, err := s.client.Do(httpReq)
 	if err != nil {
-		return domain.WechatInfo{}, err
+		return domain.OAuthIdentity{}, err
 	}
+	defer httpResp.Body.Close()
 	var res Result
 	err = json.NewDecoder(httpResp.Body).Decode(&res)
 	if err != nil {
 		// 转json为结构体出错
-		return domain.WechatInfo{}, err
+		return domain.OAuthIdentity{}, err
 	}
 	if res.ErrCode != 0 {
-		return domain.WechatInfo{},
-			fmt.Errorf("调用微信接口失败 errcode %d. errmsg %s", res.ErrCode, res.ErrMsg)
+		return domain.OAuthIdentity{},
+			fmt.Errorf("%w 调用微信接口失败 errcode %d. errmsg %s", oauth2.ErrInvalidCode, res.ErrCode, res.ErrMsg)
 	}
-	return domain.WechatInfo{
-		UnionId: res.UnionId,
-		OpenId:  res.OpenId,
+	return domain.OAuthIdentity{
+		Provider: providerName,
+		Subject:  res.OpenId,
+		UnionId:  res.UnionId,
 	}, nil
 }
 
-func (s *service) AuthURL(ctx context.Context, state string) (string, error) {
+func (s *service) AuthURL(ctx context.Context, req oauth2.AuthRequest) (string, error) {
 	const authURLPattern = `https://open.weixin.qq.com/connect/qrconnect?appid=%s&redirect_uri=%s&response_type=code&scope=snsapi_login&state=%s#wechat_redirect`
-	return fmt.Sprintf(authURLPattern, s.appID, redirectURL, state), nil
+	return fmt.Sprintf(authURLPattern, s.appID, url.QueryEscape(s.redirectURL), req.State), nil
 }
 
 type Result struct {
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/payment"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+	"webook/pkg/wechatpay"
+)
+
+var (
+	ErrPaymentNotFound = repository.ErrPaymentNotFound
+	// ErrPaymentCompleted 业务单已经支付过了，或者已经失败了
+	ErrPaymentCompleted         = errors.New("支付已经结束了")
+	ErrInvalidPaymentTransition = errors.New("支付状态不能这样变化")
+	// ErrPaymentAmountMismatch 微信那边的金额跟下单的时候不一样，不能当成支付成功
+	ErrPaymentAmountMismatch = errors.New("支付金额不一致")
+)
+
+//go:generate mockgen -source=./payment.go -package=svcmocks -destination=./mocks/payment.mock.go PaymentService
+type PaymentService interface {
+	// Prepay 创建支付，返回二维码链接
+	Prepay(ctx context.Context, pmt domain.Payment) (string, error)
+	GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error)
+	// HandleCallback 处理微信的支付通知
+	HandleCallback(ctx context.Context, txn wechatpay.Transaction) error
+	// SyncWechatInfo 主动去微信查询支付结果，对账用
+	SyncWechatInfo(ctx context.Context, bizTradeNO string) error
+	// FindExpiredPayment 长时间没有结果的支付，按照 id 升序翻页，minId 是上一批最大的 id
+	FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]domain.Payment, error)
+}
+
+// NativeGateway 微信支付 Native 下单和查单，本地开发的时候对接的是模拟器
+type NativeGateway interface {
+	NativePrepay(ctx context.Context, req wechatpay.PrepayRequest) (wechatpay.PrepayResponse, error)
+	QueryOrderByOutTradeNo(ctx context.Context, outTradeNo string) (wechatpay.Transaction, error)
+}
+
+type WechatNativePaymentService struct {
+	gateway   NativeGateway
+	repo      repository.PaymentRepository
+	producer  payment.Producer
+	notifyURL string
+	l         logger.LoggerV1
+}
+
+func NewWechatNativePaymentService(gateway NativeGateway, repo repository.PaymentRepository,
+	producer payment.Producer, notifyURL string, l logger.LoggerV1) PaymentService {
+	return &WechatNativePaymentService{
+		gateway:   gateway,
+		repo:      repo,
+		producer:  producer,
+		notifyURL: notifyURL,
+		l:         l,
+	}
+}
+
+func (s *WechatNativePaymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	pmt.Status = domain.PaymentStatusInit
+	err := s.repo.AddPayment(ctx, pmt)
+	if err == repository.ErrDuplicatePayment {
+		// 业务方重试，微信那边用同一个单号下单是幂等的
+		var old domain.Payment
+		old, err = s.repo.GetPayment(ctx, pmt.BizTradeNO)
+		if err == nil && old.Status != domain.PaymentStatusInit {
+			return "", ErrPaymentCompleted
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.gateway.NativePrepay(ctx, wechatpay.PrepayRequest{
+		Description: pmt.Description,
+		OutTradeNo:  pmt.BizTradeNO,
+		NotifyURL:   s.notifyURL,
+		Amount: wechatpay.Amount{
+			Total:    pmt.Amt.Total,
+			Currency: pmt.Amt.Currency,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.CodeURL, nil
+}
+
+func (s *WechatNativePaymentService) GetPayment(ctx context.Context, bizTradeNO string) (domain.Payment, error) {
+	return s.repo.GetPayment(ctx, bizTradeNO)
+}
+
+func (s *WechatNativePaymentService) HandleCallback(ctx context.Context, txn wechatpay.Transaction) error {
+	return s.updateByTxn(ctx, txn)
+}
+
+func (s *WechatNativePaymentService) SyncWechatInfo(ctx context.Context, bizTradeNO string) error {
+	txn, err := s.gateway.QueryOrderByOutTradeNo(ctx, bizTradeNO)
+	if err != nil {
+		return err
+	}
+	return s.updateByTxn(ctx, txn)
+}
+
+func (s *WechatNativePaymentService) FindExpiredPayment(ctx context.Context, minId int64, limit int, t time.Time) ([]domain.Payment, error) {
+	return s.repo.FindExpiredPayment(ctx, minId, limit, t)
+}
+
+// updateByTxn 按照状态机推进支付的状态，真的变了才通知业务方
+func (s *WechatNativePaymentService) updateByTxn(ctx context.Context, txn wechatpay.Transaction) error {
+	to, ok := s.toStatus(txn.TradeState)
+	if !ok {
+		// 还没有结果，等下一次通知或者对账
+		return nil
+	}
+	pmt, err := s.repo.GetPayment(ctx, txn.OutTradeNo)
+	if err != nil {
+		return err
+	}
+	if pmt.Status == to {
+		// 重复的通知
+		return nil
+	}
+	if !pmt.Status.CanTransitTo(to) {
+		s.l.Error("支付状态不能这样变化",
+			logger.String("biz_trade_no", pmt.BizTradeNO),
+			logger.Int("from", int(pmt.Status)),
+			logger.Int("to", int(to)))
+		return ErrInvalidPaymentTransition
+	}
+	if to == domain.PaymentStatusSuccess &&
+		(txn.Amount.Total != pmt.Amt.Total || txn.Amount.Currency != pmt.Amt.Currency) {
+		// 业务方只看状态就发货了，金额对不上一定要拦住，人工处理
+		s.l.Error("支付金额不一致",
+			logger.String("biz_trade_no", pmt.BizTradeNO),
+			logger.String("txn_id", txn.TransactionID),
+			logger.Int64("want_total", pmt.Amt.Total),
+			logger.String("want_currency", pmt.Amt.Currency),
+			logger.Int64("total", txn.Amount.Total),
+			logger.String("currency", txn.Amount.Currency))
+		return ErrPaymentAmountMismatch
+	}
+	changed, err := s.repo.UpdatePayment(ctx, domain.Payment{
+		BizTradeNO: txn.OutTradeNo,
+		TxnID:      txn.TransactionID,
+		Status:     to,
+	}, pmt.Status)
+	if err != nil || !changed {
+		// 没有更新说明别的地方已经处理了，由那边发消息
+		return err
+	}
+	err = s.producer.ProducePaymentEvent(payment.PaymentEvent{
+		BizTradeNO: txn.OutTradeNo,
+		Status:     to.ToUint8(),
+	})
+	if err != nil {
+		// 业务方还可以主动来查
+		s.l.Error("发送支付事件失败",
+			logger.String("biz_trade_no", txn.OutTradeNo),
+			logger.Error(err))
+	}
+	return nil
+}
+
+func (s *WechatNativePaymentService) toStatus(tradeState string) (domain.PaymentStatus, bool) {
+	switch tradeState {
+	case wechatpay.TradeStateSuccess:
+		return domain.PaymentStatusSuccess, true
+	case wechatpay.TradeStatePayError, wechatpay.TradeStateClosed, wechatpay.TradeStateRevoked:
+		return domain.PaymentStatusFailed, true
+	case wechatpay.TradeStateRefund:
+		return domain.PaymentStatusRefund, true
+	default:
+		return domain.PaymentStatusUnknown, false
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/events/payment"
+	evtmocks "webook/internal/events/payment/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+	"webook/pkg/wechatpay"
+)
+
+func TestWechatNativePaymentService_HandleCallback(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer)
+
+		txn     wechatpay.Transaction
+		wantErr error
+	}{
+		{
+			name: "支付成功，发送消息",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					Amt:        domain.Amount{Total: 100, Currency: "CNY"},
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusInit,
+				}, nil)
+				repo.EXPECT().UpdatePayment(gomock.Any(), domain.Payment{
+					BizTradeNO: "reward-1",
+					TxnID:      "txn-1",
+					Status:     domain.PaymentStatusSuccess,
+				}, domain.PaymentStatusInit).Return(true, nil)
+				producer.EXPECT().ProducePaymentEvent(payment.PaymentEvent{
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusSuccess.ToUint8(),
+				}).Return(nil)
+				return repo, producer
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo:    "reward-1",
+				TransactionID: "txn-1",
+				TradeState:    wechatpay.TradeStateSuccess,
+				Amount:        wechatpay.Amount{Total: 100, Currency: "CNY"},
+			},
+		},
+		{
+			name: "金额不一致，不能算支付成功",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					Amt:        domain.Amount{Total: 100, Currency: "CNY"},
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusInit,
+				}, nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo:    "reward-1",
+				TransactionID: "txn-1",
+				TradeState:    wechatpay.TradeStateSuccess,
+				Amount:        wechatpay.Amount{Total: 1, Currency: "CNY"},
+			},
+			wantErr: ErrPaymentAmountMismatch,
+		},
+		{
+			name: "币种不一致，不能算支付成功",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					Amt:        domain.Amount{Total: 100, Currency: "CNY"},
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusInit,
+				}, nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo:    "reward-1",
+				TransactionID: "txn-1",
+				TradeState:    wechatpay.TradeStateSuccess,
+				Amount:        wechatpay.Amount{Total: 100, Currency: "USD"},
+			},
+			wantErr: ErrPaymentAmountMismatch,
+		},
+		{
+			name: "重复通知，什么都不做",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusSuccess,
+				}, nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo: "reward-1",
+				TradeState: wechatpay.TradeStateSuccess,
+			},
+		},
+		{
+			name: "并发更新，别的地方已经处理了",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusInit,
+				}, nil)
+				repo.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.PaymentStatusInit).
+					Return(false, nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo: "reward-1",
+				TradeState: wechatpay.TradeStateClosed,
+			},
+		},
+		{
+			name: "已经失败的支付不能变成成功",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				repo := repomocks.NewMockPaymentRepository(ctrl)
+				repo.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					BizTradeNO: "reward-1",
+					Status:     domain.PaymentStatusFailed,
+				}, nil)
+				return repo, evtmocks.NewMockProducer(ctrl)
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo: "reward-1",
+				TradeState: wechatpay.TradeStateSuccess,
+			},
+			wantErr: ErrInvalidPaymentTransition,
+		},
+		{
+			name: "还没有支付结果",
+			mock: func(ctrl *gomock.Controller) (repository.PaymentRepository, payment.Producer) {
+				return repomocks.NewMockPaymentRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			txn: wechatpay.Transaction{
+				OutTradeNo: "reward-1",
+				TradeState: wechatpay.TradeStateUserPaying,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewWechatNativePaymentService(nil, repo, producer, "", logger.NewNoOpLogger())
+			err := svc.HandleCallback(context.Background(), tc.txn)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
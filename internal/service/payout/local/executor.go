@@ -0,0 +1,28 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"webook/internal/domain"
+	"webook/internal/service/payout"
+)
+
+// Executor 本地开发用的打款渠道，不会真的打款
+type Executor struct {
+	// 超过这个金额的提现都打款失败，方便在本地测试失败解冻的流程，0 表示不会失败
+	failAbove int64
+}
+
+func NewExecutor(failAbove int64) *Executor {
+	return &Executor{failAbove: failAbove}
+}
+
+func (e *Executor) Payout(ctx context.Context, w domain.Withdrawal) (string, error) {
+	if e.failAbove > 0 && w.Paid() > e.failAbove {
+		log.Println("模拟打款失败", w.Id, w.Paid())
+		return "", payout.ErrPayoutFailed
+	}
+	log.Println("模拟打款成功", w.Id, w.Uid, w.Paid())
+	return fmt.Sprintf("local-payout-%d", w.Id), nil
+}
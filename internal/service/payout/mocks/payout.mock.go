@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/payout/types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/payout/types.go -package=payoutmocks -destination=./internal/service/payout/mocks/payout.mock.go
+//
+
+// Package payoutmocks is a generated GoMock package.
+package payoutmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExecutor is a mock of Executor interface.
+type MockExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockExecutorMockRecorder
+	isgomock struct{}
+}
+
+// MockExecutorMockRecorder is the mock recorder for MockExecutor.
+type MockExecutorMockRecorder struct {
+	mock *MockExecutor
+}
+
+// NewMockExecutor creates a new mock instance.
+func NewMockExecutor(ctrl *gomock.Controller) *MockExecutor {
+	mock := &MockExecutor{ctrl: ctrl}
+	mock.recorder = &MockExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExecutor) EXPECT() *MockExecutorMockRecorder {
+	return m.recorder
+}
+
+// Payout mocks base method.
+func (m *MockExecutor) Payout(ctx context.Context, w domain.Withdrawal) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Payout", ctx, w)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Payout indicates an expected call of Payout.
+func (mr *MockExecutorMockRecorder) Payout(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Payout", reflect.TypeOf((*MockExecutor)(nil).Payout), ctx, w)
+}
@@ -0,0 +1,19 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+)
+
+// ErrPayoutFailed 渠道明确告诉我们打款失败了，钱没有出去
+var ErrPayoutFailed = errors.New("打款失败")
+
+// Executor 给用户打款的抽象，屏蔽不同打款渠道之间的区别
+// 同一笔提现重复调用，渠道必须保证只打一次款
+//
+//go:generate mockgen -source=./types.go -package=payoutmocks -destination=./mocks/payout.mock.go Executor
+type Executor interface {
+	// Payout 返回渠道的流水号
+	Payout(ctx context.Context, w domain.Withdrawal) (string, error)
+}
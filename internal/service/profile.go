@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	ErrInvalidHandle  = errors.New("handle 格式不对")
+	ErrReservedHandle = errors.New("handle 是保留字")
+	ErrHandleTaken    = repository.ErrDuplicateUser
+	ErrHandleCooldown = errors.New("handle 改得太频繁")
+	ErrInvalidAvatar  = errors.New("头像地址不对")
+)
+
+const (
+	// 改了 handle 之后要过这么久才能再改，避免主页地址老是变
+	handleCooldown = time.Hour * 24 * 30
+	// 头像地址最长这么长，跟数据库的字段一致
+	maxAvatarLen = 1024
+)
+
+// 字母开头，只能有小写字母、数字和下划线
+var handleRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{2,19}$`)
+
+// 这些留给系统用，或者容易被拿去冒充官方
+var reservedHandles = map[string]struct{}{
+	"admin": {}, "administrator": {}, "root": {}, "system": {}, "webook": {},
+	"official": {}, "support": {}, "help": {}, "api": {}, "www": {},
+	"users": {}, "articles": {}, "settings": {}, "login": {}, "signup": {},
+	"logout": {}, "notifications": {}, "null": {}, "undefined": {}, "me": {},
+}
+
+//go:generate mockgen -source=./profile.go -package=svcmocks -destination=./mocks/profile.mock.go ProfileService
+type ProfileService interface {
+	// SetHandle 第一次设置没有限制，之后改要隔 30 天。被别人用了返回 ErrHandleTaken
+	SetHandle(ctx context.Context, uid int64, handle string) error
+	// SetAvatar 空字符串就是用回默认头像
+	SetAvatar(ctx context.Context, uid int64, avatar string) error
+	SetPrivacy(ctx context.Context, uid int64, privacy domain.ProfilePrivacy) error
+	// PublicProfile 别人看到的个人主页，隐藏了的部分不会去查。
+	// 找不到返回 ErrUserNotFound
+	PublicProfile(ctx context.Context, handle string, offset, limit int) (domain.PublicProfile, error)
+}
+
+type profileService struct {
+	userRepo repository.UserRepository
+	artRepo  repository.ArticleRepository
+	intrRepo repository.InteractiveRepository
+}
+
+func NewProfileService(userRepo repository.UserRepository, artRepo repository.ArticleRepository,
+	intrRepo repository.InteractiveRepository) ProfileService {
+	return &profileService{
+		userRepo: userRepo,
+		artRepo:  artRepo,
+		intrRepo: intrRepo,
+	}
+}
+
+func (svc *profileService) SetHandle(ctx context.Context, uid int64, handle string) error {
+	handle = strings.ToLower(handle)
+	if !handleRegexp.MatchString(handle) {
+		return ErrInvalidHandle
+	}
+	if _, ok := reservedHandles[handle]; ok {
+		return ErrReservedHandle
+	}
+	u, err := svc.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if u.Handle == handle {
+		return nil
+	}
+	if u.Handle != "" && time.Since(u.HandleUtime) < handleCooldown {
+		return ErrHandleCooldown
+	}
+	return svc.userRepo.UpdateHandle(ctx, uid, handle)
+}
+
+func (svc *profileService) SetAvatar(ctx context.Context, uid int64, avatar string) error {
+	if avatar != "" {
+		if len(avatar) > maxAvatarLen {
+			return ErrInvalidAvatar
+		}
+		u, err := url.Parse(avatar)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return ErrInvalidAvatar
+		}
+	}
+	return svc.userRepo.UpdateAvatar(ctx, uid, avatar)
+}
+
+func (svc *profileService) SetPrivacy(ctx context.Context, uid int64, privacy domain.ProfilePrivacy) error {
+	return svc.userRepo.UpdateProfilePrivacy(ctx, uid, privacy)
+}
+
+func (svc *profileService) PublicProfile(ctx context.Context, handle string,
+	offset, limit int) (domain.PublicProfile, error) {
+	u, err := svc.userRepo.FindByHandle(ctx, strings.ToLower(handle))
+	if err != nil {
+		return domain.PublicProfile{}, err
+	}
+	res := domain.PublicProfile{User: u}
+	if !u.ProfilePrivacy.Hidden(domain.ProfileHideArticles) {
+		res.Articles, err = svc.artRepo.ListPubByAuthor(ctx, u.Id, offset, limit)
+		if err != nil {
+			return domain.PublicProfile{}, err
+		}
+	}
+	if !u.ProfilePrivacy.Hidden(domain.ProfileHideInteractions) {
+		res.Interactive, err = svc.intrRepo.SumArticlesByAuthor(ctx, u.Id)
+		if err != nil {
+			return domain.PublicProfile{}, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestProfileService_SetHandle(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.UserRepository
+		handle string
+
+		wantErr error
+	}{
+		{
+			name: "第一次设置",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{Id: 123}, nil)
+				repo.EXPECT().UpdateHandle(gomock.Any(), int64(123), "tom_1").Return(nil)
+				return repo
+			},
+			handle: "Tom_1",
+		},
+		{
+			name: "格式不对",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			handle:  "1tom",
+			wantErr: ErrInvalidHandle,
+		},
+		{
+			name: "保留字",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			handle:  "admin",
+			wantErr: ErrReservedHandle,
+		},
+		{
+			name: "没变",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Handle: "tom", HandleUtime: time.Now()}, nil)
+				return repo
+			},
+			handle: "tom",
+		},
+		{
+			name: "刚改过",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Handle: "tom", HandleUtime: time.Now().Add(-time.Hour)}, nil)
+				return repo
+			},
+			handle:  "jerry",
+			wantErr: ErrHandleCooldown,
+		},
+		{
+			name: "过了冷却期",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Handle: "tom", HandleUtime: time.Now().Add(-handleCooldown)}, nil)
+				repo.EXPECT().UpdateHandle(gomock.Any(), int64(123), "jerry").Return(nil)
+				return repo
+			},
+			handle: "jerry",
+		},
+		{
+			name: "被别人用了",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{Id: 123}, nil)
+				repo.EXPECT().UpdateHandle(gomock.Any(), int64(123), "jerry").
+					Return(repository.ErrDuplicateUser)
+				return repo
+			},
+			handle:  "jerry",
+			wantErr: ErrHandleTaken,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewProfileService(tc.mock(ctrl), nil, nil)
+			err := svc.SetHandle(context.Background(), 123, tc.handle)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestProfileService_PublicProfile(t *testing.T) {
+	testCases := []struct {
+		name    string
+		privacy domain.ProfilePrivacy
+		mock    func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.InteractiveRepository)
+
+		wantProfile domain.PublicProfile
+	}{
+		{
+			name: "全部公开",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.InteractiveRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				intrRepo := repomocks.NewMockInteractiveRepository(ctrl)
+				artRepo.EXPECT().ListPubByAuthor(gomock.Any(), int64(123), 0, 10).
+					Return([]domain.Article{{Id: 1}}, nil)
+				intrRepo.EXPECT().SumArticlesByAuthor(gomock.Any(), int64(123)).
+					Return(domain.Interactive{ReadCnt: 10, LikeCnt: 2}, nil)
+				return artRepo, intrRepo
+			},
+			wantProfile: domain.PublicProfile{
+				User:        domain.User{Id: 123, Handle: "tom"},
+				Articles:    []domain.Article{{Id: 1}},
+				Interactive: domain.Interactive{ReadCnt: 10, LikeCnt: 2},
+			},
+		},
+		{
+			name:    "隐藏了文章和互动数据",
+			privacy: domain.ProfileHideArticles | domain.ProfileHideInteractions,
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository, repository.InteractiveRepository) {
+				return repomocks.NewMockArticleRepository(ctrl), repomocks.NewMockInteractiveRepository(ctrl)
+			},
+			wantProfile: domain.PublicProfile{
+				User: domain.User{Id: 123, Handle: "tom",
+					ProfilePrivacy: domain.ProfileHideArticles | domain.ProfileHideInteractions},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userRepo := repomocks.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().FindByHandle(gomock.Any(), "tom").
+				Return(domain.User{Id: 123, Handle: "tom", ProfilePrivacy: tc.privacy}, nil)
+			artRepo, intrRepo := tc.mock(ctrl)
+			svc := NewProfileService(userRepo, artRepo, intrRepo)
+			p, err := svc.PublicProfile(context.Background(), "Tom", 0, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantProfile, p)
+		})
+	}
+}
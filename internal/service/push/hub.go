@@ -0,0 +1,104 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"webook/internal/domain"
+	"webook/pkg/logger"
+)
+
+var ErrTooManySessions = errors.New("连接数太多")
+
+// Session 一个设备的一条长连接
+type Session struct {
+	uid int64
+	ch  chan domain.NotificationPush
+}
+
+func (s *Session) Uid() int64 {
+	return s.uid
+}
+
+// Messages 要推给这个设备的消息，Hub 注销这个连接之后会关闭
+func (s *Session) Messages() <-chan domain.NotificationPush {
+	return s.ch
+}
+
+// Hub 管理连在本实例上的所有连接，从 Broker 收到消息之后投递给对应的用户
+type Hub struct {
+	broker Broker
+	l      logger.LoggerV1
+
+	mutex    sync.RWMutex
+	sessions map[int64]map[*Session]struct{}
+
+	// 每个用户最多几个设备同时在线
+	maxSessions int
+	// 每个连接最多积压多少条消息，客户端太慢就丢掉，反正未读数会跟着下一条消息更新
+	bufferSize int
+}
+
+func NewHub(broker Broker, l logger.LoggerV1) *Hub {
+	return &Hub{
+		broker:      broker,
+		l:           l,
+		sessions:    make(map[int64]map[*Session]struct{}),
+		maxSessions: 8,
+		bufferSize:  16,
+	}
+}
+
+// Start 订阅 Broker，实现了 events.Consumer，跟着消费者一起启动
+func (h *Hub) Start() error {
+	go func() {
+		err := h.broker.Subscribe(context.Background(), h.Dispatch)
+		if err != nil {
+			h.l.Error("退出推送订阅", logger.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (h *Hub) Register(uid int64) (*Session, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	ss, ok := h.sessions[uid]
+	if !ok {
+		ss = make(map[*Session]struct{}, 1)
+		h.sessions[uid] = ss
+	}
+	if len(ss) >= h.maxSessions {
+		return nil, ErrTooManySessions
+	}
+	s := &Session{uid: uid, ch: make(chan domain.NotificationPush, h.bufferSize)}
+	ss[s] = struct{}{}
+	return s, nil
+}
+
+func (h *Hub) Unregister(s *Session) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	ss := h.sessions[s.uid]
+	if _, ok := ss[s]; !ok {
+		return
+	}
+	delete(ss, s)
+	if len(ss) == 0 {
+		delete(h.sessions, s.uid)
+	}
+	close(s.ch)
+}
+
+// Dispatch 投递给本实例上这个用户的所有设备，用户不在这个实例上就什么都不做
+func (h *Hub) Dispatch(msg domain.NotificationPush) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	for s := range h.sessions[msg.Uid] {
+		select {
+		case s.ch <- msg:
+		default:
+			h.l.Warn("推送消息积压，丢弃", logger.Int64("uid", msg.Uid))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package push
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"webook/internal/domain"
+	"webook/pkg/logger"
+)
+
+func TestHub_Dispatch(t *testing.T) {
+	hub := NewHub(nil, logger.NewNoOpLogger())
+	phone, err := hub.Register(123)
+	require.NoError(t, err)
+	pc, err := hub.Register(123)
+	require.NoError(t, err)
+	other, err := hub.Register(456)
+	require.NoError(t, err)
+
+	msg := domain.NotificationPush{
+		Uid:    123,
+		Unread: map[domain.NotificationType]int64{domain.NotificationTypeLike: 1},
+	}
+	hub.Dispatch(msg)
+	// 同一个用户的每个设备都能收到
+	assert.Equal(t, msg, <-phone.Messages())
+	assert.Equal(t, msg, <-pc.Messages())
+	assert.Len(t, other.Messages(), 0)
+
+	// 断开之后不再投递
+	hub.Unregister(pc)
+	hub.Dispatch(msg)
+	assert.Equal(t, msg, <-phone.Messages())
+	_, ok := <-pc.Messages()
+	assert.False(t, ok)
+	// 重复注销不会 panic
+	hub.Unregister(pc)
+}
+
+func TestHub_Register(t *testing.T) {
+	hub := NewHub(nil, logger.NewNoOpLogger())
+	hub.maxSessions = 2
+	for i := 0; i < 2; i++ {
+		_, err := hub.Register(123)
+		require.NoError(t, err)
+	}
+	_, err := hub.Register(123)
+	assert.Equal(t, ErrTooManySessions, err)
+	// 别的用户不受影响
+	_, err = hub.Register(456)
+	assert.NoError(t, err)
+}
+
+func TestHub_DispatchSlowSession(t *testing.T) {
+	hub := NewHub(nil, logger.NewNoOpLogger())
+	hub.bufferSize = 1
+	s, err := hub.Register(123)
+	require.NoError(t, err)
+	hub.Dispatch(domain.NotificationPush{Uid: 123})
+	// 客户端没有读，第二条直接丢掉，不能卡住其他用户
+	hub.Dispatch(domain.NotificationPush{Uid: 123})
+	assert.Len(t, s.Messages(), 1)
+}
@@ -0,0 +1,56 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"webook/internal/domain"
+)
+
+// RedisBroker 基于 Redis 的 pub/sub，所有实例订阅同一个 channel，
+// 消息丢了也没关系，客户端重连之后会重新拉一次未读数
+type RedisBroker struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+func NewRedisBroker(client redis.UniversalClient) Broker {
+	return &RedisBroker{
+		client:  client,
+		channel: "notification:push",
+	}
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, msg domain.NotificationPush) error {
+	val, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, val).Err()
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, handle func(msg domain.NotificationPush)) error {
+	ps := b.client.Subscribe(ctx, b.channel)
+	defer ps.Close()
+	// 确认订阅成功，后面断线了 go-redis 会自己重连
+	_, err := ps.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	ch := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case m, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			var msg domain.NotificationPush
+			if json.Unmarshal([]byte(m.Payload), &msg) != nil {
+				continue
+			}
+			handle(msg)
+		}
+	}
+}
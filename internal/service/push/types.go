@@ -0,0 +1,13 @@
+package push
+
+import (
+	"context"
+	"webook/internal/domain"
+)
+
+// Broker 在多个实例之间广播推送消息，每个实例只投递给连在自己身上的用户
+type Broker interface {
+	Publish(ctx context.Context, msg domain.NotificationPush) error
+	// Subscribe 会一直阻塞，直到 ctx 结束或者订阅出错
+	Subscribe(ctx context.Context, handle func(msg domain.NotificationPush)) error
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var ErrUnknownRole = errors.New("没有这个角色")
+
+//go:generate mockgen -source=./rbac.go -package=svcmocks -destination=./mocks/rbac.mock.go RBACService
+type RBACService interface {
+	Roles(ctx context.Context, uid int64) (domain.Roles, error)
+	HasPermission(ctx context.Context, uid int64, perm string) (bool, error)
+	// Grant 已经有这个角色了也不报错
+	Grant(ctx context.Context, uid int64, role string, operator int64) error
+	Revoke(ctx context.Context, uid int64, role string) error
+}
+
+type rbacService struct {
+	repo repository.UserRoleRepository
+	// 配置文件里面的超级管理员，不用授权就是 admin，防止把自己锁在外面
+	superAdmins map[int64]struct{}
+}
+
+func NewRBACService(repo repository.UserRoleRepository, superAdmins []int64) RBACService {
+	m := make(map[int64]struct{}, len(superAdmins))
+	for _, uid := range superAdmins {
+		m[uid] = struct{}{}
+	}
+	return &rbacService{repo: repo, superAdmins: m}
+}
+
+func (svc *rbacService) Roles(ctx context.Context, uid int64) (domain.Roles, error) {
+	roles, err := svc.repo.FindRoles(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := svc.superAdmins[uid]; ok {
+		roles = append(roles, domain.RoleAdmin)
+	}
+	return roles, nil
+}
+
+func (svc *rbacService) HasPermission(ctx context.Context, uid int64, perm string) (bool, error) {
+	if _, ok := svc.superAdmins[uid]; ok {
+		return true, nil
+	}
+	roles, err := svc.repo.FindRoles(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return roles.HasPermission(perm), nil
+}
+
+func (svc *rbacService) Grant(ctx context.Context, uid int64, role string, operator int64) error {
+	if _, ok := domain.RolePermissions[role]; !ok {
+		return ErrUnknownRole
+	}
+	return svc.repo.AddRole(ctx, uid, role, operator)
+}
+
+func (svc *rbacService) Revoke(ctx context.Context, uid int64, role string) error {
+	if _, ok := domain.RolePermissions[role]; !ok {
+		return ErrUnknownRole
+	}
+	return svc.repo.RemoveRole(ctx, uid, role)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestRBACService_HasPermission(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRoleRepository
+
+		uid     int64
+		perm    string
+		wantOk  bool
+		wantErr error
+	}{
+		{
+			name: "配置里面的超级管理员",
+			mock: func(ctrl *gomock.Controller) repository.UserRoleRepository {
+				return repomocks.NewMockUserRoleRepository(ctrl)
+			},
+			uid:    1,
+			perm:   domain.PermRoleGrant,
+			wantOk: true,
+		},
+		{
+			name: "角色有这个权限",
+			mock: func(ctrl *gomock.Controller) repository.UserRoleRepository {
+				repo := repomocks.NewMockUserRoleRepository(ctrl)
+				repo.EXPECT().FindRoles(gomock.Any(), int64(123)).
+					Return(domain.Roles{domain.RoleSupport, domain.RoleModerator}, nil)
+				return repo
+			},
+			uid:    123,
+			perm:   domain.PermUserBan,
+			wantOk: true,
+		},
+		{
+			name: "角色没有这个权限",
+			mock: func(ctrl *gomock.Controller) repository.UserRoleRepository {
+				repo := repomocks.NewMockUserRoleRepository(ctrl)
+				repo.EXPECT().FindRoles(gomock.Any(), int64(123)).
+					Return(domain.Roles{domain.RoleModerator}, nil)
+				return repo
+			},
+			uid:  123,
+			perm: domain.PermWithdrawReview,
+		},
+		{
+			name: "普通用户",
+			mock: func(ctrl *gomock.Controller) repository.UserRoleRepository {
+				repo := repomocks.NewMockUserRoleRepository(ctrl)
+				repo.EXPECT().FindRoles(gomock.Any(), int64(123)).Return(domain.Roles{}, nil)
+				return repo
+			},
+			uid:  123,
+			perm: domain.PermUserRead,
+		},
+		{
+			name: "查询角色失败",
+			mock: func(ctrl *gomock.Controller) repository.UserRoleRepository {
+				repo := repomocks.NewMockUserRoleRepository(ctrl)
+				repo.EXPECT().FindRoles(gomock.Any(), int64(123)).Return(nil, errors.New("db error"))
+				return repo
+			},
+			uid:     123,
+			perm:    domain.PermUserRead,
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRBACService(tc.mock(ctrl), []int64{1})
+			ok, err := svc.HasPermission(context.Background(), tc.uid, tc.perm)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantOk, ok)
+		})
+	}
+}
+
+func TestRBACService_Grant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockUserRoleRepository(ctrl)
+	repo.EXPECT().AddRole(gomock.Any(), int64(123), domain.RoleFinance, int64(1)).Return(nil)
+	svc := NewRBACService(repo, []int64{1})
+	assert.NoError(t, svc.Grant(context.Background(), 123, domain.RoleFinance, 1))
+	assert.Equal(t, ErrUnknownRole, svc.Grant(context.Background(), 123, "root", 1))
+}
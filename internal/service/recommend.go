@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"math"
+	"sort"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+//go:generate mockgen -source=./recommend.go -package=svcmocks -destination=./mocks/recommend.mock.go RecommendService
+type RecommendService interface {
+	// Recommend 个性化推荐流，没有提前算好的结果（冷启动）就退化成热榜
+	Recommend(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error)
+	// Compute 给最近活跃的用户重新计算推荐结果
+	Compute(ctx context.Context) error
+}
+
+// 不同行为代表的兴趣强度，收藏 > 点赞 > 阅读
+const (
+	weightRead    = 1.0
+	weightLike    = 3.0
+	weightCollect = 5.0
+	// 关注了作者，相当于对这个作者有很强的兴趣
+	weightFollow = 10.0
+)
+
+type BatchRecommendService struct {
+	artSvc     ArticleService
+	intrSvc    InteractiveService
+	rankingSvc RankingService
+
+	historyRepo repository.HistoryRecordRepository
+	followRepo  repository.FollowRepository
+	repo        repository.RecommendRepository
+
+	// 控制批量获取的大小
+	batchSize int
+	// 每个用户保存多少条推荐结果
+	n int
+	// 只推荐这个时间段内发表的文章，也只看这个时间段内活跃的用户
+	window time.Duration
+	// 每种行为最多取多少条来计算兴趣
+	signalLimit int
+
+	// 文章本身的热度，只关心算法而不关心数学计算
+	popularityFunc func(intr domain.Interactive, utime time.Time) float64
+	l              logger.LoggerV1
+}
+
+func NewBatchRecommendService(artSvc ArticleService, intrSvc InteractiveService, rankingSvc RankingService,
+	historyRepo repository.HistoryRecordRepository, followRepo repository.FollowRepository,
+	repo repository.RecommendRepository, l logger.LoggerV1) RecommendService {
+	return &BatchRecommendService{
+		artSvc:      artSvc,
+		intrSvc:     intrSvc,
+		rankingSvc:  rankingSvc,
+		historyRepo: historyRepo,
+		followRepo:  followRepo,
+		repo:        repo,
+		batchSize:   100,
+		n:           100,
+		window:      7 * 24 * time.Hour,
+		signalLimit: 100,
+		popularityFunc: func(intr domain.Interactive, utime time.Time) float64 {
+			// 取对数，避免热度把个人兴趣完全盖过去
+			hot := math.Log1p(float64(intr.ReadCnt) +
+				float64(intr.LikeCnt)*weightLike + float64(intr.CollectCnt)*weightCollect)
+			hours := time.Since(utime).Hours()
+			return hot / math.Pow(hours+2, 0.5)
+		},
+		l: l,
+	}
+}
+
+func (b *BatchRecommendService) Recommend(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
+	items, err := b.repo.GetRecommend(ctx, uid, offset, limit)
+	if err != nil {
+		// 缓存出问题了，推荐流也不能挂，退化成热榜
+		b.l.Error("获取推荐结果失败", logger.Int64("uid", uid), logger.Error(err))
+	}
+	if len(items) == 0 {
+		if err == nil && offset > 0 && b.hasRecommend(ctx, uid) {
+			// 个性化的推荐已经翻完了，不能接着翻热榜，不然会跟前面的重复
+			return []domain.Article{}, nil
+		}
+		return b.fallback(ctx, offset, limit)
+	}
+	ids := slice.Map[domain.RecommendItem, int64](items, func(idx int, src domain.RecommendItem) int64 {
+		return src.Aid
+	})
+	arts, err := b.artSvc.ListPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	// 数据库返回的顺序不是推荐的顺序，并且算好之后可能有文章被撤回了
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	res := make([]domain.Article, 0, len(ids))
+	for _, id := range ids {
+		if art, ok := artMap[id]; ok {
+			res = append(res, art)
+		}
+	}
+	return res, nil
+}
+
+// hasRecommend 看看用户有没有算好的推荐结果，查不到就当成没有，前面几页也是热榜
+func (b *BatchRecommendService) hasRecommend(ctx context.Context, uid int64) bool {
+	items, err := b.repo.GetRecommend(ctx, uid, 0, 1)
+	return err == nil && len(items) > 0
+}
+
+func (b *BatchRecommendService) fallback(ctx context.Context, offset, limit int) ([]domain.Article, error) {
+	arts, err := b.rankingSvc.GetTopN(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= len(arts) {
+		return []domain.Article{}, nil
+	}
+	end := offset + limit
+	if end > len(arts) {
+		end = len(arts)
+	}
+	return arts[offset:end], nil
+}
+
+func (b *BatchRecommendService) Compute(ctx context.Context) error {
+	start := time.Now().Add(-b.window)
+	candidates, err := b.candidates(ctx, start)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	for {
+		uids, err := b.historyRepo.FindActiveUids(ctx, start, offset, b.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, uid := range uids {
+			items, err := b.computeForUser(ctx, uid, start, candidates)
+			if err == nil {
+				err = b.repo.ReplaceRecommend(ctx, uid, items)
+			}
+			if err != nil {
+				// 一个用户失败了不影响别的用户
+				b.l.Error("计算推荐结果失败", logger.Int64("uid", uid), logger.Error(err))
+			}
+		}
+		if len(uids) < b.batchSize {
+			return nil
+		}
+		offset = offset + len(uids)
+	}
+}
+
+// candidate 候选文章和它自身的热度
+type candidate struct {
+	art        domain.Article
+	popularity float64
+}
+
+// candidates 找出时间窗口内发表的文章作为候选集
+func (b *BatchRecommendService) candidates(ctx context.Context, start time.Time) ([]candidate, error) {
+	offset := 0
+	now := time.Now()
+	var res []candidate
+	for {
+		arts, err := b.artSvc.ListPub(ctx, now, offset, b.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(arts) == 0 {
+			break
+		}
+		ids := slice.Map[domain.Article, int64](arts, func(idx int, art domain.Article) int64 {
+			return art.Id
+		})
+		intrMap, err := b.intrSvc.GetByIds(ctx, "article", ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, art := range arts {
+			if art.Utime.Before(start) {
+				continue
+			}
+			res = append(res, candidate{
+				art:        art,
+				popularity: b.popularityFunc(intrMap[art.Id], art.Utime),
+			})
+		}
+		offset = offset + len(arts)
+		if len(arts) < b.batchSize || arts[len(arts)-1].Utime.Before(start) {
+			break
+		}
+	}
+	return res, nil
+}
+
+// computeForUser 根据用户的阅读、点赞、收藏记录和关注的作者算出对标签和作者的偏好，
+// 再叠加文章本身的热度打分
+func (b *BatchRecommendService) computeForUser(ctx context.Context, uid int64, start time.Time,
+	candidates []candidate) ([]domain.RecommendItem, error) {
+	records, err := b.historyRepo.FindByUid(ctx, uid, start, b.signalLimit)
+	if err != nil {
+		return nil, err
+	}
+	likedIds, err := b.intrSvc.LikedBizIds(ctx, "article", uid, b.signalLimit)
+	if err != nil {
+		return nil, err
+	}
+	collectedIds, err := b.intrSvc.CollectedBizIds(ctx, "article", uid, b.signalLimit)
+	if err != nil {
+		return nil, err
+	}
+	followees, err := b.followRepo.GetFollowee(ctx, uid, 0, int64(b.signalLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	// 每篇看过的文章代表的兴趣强度
+	signals := make(map[int64]float64, len(records)+len(likedIds)+len(collectedIds))
+	for _, r := range records {
+		signals[r.BizId] += weightRead
+	}
+	for _, id := range likedIds {
+		signals[id] += weightLike
+	}
+	for _, id := range collectedIds {
+		signals[id] += weightCollect
+	}
+
+	tagAffinity := make(map[string]float64)
+	authorAffinity := make(map[int64]float64)
+	if len(signals) > 0 {
+		ids := make([]int64, 0, len(signals))
+		for id := range signals {
+			ids = append(ids, id)
+		}
+		arts, err := b.artSvc.ListPubByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, art := range arts {
+			w := signals[art.Id]
+			for _, tag := range art.Tags {
+				tagAffinity[tag] += w
+			}
+			authorAffinity[art.Author.Id] += w
+		}
+	}
+	for _, f := range followees {
+		authorAffinity[f.Followee] += weightFollow
+	}
+
+	res := make([]domain.RecommendItem, 0, len(candidates))
+	for _, c := range candidates {
+		// 自己写的和已经看过的都不推荐
+		if c.art.Author.Id == uid {
+			continue
+		}
+		if _, ok := signals[c.art.Id]; ok {
+			continue
+		}
+		score := c.popularity
+		for _, tag := range c.art.Tags {
+			score += math.Log1p(tagAffinity[tag])
+		}
+		score += math.Log1p(authorAffinity[c.art.Author.Id])
+		res = append(res, domain.RecommendItem{
+			Aid:   c.art.Id,
+			Score: score,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+	if len(res) > b.n {
+		res = res[:b.n]
+	}
+	return res, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestBatchRecommendService_Recommend(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (ArticleService, RankingService, repository.RecommendRepository)
+
+		offset   int
+		limit    int
+		wantArts []domain.Article
+		wantErr  error
+	}{
+		{
+			name: "有推荐结果，按照推荐顺序返回，跳过已经撤回的文章",
+			mock: func(ctrl *gomock.Controller) (ArticleService, RankingService, repository.RecommendRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				rankingSvc := svcmocks.NewMockRankingService(ctrl)
+				repo := repomocks.NewMockRecommendRepository(ctrl)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 0, 3).
+					Return([]domain.RecommendItem{
+						{Aid: 3, Score: 3},
+						{Aid: 1, Score: 2},
+						{Aid: 2, Score: 1},
+					}, nil)
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{3, 1, 2}).
+					Return([]domain.Article{
+						{Id: 1},
+						{Id: 3},
+					}, nil)
+				return artSvc, rankingSvc, repo
+			},
+			offset: 0,
+			limit:  3,
+			wantArts: []domain.Article{
+				{Id: 3},
+				{Id: 1},
+			},
+		},
+		{
+			name: "冷启动，退化成热榜",
+			mock: func(ctrl *gomock.Controller) (ArticleService, RankingService, repository.RecommendRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				rankingSvc := svcmocks.NewMockRankingService(ctrl)
+				repo := repomocks.NewMockRecommendRepository(ctrl)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 1, 2).
+					Return(nil, nil)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 0, 1).
+					Return(nil, nil)
+				rankingSvc.EXPECT().GetTopN(gomock.Any()).
+					Return([]domain.Article{
+						{Id: 1},
+						{Id: 2},
+						{Id: 3},
+						{Id: 4},
+					}, nil)
+				return artSvc, rankingSvc, repo
+			},
+			offset: 1,
+			limit:  2,
+			wantArts: []domain.Article{
+				{Id: 2},
+				{Id: 3},
+			},
+		},
+		{
+			name: "推荐结果翻完了，不接着翻热榜",
+			mock: func(ctrl *gomock.Controller) (ArticleService, RankingService, repository.RecommendRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				rankingSvc := svcmocks.NewMockRankingService(ctrl)
+				repo := repomocks.NewMockRecommendRepository(ctrl)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 100, 2).
+					Return(nil, nil)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 0, 1).
+					Return([]domain.RecommendItem{{Aid: 3, Score: 3}}, nil)
+				return artSvc, rankingSvc, repo
+			},
+			offset:   100,
+			limit:    2,
+			wantArts: []domain.Article{},
+		},
+		{
+			name: "缓存出错，退化成热榜",
+			mock: func(ctrl *gomock.Controller) (ArticleService, RankingService, repository.RecommendRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				rankingSvc := svcmocks.NewMockRankingService(ctrl)
+				repo := repomocks.NewMockRecommendRepository(ctrl)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 0, 2).
+					Return(nil, errors.New("redis 错误"))
+				rankingSvc.EXPECT().GetTopN(gomock.Any()).
+					Return([]domain.Article{
+						{Id: 1},
+					}, nil)
+				return artSvc, rankingSvc, repo
+			},
+			offset: 0,
+			limit:  2,
+			wantArts: []domain.Article{
+				{Id: 1},
+			},
+		},
+		{
+			name: "查询文章失败",
+			mock: func(ctrl *gomock.Controller) (ArticleService, RankingService, repository.RecommendRepository) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				rankingSvc := svcmocks.NewMockRankingService(ctrl)
+				repo := repomocks.NewMockRecommendRepository(ctrl)
+				repo.EXPECT().GetRecommend(gomock.Any(), int64(123), 0, 1).
+					Return([]domain.RecommendItem{{Aid: 1}}, nil)
+				artSvc.EXPECT().ListPubByIds(gomock.Any(), []int64{1}).
+					Return(nil, errors.New("mock db 错误"))
+				return artSvc, rankingSvc, repo
+			},
+			offset:  0,
+			limit:   1,
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			artSvc, rankingSvc, repo := tc.mock(ctrl)
+			svc := &BatchRecommendService{
+				artSvc:     artSvc,
+				rankingSvc: rankingSvc,
+				repo:       repo,
+				l:          logger.NewNoOpLogger(),
+			}
+			arts, err := svc.Recommend(context.Background(), 123, tc.offset, tc.limit)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArts, arts)
+		})
+	}
+}
+
+func TestBatchRecommendService_computeForUser(t *testing.T) {
+	const uid = 123
+	start := time.Now().Add(-time.Hour)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+	historyRepo := repomocks.NewMockHistoryRecordRepository(ctrl)
+	followRepo := repomocks.NewMockFollowRepository(ctrl)
+
+	historyRepo.EXPECT().FindByUid(gomock.Any(), int64(uid), start, 10).
+		Return([]domain.HistoryRecord{{Uid: uid, Biz: "article", BizId: 1}}, nil)
+	intrSvc.EXPECT().LikedBizIds(gomock.Any(), "article", int64(uid), 10).
+		Return([]int64{2}, nil)
+	intrSvc.EXPECT().CollectedBizIds(gomock.Any(), "article", int64(uid), 10).
+		Return([]int64{}, nil)
+	followRepo.EXPECT().GetFollowee(gomock.Any(), int64(uid), int64(0), int64(10)).
+		Return([]domain.FollowRelation{}, nil)
+	// 看过 go 和 redis，点赞过作者 2 的文章
+	artSvc.EXPECT().ListPubByIds(gomock.Any(), gomock.Any()).
+		Return([]domain.Article{
+			{Id: 1, Tags: []string{"go"}, Author: domain.Author{Id: 1}},
+			{Id: 2, Tags: []string{"redis"}, Author: domain.Author{Id: 2}},
+		}, nil)
+
+	svc := &BatchRecommendService{
+		artSvc:      artSvc,
+		intrSvc:     intrSvc,
+		historyRepo: historyRepo,
+		followRepo:  followRepo,
+		n:           3,
+		signalLimit: 10,
+	}
+	items, err := svc.computeForUser(context.Background(), uid, start, []candidate{
+		// 已经读过了
+		{art: domain.Article{Id: 1, Tags: []string{"go"}, Author: domain.Author{Id: 1}}},
+		// 自己写的
+		{art: domain.Article{Id: 3, Tags: []string{"go"}, Author: domain.Author{Id: uid}}},
+		// 标签和作者都是用户喜欢的
+		{art: domain.Article{Id: 4, Tags: []string{"redis"}, Author: domain.Author{Id: 2}}},
+		// 只有标签匹配
+		{art: domain.Article{Id: 5, Tags: []string{"go"}, Author: domain.Author{Id: 5}}},
+		// 和用户兴趣无关，但是很热门
+		{art: domain.Article{Id: 6, Tags: []string{"java"}, Author: domain.Author{Id: 6}}, popularity: 0.5},
+		// 什么都不沾
+		{art: domain.Article{Id: 7, Author: domain.Author{Id: 7}}},
+	})
+	assert.NoError(t, err)
+	aids := make([]int64, 0, len(items))
+	for _, item := range items {
+		aids = append(aids, item.Aid)
+	}
+	assert.Equal(t, []int64{4, 5, 6}, aids)
+}
+
+func TestBatchRecommendService_computeForUser_Followee(t *testing.T) {
+	const uid = 123
+	start := time.Now().Add(-time.Hour)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+	historyRepo := repomocks.NewMockHistoryRecordRepository(ctrl)
+	followRepo := repomocks.NewMockFollowRepository(ctrl)
+
+	// 没有任何阅读、点赞、收藏记录，只关注了作者 8
+	historyRepo.EXPECT().FindByUid(gomock.Any(), int64(uid), start, 10).
+		Return([]domain.HistoryRecord{}, nil)
+	intrSvc.EXPECT().LikedBizIds(gomock.Any(), "article", int64(uid), 10).
+		Return([]int64{}, nil)
+	intrSvc.EXPECT().CollectedBizIds(gomock.Any(), "article", int64(uid), 10).
+		Return([]int64{}, nil)
+	followRepo.EXPECT().GetFollowee(gomock.Any(), int64(uid), int64(0), int64(10)).
+		Return([]domain.FollowRelation{{Follower: uid, Followee: 8}}, nil)
+
+	svc := &BatchRecommendService{
+		artSvc:      artSvc,
+		intrSvc:     intrSvc,
+		historyRepo: historyRepo,
+		followRepo:  followRepo,
+		n:           3,
+		signalLimit: 10,
+	}
+	items, err := svc.computeForUser(context.Background(), uid, start, []candidate{
+		// 很热门，但是作者没有关注
+		{art: domain.Article{Id: 1, Author: domain.Author{Id: 1}}, popularity: 1},
+		// 关注的作者写的
+		{art: domain.Article{Id: 2, Author: domain.Author{Id: 8}}},
+		// 什么都不沾
+		{art: domain.Article{Id: 3, Author: domain.Author{Id: 3}}},
+	})
+	assert.NoError(t, err)
+	aids := make([]int64, 0, len(items))
+	for _, item := range items {
+		aids = append(aids, item.Aid)
+	}
+	assert.Equal(t, []int64{2, 1, 3}, aids)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"strings"
+	accountv1 "webook/api/proto/gen/account/v1"
+	pmtv1 "webook/api/proto/gen/payment/v1"
+	"webook/internal/domain"
+	"webook/internal/events/activity"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrRewardNotFound   = repository.ErrRewardNotFound
+	ErrNotRewardTradeNO = errors.New("不是打赏的支付单号")
+)
+
+// 打赏的支付单号是 reward-{rid}
+const rewardBizTradeNOPrefix = "reward-"
+
+//go:generate mockgen -source=./reward.go -package=svcmocks -destination=./mocks/reward.mock.go RewardService
+type RewardService interface {
+	// PreReward 创建打赏和对应的支付，返回支付二维码
+	PreReward(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
+	// GetReward 只能查自己的打赏
+	GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error)
+	// UpdateReward 支付有了结果之后更新打赏，支付成功就给作者入账
+	UpdateReward(ctx context.Context, bizTradeNO string, status domain.RewardStatus) error
+}
+
+type WechatNativeRewardService struct {
+	client        pmtv1.WechatPaymentServiceClient
+	accountClient accountv1.AccountServiceClient
+	repo          repository.RewardRepository
+	producer      activity.Producer
+	// 平台抽成的百分比
+	platformRate int64
+	l            logger.LoggerV1
+}
+
+func NewWechatNativeRewardService(client pmtv1.WechatPaymentServiceClient,
+	accountClient accountv1.AccountServiceClient, repo repository.RewardRepository,
+	producer activity.Producer, platformRate int64, l logger.LoggerV1) RewardService {
+	return &WechatNativeRewardService{
+		client:        client,
+		accountClient: accountClient,
+		repo:          repo,
+		producer:      producer,
+		platformRate:  platformRate,
+		l:             l,
+	}
+}
+
+func (s *WechatNativeRewardService) PreReward(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	cu, err := s.repo.GetCachedCodeURL(ctx, r)
+	if err == nil && s.reusable(ctx, cu) {
+		return cu, nil
+	}
+	r.Status = domain.RewardStatusInit
+	rid, err := s.repo.CreateReward(ctx, r)
+	if err != nil {
+		return domain.CodeURL{}, err
+	}
+	resp, err := s.client.NativePrePay(ctx, &pmtv1.PrePayRequest{
+		Amt: &pmtv1.Amount{
+			Total:    r.Amt,
+			Currency: "CNY",
+		},
+		BizTradeNo:  s.bizTradeNO(rid),
+		Description: fmt.Sprintf("打赏-%s", r.Target.BizName),
+	})
+	if err != nil {
+		// 超时的时候支付可能已经创建了，打赏保持等待支付，后面查询的时候再确认
+		return domain.CodeURL{}, err
+	}
+	cu = domain.CodeURL{
+		Rid: rid,
+		URL: resp.CodeUrl,
+	}
+	err = s.repo.CachedCodeURL(ctx, cu, r)
+	if err != nil {
+		s.l.Error("缓存打赏二维码失败",
+			logger.Int64("rid", rid),
+			logger.Error(err))
+	}
+	return cu, nil
+}
+
+// reusable 缓存的二维码对应的打赏还在等待支付才能复用，
+// 已经付过钱或者失败了的要重新下单
+func (s *WechatNativeRewardService) reusable(ctx context.Context, cu domain.CodeURL) bool {
+	r, err := s.repo.GetReward(ctx, cu.Rid)
+	return err == nil && !r.Completed()
+}
+
+func (s *WechatNativeRewardService) GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error) {
+	r, err := s.repo.GetReward(ctx, rid)
+	if err != nil {
+		return domain.Reward{}, err
+	}
+	if r.Uid != uid {
+		// 别人的打赏当作不存在
+		return domain.Reward{}, ErrRewardNotFound
+	}
+	if r.Completed() {
+		return r, nil
+	}
+	// 支付的通知可能还没到，或者丢了，主动问一下支付服务
+	resp, err := s.client.GetPayment(ctx, &pmtv1.GetPaymentRequest{
+		BizTradeNo: s.bizTradeNO(rid),
+	})
+	if err != nil {
+		s.l.Error("查询打赏的支付状态失败",
+			logger.Int64("rid", rid),
+			logger.Error(err))
+		return r, nil
+	}
+	switch resp.GetStatus() {
+	case pmtv1.PaymentStatus_PaymentStatusSuccess:
+		r.Status = domain.RewardStatusPayed
+	case pmtv1.PaymentStatus_PaymentStatusFailed:
+		r.Status = domain.RewardStatusFailed
+	default:
+		return r, nil
+	}
+	err = s.UpdateReward(ctx, s.bizTradeNO(rid), r.Status)
+	if err != nil {
+		s.l.Error("更新打赏状态失败",
+			logger.Int64("rid", rid),
+			logger.Error(err))
+	}
+	return r, nil
+}
+
+func (s *WechatNativeRewardService) UpdateReward(ctx context.Context, bizTradeNO string, status domain.RewardStatus) error {
+	rid, err := s.toRid(bizTradeNO)
+	if err != nil {
+		return err
+	}
+	r, err := s.repo.GetReward(ctx, rid)
+	if err != nil {
+		return err
+	}
+	if r.Completed() {
+		// 支付通知和主动查询都会走到这里，已经处理过了
+		return nil
+	}
+	if status == domain.RewardStatusPayed {
+		// 先入账再改状态，入账是幂等的，中间失败了可以重来
+		err = s.credit(ctx, r)
+		if err != nil {
+			return err
+		}
+	}
+	ok, err := s.repo.UpdateStatus(ctx, r, status)
+	if err != nil || !ok || status != domain.RewardStatusPayed {
+		return err
+	}
+	// 只有真正改了状态的那一次才通知
+	er := s.producer.ProduceRewardEvent(activity.RewardEvent{
+		Rid:       r.Id,
+		Uid:       r.Uid,
+		Biz:       r.Target.Biz,
+		BizId:     r.Target.BizId,
+		BizName:   r.Target.BizName,
+		TargetUid: r.Target.Uid,
+		Amt:       r.Amt,
+	})
+	if er != nil {
+		s.l.Error("发送打赏消息失败",
+			logger.Int64("rid", rid),
+			logger.Error(er))
+	}
+	return nil
+}
+
+// credit 按照平台抽成的比例，给作者和平台分别入账
+func (s *WechatNativeRewardService) credit(ctx context.Context, r domain.Reward) error {
+	systemAmt := r.Amt * s.platformRate / 100
+	_, err := s.accountClient.Credit(ctx, &accountv1.CreditRequest{
+		Biz:   "reward",
+		BizId: r.Id,
+		Items: []*accountv1.CreditItem{
+			{
+				AccountType: accountv1.AccountType_AccountTypeSystem,
+				Amt:         systemAmt,
+				Currency:    "CNY",
+			},
+			{
+				Uid:         r.Target.Uid,
+				AccountType: accountv1.AccountType_AccountTypeReward,
+				Amt:         r.Amt - systemAmt,
+				Currency:    "CNY",
+			},
+		},
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		// 之前已经入过账了
+		return nil
+	}
+	return err
+}
+
+func (s *WechatNativeRewardService) bizTradeNO(rid int64) string {
+	return rewardBizTradeNOPrefix + strconv.FormatInt(rid, 10)
+}
+
+func (s *WechatNativeRewardService) toRid(bizTradeNO string) (int64, error) {
+	if !strings.HasPrefix(bizTradeNO, rewardBizTradeNOPrefix) {
+		return 0, ErrNotRewardTradeNO
+	}
+	return strconv.ParseInt(strings.TrimPrefix(bizTradeNO, rewardBizTradeNOPrefix), 10, 64)
+}
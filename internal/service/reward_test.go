@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	accountv1 "webook/api/proto/gen/account/v1"
+	accountv1mocks "webook/api/proto/gen/account/v1/mocks"
+	pmtv1 "webook/api/proto/gen/payment/v1"
+	pmtv1mocks "webook/api/proto/gen/payment/v1/mocks"
+	"webook/internal/domain"
+	evtmocks "webook/internal/events/activity/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestWechatNativeRewardService_UpdateReward(t *testing.T) {
+	reward := domain.Reward{
+		Id:     1,
+		Uid:    123,
+		Target: domain.Target{Biz: "article", BizId: 2, Uid: 456},
+		Amt:    100,
+		Status: domain.RewardStatusInit,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient)
+
+		bizTradeNO string
+		status     domain.RewardStatus
+		// 支付成功要通知被打赏的人
+		wantEvent bool
+		wantErr   error
+	}{
+		{
+			name: "支付成功，按照比例入账",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				accountClient := accountv1mocks.NewMockAccountServiceClient(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(reward, nil)
+				accountClient.EXPECT().Credit(gomock.Any(), &accountv1.CreditRequest{
+					Biz:   "reward",
+					BizId: 1,
+					Items: []*accountv1.CreditItem{
+						{AccountType: accountv1.AccountType_AccountTypeSystem, Amt: 10, Currency: "CNY"},
+						{Uid: 456, AccountType: accountv1.AccountType_AccountTypeReward, Amt: 90, Currency: "CNY"},
+					},
+				}).Return(&accountv1.CreditResponse{}, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), reward, domain.RewardStatusPayed).Return(true, nil)
+				return repo, accountClient
+			},
+			bizTradeNO: "reward-1",
+			status:     domain.RewardStatusPayed,
+			wantEvent:  true,
+		},
+		{
+			name: "已经入过账了，只改状态",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				accountClient := accountv1mocks.NewMockAccountServiceClient(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(reward, nil)
+				accountClient.EXPECT().Credit(gomock.Any(), gomock.Any()).
+					Return(nil, status.Error(codes.AlreadyExists, "重复入账"))
+				repo.EXPECT().UpdateStatus(gomock.Any(), reward, domain.RewardStatusPayed).Return(true, nil)
+				return repo, accountClient
+			},
+			bizTradeNO: "reward-1",
+			status:     domain.RewardStatusPayed,
+			wantEvent:  true,
+		},
+		{
+			name: "入账失败，不改状态",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				accountClient := accountv1mocks.NewMockAccountServiceClient(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(reward, nil)
+				accountClient.EXPECT().Credit(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("mock 网络错误"))
+				return repo, accountClient
+			},
+			bizTradeNO: "reward-1",
+			status:     domain.RewardStatusPayed,
+			wantErr:    errors.New("mock 网络错误"),
+		},
+		{
+			name: "支付失败，不入账",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(reward, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), reward, domain.RewardStatusFailed).Return(true, nil)
+				return repo, accountv1mocks.NewMockAccountServiceClient(ctrl)
+			},
+			bizTradeNO: "reward-1",
+			status:     domain.RewardStatusFailed,
+		},
+		{
+			name: "已经处理过了",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				payed := reward
+				payed.Status = domain.RewardStatusPayed
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(payed, nil)
+				return repo, accountv1mocks.NewMockAccountServiceClient(ctrl)
+			},
+			bizTradeNO: "reward-1",
+			status:     domain.RewardStatusPayed,
+		},
+		{
+			name: "别的业务的支付",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, accountv1.AccountServiceClient) {
+				return repomocks.NewMockRewardRepository(ctrl), accountv1mocks.NewMockAccountServiceClient(ctrl)
+			},
+			bizTradeNO: "order-1",
+			status:     domain.RewardStatusPayed,
+			wantErr:    ErrNotRewardTradeNO,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, accountClient := tc.mock(ctrl)
+			producer := evtmocks.NewMockProducer(ctrl)
+			if tc.wantEvent {
+				producer.EXPECT().ProduceRewardEvent(gomock.Any()).Return(nil)
+			}
+			svc := NewWechatNativeRewardService(nil, accountClient, repo, producer, 10, logger.NewNoOpLogger())
+			err := svc.UpdateReward(context.Background(), tc.bizTradeNO, tc.status)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestWechatNativeRewardService_PreReward(t *testing.T) {
+	r := domain.Reward{
+		Uid:    123,
+		Target: domain.Target{Biz: "article", BizId: 2, BizName: "文章", Uid: 456},
+		Amt:    100,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.RewardRepository, pmtv1.WechatPaymentServiceClient)
+
+		wantCu  domain.CodeURL
+		wantErr error
+	}{
+		{
+			name: "还没支付，复用之前的二维码",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, pmtv1.WechatPaymentServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetCachedCodeURL(gomock.Any(), r).
+					Return(domain.CodeURL{Rid: 1, URL: "weixin://1"}, nil)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).
+					Return(domain.Reward{Id: 1, Status: domain.RewardStatusInit}, nil)
+				return repo, pmtv1mocks.NewMockWechatPaymentServiceClient(ctrl)
+			},
+			wantCu: domain.CodeURL{Rid: 1, URL: "weixin://1"},
+		},
+		{
+			name: "缓存的打赏已经付过钱了，重新下单",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, pmtv1.WechatPaymentServiceClient) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				client := pmtv1mocks.NewMockWechatPaymentServiceClient(ctrl)
+				repo.EXPECT().GetCachedCodeURL(gomock.Any(), r).
+					Return(domain.CodeURL{Rid: 1, URL: "weixin://1"}, nil)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).
+					Return(domain.Reward{Id: 1, Status: domain.RewardStatusPayed}, nil)
+				pending := r
+				pending.Status = domain.RewardStatusInit
+				repo.EXPECT().CreateReward(gomock.Any(), pending).Return(int64(2), nil)
+				client.EXPECT().NativePrePay(gomock.Any(), gomock.Any()).
+					Return(&pmtv1.NativePrePayResponse{CodeUrl: "weixin://2"}, nil)
+				repo.EXPECT().CachedCodeURL(gomock.Any(), domain.CodeURL{Rid: 2, URL: "weixin://2"}, pending).
+					Return(nil)
+				return repo, client
+			},
+			wantCu: domain.CodeURL{Rid: 2, URL: "weixin://2"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, client := tc.mock(ctrl)
+			svc := NewWechatNativeRewardService(client, accountv1mocks.NewMockAccountServiceClient(ctrl),
+				repo, evtmocks.NewMockProducer(ctrl), 10, logger.NewNoOpLogger())
+			cu, err := svc.PreReward(context.Background(), r)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCu, cu)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"webook/internal/service/storage"
+)
+
+// Storage 本地开发和单实例部署用，文件放在一个目录下面
+type Storage struct {
+	dir string
+}
+
+func NewStorage(dir string) *Storage {
+	return &Storage{dir: dir}
+}
+
+func (s *Storage) Put(ctx context.Context, key string, data []byte) error {
+	path := s.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (s *Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, storage.ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path key 是服务端自己生成的，这里还是防一下 ../ 跳出目录
+func (s *Storage) path(key string) string {
+	return filepath.Join(s.dir, filepath.Clean("/"+key))
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrObjectNotFound = errors.New("文件不存在")
+
+// Storage 存放导出包这类文件，屏蔽本地目录还是对象存储
+//
+//go:generate mockgen -source=./types.go -package=storagemocks -destination=./mocks/storage.mock.go Storage
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get 不存在返回 ErrObjectNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 不存在也算成功
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+	"webook/internal/repository"
+	"webook/pkg/totp"
+)
+
+const (
+	totpIssuer = "webook"
+	// 恢复码的个数和长度
+	recoveryCodeCnt = 10
+	recoveryCodeLen = 10
+	// 恢复码里面去掉了容易看错的 0 O 1 I L
+	recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("已经开启了两步验证")
+	ErrTwoFactorNotEnrolled = errors.New("还没有开启两步验证")
+	ErrInvalidTwoFactorCode = errors.New("两步验证的验证码错误")
+)
+
+//go:generate mockgen -source=./two_factor.go -package=svcmocks -destination=./mocks/two_factor.mock.go TwoFactorService
+type TwoFactorService interface {
+	// Enroll 生成新的密钥，返回密钥和给 App 扫码用的链接，要 Confirm 之后才算开启
+	Enroll(ctx context.Context, uid int64, account string) (string, string, error)
+	// Confirm 用 App 上的第一个验证码确认开启，返回恢复码明文，只有这一次能看到
+	Confirm(ctx context.Context, uid int64, code string) ([]string, error)
+	Enabled(ctx context.Context, uid int64) (bool, error)
+	// Verify code 可以是 App 上的验证码，也可以是恢复码
+	Verify(ctx context.Context, uid int64, code string) error
+	// Disable 关闭之前要再验证一次
+	Disable(ctx context.Context, uid int64, code string) error
+}
+
+type twoFactorService struct {
+	repo repository.TwoFactorRepository
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository) TwoFactorService {
+	return &twoFactorService{repo: repo}
+}
+
+func (svc *twoFactorService) Enroll(ctx context.Context, uid int64, account string) (string, string, error) {
+	tf, err := svc.repo.Find(ctx, uid)
+	switch {
+	case err == nil && tf.Enabled:
+		return "", "", ErrTwoFactorEnabled
+	case err != nil && err != repository.ErrTwoFactorNotFound:
+		return "", "", err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	err = svc.repo.SaveSecret(ctx, uid, secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, totp.URI(totpIssuer, account, secret), nil
+}
+
+func (svc *twoFactorService) Confirm(ctx context.Context, uid int64, code string) ([]string, error) {
+	tf, err := svc.repo.Find(ctx, uid)
+	switch {
+	case err == repository.ErrTwoFactorNotFound:
+		return nil, ErrTwoFactorNotEnrolled
+	case err != nil:
+		return nil, err
+	case tf.Enabled:
+		return nil, ErrTwoFactorEnabled
+	}
+	step, ok := totp.Verify(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	codes := make([]string, 0, recoveryCodeCnt)
+	hashes := make([]string, 0, recoveryCodeCnt)
+	for i := 0; i < recoveryCodeCnt; i++ {
+		c, err := svc.recoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, svc.hashRecoveryCode(c))
+	}
+	err = svc.repo.Enable(ctx, uid, step, hashes)
+	if err == repository.ErrTwoFactorNotFound {
+		// 并发确认，另外一个请求已经开启了
+		return nil, ErrTwoFactorEnabled
+	}
+	return codes, err
+}
+
+func (svc *twoFactorService) Enabled(ctx context.Context, uid int64) (bool, error) {
+	tf, err := svc.repo.Find(ctx, uid)
+	if err == repository.ErrTwoFactorNotFound {
+		return false, nil
+	}
+	return tf.Enabled, err
+}
+
+func (svc *twoFactorService) Verify(ctx context.Context, uid int64, code string) error {
+	tf, err := svc.repo.Find(ctx, uid)
+	switch {
+	case err == repository.ErrTwoFactorNotFound || err == nil && !tf.Enabled:
+		return ErrTwoFactorNotEnrolled
+	case err != nil:
+		return err
+	}
+	code = strings.TrimSpace(code)
+	if step, ok := totp.Verify(tf.Secret, code, time.Now()); ok {
+		// 时间步比上次用过的旧，说明验证码被重放了
+		ok, err = svc.repo.UseStep(ctx, uid, step)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	ok, err := svc.repo.UseRecoveryCode(ctx, uid, svc.hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (svc *twoFactorService) Disable(ctx context.Context, uid int64, code string) error {
+	err := svc.Verify(ctx, uid, code)
+	if err != nil {
+		return err
+	}
+	return svc.repo.Delete(ctx, uid)
+}
+
+func (svc *twoFactorService) recoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeLen)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = recoveryCodeAlphabet[n.Int64()]
+	}
+	// 中间加个横线方便抄
+	return string(buf[:recoveryCodeLen/2]) + "-" + string(buf[recoveryCodeLen/2:]), nil
+}
+
+// hashRecoveryCode 恢复码是随机生成的，熵足够，用 SHA256 就行了
+func (svc *twoFactorService) hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
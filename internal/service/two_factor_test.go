@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/totp"
+)
+
+const testTotpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTwoFactorService_Verify(t *testing.T) {
+	code, err := totp.Code(testTotpSecret, time.Now())
+	require.NoError(t, err)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.TwoFactorRepository
+
+		code    string
+		wantErr error
+	}{
+		{
+			name: "App 上的验证码",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.TwoFactor{Uid: 123, Secret: testTotpSecret, Enabled: true}, nil)
+				repo.EXPECT().UseStep(gomock.Any(), int64(123), gomock.Any()).Return(true, nil)
+				return repo
+			},
+			code: code,
+		},
+		{
+			name: "验证码已经用过了",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.TwoFactor{Uid: 123, Secret: testTotpSecret, Enabled: true}, nil)
+				repo.EXPECT().UseStep(gomock.Any(), int64(123), gomock.Any()).Return(false, nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "恢复码，大小写和横线都不影响",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.TwoFactor{Uid: 123, Secret: testTotpSecret, Enabled: true}, nil)
+				svc := &twoFactorService{}
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(123), svc.hashRecoveryCode("abcde23456")).
+					Return(true, nil)
+				return repo
+			},
+			code: "ABCDE-23456",
+		},
+		{
+			name: "恢复码不对",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.TwoFactor{Uid: 123, Secret: testTotpSecret, Enabled: true}, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(123), gomock.Any()).Return(false, nil)
+				return repo
+			},
+			code:    "abcde-23456",
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "扫了码但是没有确认",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), int64(123)).
+					Return(domain.TwoFactor{Uid: 123, Secret: testTotpSecret}, nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrTwoFactorNotEnrolled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewTwoFactorService(tc.mock(ctrl))
+			err := svc.Verify(context.Background(), 123, tc.code)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockTwoFactorRepository(ctrl)
+	repo.EXPECT().Find(gomock.Any(), int64(123)).
+		Return(domain.TwoFactor{Uid: 123, Secret: testTotpSecret}, nil)
+	var hashes []string
+	repo.EXPECT().Enable(gomock.Any(), int64(123), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uid int64, step int64, codeHashes []string) error {
+			hashes = codeHashes
+			return nil
+		})
+	svc := NewTwoFactorService(repo)
+	code, err := totp.Code(testTotpSecret, time.Now())
+	require.NoError(t, err)
+	codes, err := svc.Confirm(context.Background(), 123, code)
+	require.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCnt)
+	// 只存哈希，不存明文
+	for i, c := range codes {
+		assert.Equal(t, (&twoFactorService{}).hashRecoveryCode(c), hashes[i])
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"webook/internal/domain"
@@ -11,17 +12,48 @@ import (
 
 var (
 	ErrDuplicateEmail        = repository.ErrDuplicateUser
+	ErrUserNotFound          = repository.ErrUserNotFound
 	ErrInvalidUserOrPassword = errors.New("用户名或密码错误")
+	ErrEmailNotVerified      = errors.New("邮箱还没有验证")
+	ErrIdentityConflict      = errors.New("已经绑定了其他账号")
+	ErrLastIdentity          = errors.New("至少要保留一种登录方式")
+	ErrInvalidMerge          = errors.New("不能合并这两个账号")
 )
 
 //go:generate mockgen -source=./user.go -package=svcmocks -destination=./mocks/user.mock.go UserService
 type UserService interface {
 	Signup(ctx context.Context, u domain.User) error
+	// Login 邮箱密码登录，密码对了但是邮箱还没有验证返回 ErrEmailNotVerified
 	Login(ctx context.Context, email string, password string) (domain.User, error)
 	FindById(ctx context.Context, uid int64) (domain.User, error)
+	FindByEmail(ctx context.Context, email string) (domain.User, error)
+	FindByPhone(ctx context.Context, phone string) (domain.User, error)
 	UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error
 	FindOrCreate(ctx context.Context, phone string) (domain.User, error)
-	FindOrCreateByWechat(ctx context.Context, info domain.WechatInfo) (domain.User, error)
+	// FindOrCreateByIdentity 第三方账号登录，第一次登录的时候创建用户
+	FindOrCreateByIdentity(ctx context.Context, identity domain.OAuthIdentity) (domain.User, error)
+	// FindIdentities 用户绑定了哪些第三方账号
+	FindIdentities(ctx context.Context, uid int64) ([]domain.OAuthIdentity, error)
+	// UpdateNotificationMute 更新通知的免打扰设置
+	UpdateNotificationMute(ctx context.Context, uid int64, mute domain.NotificationMute) error
+	// VerifyEmail 验证码校验通过之后调用，把邮箱标记成已验证
+	VerifyEmail(ctx context.Context, email string) error
+	// ChangePassword 登录之后改密码，要先校验旧密码
+	ChangePassword(ctx context.Context, uid int64, oldPassword, newPassword string) error
+	// ResetPassword 忘记密码，调用方已经用验证码确认过是本人了
+	ResetPassword(ctx context.Context, uid int64, password string) error
+	// BindPhone 调用方已经校验过验证码了。已经被别的账号绑定了的话返回 ErrIdentityConflict，
+	// 这时候可以走合并账号。下面两个也一样
+	BindPhone(ctx context.Context, uid int64, phone string) error
+	BindEmail(ctx context.Context, uid int64, email string) error
+	BindIdentity(ctx context.Context, uid int64, identity domain.OAuthIdentity) error
+	// Unbind 解绑之后要还能登录，不然返回 ErrLastIdentity。UnbindIdentity 也一样
+	Unbind(ctx context.Context, uid int64, typ domain.IdentityType) error
+	UnbindIdentity(ctx context.Context, uid int64, provider string) error
+	// Merge 把 duplicate 的文章和点赞收藏挪到 primary 上面，duplicate 之后就不能再登录了
+	Merge(ctx context.Context, primary, duplicate int64) error
+	// List 管理后台分页查看用户
+	List(ctx context.Context, offset, limit int) ([]domain.User, error)
 }
 
 type userService struct {
@@ -63,6 +95,10 @@ func (svc *userService) Login(ctx context.Context, email string, password string
 	if err != nil {
 		return domain.User{}, ErrInvalidUserOrPassword
 	}
+	// 密码对了才告诉他邮箱没验证，不然可以拿来探测邮箱有没有注册
+	if !u.EmailVerified {
+		return domain.User{}, ErrEmailNotVerified
+	}
 	// 找到用户并返回
 	return u, nil
 }
@@ -71,6 +107,14 @@ func (svc *userService) FindById(ctx context.Context, uid int64) (domain.User, e
 	return svc.repo.FindById(ctx, uid)
 }
 
+func (svc *userService) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	return svc.repo.FindByEmail(ctx, email)
+}
+
+func (svc *userService) FindByPhone(ctx context.Context, phone string) (domain.User, error) {
+	return svc.repo.FindByPhone(ctx, phone)
+}
+
 func (svc *userService) UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error {
 	return svc.repo.UpdateNonSensitiveInfo(ctx, user)
 }
@@ -98,24 +142,174 @@ func (svc *userService) FindOrCreate(ctx context.Context, phone string) (domain.
 	return svc.repo.FindByPhone(ctx, phone)
 }
 
-func (svc *userService) FindOrCreateByWechat(ctx context.Context, wechatInfo domain.WechatInfo) (domain.User, error) {
-	u, err := svc.repo.FindByWechat(ctx, wechatInfo.OpenId)
+func (svc *userService) FindOrCreateByIdentity(ctx context.Context, identity domain.OAuthIdentity) (domain.User, error) {
+	u, err := svc.repo.FindByIdentity(ctx, identity.Provider, identity.Subject)
 	if err != repository.ErrUserNotFound {
 		return u, err
 	}
 
 	// 这边意味着是一个新用户
-	// JSON 格式的 wechatInfo
 	// 直接使用包变量
-	zap.L().Info("这是一个新用户", zap.Any("wechatInfo", wechatInfo))
-	// 使用注入的logger
-	//svc.logger.Info("这是一个新用户", zap.Any("wechatInfo", wechatInfo))
-	
-	err = svc.repo.Create(ctx, domain.User{
-		WechatInfo: wechatInfo,
-	})
+	zap.L().Info("这是一个新用户", zap.String("provider", identity.Provider),
+		zap.String("subject", identity.Subject))
+	// 邮箱不带过来，不然会跟已经注册的邮箱冲突，要用的话走绑定
+	err = svc.repo.CreateWithIdentity(ctx, domain.User{
+		Nickname: identity.Name,
+	}, identity)
 	if err != nil && err != repository.ErrDuplicateUser {
 		return domain.User{}, err
 	}
-	return svc.repo.FindByWechat(ctx, wechatInfo.OpenId)
+	// 要么err==nil，要么ErrDuplicateUser代表并发登录已经创建了
+	return svc.repo.FindByIdentity(ctx, identity.Provider, identity.Subject)
+}
+
+func (svc *userService) FindIdentities(ctx context.Context, uid int64) ([]domain.OAuthIdentity, error) {
+	return svc.repo.FindIdentities(ctx, uid)
+}
+
+func (svc *userService) UpdateNotificationMute(ctx context.Context, uid int64, mute domain.NotificationMute) error {
+	return svc.repo.UpdateNotificationMute(ctx, uid, mute)
+}
+
+func (svc *userService) VerifyEmail(ctx context.Context, email string) error {
+	u, err := svc.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerified {
+		return nil
+	}
+	return svc.repo.MarkEmailVerified(ctx, u.Id)
+}
+
+func (svc *userService) ChangePassword(ctx context.Context, uid int64, oldPassword, newPassword string) error {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 手机号或者微信注册的用户没有密码，只能走忘记密码
+	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(oldPassword))
+	if err != nil {
+		return ErrInvalidUserOrPassword
+	}
+	return svc.ResetPassword(ctx, uid, newPassword)
+}
+
+func (svc *userService) ResetPassword(ctx context.Context, uid int64, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return svc.repo.UpdatePassword(ctx, uid, string(hash))
+}
+
+func (svc *userService) BindPhone(ctx context.Context, uid int64, phone string) error {
+	return svc.bind(ctx, uid, func() (domain.User, error) {
+		return svc.repo.FindByPhone(ctx, phone)
+	}, func() error {
+		return svc.repo.UpdatePhone(ctx, uid, phone)
+	})
+}
+
+func (svc *userService) BindEmail(ctx context.Context, uid int64, email string) error {
+	return svc.bind(ctx, uid, func() (domain.User, error) {
+		return svc.repo.FindByEmail(ctx, email)
+	}, func() error {
+		return svc.repo.UpdateEmail(ctx, uid, email)
+	})
+}
+
+func (svc *userService) BindIdentity(ctx context.Context, uid int64, identity domain.OAuthIdentity) error {
+	identity.Uid = uid
+	return svc.bind(ctx, uid, func() (domain.User, error) {
+		return svc.repo.FindByIdentity(ctx, identity.Provider, identity.Subject)
+	}, func() error {
+		return svc.repo.BindIdentity(ctx, identity)
+	})
+}
+
+// bind 先看看是不是已经被绑定了，已经有的同类身份直接换掉
+func (svc *userService) bind(ctx context.Context, uid int64,
+	find func() (domain.User, error), update func() error) error {
+	u, err := find()
+	switch {
+	case err == nil && u.Id == uid:
+		return nil
+	case err == nil:
+		return ErrIdentityConflict
+	case err != repository.ErrUserNotFound:
+		return err
+	}
+	err = update()
+	if err == repository.ErrDuplicateUser {
+		// 查完之后被别人抢先绑定了
+		return ErrIdentityConflict
+	}
+	return err
+}
+
+func (svc *userService) Unbind(ctx context.Context, uid int64, typ domain.IdentityType) error {
+	u, identities, err := svc.loginIdentities(ctx, uid)
+	if err != nil {
+		return err
+	}
+	remain := len(identities)
+	for _, it := range u.LoginIdentities() {
+		if it != typ {
+			remain++
+		}
+	}
+	if remain == 0 {
+		return ErrLastIdentity
+	}
+	switch typ {
+	case domain.IdentityTypePhone:
+		return svc.repo.UpdatePhone(ctx, uid, "")
+	case domain.IdentityTypeEmail:
+		return svc.repo.UpdateEmail(ctx, uid, "")
+	default:
+		return fmt.Errorf("未知的身份类型 %d", typ)
+	}
+}
+
+func (svc *userService) UnbindIdentity(ctx context.Context, uid int64, provider string) error {
+	u, identities, err := svc.loginIdentities(ctx, uid)
+	if err != nil {
+		return err
+	}
+	remain := len(u.LoginIdentities())
+	for _, identity := range identities {
+		if identity.Provider != provider {
+			remain++
+		}
+	}
+	if remain == 0 {
+		return ErrLastIdentity
+	}
+	return svc.repo.UnbindIdentity(ctx, uid, provider)
+}
+
+func (svc *userService) loginIdentities(ctx context.Context, uid int64) (domain.User, []domain.OAuthIdentity, error) {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	identities, err := svc.repo.FindIdentities(ctx, uid)
+	return u, identities, err
+}
+
+func (svc *userService) Merge(ctx context.Context, primary, duplicate int64) error {
+	if primary <= 0 || duplicate <= 0 || primary == duplicate {
+		return ErrInvalidMerge
+	}
+	err := svc.repo.Merge(ctx, primary, duplicate)
+	if err == repository.ErrUserNotFound {
+		// 其中一个已经被合并过了
+		return ErrInvalidMerge
+	}
+	return err
+}
+
+func (svc *userService) List(ctx context.Context, offset, limit int) ([]domain.User, error) {
+	return svc.repo.List(ctx, offset, limit)
 }
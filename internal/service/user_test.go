@@ -41,9 +41,10 @@ func Test_userService_Login(t *testing.T) {
 				repo := repomocks.NewMockUserRepository(ctrl)
 				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
 					Return(domain.User{
-						Email:    "123@qq.com",
-						Password: "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
-						Phone:    "15212345678",
+						Email:         "123@qq.com",
+						EmailVerified: true,
+						Password:      "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
+						Phone:         "15212345678",
 					}, nil)
 				return repo
 			},
@@ -52,12 +53,30 @@ func Test_userService_Login(t *testing.T) {
 			password: "123456##hello",
 
 			wantUser: domain.User{
-				Email:    "123@qq.com",
-				Password: "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
-				Phone:    "15212345678",
+				Email:         "123@qq.com",
+				EmailVerified: true,
+				Password:      "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
+				Phone:         "15212345678",
 			},
 			wantErr: nil,
 		},
+		{
+			name: "邮箱还没有验证",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{
+						Email:    "123@qq.com",
+						Password: "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
+					}, nil)
+				return repo
+			},
+			email:    "123@qq.com",
+			password: "123456##hello",
+
+			wantUser: domain.User{},
+			wantErr:  ErrEmailNotVerified,
+		},
 		{
 			name: "用户未找到",
 			mock: func(ctrl *gomock.Controller) repository.UserRepository {
@@ -94,9 +113,10 @@ func Test_userService_Login(t *testing.T) {
 				repo := repomocks.NewMockUserRepository(ctrl)
 				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
 					Return(domain.User{
-						Email:    "123@qq.com",
-						Password: "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
-						Phone:    "15212345678",
+						Email:         "123@qq.com",
+						EmailVerified: true,
+						Password:      "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
+						Phone:         "15212345678",
 					}, nil)
 				return repo
 			},
@@ -120,3 +140,221 @@ func Test_userService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_ChangePassword(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		oldPassword string
+		wantErr     error
+	}{
+		{
+			name: "修改成功，存的是新密码加密之后的结果",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:       123,
+					Password: "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
+				}, nil)
+				repo.EXPECT().UpdatePassword(gomock.Any(), int64(123), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, uid int64, password string) error {
+						err := bcrypt.CompareHashAndPassword([]byte(password), []byte("new##hello123"))
+						assert.NoError(t, err)
+						return nil
+					})
+				return repo
+			},
+			oldPassword: "123456##hello",
+		},
+		{
+			name: "旧密码不对",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:       123,
+					Password: "$2a$10$gOQb48UD7PMyymwmC9d82uptrdqMBuMQpYBXzBlvhyIhRdlv4BsBO",
+				}, nil)
+				return repo
+			},
+			oldPassword: "wrong##hello",
+			wantErr:     ErrInvalidUserOrPassword,
+		},
+		{
+			name: "手机号注册的用户没有密码",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Phone: "15212345678"}, nil)
+				return repo
+			},
+			oldPassword: "",
+			wantErr:     ErrInvalidUserOrPassword,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserService(tc.mock(ctrl))
+			err := svc.ChangePassword(context.Background(), 123, tc.oldPassword, "new##hello123")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUserService_BindPhone(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		wantErr error
+	}{
+		{
+			name: "绑定成功",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByPhone(gomock.Any(), "15212345678").
+					Return(domain.User{}, repository.ErrUserNotFound)
+				repo.EXPECT().UpdatePhone(gomock.Any(), int64(123), "15212345678").Return(nil)
+				return repo
+			},
+		},
+		{
+			name: "已经绑定在自己身上",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByPhone(gomock.Any(), "15212345678").
+					Return(domain.User{Id: 123, Phone: "15212345678"}, nil)
+				return repo
+			},
+		},
+		{
+			name: "被别的账号绑定了",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByPhone(gomock.Any(), "15212345678").
+					Return(domain.User{Id: 456, Phone: "15212345678"}, nil)
+				return repo
+			},
+			wantErr: ErrIdentityConflict,
+		},
+		{
+			name: "查完之后被别人抢先绑定",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByPhone(gomock.Any(), "15212345678").
+					Return(domain.User{}, repository.ErrUserNotFound)
+				repo.EXPECT().UpdatePhone(gomock.Any(), int64(123), "15212345678").
+					Return(repository.ErrDuplicateUser)
+				return repo
+			},
+			wantErr: ErrIdentityConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserService(tc.mock(ctrl))
+			err := svc.BindPhone(context.Background(), 123, "15212345678")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUserService_Unbind(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		typ     domain.IdentityType
+		wantErr error
+	}{
+		{
+			name: "还能用微信登录",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:    123,
+					Phone: "15212345678",
+				}, nil)
+				repo.EXPECT().FindIdentities(gomock.Any(), int64(123)).Return([]domain.OAuthIdentity{
+					{Uid: 123, Provider: "wechat", Subject: "open_id"},
+				}, nil)
+				repo.EXPECT().UpdatePhone(gomock.Any(), int64(123), "").Return(nil)
+				return repo
+			},
+			typ: domain.IdentityTypePhone,
+		},
+		{
+			name: "最后一种登录方式",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{
+					Id:    123,
+					Phone: "15212345678",
+					// 没有密码的邮箱不能用来登录
+					Email: "123@qq.com",
+				}, nil)
+				repo.EXPECT().FindIdentities(gomock.Any(), int64(123)).Return(nil, nil)
+				return repo
+			},
+			typ:     domain.IdentityTypePhone,
+			wantErr: ErrLastIdentity,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserService(tc.mock(ctrl))
+			err := svc.Unbind(context.Background(), 123, tc.typ)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUserService_UnbindIdentity(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		wantErr error
+	}{
+		{
+			name: "还绑定了别的第三方账号",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{Id: 123}, nil)
+				repo.EXPECT().FindIdentities(gomock.Any(), int64(123)).Return([]domain.OAuthIdentity{
+					{Uid: 123, Provider: "github", Subject: "1"},
+					{Uid: 123, Provider: "wechat", Subject: "open_id"},
+				}, nil)
+				repo.EXPECT().UnbindIdentity(gomock.Any(), int64(123), "github").Return(nil)
+				return repo
+			},
+		},
+		{
+			name: "只剩这一个第三方账号",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).Return(domain.User{Id: 123}, nil)
+				repo.EXPECT().FindIdentities(gomock.Any(), int64(123)).Return([]domain.OAuthIdentity{
+					{Uid: 123, Provider: "github", Subject: "1"},
+				}, nil)
+				return repo
+			},
+			wantErr: ErrLastIdentity,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserService(tc.mock(ctrl))
+			err := svc.UnbindIdentity(context.Background(), 123, "github")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/payout"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrWithdrawalNotFound = repository.ErrWithdrawalNotFound
+	// ErrWithdrawalProcessed 已经被别人处理过了
+	ErrWithdrawalProcessed = repository.ErrWithdrawalStatusChanged
+	ErrWithdrawAmtTooSmall = errors.New("提现金额太小")
+)
+
+//go:generate mockgen -source=./withdraw.go -package=svcmocks -destination=./mocks/withdraw.mock.go WithdrawService
+type WithdrawService interface {
+	// Apply 申请提现，成功之后钱就冻结了，等待管理员审核
+	Apply(ctx context.Context, uid int64, amt int64) (domain.Withdrawal, error)
+	List(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error)
+	ListPending(ctx context.Context, offset, limit int) ([]domain.Withdrawal, error)
+	// Approve 审核通过之后马上打款，打款失败会自动解冻
+	// 对于正在打款的提现，再调用一次就是重试打款
+	Approve(ctx context.Context, wid int64, operator int64) (domain.Withdrawal, error)
+	Reject(ctx context.Context, wid int64, operator int64, reason string) error
+	// Logs 提现的审计日志
+	Logs(ctx context.Context, wid int64) ([]domain.WithdrawalLog, error)
+}
+
+// WithdrawRule 提现的金额和手续费规则，单位都是分
+type WithdrawRule struct {
+	MinAmt int64
+	// 手续费的千分比
+	FeeRate int64
+	MinFee  int64
+}
+
+func (r WithdrawRule) Fee(amt int64) int64 {
+	fee := amt * r.FeeRate / 1000
+	if fee < r.MinFee {
+		return r.MinFee
+	}
+	return fee
+}
+
+type withdrawService struct {
+	repo       repository.WithdrawalRepository
+	accountSvc AccountService
+	executor   payout.Executor
+	rule       WithdrawRule
+	l          logger.LoggerV1
+}
+
+func NewWithdrawService(repo repository.WithdrawalRepository, accountSvc AccountService,
+	executor payout.Executor, rule WithdrawRule, l logger.LoggerV1) WithdrawService {
+	return &withdrawService{
+		repo:       repo,
+		accountSvc: accountSvc,
+		executor:   executor,
+		rule:       rule,
+		l:          l,
+	}
+}
+
+func (s *withdrawService) Apply(ctx context.Context, uid int64, amt int64) (domain.Withdrawal, error) {
+	fee := s.rule.Fee(amt)
+	// 扣完手续费至少要剩下一分钱
+	if amt < s.rule.MinAmt || amt <= fee {
+		return domain.Withdrawal{}, ErrWithdrawAmtTooSmall
+	}
+	now := time.Now()
+	w := domain.Withdrawal{
+		Uid:      uid,
+		Amt:      amt,
+		Fee:      fee,
+		Currency: "CNY",
+		Status:   domain.WithdrawalStatusPending,
+		Ctime:    now,
+		Utime:    now,
+	}
+	id, err := s.repo.Create(ctx, w)
+	if err != nil {
+		return domain.Withdrawal{}, err
+	}
+	w.Id = id
+	frozen, err := s.ensureFrozen(ctx, w)
+	if err != nil {
+		// 不确定有没有冻结成功，留着等审核的时候再冻结一次
+		return domain.Withdrawal{}, err
+	}
+	if !frozen {
+		return domain.Withdrawal{}, s.closeUnfrozen(ctx, w)
+	}
+	return w, nil
+}
+
+func (s *withdrawService) List(ctx context.Context, uid int64, offset, limit int) ([]domain.Withdrawal, error) {
+	return s.repo.ListByUid(ctx, uid, offset, limit)
+}
+
+func (s *withdrawService) ListPending(ctx context.Context, offset, limit int) ([]domain.Withdrawal, error) {
+	return s.repo.ListByStatus(ctx, domain.WithdrawalStatusPending, offset, limit)
+}
+
+func (s *withdrawService) Approve(ctx context.Context, wid int64, operator int64) (domain.Withdrawal, error) {
+	w, err := s.repo.FindById(ctx, wid)
+	if err != nil {
+		return domain.Withdrawal{}, err
+	}
+	switch w.Status {
+	case domain.WithdrawalStatusPending:
+		frozen, err := s.ensureFrozen(ctx, w)
+		if err != nil {
+			return domain.Withdrawal{}, err
+		}
+		if !frozen {
+			return domain.Withdrawal{}, s.closeUnfrozen(ctx, w)
+		}
+		w.Status = domain.WithdrawalStatusApproved
+		err = s.repo.UpdateStatus(ctx, w, domain.WithdrawalStatusPending, operator, "审核通过")
+		if err != nil {
+			return domain.Withdrawal{}, err
+		}
+	case domain.WithdrawalStatusApproved:
+		// 上一次打款结果未知，渠道保证同一笔提现只打一次款
+	default:
+		return domain.Withdrawal{}, ErrWithdrawalProcessed
+	}
+	return s.payout(ctx, w)
+}
+
+func (s *withdrawService) payout(ctx context.Context, w domain.Withdrawal) (domain.Withdrawal, error) {
+	txnID, err := s.executor.Payout(ctx, w)
+	switch {
+	case errors.Is(err, payout.ErrPayoutFailed):
+		// 先解冻再改状态，解冻是幂等的，中间失败了还停在打款中，管理员重试的时候会再解冻一次。
+		// 反过来的话状态已经是终态了，解冻失败就再也没有机会重来
+		err = s.accountSvc.Unfreeze(ctx, w)
+		if err != nil && err != ErrDuplicateCredit {
+			s.l.Error("打款失败之后解冻失败", logger.Int64("wid", w.Id), logger.Error(err))
+			return domain.Withdrawal{}, err
+		}
+		w.Status = domain.WithdrawalStatusFailed
+		err = s.repo.UpdateStatus(ctx, w, domain.WithdrawalStatusApproved, 0, "打款失败，自动解冻")
+		if err != nil {
+			return domain.Withdrawal{}, err
+		}
+		return w, nil
+	case err != nil:
+		// 不知道钱有没有打出去，保持打款中，等管理员重试
+		s.l.Error("打款结果未知", logger.Int64("wid", w.Id), logger.Error(err))
+		return domain.Withdrawal{}, err
+	}
+	err = s.accountSvc.Settle(ctx, w)
+	if err != nil && err != ErrDuplicateCredit {
+		return domain.Withdrawal{}, err
+	}
+	w.Status = domain.WithdrawalStatusSuccess
+	w.TxnID = txnID
+	err = s.repo.UpdateStatus(ctx, w, domain.WithdrawalStatusApproved, 0, "打款成功")
+	if err != nil {
+		return domain.Withdrawal{}, err
+	}
+	return w, nil
+}
+
+func (s *withdrawService) Reject(ctx context.Context, wid int64, operator int64, reason string) error {
+	w, err := s.repo.FindById(ctx, wid)
+	if err != nil {
+		return err
+	}
+	if w.Status != domain.WithdrawalStatusPending {
+		return ErrWithdrawalProcessed
+	}
+	frozen, err := s.ensureFrozen(ctx, w)
+	if err != nil {
+		return err
+	}
+	if frozen {
+		// 跟打款失败一样先解冻，失败了还停在待审核，管理员可以再驳回一次
+		err = s.accountSvc.Unfreeze(ctx, w)
+		if err != nil && err != ErrDuplicateCredit {
+			return err
+		}
+	}
+	w.Status = domain.WithdrawalStatusRejected
+	return s.repo.UpdateStatus(ctx, w, domain.WithdrawalStatusPending, operator, reason)
+}
+
+func (s *withdrawService) Logs(ctx context.Context, wid int64) ([]domain.WithdrawalLog, error) {
+	return s.repo.FindLogs(ctx, wid)
+}
+
+// ensureFrozen 冻结是幂等的，返回钱是不是已经冻结了，余额不足的时候返回 false
+func (s *withdrawService) ensureFrozen(ctx context.Context, w domain.Withdrawal) (bool, error) {
+	err := s.accountSvc.Freeze(ctx, w)
+	switch err {
+	case nil, ErrDuplicateCredit:
+		return true, nil
+	case ErrInsufficientBalance:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// closeUnfrozen 余额不足冻结不了，直接关闭这个提现
+func (s *withdrawService) closeUnfrozen(ctx context.Context, w domain.Withdrawal) error {
+	from := w.Status
+	w.Status = domain.WithdrawalStatusRejected
+	err := s.repo.UpdateStatus(ctx, w, from, 0, "余额不足")
+	if err != nil {
+		return err
+	}
+	return ErrInsufficientBalance
+}
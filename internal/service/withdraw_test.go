@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/internal/service/payout"
+	payoutmocks "webook/internal/service/payout/mocks"
+	"webook/pkg/logger"
+)
+
+func TestWithdrawService_Apply(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService)
+
+		amt     int64
+		wantFee int64
+		wantErr error
+	}{
+		{
+			name: "申请成功，按照千分比收手续费",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, w domain.Withdrawal) error {
+						assert.Equal(t, int64(1), w.Id)
+						assert.Equal(t, int64(100000), w.Amt)
+						return nil
+					})
+				return repo, accountSvc
+			},
+			amt:     100000,
+			wantFee: 600,
+		},
+		{
+			name: "金额太小",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				return repomocks.NewMockWithdrawalRepository(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			amt:     999,
+			wantErr: ErrWithdrawAmtTooSmall,
+		},
+		{
+			name: "余额不足，直接关闭",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrInsufficientBalance)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusPending,
+					int64(0), "余额不足").
+					DoAndReturn(func(ctx context.Context, w domain.Withdrawal, from domain.WithdrawalStatus,
+						operator int64, remark string) error {
+						assert.Equal(t, domain.WithdrawalStatusRejected, w.Status)
+						return nil
+					})
+				return repo, accountSvc
+			},
+			amt:     100000,
+			wantErr: ErrInsufficientBalance,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, accountSvc := tc.mock(ctrl)
+			svc := NewWithdrawService(repo, accountSvc, payoutmocks.NewMockExecutor(ctrl),
+				WithdrawRule{MinAmt: 1000, FeeRate: 6, MinFee: 10}, logger.NewNoOpLogger())
+			w, err := svc.Apply(context.Background(), 123, tc.amt)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantFee, w.Fee)
+			assert.Equal(t, domain.WithdrawalStatusPending, w.Status)
+		})
+	}
+}
+
+func TestWithdrawService_Approve(t *testing.T) {
+	pending := domain.Withdrawal{
+		Id:       1,
+		Uid:      123,
+		Amt:      1000,
+		Fee:      10,
+		Currency: "CNY",
+		Status:   domain.WithdrawalStatusPending,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService, payout.Executor)
+
+		wantStatus domain.WithdrawalStatus
+		wantErr    error
+	}{
+		{
+			name: "打款成功，出账",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService, payout.Executor) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				executor := payoutmocks.NewMockExecutor(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(pending, nil)
+				// 申请的时候已经冻结过了
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrDuplicateCredit)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusPending,
+					int64(9), "审核通过").Return(nil)
+				executor.EXPECT().Payout(gomock.Any(), gomock.Any()).Return("txn-1", nil)
+				accountSvc.EXPECT().Settle(gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusApproved,
+					int64(0), "打款成功").
+					DoAndReturn(func(ctx context.Context, w domain.Withdrawal, from domain.WithdrawalStatus,
+						operator int64, remark string) error {
+						assert.Equal(t, "txn-1", w.TxnID)
+						return nil
+					})
+				return repo, accountSvc, executor
+			},
+			wantStatus: domain.WithdrawalStatusSuccess,
+		},
+		{
+			name: "打款失败，自动解冻",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService, payout.Executor) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				executor := payoutmocks.NewMockExecutor(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(pending, nil)
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrDuplicateCredit)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusPending,
+					int64(9), "审核通过").Return(nil)
+				executor.EXPECT().Payout(gomock.Any(), gomock.Any()).Return("", payout.ErrPayoutFailed)
+				gomock.InOrder(
+					accountSvc.EXPECT().Unfreeze(gomock.Any(), gomock.Any()).Return(nil),
+					repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusApproved,
+						int64(0), gomock.Any()).Return(nil),
+				)
+				return repo, accountSvc, executor
+			},
+			wantStatus: domain.WithdrawalStatusFailed,
+		},
+		{
+			name: "打款失败，解冻也失败，保持打款中等待重试",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService, payout.Executor) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				executor := payoutmocks.NewMockExecutor(ctrl)
+				approved := pending
+				approved.Status = domain.WithdrawalStatusApproved
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(approved, nil)
+				executor.EXPECT().Payout(gomock.Any(), gomock.Any()).Return("", payout.ErrPayoutFailed)
+				// 不会改成失败
+				accountSvc.EXPECT().Unfreeze(gomock.Any(), gomock.Any()).Return(errors.New("mock db 错误"))
+				return repo, accountSvc, executor
+			},
+			wantErr: errors.New("mock db 错误"),
+		},
+		{
+			name: "打款结果未知，保持打款中",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService, payout.Executor) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				executor := payoutmocks.NewMockExecutor(ctrl)
+				approved := pending
+				approved.Status = domain.WithdrawalStatusApproved
+				// 重试打款，不需要再冻结和审核
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(approved, nil)
+				executor.EXPECT().Payout(gomock.Any(), gomock.Any()).Return("", errors.New("超时"))
+				return repo, accountSvc, executor
+			},
+			wantErr: errors.New("超时"),
+		},
+		{
+			name: "已经处理过了",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService, payout.Executor) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				rejected := pending
+				rejected.Status = domain.WithdrawalStatusRejected
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(rejected, nil)
+				return repo, svcmocks.NewMockAccountService(ctrl), payoutmocks.NewMockExecutor(ctrl)
+			},
+			wantErr: ErrWithdrawalProcessed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, accountSvc, executor := tc.mock(ctrl)
+			svc := NewWithdrawService(repo, accountSvc, executor,
+				WithdrawRule{MinAmt: 1000, FeeRate: 6, MinFee: 10}, logger.NewNoOpLogger())
+			w, err := svc.Approve(context.Background(), 1, 9)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantStatus, w.Status)
+		})
+	}
+}
+
+func TestWithdrawService_Reject(t *testing.T) {
+	pending := domain.Withdrawal{
+		Id:       1,
+		Uid:      123,
+		Amt:      1000,
+		Fee:      10,
+		Currency: "CNY",
+		Status:   domain.WithdrawalStatusPending,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService)
+
+		wantErr error
+	}{
+		{
+			name: "先解冻再驳回",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(pending, nil)
+				gomock.InOrder(
+					accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrDuplicateCredit),
+					accountSvc.EXPECT().Unfreeze(gomock.Any(), gomock.Any()).Return(nil),
+					repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusPending,
+						int64(9), "信息不全").
+						DoAndReturn(func(ctx context.Context, w domain.Withdrawal, from domain.WithdrawalStatus,
+							operator int64, remark string) error {
+							assert.Equal(t, domain.WithdrawalStatusRejected, w.Status)
+							return nil
+						}),
+				)
+				return repo, accountSvc
+			},
+		},
+		{
+			name: "上一次已经解冻过了",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(pending, nil)
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrDuplicateCredit)
+				accountSvc.EXPECT().Unfreeze(gomock.Any(), gomock.Any()).Return(ErrDuplicateCredit)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusPending,
+					int64(9), "信息不全").Return(nil)
+				return repo, accountSvc
+			},
+		},
+		{
+			name: "解冻失败，还是待审核",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(pending, nil)
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrDuplicateCredit)
+				accountSvc.EXPECT().Unfreeze(gomock.Any(), gomock.Any()).Return(errors.New("db错误"))
+				// 不能改状态
+				return repo, accountSvc
+			},
+			wantErr: errors.New("db错误"),
+		},
+		{
+			name: "余额不足没有冻结，直接驳回",
+			mock: func(ctrl *gomock.Controller) (repository.WithdrawalRepository, AccountService) {
+				repo := repomocks.NewMockWithdrawalRepository(ctrl)
+				accountSvc := svcmocks.NewMockAccountService(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(pending, nil)
+				accountSvc.EXPECT().Freeze(gomock.Any(), gomock.Any()).Return(ErrInsufficientBalance)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), domain.WithdrawalStatusPending,
+					int64(9), "信息不全").Return(nil)
+				return repo, accountSvc
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, accountSvc := tc.mock(ctrl)
+			svc := NewWithdrawService(repo, accountSvc, payoutmocks.NewMockExecutor(ctrl),
+				WithdrawRule{MinAmt: 1000, FeeRate: 6, MinFee: 10}, logger.NewNoOpLogger())
+			err := svc.Reject(context.Background(), 1, 9, "信息不全")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
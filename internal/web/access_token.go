@@ -0,0 +1,89 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/errs"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// AccessTokenHandler 个人访问令牌，给脚本和第三方客户端调用接口用。
+// 这些接口本身不能用个人访问令牌调用，只能登录之后管理
+type AccessTokenHandler struct {
+	svc service.AccessTokenService
+}
+
+func NewAccessTokenHandler(svc service.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{svc: svc}
+}
+
+func (h *AccessTokenHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/users/tokens")
+	g.POST("", ginx.WrapBodyAndClaims(h.Create))
+	g.GET("", ginx.WrapClaims(h.List))
+	g.POST("/revoke", ginx.WrapBodyAndClaims(h.Revoke))
+}
+
+func (h *AccessTokenHandler) Create(ctx *gin.Context, req CreateAccessTokenReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	if req.Name == "" {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "名字不能为空"}, nil
+	}
+	token, t, err := h.svc.Create(ctx, uc.Uid, req.Name, req.Scopes, time.Duration(req.ExpireDays)*time.Hour*24)
+	switch err {
+	case nil:
+		vo := newAccessTokenVo(t)
+		vo.Token = token
+		return ginx.Result{Data: vo}, nil
+	case service.ErrInvalidScope:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "不支持的权限"}, nil
+	case service.ErrInvalidTokenTTL:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "有效期最长一年"}, nil
+	case service.ErrTooManyAccessTokens:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "令牌太多了，先撤销一些不用的"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *AccessTokenHandler) List(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	tokens, err := h.svc.List(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(tokens, func(idx int, src domain.AccessToken) AccessTokenVo {
+			return newAccessTokenVo(src)
+		}),
+	}, nil
+}
+
+func (h *AccessTokenHandler) Revoke(ctx *gin.Context, req RevokeAccessTokenReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Revoke(ctx, uc.Uid, req.Id)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrAccessTokenNotFound:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "令牌不存在"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func newAccessTokenVo(t domain.AccessToken) AccessTokenVo {
+	vo := AccessTokenVo{
+		Id:     t.Id,
+		Name:   t.Name,
+		Prefix: t.Prefix,
+		Scopes: t.Scopes,
+		Expire: t.Expire.UnixMilli(),
+		Ctime:  t.Ctime.UnixMilli(),
+	}
+	if !t.LastUsed.IsZero() {
+		vo.LastUsed = t.LastUsed.UnixMilli()
+	}
+	return vo
+}
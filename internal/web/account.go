@@ -0,0 +1,84 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// AccountHandler 用户查自己的余额和流水
+type AccountHandler struct {
+	svc service.AccountService
+}
+
+func NewAccountHandler(svc service.AccountService) *AccountHandler {
+	return &AccountHandler{svc: svc}
+}
+
+func (h *AccountHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/account")
+	g.GET("/balance", ginx.WrapClaims(h.Balance))
+	// /account/statement?offset=?&limit=?
+	g.GET("/statement", ginx.WrapBodyAndClaims(h.Statement))
+}
+
+func (h *AccountHandler) Balance(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	accs, err := h.svc.Balance(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.Account, AccountVo](accs, func(idx int, src domain.Account) AccountVo {
+			return AccountVo{
+				Type:     accountTypeName(src.Type),
+				Currency: src.Currency,
+				Balance:  src.Balance,
+				Utime:    src.Utime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
+func (h *AccountHandler) Statement(ctx *gin.Context, req StatementReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	acts, err := h.svc.Statement(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.AccountActivity, AccountActivityVo](acts,
+			func(idx int, src domain.AccountActivity) AccountActivityVo {
+				return AccountActivityVo{
+					Id:       src.Id,
+					Type:     accountTypeName(src.AccountType),
+					Biz:      src.Biz,
+					BizId:    src.BizId,
+					Amt:      src.Amt,
+					Currency: src.Currency,
+					Balance:  src.Balance,
+					Ctime:    src.Ctime.UnixMilli(),
+				}
+			}),
+	}, nil
+}
+
+func accountTypeName(typ domain.AccountType) string {
+	switch typ {
+	case domain.AccountTypeReward:
+		return "reward"
+	case domain.AccountTypeSystem:
+		return "system"
+	case domain.AccountTypeFrozen:
+		return "frozen"
+	default:
+		return "unknown"
+	}
+}
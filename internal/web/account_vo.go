@@ -0,0 +1,25 @@
+package web
+
+type StatementReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type AccountVo struct {
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+	// 单位是分
+	Balance int64 `json:"balance"`
+	Utime   int64 `json:"utime"`
+}
+
+type AccountActivityVo struct {
+	Id       int64  `json:"id"`
+	Type     string `json:"type"`
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"bizId"`
+	Amt      int64  `json:"amt"`
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+	Ctime    int64  `json:"ctime"`
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/middleware"
+	"webook/pkg/ginx"
+)
+
+// AdminHandler 管理后台里面跟用户账号无关的部分
+type AdminHandler struct {
+	artSvc   service.ArticleService
+	jobSvc   service.CronJobService
+	auditSvc service.AuditService
+	admin    *middleware.AdminMiddlewareBuilder
+}
+
+func NewAdminHandler(artSvc service.ArticleService, jobSvc service.CronJobService,
+	auditSvc service.AuditService, admin *middleware.AdminMiddlewareBuilder) *AdminHandler {
+	return &AdminHandler{
+		artSvc:   artSvc,
+		jobSvc:   jobSvc,
+		auditSvc: auditSvc,
+		admin:    admin,
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(server *gin.Engine) {
+	ag := server.Group("/admin")
+	// 违规的文章强制下线，作者自己还能看到
+	ag.POST("/articles/withdraw", h.admin.Build(domain.PermArticleWithdraw), ginx.WrapBody(h.WithdrawArticle))
+	// /admin/jobs?offset=0&limit=10
+	ag.GET("/jobs", h.admin.Build(domain.PermJobRead), ginx.WrapBody(h.Jobs))
+	// /admin/audit_logs?operator=1&offset=0&limit=10
+	ag.GET("/audit_logs", h.admin.Build(domain.PermAuditRead), ginx.WrapBody(h.AuditLogs))
+}
+
+func (h *AdminHandler) WithdrawArticle(ctx *gin.Context, req AdminArticleReq) (ginx.Result, error) {
+	art, err := h.artSvc.GetById(ctx, req.Id)
+	switch err {
+	case nil:
+	case service.ErrArticleNotFound:
+		return ginx.Result{Code: 4, Msg: "文章不存在"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	err = h.artSvc.Withdraw(ctx, art.Author.Id, art.Id)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *AdminHandler) Jobs(ctx *gin.Context, req AdminListReq) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	jobs, err := h.jobSvc.List(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(jobs, func(idx int, src domain.Job) AdminJobVo {
+			return AdminJobVo{
+				Id:         src.Id,
+				Name:       src.Name,
+				Executor:   src.Executor,
+				Expression: src.Expression,
+				Status:     src.Status.String(),
+				NextTime:   src.NextExecTime.UnixMilli(),
+				Utime:      src.Utime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
+func (h *AdminHandler) AuditLogs(ctx *gin.Context, req AuditLogListReq) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	logs, err := h.auditSvc.List(ctx, req.Operator, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(logs, func(idx int, src domain.AuditLog) AuditLogVo {
+			return AuditLogVo{
+				Id:       src.Id,
+				Operator: src.Operator,
+				Action:   src.Action,
+				Detail:   src.Detail,
+				IP:       src.IP,
+				Code:     src.Code,
+				Ctime:    src.Ctime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
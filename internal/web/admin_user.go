@@ -0,0 +1,178 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/internal/web/middleware"
+	"webook/pkg/ginx"
+)
+
+// AdminUserHandler 管理员管理用户账号
+type AdminUserHandler struct {
+	jwt.Handler
+	userSvc service.UserService
+	guard   service.LoginGuardService
+	banSvc  service.BanService
+	rbac    service.RBACService
+	admin   *middleware.AdminMiddlewareBuilder
+}
+
+func NewAdminUserHandler(hdl jwt.Handler, userSvc service.UserService, guard service.LoginGuardService,
+	banSvc service.BanService, rbac service.RBACService,
+	admin *middleware.AdminMiddlewareBuilder) *AdminUserHandler {
+	return &AdminUserHandler{
+		Handler: hdl,
+		userSvc: userSvc,
+		guard:   guard,
+		banSvc:  banSvc,
+		rbac:    rbac,
+		admin:   admin,
+	}
+}
+
+func (h *AdminUserHandler) RegisterRoutes(server *gin.Engine) {
+	ag := server.Group("/admin/users")
+	// /admin/users?offset=0&limit=10
+	ag.GET("", h.admin.Build(domain.PermUserRead), ginx.WrapBody(h.List))
+	// 登录失败太多次被锁住的账号，用户找客服之后手动解锁
+	ag.POST("/unlock", h.admin.Build(domain.PermUserUnlock), ginx.WrapBodyAndClaims(h.Unlock))
+	ag.POST("/ban", h.admin.Build(domain.PermUserBan), ginx.WrapBodyAndClaims(h.Ban))
+	ag.POST("/unban", h.admin.Build(domain.PermUserBan), ginx.WrapBody(h.Unban))
+	ag.POST("/roles/grant", h.admin.Build(domain.PermRoleGrant), ginx.WrapBodyAndClaims(h.GrantRole))
+	ag.POST("/roles/revoke", h.admin.Build(domain.PermRoleGrant), ginx.WrapBodyAndClaims(h.RevokeRole))
+}
+
+func (h *AdminUserHandler) List(ctx *gin.Context, req AdminListReq) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	users, err := h.userSvc.List(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	res := make([]AdminUserVo, 0, len(users))
+	for _, u := range users {
+		// 角色有缓存，一页最多一百个，逐个查问题不大
+		roles, err := h.rbac.Roles(ctx, u.Id)
+		if err != nil {
+			return ginx.Result{Code: 5, Msg: "系统错误"}, err
+		}
+		res = append(res, AdminUserVo{
+			Id:            u.Id,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerified,
+			Phone:         u.Phone,
+			Nickname:      u.Nickname,
+			Roles:         roles,
+			Ctime:         u.Ctime.UnixMilli(),
+		})
+	}
+	return ginx.Result{Data: res}, nil
+}
+
+func (h *AdminUserHandler) Unlock(ctx *gin.Context, req AdminUserReq, uc jwt.UserClaims) (ginx.Result, error) {
+	u, err := h.userSvc.FindById(ctx, req.Uid)
+	switch err {
+	case nil:
+	case service.ErrUserNotFound:
+		return ginx.Result{Code: 4, Msg: "用户不存在"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	err = h.guard.Unlock(ctx, u, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+var banScopes = map[string]domain.BanScope{
+	"login":   domain.BanScopeLogin,
+	"publish": domain.BanScopePublish,
+	"comment": domain.BanScopeComment,
+}
+
+// Ban 禁止登录的话所有设备马上下线，全部封禁的话已经发表的文章也会下线
+func (h *AdminUserHandler) Ban(ctx *gin.Context, req AdminBanReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Uid == uc.Uid {
+		return ginx.Result{Code: 4, Msg: "不能封禁自己"}, nil
+	}
+	if req.Reason == "" {
+		return ginx.Result{Code: 4, Msg: "要写明封禁原因"}, nil
+	}
+	if req.ExpireDays < 0 {
+		return ginx.Result{Code: 4, Msg: "封禁期限不合法"}, nil
+	}
+	b := domain.UserBan{Uid: req.Uid, Reason: req.Reason, Operator: uc.Uid}
+	for _, name := range req.Scopes {
+		scope, ok := banScopes[name]
+		if !ok {
+			return ginx.Result{Code: 4, Msg: "不支持的封禁范围"}, nil
+		}
+		b.Scope |= scope
+	}
+	if len(req.Scopes) == 0 {
+		b.Scope = domain.BanScopeAll
+	}
+	if req.ExpireDays > 0 {
+		b.Expire = time.Now().Add(time.Duration(req.ExpireDays) * time.Hour * 24)
+	}
+	_, err := h.userSvc.FindById(ctx, req.Uid)
+	switch err {
+	case nil:
+	case service.ErrUserNotFound:
+		return ginx.Result{Code: 4, Msg: "用户不存在"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	err = h.banSvc.Ban(ctx, b)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	if !b.Scope.Has(domain.BanScopeLogin) {
+		return ginx.Result{Msg: "OK"}, nil
+	}
+	err = h.ClearUserTokens(ctx, req.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "已经封禁，但是踢下线失败"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *AdminUserHandler) Unban(ctx *gin.Context, req AdminUserReq) (ginx.Result, error) {
+	err := h.banSvc.Unban(ctx, req.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *AdminUserHandler) GrantRole(ctx *gin.Context, req AdminRoleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.rbac.Grant(ctx, req.Uid, req.Role, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrUnknownRole:
+		return ginx.Result{Code: 4, Msg: "没有这个角色"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *AdminUserHandler) RevokeRole(ctx *gin.Context, req AdminRoleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Uid == uc.Uid && req.Role == domain.RoleAdmin {
+		return ginx.Result{Code: 4, Msg: "不能撤销自己的管理员角色"}, nil
+	}
+	err := h.rbac.Revoke(ctx, req.Uid, req.Role)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrUnknownRole:
+		return ginx.Result{Code: 4, Msg: "没有这个角色"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
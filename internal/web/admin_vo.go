@@ -0,0 +1,61 @@
+package web
+
+type AdminListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type AdminUserVo struct {
+	Id            int64    `json:"id"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"emailVerified"`
+	Phone         string   `json:"phone"`
+	Nickname      string   `json:"nickname"`
+	Roles         []string `json:"roles"`
+	Ctime         int64    `json:"ctime"`
+}
+
+type AdminBanReq struct {
+	Uid    int64  `json:"uid"`
+	Reason string `json:"reason"`
+	// login、publish、comment 的组合，不传就是全部封禁
+	Scopes []string `json:"scopes"`
+	// 0 就是永久封禁
+	ExpireDays int `json:"expireDays"`
+}
+
+type AdminRoleReq struct {
+	Uid  int64  `json:"uid"`
+	Role string `json:"role"`
+}
+
+type AdminArticleReq struct {
+	Id int64 `json:"id"`
+}
+
+type AdminJobVo struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Expression string `json:"expression"`
+	Status     string `json:"status"`
+	NextTime   int64  `json:"nextTime"`
+	Utime      int64  `json:"utime"`
+}
+
+type AuditLogListReq struct {
+	// 只看某个管理员的，不传就是所有的
+	Operator int64 `form:"operator"`
+	Offset   int   `form:"offset"`
+	Limit    int   `form:"limit"`
+}
+
+type AuditLogVo struct {
+	Id       int64  `json:"id"`
+	Operator int64  `json:"operator"`
+	Action   string `json:"action"`
+	Detail   string `json:"detail"`
+	IP       string `json:"ip"`
+	Code     int    `json:"code"`
+	Ctime    int64  `json:"ctime"`
+}
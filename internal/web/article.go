@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 	"webook/internal/domain"
+	"webook/internal/errs"
 	"webook/internal/service"
 	"webook/internal/web/jwt"
 	"webook/pkg/ginx"
@@ -18,15 +19,18 @@ import (
 type ArticleHandler struct {
 	svc     service.ArticleService
 	intrSvc service.InteractiveService
+	banSvc  service.BanService
 	l       logger.LoggerV1
 	biz     string
 }
 
-func NewArticleHandler(svc service.ArticleService, l logger.LoggerV1, intrSvc service.InteractiveService) *ArticleHandler {
+func NewArticleHandler(svc service.ArticleService, l logger.LoggerV1, intrSvc service.InteractiveService,
+	banSvc service.BanService) *ArticleHandler {
 	return &ArticleHandler{
 		svc:     svc,
 		l:       l,
 		intrSvc: intrSvc,
+		banSvc:  banSvc,
 		biz:     "article",
 	}
 }
@@ -62,6 +66,7 @@ func (h *ArticleHandler) Edit(ctx *gin.Context, req ArticleEditReq, uc jwt.UserC
 		Id:      req.Id,
 		Title:   req.Title,
 		Content: req.Content,
+		Tags:    req.Tags,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
@@ -85,11 +90,19 @@ func (h *ArticleHandler) Publish(ctx *gin.Context, req PublishReq, uc jwt.UserCl
 		Id:      req.Id,
 		Title:   req.Title,
 		Content: req.Content,
+		Tags:    req.Tags,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
 	})
-	if err != nil {
+	switch err {
+	case nil:
+	case service.ErrUserBanned:
+		return ginx.Result{
+			Msg:  "你已被禁止发表文章",
+			Code: errs.ArticlePublishBanned,
+		}, nil
+	default:
 		return ginx.Result{
 			Msg:  "系统错误",
 			Code: 5,
@@ -238,6 +251,13 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 
 	// 等待结果
 	err = eg.Wait()
+	if err == service.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg:  "文章不存在",
+			Code: 4,
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, ginx.Result{
 			Msg:  "系统错误",
@@ -276,6 +296,7 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 			LikeCnt:    intr.LikeCnt,
 			Liked:      intr.Liked,
 			Collected:  intr.Collected,
+			Tags:       art.Tags,
 
 			Status: art.Status.ToUint8(),
 			Ctime:  art.Ctime.Format(time.DateTime),
@@ -285,6 +306,12 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 }
 
 func (h *ArticleHandler) Like(ctx *gin.Context, req ArticleLikeReq, uc jwt.UserClaims) (ginx.Result, error) {
+	// 取消点赞不用管有没有被封禁
+	if req.Like {
+		if res, ok, err := checkInteractionBan(ctx, h.banSvc, uc.Uid); !ok {
+			return res, err
+		}
+	}
 	var err error
 	if req.Like {
 		// 点赞
@@ -309,6 +336,9 @@ func (h *ArticleHandler) Like(ctx *gin.Context, req ArticleLikeReq, uc jwt.UserC
 }
 
 func (h *ArticleHandler) Collect(ctx *gin.Context, req ArticleCollectReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if res, ok, err := checkInteractionBan(ctx, h.banSvc, uc.Uid); !ok {
+		return res, err
+	}
 	err := h.intrSvc.Collect(ctx, h.biz, req.Id, req.Cid, uc.Uid)
 	if err != nil {
 		h.l.Error("收藏失败",
@@ -324,3 +354,16 @@ func (h *ArticleHandler) Collect(ctx *gin.Context, req ArticleCollectReq, uc jwt
 		Msg: "OK",
 	}, nil
 }
+
+// checkInteractionBan 评论、点赞、收藏之前检查是不是被封禁了
+func checkInteractionBan(ctx *gin.Context, banSvc service.BanService, uid int64) (ginx.Result, bool, error) {
+	err := banSvc.Check(ctx, uid, domain.BanScopeComment)
+	switch err {
+	case nil:
+		return ginx.Result{}, true, nil
+	case service.ErrUserBanned:
+		return ginx.Result{Code: errs.InteractionBanned, Msg: "你已被禁止评论、点赞和收藏"}, false, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, false, err
+	}
+}
@@ -1,15 +1,16 @@
 package web
 
 type ArticleVo struct {
-	Id         int64  `json:"id,omitempty"`
-	Title      string `json:"title,omitempty"`
-	Abstract   string `json:"abstract,omitempty"`
-	Content    string `json:"content,omitempty"`
-	AuthorId   int64  `json:"authorId,omitempty"`
-	AuthorName string `json:"authorName,omitempty"`
-	Status     uint8  `json:"status,omitempty"`
-	Ctime      string `json:"ctime,omitempty"`
-	Utime      string `json:"utime,omitempty"`
+	Id         int64    `json:"id,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Abstract   string   `json:"abstract,omitempty"`
+	Content    string   `json:"content,omitempty"`
+	AuthorId   int64    `json:"authorId,omitempty"`
+	AuthorName string   `json:"authorName,omitempty"`
+	Status     uint8    `json:"status,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Ctime      string   `json:"ctime,omitempty"`
+	Utime      string   `json:"utime,omitempty"`
 
 	ReadCnt    int64 `json:"readCnt"`
 	LikeCnt    int64 `json:"likeCnt"`
@@ -22,12 +23,15 @@ type PublishReq struct {
 	Id      int64  `json:"id"`
 	Title   string `json:"title"`
 	Content string `json:"content"`
+	// Tags 不传就保留原本的标签
+	Tags []string `json:"tags"`
 }
 
 type ArticleEditReq struct {
-	Id      int64  `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Id      int64    `json:"id"`
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
 }
 
 type ArticleWithdrawReq struct {
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	igrpc "webook/internal/grpc"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// CommentHandler 评论的 HTTP 网关，真正的逻辑都在 CommentService 的 gRPC 服务里面
+type CommentHandler struct {
+	client commentv1.CommentServiceClient
+	banSvc service.BanService
+}
+
+func NewCommentHandler(client commentv1.CommentServiceClient, banSvc service.BanService) *CommentHandler {
+	return &CommentHandler{client: client, banSvc: banSvc}
+}
+
+func (h *CommentHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/comments")
+	// /comments/list?biz=article&bizId=1&minId=0&limit=10
+	g.GET("/list", ginx.WrapBody(h.List))
+	// /comments/replies?rid=1&maxId=0&limit=10
+	g.GET("/replies", ginx.WrapBody(h.Replies))
+	g.POST("/create", ginx.WrapBodyAndClaims(h.Create))
+	g.POST("/delete", ginx.WrapBodyAndClaims(h.Delete))
+}
+
+func (h *CommentHandler) List(ctx *gin.Context, req CommentListReq) (ginx.Result, error) {
+	if req.Biz == "" || req.BizId <= 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "参数错误"}, nil
+	}
+	resp, err := h.client.GetCommentList(ctx, &commentv1.CommentListRequest{
+		Biz:   req.Biz,
+		Bizid: req.BizId,
+		MinId: req.MinId,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toVos(resp.GetComments())}, nil
+}
+
+func (h *CommentHandler) Replies(ctx *gin.Context, req CommentRepliesReq) (ginx.Result, error) {
+	if req.Rid <= 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "参数错误"}, nil
+	}
+	resp, err := h.client.GetMoreReplies(ctx, &commentv1.GetMoreRepliesRequest{
+		Rid:   req.Rid,
+		MaxId: req.MaxId,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toVos(resp.GetReplies())}, nil
+}
+
+func (h *CommentHandler) Create(ctx *gin.Context, req CommentCreateReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Biz == "" || req.BizId <= 0 || req.Content == "" {
+		return ginx.Result{Code: 4, Msg: "参数错误"}, nil
+	}
+	if res, ok, err := checkInteractionBan(ctx, h.banSvc, uc.Uid); !ok {
+		return res, err
+	}
+	cmt := &commentv1.Comment{
+		Uid:     uc.Uid,
+		Biz:     req.Biz,
+		Bizid:   req.BizId,
+		Content: req.Content,
+	}
+	if req.ParentId > 0 {
+		cmt.ParentComment = &commentv1.Comment{Id: req.ParentId}
+	}
+	// 评论服务以 metadata 里面的用户为准
+	_, err := h.client.CreateComment(igrpc.WithUid(ctx, uc.Uid), &commentv1.CreateCommentRequest{Comment: cmt})
+	switch status.Code(err) {
+	case codes.OK:
+		return ginx.Result{Msg: "OK"}, nil
+	case codes.InvalidArgument, codes.NotFound:
+		return ginx.Result{Code: 4, Msg: status.Convert(err).Message()}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *CommentHandler) Delete(ctx *gin.Context, req CommentDeleteReq, uc jwt.UserClaims) (ginx.Result, error) {
+	// 带上当前用户，只能删除自己的评论
+	_, err := h.client.DeleteComment(igrpc.WithUid(ctx, uc.Uid), &commentv1.DeleteCommentRequest{
+		Id: req.Id,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return ginx.Result{Msg: "OK"}, nil
+	case codes.PermissionDenied:
+		return ginx.Result{Code: 4, Msg: "只能删除自己的评论"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *CommentHandler) toVos(cs []*commentv1.Comment) []CommentVo {
+	return slice.Map[*commentv1.Comment, CommentVo](cs, func(idx int, src *commentv1.Comment) CommentVo {
+		return CommentVo{
+			Id:       src.GetId(),
+			Uid:      src.GetUid(),
+			Biz:      src.GetBiz(),
+			BizId:    src.GetBizid(),
+			Content:  src.GetContent(),
+			RootId:   src.GetRootComment().GetId(),
+			ParentId: src.GetParentComment().GetId(),
+			Ctime:    src.GetCtime().AsTime().Local().Format(time.DateTime),
+		}
+	})
+}
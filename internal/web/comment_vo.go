@@ -0,0 +1,40 @@
+package web
+
+type CommentVo struct {
+	Id       int64  `json:"id"`
+	Uid      int64  `json:"uid"`
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"bizId"`
+	Content  string `json:"content"`
+	RootId   int64  `json:"rootId,omitempty"`
+	ParentId int64  `json:"parentId,omitempty"`
+	Ctime    string `json:"ctime"`
+}
+
+type CommentListReq struct {
+	Biz   string `form:"biz"`
+	BizId int64  `form:"bizId"`
+	// 上一批最小的 ID，第一次查询不传
+	MinId int64 `form:"minId"`
+	Limit int64 `form:"limit"`
+}
+
+type CommentRepliesReq struct {
+	// 根评论 ID
+	Rid int64 `form:"rid"`
+	// 上一批最大的 ID，第一次查询不传
+	MaxId int64 `form:"maxId"`
+	Limit int64 `form:"limit"`
+}
+
+type CommentCreateReq struct {
+	Biz     string `json:"biz"`
+	BizId   int64  `json:"bizId"`
+	Content string `json:"content"`
+	// 回复哪一条评论，不传就是根评论
+	ParentId int64 `json:"parentId"`
+}
+
+type CommentDeleteReq struct {
+	Id int64 `json:"id"`
+}
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// FeedHandler 信息流
+type FeedHandler struct {
+	recSvc  service.RecommendService
+	feedSvc service.FeedService
+	intrSvc service.InteractiveService
+	l       logger.LoggerV1
+	biz     string
+}
+
+func NewFeedHandler(recSvc service.RecommendService, feedSvc service.FeedService,
+	intrSvc service.InteractiveService, l logger.LoggerV1) *FeedHandler {
+	return &FeedHandler{
+		recSvc:  recSvc,
+		feedSvc: feedSvc,
+		intrSvc: intrSvc,
+		l:       l,
+		biz:     "article",
+	}
+}
+
+func (h *FeedHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/feed")
+	// /feed/recommend?offset=?&limit=?
+	g.GET("/recommend", ginx.WrapClaims(h.Recommend))
+	// /feed/following?cursor=?&limit=?，第一页不传 cursor
+	g.GET("/following", ginx.WrapBodyAndClaims(h.Following))
+}
+
+func (h *FeedHandler) Recommend(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if offset < 0 || limit <= 0 || limit > 100 {
+		return ginx.Result{
+			Code: 4,
+			Msg:  "分页参数错误",
+		}, nil
+	}
+	arts, err := h.recSvc.Recommend(ctx, uc.Uid, offset, limit)
+	if err != nil {
+		return ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: h.toVos(ctx, uc.Uid, arts),
+	}, nil
+}
+
+func (h *FeedHandler) Following(ctx *gin.Context, req FollowingReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	cursor, ok := parseFeedCursor(req.Cursor)
+	if !ok || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{
+			Code: 4,
+			Msg:  "分页参数错误",
+		}, nil
+	}
+	arts, next, err := h.feedSvc.Following(ctx, uc.Uid, cursor, req.Limit)
+	if err != nil {
+		return ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: FollowingVo{
+			Arts:   h.toVos(ctx, uc.Uid, arts),
+			Cursor: formatFeedCursor(next),
+		},
+	}, nil
+}
+
+// parseFeedCursor 游标的格式是 ctime_aid，空字符串表示第一页
+func parseFeedCursor(s string) (domain.FeedCursor, bool) {
+	if s == "" {
+		return domain.FeedCursor{}, true
+	}
+	ctimeStr, aidStr, ok := strings.Cut(s, "_")
+	if !ok {
+		return domain.FeedCursor{}, false
+	}
+	ctime, err := strconv.ParseInt(ctimeStr, 10, 64)
+	if err != nil || ctime <= 0 {
+		return domain.FeedCursor{}, false
+	}
+	aid, err := strconv.ParseInt(aidStr, 10, 64)
+	if err != nil || aid <= 0 {
+		return domain.FeedCursor{}, false
+	}
+	return domain.FeedCursor{Ctime: ctime, Aid: aid}, true
+}
+
+func formatFeedCursor(c domain.FeedCursor) string {
+	if c.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(c.Ctime, 10) + "_" + strconv.FormatInt(c.Aid, 10)
+}
+
+func (h *FeedHandler) toVos(ctx *gin.Context, uid int64, arts []domain.Article) []ArticleVo {
+	ids := slice.Map[domain.Article, int64](arts, func(idx int, src domain.Article) int64 {
+		return src.Id
+	})
+	intrMap := map[int64]domain.Interactive{}
+	if len(ids) > 0 {
+		var err error
+		intrMap, err = h.intrSvc.GetByIds(ctx, h.biz, ids)
+		if err != nil {
+			// 计数拿不到也不影响信息流展示
+			h.l.Error("获取信息流文章的互动数据失败",
+				logger.Int64("uid", uid),
+				logger.Error(err))
+		}
+	}
+	return slice.Map[domain.Article, ArticleVo](arts, func(idx int, src domain.Article) ArticleVo {
+		intr := intrMap[src.Id]
+		return ArticleVo{
+			Id:         src.Id,
+			Title:      src.Title,
+			Abstract:   src.Abstract(),
+			AuthorId:   src.Author.Id,
+			AuthorName: src.Author.Name,
+			Tags:       src.Tags,
+			ReadCnt:    intr.ReadCnt,
+			LikeCnt:    intr.LikeCnt,
+			CollectCnt: intr.CollectCnt,
+			Ctime:      src.Ctime.Format(time.DateTime),
+			Utime:      src.Utime.Format(time.DateTime),
+		}
+	})
+}
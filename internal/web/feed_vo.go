@@ -0,0 +1,13 @@
+package web
+
+type FollowingReq struct {
+	// 上一页返回的游标，第一页不传
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type FollowingVo struct {
+	Arts []ArticleVo `json:"arts"`
+	// 下一页的游标，空字符串表示没有更多了
+	Cursor string `json:"cursor"`
+}
@@ -0,0 +1,133 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// FollowHandler 关注的 HTTP 网关
+type FollowHandler struct {
+	client followv1.FollowServiceClient
+}
+
+func NewFollowHandler(client followv1.FollowServiceClient) *FollowHandler {
+	return &FollowHandler{client: client}
+}
+
+func (h *FollowHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/follow")
+	g.POST("/follow", ginx.WrapBodyAndClaims(h.Follow))
+	g.POST("/cancel", ginx.WrapBodyAndClaims(h.CancelFollow))
+	// /follow/followees?uid=1&offset=0&limit=10
+	g.GET("/followees", ginx.WrapBodyAndClaims(h.Followees))
+	// /follow/followers?uid=1
+	g.GET("/followers", ginx.WrapBodyAndClaims(h.Followers))
+	// 自己有没有关注某个人
+	g.GET("/info", ginx.WrapBodyAndClaims(h.FollowInfo))
+	g.GET("/statics", ginx.WrapBodyAndClaims(h.Statics))
+}
+
+func (h *FollowHandler) Follow(ctx *gin.Context, req FollowReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Followee <= 0 {
+		return ginx.Result{Code: 4, Msg: "参数错误"}, nil
+	}
+	_, err := h.client.Follow(ctx, &followv1.FollowRequest{
+		Followee: req.Followee,
+		Follower: uc.Uid,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return ginx.Result{Msg: "OK"}, nil
+	case codes.InvalidArgument:
+		return ginx.Result{Code: 4, Msg: status.Convert(err).Message()}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *FollowHandler) CancelFollow(ctx *gin.Context, req FollowReq, uc jwt.UserClaims) (ginx.Result, error) {
+	_, err := h.client.CancelFollow(ctx, &followv1.CancelFollowRequest{
+		Followee: req.Followee,
+		Follower: uc.Uid,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *FollowHandler) Followees(ctx *gin.Context, req FolloweeListReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	uid := req.Uid
+	if uid == 0 {
+		uid = uc.Uid
+	}
+	resp, err := h.client.GetFollowee(ctx, &followv1.GetFolloweeRequest{
+		Follower: uid,
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toVos(resp.GetFollowRelations())}, nil
+}
+
+func (h *FollowHandler) Followers(ctx *gin.Context, req FollowUidReq, uc jwt.UserClaims) (ginx.Result, error) {
+	uid := req.Uid
+	if uid == 0 {
+		uid = uc.Uid
+	}
+	resp, err := h.client.GetFollower(ctx, &followv1.GetFollowerRequest{
+		Followee: uid,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toVos(resp.GetFollowRelations())}, nil
+}
+
+func (h *FollowHandler) FollowInfo(ctx *gin.Context, req FollowInfoReq, uc jwt.UserClaims) (ginx.Result, error) {
+	resp, err := h.client.FollowInfo(ctx, &followv1.FollowInfoRequest{
+		Follower: uc.Uid,
+		Followee: req.Followee,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	// 没有关注的时候关系是空的
+	return ginx.Result{Data: resp.GetFollowRelation() != nil}, nil
+}
+
+func (h *FollowHandler) Statics(ctx *gin.Context, req FollowUidReq, uc jwt.UserClaims) (ginx.Result, error) {
+	uid := req.Uid
+	if uid == 0 {
+		uid = uc.Uid
+	}
+	resp, err := h.client.GetFollowStatic(ctx, &followv1.GetFollowStaticRequest{
+		Followee: uid,
+	})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: FollowStaticsVo{
+		Followers: resp.GetFollowStatic().GetFollowers(),
+		Followees: resp.GetFollowStatic().GetFollowees(),
+	}}, nil
+}
+
+func (h *FollowHandler) toVos(rs []*followv1.FollowRelation) []FollowRelationVo {
+	return slice.Map[*followv1.FollowRelation, FollowRelationVo](rs, func(idx int, src *followv1.FollowRelation) FollowRelationVo {
+		return FollowRelationVo{
+			Followee: src.GetFollowee(),
+			Follower: src.GetFollower(),
+		}
+	})
+}
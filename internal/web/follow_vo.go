@@ -0,0 +1,32 @@
+package web
+
+type FollowReq struct {
+	// 被关注的人
+	Followee int64 `json:"followee"`
+}
+
+type FolloweeListReq struct {
+	// 不传就是查自己的
+	Uid    int64 `form:"uid"`
+	Offset int64 `form:"offset"`
+	Limit  int64 `form:"limit"`
+}
+
+type FollowUidReq struct {
+	// 不传就是查自己的
+	Uid int64 `form:"uid"`
+}
+
+type FollowInfoReq struct {
+	Followee int64 `form:"followee"`
+}
+
+type FollowRelationVo struct {
+	Followee int64 `json:"followee"`
+	Follower int64 `json:"follower"`
+}
+
+type FollowStaticsVo struct {
+	Followers int64 `json:"followers"`
+	Followees int64 `json:"followees"`
+}
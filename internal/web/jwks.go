@@ -0,0 +1,34 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// JWKSHandler 公开 access token 的公钥，其它服务用 pkg/jwks 拉下来自己校验 token
+type JWKSHandler struct {
+	keys jwt.Keys
+	l    logger.LoggerV1
+}
+
+func NewJWKSHandler(keys jwt.Keys, l logger.LoggerV1) *JWKSHandler {
+	return &JWKSHandler{keys: keys, l: l}
+}
+
+func (h *JWKSHandler) RegisterRoutes(server *gin.Engine) {
+	server.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+func (h *JWKSHandler) JWKS(ctx *gin.Context) {
+	set, err := h.keys.Access.JWKS()
+	if err != nil {
+		h.l.Error("生成 JWKS 失败", logger.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	// 轮换的时候新公钥要先发布出去，缓存不能太久
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"webook/pkg/jwks"
+)
+
+var ErrInvalidToken = errors.New("token 无效")
+
+// Key 一个签名密钥。轮换下来的旧密钥只留公钥，用来校验还没过期的 token
+type Key struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// Keys access token 的公钥会通过 JWKS 公开给其它服务，
+// refresh token 只有我们自己校验，所以分开两套
+type Keys struct {
+	Access  *KeySet
+	Refresh *KeySet
+}
+
+// KeySet 用一个密钥签名，所有密钥都可以用来校验，token 头里面的 kid 决定用哪一个
+type KeySet struct {
+	signing Key
+	keys    map[string]Key
+}
+
+// NewKeySet signingKid 对应的密钥必须有私钥
+func NewKeySet(signingKid string, keys ...Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]Key, len(keys))}
+	for _, k := range keys {
+		if _, ok := ks.keys[k.Kid]; ok {
+			return nil, fmt.Errorf("kid %s 重复了", k.Kid)
+		}
+		ks.keys[k.Kid] = k
+	}
+	signing, ok := ks.keys[signingKid]
+	if !ok || signing.Private == nil {
+		return nil, fmt.Errorf("没有 kid 为 %s 的私钥", signingKid)
+	}
+	ks.signing = signing
+	return ks, nil
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.Kid
+	return token.SignedString(ks.signing.Private)
+}
+
+// Parse 校验签名和过期时间，解析到 claims 里面
+func (ks *KeySet) Parse(tokenStr string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenStr, claims, ks.keyfunc)
+	if err != nil {
+		return err
+	}
+	if token == nil || !token.Valid {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (ks *KeySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的 kid %s", kid)
+	}
+	// 防止算法混淆，比如拿公钥当 HMAC 的密钥
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("kid %s 的算法是 %s，token 用的是 %s", kid, key.Method.Alg(), token.Method.Alg())
+	}
+	return key.Public, nil
+}
+
+// JWKS 所有能用来校验的公钥
+func (ks *KeySet) JWKS() (jwks.Set, error) {
+	set := jwks.Set{Keys: make([]jwks.JWK, 0, len(ks.keys))}
+	for _, k := range ks.keys {
+		jwk, err := jwks.FromPublicKey(k.Kid, k.Method.Alg(), k.Public)
+		if err != nil {
+			return jwks.Set{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// ParseKey 从 PEM 里面加载密钥。有私钥就能签名，只有公钥的只能校验。
+// alg 只支持 RS256 和 EdDSA
+func ParseKey(kid, alg string, privatePEM, publicPEM []byte) (Key, error) {
+	key := Key{Kid: kid, Method: jwt.GetSigningMethod(alg)}
+	if alg != jwt.SigningMethodRS256.Alg() && alg != jwt.SigningMethodEdDSA.Alg() {
+		return Key{}, fmt.Errorf("kid %s 不支持的算法 %s", kid, alg)
+	}
+	switch {
+	case len(privatePEM) > 0:
+		block, _ := pem.Decode(privatePEM)
+		if block == nil {
+			return Key{}, fmt.Errorf("kid %s 私钥不是 PEM 格式", kid)
+		}
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			// openssl genrsa 生成的是 PKCS1
+			priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		}
+		if err != nil {
+			return Key{}, fmt.Errorf("kid %s 解析私钥失败 %w", kid, err)
+		}
+		signer, ok := priv.(crypto.Signer)
+		if !ok {
+			return Key{}, fmt.Errorf("kid %s 不支持的私钥 %T", kid, priv)
+		}
+		key.Private = signer
+		key.Public = signer.Public()
+	case len(publicPEM) > 0:
+		block, _ := pem.Decode(publicPEM)
+		if block == nil {
+			return Key{}, fmt.Errorf("kid %s 公钥不是 PEM 格式", kid)
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("kid %s 解析公钥失败 %w", kid, err)
+		}
+		key.Public = pub
+	default:
+		return Key{}, fmt.Errorf("kid %s 没有密钥", kid)
+	}
+	switch key.Public.(type) {
+	case *rsa.PublicKey:
+		if alg != jwt.SigningMethodRS256.Alg() {
+			return Key{}, fmt.Errorf("kid %s 是 RSA 密钥，算法却是 %s", kid, alg)
+		}
+	case ed25519.PublicKey:
+		if alg != jwt.SigningMethodEdDSA.Alg() {
+			return Key{}, fmt.Errorf("kid %s 是 Ed25519 密钥，算法却是 %s", kid, alg)
+		}
+	default:
+		return Key{}, fmt.Errorf("kid %s 不支持的密钥 %T", kid, key.Public)
+	}
+	return key, nil
+}
+
+// GenerateKey 临时生成一个 Ed25519 密钥，只适合本地开发，重启之后原来的 token 都失效
+func GenerateKey(kid string) (Key, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{Kid: kid, Method: jwt.SigningMethodEdDSA, Private: priv, Public: pub}, nil
+}
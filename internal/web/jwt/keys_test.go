@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey, err := GenerateKey("old")
+	require.NoError(t, err)
+	newKey, err := GenerateKey("new")
+	require.NoError(t, err)
+	oldSet, err := NewKeySet("old", oldKey)
+	require.NoError(t, err)
+	// 轮换之后旧密钥只留公钥
+	newSet, err := NewKeySet("new", newKey, Key{Kid: "old", Method: oldKey.Method, Public: oldKey.Public})
+	require.NoError(t, err)
+
+	claims := UserClaims{
+		Uid: 123,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	oldToken, err := oldSet.Sign(claims)
+	require.NoError(t, err)
+	newToken, err := newSet.Sign(claims)
+	require.NoError(t, err)
+
+	var uc UserClaims
+	require.NoError(t, newSet.Parse(oldToken, &uc))
+	assert.Equal(t, int64(123), uc.Uid)
+	require.NoError(t, newSet.Parse(newToken, &uc))
+	// 旧的那一套不认识新密钥
+	assert.Error(t, oldSet.Parse(newToken, &uc))
+}
+
+func TestKeySet_Parse(t *testing.T) {
+	key, err := GenerateKey("k1")
+	require.NoError(t, err)
+	ks, err := NewKeySet("k1", key)
+	require.NoError(t, err)
+	testCases := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr bool
+	}{
+		{
+			name: "过期了",
+			token: func(t *testing.T) string {
+				tokenStr, err := ks.Sign(UserClaims{RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+				}})
+				require.NoError(t, err)
+				return tokenStr
+			},
+			wantErr: true,
+		},
+		{
+			name: "拿公钥当 HMAC 的密钥",
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, UserClaims{})
+				token.Header["kid"] = "k1"
+				tokenStr, err := token.SignedString([]byte(key.Public.(ed25519.PublicKey)))
+				require.NoError(t, err)
+				return tokenStr
+			},
+			wantErr: true,
+		},
+		{
+			name: "没有 kid",
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, UserClaims{})
+				tokenStr, err := token.SignedString(key.Private)
+				require.NoError(t, err)
+				return tokenStr
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var uc UserClaims
+			err := ks.Parse(tc.token(t), &uc)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	key, err := ParseKey("rsa", "RS256", privPEM, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, key.Private)
+	pubKey, err := ParseKey("rsa", "RS256", nil, pubPEM)
+	require.NoError(t, err)
+	assert.Nil(t, pubKey.Private)
+	assert.True(t, priv.PublicKey.Equal(pubKey.Public))
+	// 算法和密钥对不上
+	_, err = ParseKey("rsa", "EdDSA", privPEM, nil)
+	assert.Error(t, err)
+	_, err = ParseKey("rsa", "HS256", privPEM, nil)
+	assert.Error(t, err)
+}
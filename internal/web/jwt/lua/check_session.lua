@@ -0,0 +1,15 @@
+-- 已经退出或者被踢下线的 ssid
+local blacklistKey = KEYS[1]
+-- 会话记录
+local sessionKey = KEYS[2]
+-- 当前时间，毫秒数
+local now = ARGV[1]
+
+if redis.call("exists", blacklistKey) == 1 then
+    return 1
+end
+-- 会话已经过期了就不要再写，不然会留下一个不会过期的 key
+if redis.call("exists", sessionKey) == 1 then
+    redis.call("hset", sessionKey, "last_seen", now)
+end
+return 0
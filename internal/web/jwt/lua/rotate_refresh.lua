@@ -0,0 +1,25 @@
+-- 已经退出或者被踢下线的 ssid
+local blacklistKey = KEYS[1]
+-- 会话记录，refresh_seq 是这一族 refresh token 最新的序号
+local sessionKey = KEYS[2]
+-- 用户名下的 ssid 集合
+local ssidsKey = KEYS[3]
+-- 请求带上来的 refresh token 的序号
+local seq = tonumber(ARGV[1])
+-- 当前时间，毫秒数
+local now = ARGV[2]
+-- 新的 refresh token 的有效期，毫秒数
+local expiration = ARGV[3]
+
+if redis.call("exists", blacklistKey) == 1 or redis.call("exists", sessionKey) == 0 then
+    return -1
+end
+local cur = tonumber(redis.call("hget", sessionKey, "refresh_seq") or "0")
+if cur ~= seq then
+    -- 旧的 refresh token 又被拿出来用了，说明被偷了
+    return -2
+end
+redis.call("hset", sessionKey, "refresh_seq", cur + 1, "last_seen", now)
+redis.call("pexpire", sessionKey, expiration)
+redis.call("pexpire", ssidsKey, expiration)
+return cur + 1
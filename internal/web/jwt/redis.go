@@ -1,36 +1,69 @@
 package jwt
 
 import (
+	"context"
+	_ "embed"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"strconv"
 	"strings"
 	"time"
+	"webook/pkg/logger"
 )
 
+//go:embed lua/check_session.lua
+var luaCheckSession string
+
+//go:embed lua/rotate_refresh.lua
+var luaRotateRefresh string
+
+type Config struct {
+	AccessExpiration  time.Duration
+	RefreshExpiration time.Duration
+}
+
 type RedisJWTHandler struct {
-	client        redis.Cmdable
-	signingMethod jwt.SigningMethod
-	rcExpiration  time.Duration
+	client       redis.Cmdable
+	keys         Keys
+	l            logger.LoggerV1
+	atExpiration time.Duration
+	rcExpiration time.Duration
 }
 
-func NewRedisJWTHandler(client redis.Cmdable) Handler {
+func NewRedisJWTHandler(client redis.Cmdable, keys Keys, cfg Config, l logger.LoggerV1) Handler {
 	return &RedisJWTHandler{
-		client:        client,
-		signingMethod: jwt.SigningMethodHS512,
-		rcExpiration:  time.Hour * 24 * 7,
+		client:       client,
+		keys:         keys,
+		l:            l,
+		atExpiration: cfg.AccessExpiration,
+		rcExpiration: cfg.RefreshExpiration,
 	}
 }
 
+func (h *RedisJWTHandler) ParseAccessToken(tokenStr string) (UserClaims, error) {
+	var uc UserClaims
+	err := h.keys.Access.Parse(tokenStr, &uc)
+	return uc, err
+}
+
+func (h *RedisJWTHandler) ParseRefreshToken(tokenStr string) (RefreshClaims, error) {
+	var rc RefreshClaims
+	err := h.keys.Refresh.Parse(tokenStr, &rc)
+	return rc, err
+}
+
+// CheckSession 顺便更新会话的最近活跃时间
 func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, ssid string) error {
-	cnt, err := h.client.Exists(ctx, fmt.Sprintf("users:ssid:%s", ssid)).Result()
+	res, err := h.client.Eval(ctx, luaCheckSession,
+		[]string{h.blacklistKey(ssid), h.sessionKey(ssid)}, time.Now().UnixMilli()).Int()
 	if err != nil {
 		return err
 	}
-	if cnt > 0 {
+	if res > 0 {
 		return errors.New("token 无效")
 	}
 	return nil
@@ -53,19 +86,147 @@ func (h *RedisJWTHandler) ExtractToken(ctx *gin.Context) string {
 
 func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
 	ssid := uuid.New().String()
-	err := h.setRefreshToken(ctx, uid, ssid)
+	err := h.setRefreshToken(ctx, uid, ssid, 0)
+	if err != nil {
+		return err
+	}
+	err = h.addSession(ctx, uid, ssid)
 	if err != nil {
 		return err
 	}
 	return h.SetJWTToken(ctx, uid, ssid)
 }
 
+// addSession 记下这次登录的设备，跟着 refresh token 一起过期。
+// 用户名下的 ssid 集合跟着最后一次登录过期，里面可能有已经过期的 ssid
+func (h *RedisJWTHandler) addSession(ctx *gin.Context, uid int64, ssid string) error {
+	now := time.Now().UnixMilli()
+	key := h.userSsidsKey(uid)
+	sessKey := h.sessionKey(ssid)
+	pipe := h.client.TxPipeline()
+	pipe.HSet(ctx, sessKey, map[string]any{
+		"uid":        uid,
+		"user_agent": ctx.GetHeader("User-Agent"),
+		"ip":         ctx.ClientIP(),
+		"ctime":      now,
+		"last_seen":  now,
+		// 同一个 ssid 下面的 refresh token 是一族，每换一次序号加一
+		"refresh_seq": 0,
+	})
+	pipe.Expire(ctx, sessKey, h.rcExpiration)
+	pipe.SAdd(ctx, key, ssid)
+	pipe.Expire(ctx, key, h.rcExpiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisJWTHandler) ListSessions(ctx context.Context, uid int64) ([]Session, error) {
+	ssids, err := h.client.SMembers(ctx, h.userSsidsKey(uid)).Result()
+	if err != nil {
+		return nil, err
+	}
+	pipe := h.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(ssids))
+	for _, ssid := range ssids {
+		cmds = append(cmds, pipe.HGetAll(ctx, h.sessionKey(ssid)))
+	}
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Session, 0, len(ssids))
+	var expired []any
+	for i, cmd := range cmds {
+		vals := cmd.Val()
+		if len(vals) == 0 {
+			expired = append(expired, ssids[i])
+			continue
+		}
+		ctime, _ := strconv.ParseInt(vals["ctime"], 10, 64)
+		lastSeen, _ := strconv.ParseInt(vals["last_seen"], 10, 64)
+		res = append(res, Session{
+			Ssid:      ssids[i],
+			UserAgent: vals["user_agent"],
+			IP:        vals["ip"],
+			Ctime:     time.UnixMilli(ctime),
+			LastSeen:  time.UnixMilli(lastSeen),
+		})
+	}
+	if len(expired) > 0 {
+		// 顺手清掉已经过期的，清不掉下次再清
+		_ = h.client.SRem(ctx, h.userSsidsKey(uid), expired...).Err()
+	}
+	return res, nil
+}
+
+func (h *RedisJWTHandler) RevokeSession(ctx context.Context, uid int64, ssid string) error {
+	ok, err := h.client.SIsMember(ctx, h.userSsidsKey(uid), ssid).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return h.revoke(ctx, uid, []string{ssid})
+}
+
+func (h *RedisJWTHandler) RevokeOtherSessions(ctx context.Context, uid int64, current string) error {
+	ssids, err := h.client.SMembers(ctx, h.userSsidsKey(uid)).Result()
+	if err != nil {
+		return err
+	}
+	others := make([]string, 0, len(ssids))
+	for _, ssid := range ssids {
+		if ssid != current {
+			others = append(others, ssid)
+		}
+	}
+	return h.revoke(ctx, uid, others)
+}
+
+func (h *RedisJWTHandler) ClearUserTokens(ctx context.Context, uid int64) error {
+	ssids, err := h.client.SMembers(ctx, h.userSsidsKey(uid)).Result()
+	if err != nil {
+		return err
+	}
+	return h.revoke(ctx, uid, ssids)
+}
+
+// revoke 拉黑 ssid 并且删掉会话记录，已经签发出去的 token 在 CheckSession 的时候就过不去了
+func (h *RedisJWTHandler) revoke(ctx context.Context, uid int64, ssids []string) error {
+	if len(ssids) == 0 {
+		return nil
+	}
+	members := make([]any, 0, len(ssids))
+	pipe := h.client.TxPipeline()
+	for _, ssid := range ssids {
+		pipe.Set(ctx, h.blacklistKey(ssid), "", h.rcExpiration)
+		pipe.Del(ctx, h.sessionKey(ssid))
+		members = append(members, ssid)
+	}
+	pipe.SRem(ctx, h.userSsidsKey(uid), members...)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisJWTHandler) userSsidsKey(uid int64) string {
+	return fmt.Sprintf("users:ssids:%d", uid)
+}
+
+func (h *RedisJWTHandler) sessionKey(ssid string) string {
+	return fmt.Sprintf("users:session:%s", ssid)
+}
+
+func (h *RedisJWTHandler) blacklistKey(ssid string) string {
+	return fmt.Sprintf("users:ssid:%s", ssid)
+}
+
 func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
 	ctx.Header("x-jwt-token", "")
 	ctx.Header("x-refresh-token", "")
 	uc := ctx.MustGet("user").(UserClaims)
 	// 该用户退出，清除掉其所有token，并记录下他的ssid，防止冒用token进行操作
-	return h.client.Set(ctx, fmt.Sprintf("users:ssid:%s", uc.Ssid), "", h.rcExpiration).Err()
+	return h.revoke(ctx, uc.Uid, []string{uc.Ssid})
 }
 
 func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string) error {
@@ -74,12 +235,10 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 		Ssid:      ssid,
 		UserAgent: ctx.GetHeader("User-Agent"),
 		RegisteredClaims: jwt.RegisteredClaims{
-			// 30分钟过期
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 30)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.atExpiration)),
 		},
 	}
-	token := jwt.NewWithClaims(h.signingMethod, uc)
-	tokenStr, err := token.SignedString(JWTKey)
+	tokenStr, err := h.keys.Access.Sign(uc)
 	if err != nil {
 		return err
 	}
@@ -87,16 +246,47 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 	return nil
 }
 
-func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, ssid string) error {
+// RotateRefreshToken refresh token 只能用一次，用了就换一个新的。
+// 旧的再被用的时候，分不清哪个是用户哪个是攻击者，所以整个会话都踢掉
+func (h *RedisJWTHandler) RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error {
+	seq, err := h.client.Eval(ctx, luaRotateRefresh,
+		[]string{h.blacklistKey(rc.Ssid), h.sessionKey(rc.Ssid), h.userSsidsKey(rc.Uid)},
+		rc.Seq, time.Now().UnixMilli(), h.rcExpiration.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	switch seq {
+	case -1:
+		return ErrInvalidToken
+	case -2:
+		h.l.Warn("安全事件：refresh token 被重复使用",
+			logger.String("event", "refresh_token_reused"),
+			logger.Int64("uid", rc.Uid),
+			logger.String("ssid", rc.Ssid),
+			logger.String("ip", ctx.ClientIP()))
+		err = h.revoke(ctx, rc.Uid, []string{rc.Ssid})
+		if err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	}
+	err = h.setRefreshToken(ctx, rc.Uid, rc.Ssid, seq)
+	if err != nil {
+		return err
+	}
+	return h.SetJWTToken(ctx, rc.Uid, rc.Ssid)
+}
+
+func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, ssid string, seq int64) error {
 	rc := RefreshClaims{
 		Uid:  uid,
 		Ssid: ssid,
+		Seq:  seq,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.rcExpiration)),
 		},
 	}
-	token := jwt.NewWithClaims(h.signingMethod, rc)
-	tokenStr, err := token.SignedString(RCJWTKey)
+	tokenStr, err := h.keys.Refresh.Sign(rc)
 	if err != nil {
 		return err
 	}
@@ -104,13 +294,12 @@ func (h *RedisJWTHandler) setRefreshToken(ctx *gin.Context, uid int64, ssid stri
 	return nil
 }
 
-var JWTKey = []byte("07EAYgFPX316mrAzJGsQWXq1T3tfHlPc")
-var RCJWTKey = []byte("07EAYgFPX316mrAzJGsQWXq1T3tfHlPa")
-
 type RefreshClaims struct {
 	jwt.RegisteredClaims
 	Uid  int64
 	Ssid string
+	// 在这一族 refresh token 里面的序号
+	Seq int64
 }
 
 type UserClaims struct {
@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"webook/internal/repository/cache/redismocks"
+	"webook/pkg/logger"
+)
+
+func TestRedisJWTHandler_RotateRefreshToken(t *testing.T) {
+	testCases := []struct {
+		name string
+		// lua 脚本的返回值
+		res int64
+
+		wantErr error
+		wantSeq int64
+	}{
+		{
+			name:    "换成下一个序号",
+			res:     3,
+			wantSeq: 3,
+		},
+		{
+			name:    "会话已经失效",
+			res:     -1,
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			client := redismocks.NewMockCmdable(ctrl)
+			cmd := redis.NewCmd(context.Background())
+			cmd.SetVal(tc.res)
+			client.EXPECT().Eval(gomock.Any(), luaRotateRefresh,
+				[]string{"users:ssid:ssid-1", "users:session:ssid-1", "users:ssids:123"},
+				int64(2), gomock.Any(), (time.Hour * 24 * 7).Milliseconds()).
+				Return(cmd)
+			key, err := GenerateKey("k1")
+			require.NoError(t, err)
+			ks, err := NewKeySet("k1", key)
+			require.NoError(t, err)
+			h := NewRedisJWTHandler(client, Keys{Access: ks, Refresh: ks}, Config{
+				AccessExpiration:  time.Minute * 30,
+				RefreshExpiration: time.Hour * 24 * 7,
+			}, logger.NewNoOpLogger())
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/users/refresh_token", nil)
+			err = h.RotateRefreshToken(ctx, RefreshClaims{Uid: 123, Ssid: "ssid-1", Seq: 2})
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			rc, err := h.ParseRefreshToken(recorder.Header().Get("x-refresh-token"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSeq, rc.Seq)
+			assert.Equal(t, "ssid-1", rc.Ssid)
+			assert.NotEmpty(t, recorder.Header().Get("x-jwt-token"))
+		})
+	}
+}
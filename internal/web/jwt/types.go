@@ -1,11 +1,45 @@
 package jwt
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("会话不存在")
+	ErrRefreshTokenReused = errors.New("refresh token 被重复使用")
+)
 
 type Handler interface {
 	ExtractToken(ctx *gin.Context) string
+	// ParseAccessToken 校验签名和过期时间，不检查会话有没有被踢掉
+	ParseAccessToken(tokenStr string) (UserClaims, error)
+	ParseRefreshToken(tokenStr string) (RefreshClaims, error)
+	// RotateRefreshToken 换一对新的 access token 和 refresh token，
+	// 旧的 refresh token 被重复使用的时候整个会话都失效，返回 ErrRefreshTokenReused
+	RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error
 	SetLoginToken(ctx *gin.Context, uid int64) error
 	SetJWTToken(ctx *gin.Context, uid int64, ssid string) error
 	CheckSession(ctx *gin.Context, ssid string) error
 	ClearToken(ctx *gin.Context) error
+	// ClearUserTokens 让这个用户所有设备上的登录态都失效，比如改了密码
+	ClearUserTokens(ctx context.Context, uid int64) error
+	// ListSessions 这个用户还没有过期的登录会话
+	ListSessions(ctx context.Context, uid int64) ([]Session, error)
+	// RevokeSession 踢掉某一个会话，不是这个用户的返回 ErrSessionNotFound
+	RevokeSession(ctx context.Context, uid int64, ssid string) error
+	// RevokeOtherSessions 除了 current 之外的设备都下线
+	RevokeOtherSessions(ctx context.Context, uid int64, current string) error
+}
+
+// Session 一次登录，对应一个 ssid
+type Session struct {
+	Ssid      string
+	UserAgent string
+	IP        string
+	Ctime     time.Time
+	// 最近一次带着这个会话访问的时间
+	LastSeen time.Time
 }
@@ -0,0 +1,14 @@
+package middleware
+
+import "webook/internal/domain"
+
+// accessTokenScopes 可以用个人访问令牌调用的接口和需要的权限，key 是方法加上路由
+var accessTokenScopes = map[string]string{
+	"POST /articles/edit":      domain.ScopeArticlesWrite,
+	"POST /articles/publish":   domain.ScopeArticlesWrite,
+	"POST /articles/withdraw":  domain.ScopeArticlesWrite,
+	"GET /articles/detail/:id": domain.ScopeArticlesRead,
+	"POST /articles/list":      domain.ScopeArticlesRead,
+	"GET /articles/pub/:id":    domain.ScopeArticlesRead,
+	"GET /users/profile":       domain.ScopeProfileRead,
+}
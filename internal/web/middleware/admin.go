@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// 审计日志里面请求体最多记这么长
+const maxAuditDetail = 4096
+
+// AdminMiddlewareBuilder 管理后台的接口按照权限控制，必须放在登录校验之后。
+// 除了查询以外的操作都会记审计日志
+type AdminMiddlewareBuilder struct {
+	rbac  service.RBACService
+	audit service.AuditService
+	l     logger.LoggerV1
+}
+
+func NewAdminMiddlewareBuilder(rbac service.RBACService, audit service.AuditService,
+	l logger.LoggerV1) *AdminMiddlewareBuilder {
+	return &AdminMiddlewareBuilder{rbac: rbac, audit: audit, l: l}
+}
+
+// Build perm 是访问这些接口需要的权限，比如 domain.PermUserBan
+func (b *AdminMiddlewareBuilder) Build(perm string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		val, ok := ctx.Get("user")
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		uc, ok := val.(ijwt.UserClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		allowed, err := b.rbac.HasPermission(ctx, uc.Uid, perm)
+		if err != nil {
+			b.l.Error("查询管理员权限失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if ctx.Request.Method == http.MethodGet {
+			return
+		}
+		b.auditNext(ctx, uc.Uid)
+	}
+}
+
+// auditNext 先执行后面的 handler，再把请求和结果记下来
+func (b *AdminMiddlewareBuilder) auditNext(ctx *gin.Context, uid int64) {
+	var body []byte
+	if ctx.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	ctx.Next()
+	if len(body) > maxAuditDetail {
+		body = body[:maxAuditDetail]
+	}
+	// HTTP 状态码都是 200，要看业务错误码才知道操作成功没有
+	code, ok := ginx.ResultCode(ctx)
+	if !ok {
+		code = domain.AuditCodeNoResult
+	}
+	err := b.audit.Record(ctx, domain.AuditLog{
+		Operator: uid,
+		Action:   ctx.Request.Method + " " + ctx.FullPath(),
+		Detail:   string(body),
+		IP:       ctx.ClientIP(),
+		Code:     code,
+	})
+	if err != nil {
+		// 操作已经做完了，只能靠日志补救
+		b.l.Error("记录审计日志失败",
+			logger.Int64("uid", uid),
+			logger.String("action", ctx.Request.Method+" "+ctx.FullPath()),
+			logger.String("detail", string(body)),
+			logger.Error(err))
+	}
+}
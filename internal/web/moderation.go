@@ -0,0 +1,88 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/internal/web/middleware"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// ModerationHandler 管理员审核内容
+type ModerationHandler struct {
+	svc   service.ModerationService
+	admin *middleware.AdminMiddlewareBuilder
+	l     logger.LoggerV1
+}
+
+func NewModerationHandler(svc service.ModerationService, admin *middleware.AdminMiddlewareBuilder,
+	l logger.LoggerV1) *ModerationHandler {
+	return &ModerationHandler{
+		svc:   svc,
+		admin: admin,
+		l:     l,
+	}
+}
+
+func (h *ModerationHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/admin/moderation", h.admin.Build(domain.PermModeration))
+	// /admin/moderation/tasks?offset=0&limit=10
+	g.GET("/tasks", ginx.WrapBody(h.Tasks))
+	g.POST("/approve", ginx.WrapBodyAndClaims(h.Approve))
+	g.POST("/reject", ginx.WrapBodyAndClaims(h.Reject))
+}
+
+func (h *ModerationHandler) Tasks(ctx *gin.Context, req ModerationTaskListReq) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	ts, err := h.svc.ListPending(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.ModerationTask, ModerationTaskVo](ts, func(idx int, src domain.ModerationTask) ModerationTaskVo {
+			return ModerationTaskVo{
+				Id:      src.Id,
+				Biz:     src.Biz,
+				BizId:   src.BizId,
+				Uid:     src.Uid,
+				Content: src.Content,
+				Hits:    src.Hits,
+				Ctime:   src.Ctime.Format(time.DateTime),
+			}
+		}),
+	}, nil
+}
+
+func (h *ModerationHandler) Approve(ctx *gin.Context, req ModerationReviewReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Approve(ctx, req.Id, uc.Uid)
+	return h.reviewResult(req.Id, uc.Uid, err)
+}
+
+func (h *ModerationHandler) Reject(ctx *gin.Context, req ModerationReviewReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.Reject(ctx, req.Id, uc.Uid, req.Reason)
+	return h.reviewResult(req.Id, uc.Uid, err)
+}
+
+func (h *ModerationHandler) reviewResult(id, reviewer int64, err error) (ginx.Result, error) {
+	switch err {
+	case nil:
+		h.l.Info("审核完成",
+			logger.Int64("tid", id),
+			logger.Int64("reviewer", reviewer))
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrModerationTaskNotFound:
+		return ginx.Result{Code: 4, Msg: "审核任务不存在"}, nil
+	case service.ErrModerationTaskReviewed:
+		return ginx.Result{Code: 4, Msg: "审核任务已经处理过了"}, nil
+	case service.ErrModerationContentChanged:
+		return ginx.Result{Code: 4, Msg: "内容已经被修改，请审核新的内容"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
@@ -0,0 +1,22 @@
+package web
+
+type ModerationTaskVo struct {
+	Id      int64    `json:"id"`
+	Biz     string   `json:"biz"`
+	BizId   int64    `json:"bizId"`
+	Uid     int64    `json:"uid"`
+	Content string   `json:"content"`
+	Hits    []string `json:"hits"`
+	Ctime   string   `json:"ctime"`
+}
+
+type ModerationTaskListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type ModerationReviewReq struct {
+	Id int64 `json:"id"`
+	// 拒绝的时候填写原因
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,310 @@
+package web
+
+import (
+	"fmt"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/service/push"
+	"webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// 长连接上多久发一次心跳，避免被网关当成空闲连接断掉。
+// 每次心跳的时候也会重新校验一次登录态，会话被踢掉或者用户被封禁之后连接最多再保持这么久
+const pushHeartbeat = time.Second * 30
+
+// 对外暴露的通知类型的名字
+var notificationTypeNames = map[domain.NotificationType]string{
+	domain.NotificationTypeLike:    "like",
+	domain.NotificationTypeComment: "comment",
+	domain.NotificationTypeReply:   "reply",
+	domain.NotificationTypeFollow:  "follow",
+	domain.NotificationTypeReward:  "reward",
+	domain.NotificationTypeSystem:  "system",
+}
+
+// NotificationHandler 站内通知，在线的用户通过 WebSocket 或者 SSE 实时收到新通知
+type NotificationHandler struct {
+	svc     service.NotificationService
+	userSvc service.UserService
+	hub     *push.Hub
+	jwtHdl  jwt.Handler
+}
+
+func NewNotificationHandler(svc service.NotificationService, userSvc service.UserService,
+	hub *push.Hub, jwtHdl jwt.Handler) *NotificationHandler {
+	return &NotificationHandler{svc: svc, userSvc: userSvc, hub: hub, jwtHdl: jwtHdl}
+}
+
+func (h *NotificationHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/notifications")
+	// /notifications?offset=?&limit=?
+	g.GET("", ginx.WrapBodyAndClaims(h.List))
+	g.GET("/unread", ginx.WrapClaims(h.Unread))
+	g.POST("/read", ginx.WrapBodyAndClaims(h.MarkRead))
+	g.POST("/read_all", ginx.WrapClaims(h.MarkAllRead))
+	g.GET("/settings", ginx.WrapClaims(h.Settings))
+	g.POST("/settings", ginx.WrapBodyAndClaims(h.UpdateSettings))
+	// 长连接自己校验登录态，不走登录中间件
+	g.GET("/ws", h.WebSocket)
+	// 不支持 WebSocket 的时候退化成 SSE
+	g.GET("/sse", h.SSE)
+}
+
+func (h *NotificationHandler) List(ctx *gin.Context, req NotificationListReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	ns, err := h.svc.List(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.Notification, NotificationVo](ns, func(idx int, src domain.Notification) NotificationVo {
+			return h.toVo(ctx, src)
+		}),
+	}, nil
+}
+
+func (h *NotificationHandler) toVo(ctx *gin.Context, n domain.Notification) NotificationVo {
+	return NotificationVo{
+		Id:       n.Id,
+		Type:     notificationTypeNames[n.Type],
+		Biz:      n.Biz,
+		BizId:    n.BizId,
+		Actors:   n.Actors,
+		ActorCnt: n.ActorCnt,
+		Summary:  h.summary(ctx, n),
+		Content:  n.Content,
+		Read:     n.Read,
+		Utime:    n.Utime.UnixMilli(),
+	}
+}
+
+// summary 拼出 "X 等 N 人赞了你的文章《...》" 这种文案
+func (h *NotificationHandler) summary(ctx *gin.Context, n domain.Notification) string {
+	if n.Type == domain.NotificationTypeSystem {
+		// 系统通知没有触发的人
+		return n.Content
+	}
+	who := "有人"
+	if len(n.Actors) > 0 {
+		// 查不到就用默认的名字，不影响整个列表
+		u, err := h.userSvc.FindById(ctx, n.Actors[0])
+		if err == nil && u.Nickname != "" {
+			who = u.Nickname
+		}
+	}
+	if n.ActorCnt > 1 {
+		who = fmt.Sprintf("%s 等 %d 人", who, n.ActorCnt)
+	}
+	switch n.Type {
+	case domain.NotificationTypeLike:
+		return fmt.Sprintf("%s赞了你的文章《%s》", who, n.BizName)
+	case domain.NotificationTypeComment:
+		return fmt.Sprintf("%s评论了你的文章《%s》", who, n.BizName)
+	case domain.NotificationTypeReply:
+		return fmt.Sprintf("%s回复了你在《%s》下的评论", who, n.BizName)
+	case domain.NotificationTypeFollow:
+		return fmt.Sprintf("%s关注了你", who)
+	case domain.NotificationTypeReward:
+		return fmt.Sprintf("%s打赏了你的文章《%s》%s 元", who, n.BizName, n.Content)
+	default:
+		return ""
+	}
+}
+
+func (h *NotificationHandler) Unread(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	cnts, err := h.svc.UnreadCnt(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toUnreadVo(cnts)}, nil
+}
+
+func (h *NotificationHandler) toUnreadVo(cnts map[domain.NotificationType]int64) UnreadVo {
+	res := UnreadVo{Types: make(map[string]int64, len(notificationTypeNames))}
+	for typ, name := range notificationTypeNames {
+		res.Types[name] = cnts[typ]
+		res.Total += cnts[typ]
+	}
+	return res
+}
+
+func (h *NotificationHandler) MarkRead(ctx *gin.Context, req MarkReadReq, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.MarkRead(ctx, uc.Uid, req.Id)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrNotificationNotFound:
+		// 已经读过了或者不是自己的
+		return ginx.Result{Code: 4, Msg: "通知不存在"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *NotificationHandler) MarkAllRead(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.MarkAllRead(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *NotificationHandler) Settings(ctx *gin.Context, uc jwt.UserClaims) (ginx.Result, error) {
+	u, err := h.userSvc.FindById(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	res := make(map[string]bool, len(notificationTypeNames))
+	for typ, name := range notificationTypeNames {
+		res[name] = u.NotificationMute.Muted(typ)
+	}
+	return ginx.Result{Data: NotificationSettingsVo{Mute: res}}, nil
+}
+
+func (h *NotificationHandler) UpdateSettings(ctx *gin.Context, req NotificationSettingsVo, uc jwt.UserClaims) (ginx.Result, error) {
+	u, err := h.userSvc.FindById(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	mute := u.NotificationMute
+	// 没有传的类型保持原样
+	for typ, name := range notificationTypeNames {
+		if muted, ok := req.Mute[name]; ok {
+			mute = mute.Set(typ, muted)
+		}
+	}
+	err = h.userSvc.UpdateNotificationMute(ctx, uc.Uid, mute)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+// WebSocket 每个设备一条连接，只往下推，客户端发过来的东西都忽略
+func (h *NotificationHandler) WebSocket(ctx *gin.Context) {
+	sess, uc, ok := h.register(ctx)
+	if !ok {
+		return
+	}
+	defer h.hub.Unregister(sess)
+	websocket.Server{Handler: func(conn *websocket.Conn) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			// 读只是为了知道客户端断开了
+			var msg string
+			for websocket.Message.Receive(conn, &msg) == nil {
+			}
+		}()
+		h.pushLoop(ctx, sess, uc, done, func(event string, data any) error {
+			_ = conn.SetWriteDeadline(time.Now().Add(time.Second * 10))
+			return websocket.JSON.Send(conn, PushVo{Event: event, Data: data})
+		})
+	}}.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+func (h *NotificationHandler) SSE(ctx *gin.Context) {
+	sess, uc, ok := h.register(ctx)
+	if !ok {
+		return
+	}
+	defer h.hub.Unregister(sess)
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	// 不要让 nginx 缓冲
+	ctx.Header("X-Accel-Buffering", "no")
+	h.pushLoop(ctx, sess, uc, ctx.Request.Context().Done(), func(event string, data any) error {
+		ctx.SSEvent(event, data)
+		ctx.Writer.Flush()
+		return ctx.Request.Context().Err()
+	})
+}
+
+// register 校验登录态并且登记这条连接，失败的时候已经写好了响应
+func (h *NotificationHandler) register(ctx *gin.Context) (*push.Session, jwt.UserClaims, bool) {
+	uc, err := h.authenticate(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return nil, jwt.UserClaims{}, false
+	}
+	sess, err := h.hub.Register(uc.Uid)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusTooManyRequests)
+		return nil, jwt.UserClaims{}, false
+	}
+	return sess, uc, true
+}
+
+// authenticate 跟登录中间件的校验一样，只是浏览器建长连接的时候带不了 Authorization 头，
+// 所以也允许放在 token 参数里面
+func (h *NotificationHandler) authenticate(ctx *gin.Context) (jwt.UserClaims, error) {
+	tokenStr := h.jwtHdl.ExtractToken(ctx)
+	if tokenStr == "" {
+		tokenStr = ctx.Query("token")
+	}
+	uc, err := h.jwtHdl.ParseAccessToken(tokenStr)
+	if err != nil {
+		return jwt.UserClaims{}, err
+	}
+	if uc.UserAgent != ctx.GetHeader("User-Agent") {
+		return jwt.UserClaims{}, jwt.ErrInvalidToken
+	}
+	err = h.jwtHdl.CheckSession(ctx, uc.Ssid)
+	return uc, err
+}
+
+// stillLoggedIn 长连接建立之后 token 过期了，或者会话被踢掉了（封禁也会踢掉所有会话），
+// 都要断开，让客户端重新登录
+func (h *NotificationHandler) stillLoggedIn(ctx *gin.Context, uc jwt.UserClaims) bool {
+	if uc.ExpiresAt != nil && uc.ExpiresAt.Before(time.Now()) {
+		return false
+	}
+	return h.jwtHdl.CheckSession(ctx, uc.Ssid) == nil
+}
+
+// pushLoop 先同步一次未读数，然后把 Hub 投递过来的消息推下去，直到连接断开或者登录态失效
+func (h *NotificationHandler) pushLoop(ctx *gin.Context, sess *push.Session, uc jwt.UserClaims,
+	done <-chan struct{}, send func(event string, data any) error) {
+	cnts, err := h.svc.UnreadCnt(ctx, sess.Uid())
+	// 查不到未读数也不影响后面的推送
+	if err == nil && send("unread", h.toUnreadVo(cnts)) != nil {
+		return
+	}
+	ticker := time.NewTicker(pushHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case msg, ok := <-sess.Messages():
+			if !ok {
+				return
+			}
+			if msg.Notification != nil && send("notification", h.toVo(ctx, *msg.Notification)) != nil {
+				return
+			}
+			if send("unread", h.toUnreadVo(msg.Unread)) != nil {
+				return
+			}
+		case <-ticker.C:
+			if !h.stillLoggedIn(ctx, uc) {
+				_ = send("logout", "")
+				return
+			}
+			if send("ping", "") != nil {
+				return
+			}
+		}
+	}
+}
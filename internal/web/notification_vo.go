@@ -0,0 +1,41 @@
+package web
+
+type NotificationListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type MarkReadReq struct {
+	Id int64 `json:"id"`
+}
+
+type NotificationVo struct {
+	Id    int64  `json:"id"`
+	Type  string `json:"type"`
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// 最近的几个人，最新的在前面
+	Actors   []int64 `json:"actors"`
+	ActorCnt int64   `json:"actorCnt"`
+	Summary  string  `json:"summary"`
+	Content  string  `json:"content"`
+	Read     bool    `json:"read"`
+	Utime    int64   `json:"utime"`
+}
+
+type UnreadVo struct {
+	Total int64            `json:"total"`
+	Types map[string]int64 `json:"types"`
+}
+
+// NotificationSettingsVo 每种通知是不是免打扰
+type NotificationSettingsVo struct {
+	Mute map[string]bool `json:"mute"`
+}
+
+// PushVo 长连接上推下去的消息，event 是 notification、unread、ping 或者 logout，
+// 收到 logout 说明登录态已经失效，连接马上会断开，不要用原来的 token 重连
+type PushVo struct {
+	Event string `json:"event"`
+	Data  any    `json:"data"`
+}
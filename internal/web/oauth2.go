@@ -0,0 +1,207 @@
+package web
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/errs"
+	"webook/internal/service"
+	"webook/internal/service/oauth2"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+const stateCookieName = "jwt-state"
+
+// OAuth2Handler 第三方登录，微信、GitHub 和配置的 OIDC 提供方都走这里，
+// 路由里面的 provider 就是 oauth2.Provider 的 Name
+type OAuth2Handler struct {
+	registry *oauth2.Registry
+	userSvc  service.UserService
+	banSvc   service.BanService
+	// twoFactorSvc 第三方登录也要过两步验证
+	twoFactorSvc service.TwoFactorService
+	ijwt.Handler
+	key          []byte
+	mergeKey     MergeTicketKey
+	twoFactorKey TwoFactorKey
+}
+
+func NewOAuth2Handler(registry *oauth2.Registry, hdl ijwt.Handler, userSvc service.UserService,
+	banSvc service.BanService, twoFactorSvc service.TwoFactorService,
+	stateKey []byte, mergeKey MergeTicketKey, twoFactorKey TwoFactorKey) *OAuth2Handler {
+	return &OAuth2Handler{
+		registry:     registry,
+		userSvc:      userSvc,
+		banSvc:       banSvc,
+		twoFactorSvc: twoFactorSvc,
+		key:          stateKey,
+		mergeKey:     mergeKey,
+		twoFactorKey: twoFactorKey,
+		Handler:      hdl,
+	}
+}
+
+func (o *OAuth2Handler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/oauth2")
+	g.GET("/providers", o.Providers)
+	g.GET("/:provider/authurl", o.AuthURL)
+	g.Any("/:provider/callback", o.Callback)
+	// 已经登录的用户绑定第三方账号，回调还是走上面那个
+	g.GET("/:provider/bind/authurl", ginx.WrapClaims(o.BindAuthURL))
+}
+
+// Providers 前端根据这个展示有哪些登录按钮
+func (o *OAuth2Handler) Providers(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, ginx.Result{Data: o.registry.Names()})
+}
+
+func (o *OAuth2Handler) AuthURL(ctx *gin.Context) {
+	res, err := o.authURL(ctx, 0)
+	if err != nil {
+		zap.L().Error("构造跳转URL失败", zap.String("provider", ctx.Param("provider")), zap.Error(err))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// BindAuthURL 跟 AuthURL 一样，只是在 state 里面记下了要绑定到哪个用户
+func (o *OAuth2Handler) BindAuthURL(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	return o.authURL(ctx, uc.Uid)
+}
+
+func (o *OAuth2Handler) authURL(ctx *gin.Context, bindUid int64) (ginx.Result, error) {
+	p, ok := o.registry.Get(ctx.Param("provider"))
+	if !ok {
+		return ginx.Result{Msg: "不支持的登录方式", Code: 4}, nil
+	}
+	req, err := oauth2.NewAuthRequest()
+	if err != nil {
+		return ginx.Result{Msg: "服务器异常", Code: 5}, err
+	}
+	val, err := p.AuthURL(ctx, req)
+	if err != nil {
+		return ginx.Result{Msg: "构造跳转URL失败", Code: 5}, err
+	}
+	// 在构造URL的时候就将state放到cookie中，callback的时候验证
+	err = o.setStateCookie(ctx, p.Name(), req, bindUid)
+	if err != nil {
+		return ginx.Result{Msg: "服务器异常", Code: 5}, err
+	}
+	return ginx.Result{Data: val}, nil
+}
+
+func (o *OAuth2Handler) Callback(ctx *gin.Context) {
+	p, ok := o.registry.Get(ctx.Param("provider"))
+	if !ok {
+		ctx.JSON(http.StatusOK, ginx.Result{Msg: "不支持的登录方式", Code: 4})
+		return
+	}
+	sc, err := o.verifyState(ctx, p.Name())
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg:  "非法请求",
+			Code: 4,
+		})
+		return
+	}
+	// 用过一次就作废
+	ctx.SetCookie(stateCookieName, "", -1, o.callbackPath(p.Name()), "", false, true)
+
+	code := ctx.Query("code")
+	identity, err := p.VerifyCode(ctx, code, sc.AuthRequest)
+	if err != nil {
+		zap.L().Warn("第三方授权码校验失败", zap.String("provider", p.Name()), zap.Error(err))
+		ctx.JSON(http.StatusOK, ginx.Result{Msg: "授权码有误", Code: 4})
+		return
+	}
+	if sc.BindUid > 0 {
+		err = o.userSvc.BindIdentity(ctx, sc.BindUid, identity)
+		res, err := bindResult(o.mergeKey, sc.BindUid, err, func() (domain.User, error) {
+			// 冲突说明这个第三方账号已经有用户了，不会新建
+			return o.userSvc.FindOrCreateByIdentity(ctx, identity)
+		})
+		if err != nil {
+			zap.L().Error("绑定第三方账号失败", zap.String("provider", p.Name()),
+				zap.Int64("uid", sc.BindUid), zap.Error(err))
+		}
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+	u, err := o.userSvc.FindOrCreateByIdentity(ctx, identity)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg:  "系统错误",
+			Code: 5,
+		})
+		return
+	}
+	err = o.banSvc.Check(ctx, u.Id, domain.BanScopeLogin)
+	switch err {
+	case nil:
+	case service.ErrUserBanned:
+		ctx.JSON(http.StatusOK, ginx.Result{Msg: "账号已被封禁", Code: errs.UserBanned})
+		return
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{Msg: "系统错误", Code: 5})
+		return
+	}
+	res, err := setLoginTokenOrPending(ctx, o.twoFactorSvc, o.twoFactorKey, o.Handler, u.Id)
+	if err != nil {
+		zap.L().Error("第三方登录失败", zap.String("provider", p.Name()),
+			zap.Int64("uid", u.Id), zap.Error(err))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// verifyState 验证第三方登录的state是否是原用户的，防止csrf攻击
+func (o *OAuth2Handler) verifyState(ctx *gin.Context, provider string) (StateClaims, error) {
+	state := ctx.Query("state")
+	ck, err := ctx.Cookie(stateCookieName)
+	if err != nil {
+		return StateClaims{}, fmt.Errorf("无法获得 cookie %w", err)
+	}
+	var sc StateClaims
+	_, err = jwt.ParseWithClaims(ck, &sc, func(token *jwt.Token) (interface{}, error) {
+		return o.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}))
+	if err != nil {
+		return StateClaims{}, fmt.Errorf("解析token失败 %w", err)
+	}
+	if state == "" || state != sc.State || provider != sc.Provider {
+		// state不匹配，有攻击者
+		return StateClaims{}, fmt.Errorf("state 不匹配")
+	}
+	return sc, nil
+}
+
+// setStateCookie PKCE 的 code_verifier 也放在 cookie 里面，只有回调地址能拿到
+func (o *OAuth2Handler) setStateCookie(ctx *gin.Context, provider string, req oauth2.AuthRequest, bindUid int64) error {
+	claims := StateClaims{
+		AuthRequest: req,
+		Provider:    provider,
+		BindUid:     bindUid,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	tokenStr, err := token.SignedString(o.key)
+	if err != nil {
+		return err
+	}
+	ctx.SetCookie(stateCookieName, tokenStr, 600,
+		o.callbackPath(provider), "", false, true)
+	return nil
+}
+
+func (o *OAuth2Handler) callbackPath(provider string) string {
+	return "/oauth2/" + provider + "/callback"
+}
+
+type StateClaims struct {
+	jwt.RegisteredClaims
+	oauth2.AuthRequest
+	Provider string
+	// 绑定第三方账号的时候才有，是发起绑定的用户
+	BindUid int64
+}
@@ -0,0 +1,49 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/wechatpay"
+)
+
+// WechatPaymentHandler 接收微信的支付通知，响应格式是微信规定的，所以不用 ginx
+type WechatPaymentHandler struct {
+	handler *wechatpay.NotifyHandler
+	svc     service.PaymentService
+	l       logger.LoggerV1
+}
+
+func NewWechatPaymentHandler(handler *wechatpay.NotifyHandler, svc service.PaymentService,
+	l logger.LoggerV1) *WechatPaymentHandler {
+	return &WechatPaymentHandler{
+		handler: handler,
+		svc:     svc,
+		l:       l,
+	}
+}
+
+func (h *WechatPaymentHandler) RegisterRoutes(server *gin.Engine) {
+	server.POST("/pay/callback", h.HandleNative)
+}
+
+func (h *WechatPaymentHandler) HandleNative(ctx *gin.Context) {
+	txn, err := h.handler.ParseNotifyRequest(ctx.Request)
+	if err != nil {
+		// 验签失败大概率是有人伪造，不要让微信重试
+		h.l.Error("解析支付通知失败", logger.Error(err))
+		ctx.JSON(http.StatusBadRequest, wechatpay.ErrorResponse{Code: "FAIL", Message: "验签失败"})
+		return
+	}
+	err = h.svc.HandleCallback(ctx, txn)
+	if err != nil {
+		// 返回失败，微信会重新通知
+		h.l.Error("处理支付通知失败",
+			logger.String("biz_trade_no", txn.OutTradeNo),
+			logger.Error(err))
+		ctx.JSON(http.StatusInternalServerError, wechatpay.ErrorResponse{Code: "FAIL", Message: "系统错误"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
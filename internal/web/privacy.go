@@ -0,0 +1,134 @@
+package web
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/errs"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// PrivacyHandler 导出个人数据和注销账号。
+// 个人访问令牌不能调用这些接口，只能登录之后操作
+type PrivacyHandler struct {
+	exportSvc   service.DataExportService
+	deletionSvc service.AccountDeletionService
+	l           logger.LoggerV1
+}
+
+func NewPrivacyHandler(exportSvc service.DataExportService, deletionSvc service.AccountDeletionService,
+	l logger.LoggerV1) *PrivacyHandler {
+	return &PrivacyHandler{
+		exportSvc:   exportSvc,
+		deletionSvc: deletionSvc,
+		l:           l,
+	}
+}
+
+func (h *PrivacyHandler) RegisterRoutes(server *gin.Engine) {
+	ug := server.Group("/users")
+	// 申请之后异步打包，好了会收到系统通知
+	ug.POST("/export", ginx.WrapClaims(h.RequestExport))
+	ug.GET("/export", ginx.WrapClaims(h.ExportStatus))
+	ug.GET("/export/download", h.DownloadExport)
+	ug.POST("/delete", ginx.WrapClaims(h.RequestDeletion))
+	ug.GET("/delete", ginx.WrapClaims(h.DeletionStatus))
+	ug.POST("/delete/cancel", ginx.WrapClaims(h.CancelDeletion))
+}
+
+func (h *PrivacyHandler) RequestExport(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	e, err := h.exportSvc.Request(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{Data: newDataExportVo(e)}, nil
+	case service.ErrDataExportInProgress:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "上一次导出还没有完成"}, nil
+	case service.ErrDataExportTooFrequent:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "一天只能导出一次"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *PrivacyHandler) ExportStatus(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	e, err := h.exportSvc.Latest(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{Data: newDataExportVo(e)}, nil
+	case service.ErrDataExportNotFound:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "还没有申请过导出"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+// DownloadExport 直接返回 zip，不走 ginx.Result
+func (h *PrivacyHandler) DownloadExport(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	data, err := h.exportSvc.Download(ctx, uc.Uid)
+	switch err {
+	case nil:
+	case service.ErrDataExportNotReady:
+		ctx.JSON(http.StatusOK, ginx.Result{Code: errs.UserInvalidInput, Msg: "没有可以下载的导出"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"})
+		h.l.Error("下载数据导出失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="webook-%d.zip"`, uc.Uid))
+	ctx.Data(http.StatusOK, "application/zip", data)
+}
+
+func (h *PrivacyHandler) RequestDeletion(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	d, err := h.deletionSvc.Request(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: newAccountDeletionVo(d)}, nil
+}
+
+func (h *PrivacyHandler) DeletionStatus(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	d, err := h.deletionSvc.Find(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{Data: newAccountDeletionVo(d)}, nil
+	case service.ErrAccountDeletionNotFound:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "没有申请过注销"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *PrivacyHandler) CancelDeletion(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.deletionSvc.Cancel(ctx, uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrAccountDeletionNotFound:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "没有可以撤销的注销申请"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func newDataExportVo(e domain.DataExport) DataExportVo {
+	return DataExportVo{
+		Id:     e.Id,
+		Status: e.Status.String(),
+		Ctime:  e.Ctime.UnixMilli(),
+		Utime:  e.Utime.UnixMilli(),
+	}
+}
+
+func newAccountDeletionVo(d domain.AccountDeletion) AccountDeletionVo {
+	return AccountDeletionVo{
+		Status:      d.Status.String(),
+		ScheduledAt: d.ScheduledAt.UnixMilli(),
+		Ctime:       d.Ctime.UnixMilli(),
+	}
+}
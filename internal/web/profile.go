@@ -0,0 +1,167 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/internal/domain"
+	"webook/internal/errs"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// 对外暴露的个人主页各部分的名字
+var profilePrivacyNames = map[domain.ProfilePrivacy]string{
+	domain.ProfileHideArticles:     "articles",
+	domain.ProfileHideFollows:      "follows",
+	domain.ProfileHideInteractions: "interactions",
+}
+
+// ProfileHandler 公开的个人主页，还有 handle、头像和主页隐私这些设置
+type ProfileHandler struct {
+	svc          service.ProfileService
+	userSvc      service.UserService
+	followClient followv1.FollowServiceClient
+	l            logger.LoggerV1
+}
+
+func NewProfileHandler(svc service.ProfileService, userSvc service.UserService,
+	followClient followv1.FollowServiceClient, l logger.LoggerV1) *ProfileHandler {
+	return &ProfileHandler{
+		svc:          svc,
+		userSvc:      userSvc,
+		followClient: followClient,
+		l:            l,
+	}
+}
+
+func (h *ProfileHandler) RegisterRoutes(server *gin.Engine) {
+	// 不用登录，/u/:handle?offset=0&limit=10
+	server.GET("/u/:handle", ginx.WrapBody(h.PublicProfile))
+	ug := server.Group("/users")
+	ug.POST("/handle", ginx.WrapBodyAndClaims(h.SetHandle))
+	ug.POST("/avatar", ginx.WrapBodyAndClaims(h.SetAvatar))
+	ug.GET("/privacy", ginx.WrapClaims(h.Privacy))
+	ug.POST("/privacy", ginx.WrapBodyAndClaims(h.UpdatePrivacy))
+}
+
+func (h *ProfileHandler) PublicProfile(ctx *gin.Context, req ProfileListReq) (ginx.Result, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "分页参数错误"}, nil
+	}
+	p, err := h.svc.PublicProfile(ctx, ctx.Param("handle"), req.Offset, req.Limit)
+	switch err {
+	case nil:
+	case service.ErrUserNotFound:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "用户不存在"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	u := p.User
+	vo := PublicProfileVo{
+		Handle:      u.Handle,
+		Nickname:    u.Nickname,
+		Avatar:      u.Avatar,
+		Description: u.Description,
+	}
+	if !u.ProfilePrivacy.Hidden(domain.ProfileHideArticles) {
+		vo.Articles = slice.Map(p.Articles, func(idx int, src domain.Article) ArticleVo {
+			return ArticleVo{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				Tags:     src.Tags,
+				Ctime:    src.Ctime.Format(time.DateTime),
+				Utime:    src.Utime.Format(time.DateTime),
+			}
+		})
+	}
+	if !u.ProfilePrivacy.Hidden(domain.ProfileHideFollows) {
+		// 关注数拿不到也不影响展示主页
+		static, err := h.followClient.GetFollowStatic(ctx, &followv1.GetFollowStaticRequest{
+			Followee: u.Id,
+		})
+		if err != nil {
+			h.l.Error("获取关注数失败", logger.Int64("uid", u.Id), logger.Error(err))
+		} else {
+			vo.Follow = &ProfileFollowVo{
+				Followers: static.GetFollowStatic().GetFollowers(),
+				Followees: static.GetFollowStatic().GetFollowees(),
+			}
+		}
+	}
+	if !u.ProfilePrivacy.Hidden(domain.ProfileHideInteractions) {
+		vo.Interactive = &ProfileInteractiveVo{
+			ReadCnt:    p.Interactive.ReadCnt,
+			LikeCnt:    p.Interactive.LikeCnt,
+			CollectCnt: p.Interactive.CollectCnt,
+		}
+	}
+	return ginx.Result{Data: vo}, nil
+}
+
+func (h *ProfileHandler) SetHandle(ctx *gin.Context, req SetHandleReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.SetHandle(ctx, uc.Uid, req.Handle)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrInvalidHandle:
+		return ginx.Result{Code: errs.UserInvalidInput,
+			Msg: "handle 要以字母开头，只能有字母、数字和下划线，长度 3 到 20"}, nil
+	case service.ErrReservedHandle, service.ErrHandleTaken:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "这个 handle 不能用"}, nil
+	case service.ErrHandleCooldown:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "30 天之内只能改一次"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *ProfileHandler) SetAvatar(ctx *gin.Context, req SetAvatarReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.svc.SetAvatar(ctx, uc.Uid, req.Avatar)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case service.ErrInvalidAvatar:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "头像地址不对"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *ProfileHandler) Privacy(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	u, err := h.userSvc.FindById(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	res := make(map[string]bool, len(profilePrivacyNames))
+	for item, name := range profilePrivacyNames {
+		res[name] = u.ProfilePrivacy.Hidden(item)
+	}
+	return ginx.Result{Data: ProfilePrivacyVo{Hide: res}}, nil
+}
+
+func (h *ProfileHandler) UpdatePrivacy(ctx *gin.Context, req ProfilePrivacyVo, uc ijwt.UserClaims) (ginx.Result, error) {
+	u, err := h.userSvc.FindById(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	privacy := u.ProfilePrivacy
+	// 没有传的部分保持原样
+	for item, name := range profilePrivacyNames {
+		if hidden, ok := req.Hide[name]; ok {
+			privacy = privacy.Set(item, hidden)
+		}
+	}
+	err = h.svc.SetPrivacy(ctx, uc.Uid, privacy)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
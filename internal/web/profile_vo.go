@@ -0,0 +1,44 @@
+package web
+
+type ProfileListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type SetHandleReq struct {
+	Handle string `json:"handle"`
+}
+
+type SetAvatarReq struct {
+	// 为空就是用回默认头像
+	Avatar string `json:"avatar"`
+}
+
+// ProfilePrivacyVo 个人主页上每一部分是不是隐藏
+type ProfilePrivacyVo struct {
+	Hide map[string]bool `json:"hide"`
+}
+
+// PublicProfileVo 别人看到的个人主页，隐藏了的部分是 null
+type PublicProfileVo struct {
+	Handle      string `json:"handle"`
+	Nickname    string `json:"nickname"`
+	Avatar      string `json:"avatar"`
+	Description string `json:"description"`
+
+	Articles    []ArticleVo           `json:"articles"`
+	Follow      *ProfileFollowVo      `json:"follow"`
+	Interactive *ProfileInteractiveVo `json:"interactive"`
+}
+
+type ProfileFollowVo struct {
+	Followers int64 `json:"followers"`
+	Followees int64 `json:"followees"`
+}
+
+// ProfileInteractiveVo 所有发表的文章加起来的数据
+type ProfileInteractiveVo struct {
+	ReadCnt    int64 `json:"readCnt"`
+	LikeCnt    int64 `json:"likeCnt"`
+	CollectCnt int64 `json:"collectCnt"`
+}
@@ -0,0 +1,79 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	rewardv1 "webook/api/proto/gen/reward/v1"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// RewardHandler 打赏的 HTTP 网关
+type RewardHandler struct {
+	client rewardv1.RewardServiceClient
+	artSvc service.ArticleService
+}
+
+func NewRewardHandler(client rewardv1.RewardServiceClient, artSvc service.ArticleService) *RewardHandler {
+	return &RewardHandler{client: client, artSvc: artSvc}
+}
+
+func (h *RewardHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/reward")
+	g.POST("/article", ginx.WrapBodyAndClaims(h.RewardArticle))
+	// /reward/detail?rid=?，前端拿到二维码之后轮询这个接口
+	g.GET("/detail", ginx.WrapBodyAndClaims(h.GetReward))
+}
+
+func (h *RewardHandler) RewardArticle(ctx *gin.Context, req RewardArticleReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Amt <= 0 {
+		return ginx.Result{Code: 4, Msg: "打赏金额不合法"}, nil
+	}
+	// 打赏不算阅读，所以不用 GetPubById
+	arts, err := h.artSvc.ListPubByIds(ctx, []int64{req.Id})
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	if len(arts) == 0 {
+		return ginx.Result{Code: 4, Msg: "文章不存在"}, nil
+	}
+	art := arts[0]
+	resp, err := h.client.PreReward(ctx, &rewardv1.PreRewardRequest{
+		Biz:       "article",
+		BizId:     art.Id,
+		BizName:   art.Title,
+		TargetUid: art.Author.Id,
+		Uid:       uc.Uid,
+		Amt:       req.Amt,
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		return ginx.Result{Code: 4, Msg: status.Convert(err).Message()}, nil
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: RewardVo{
+			Rid:     resp.GetRid(),
+			CodeURL: resp.GetCodeUrl(),
+		},
+	}, nil
+}
+
+func (h *RewardHandler) GetReward(ctx *gin.Context, req GetRewardReq, uc jwt.UserClaims) (ginx.Result, error) {
+	resp, err := h.client.GetReward(ctx, &rewardv1.GetRewardRequest{
+		Rid: req.Rid,
+		Uid: uc.Uid,
+	})
+	if status.Code(err) == codes.NotFound {
+		return ginx.Result{Code: 4, Msg: "打赏不存在"}, nil
+	}
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: resp.GetStatus().String(),
+	}, nil
+}
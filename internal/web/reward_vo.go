@@ -0,0 +1,16 @@
+package web
+
+type RewardArticleReq struct {
+	Id int64 `json:"id"`
+	// 单位是分
+	Amt int64 `json:"amt"`
+}
+
+type GetRewardReq struct {
+	Rid int64 `form:"rid"`
+}
+
+type RewardVo struct {
+	Rid     int64  `json:"rid"`
+	CodeURL string `json:"codeURL"`
+}
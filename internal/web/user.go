@@ -1,13 +1,17 @@
 package web
 
 import (
+	"fmt"
 	regexp "github.com/dlclark/regexp2"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
+	"math"
 	"net/http"
+	"sort"
 	"time"
+	followv1 "webook/api/proto/gen/follow/v1"
 	"webook/internal/domain"
 	"webook/internal/errs"
 	"webook/internal/service"
@@ -20,6 +24,9 @@ const (
 	// 和上面比起来，用 ` 看起来就比较清爽
 	passwordRegexPattern = `^(?=.*[A-Za-z])(?=.*\d)(?=.*[$@$!%*#?&])[A-Za-z\d$@$!%*#?&]{8,}$`
 	bizLogin             = "login"
+	bizEmailVerify       = "email_verify"
+	bizResetPassword     = "reset_password"
+	bizBind              = "bind"
 )
 
 type UserHandler struct {
@@ -28,14 +35,29 @@ type UserHandler struct {
 	passwordRexExp *regexp.Regexp
 	svc            service.UserService
 	codeSvc        service.CodeService
+	followClient   followv1.FollowServiceClient
+	twoFactorSvc   service.TwoFactorService
+	guard          service.LoginGuardService
+	banSvc         service.BanService
+	mergeKey       MergeTicketKey
+	twoFactorKey   TwoFactorKey
 }
 
-func NewUserHandler(svc service.UserService, hdl ijwt.Handler, codeSvc service.CodeService) *UserHandler {
+func NewUserHandler(svc service.UserService, hdl ijwt.Handler, codeSvc service.CodeService,
+	followClient followv1.FollowServiceClient, twoFactorSvc service.TwoFactorService,
+	guard service.LoginGuardService, banSvc service.BanService,
+	mergeKey MergeTicketKey, twoFactorKey TwoFactorKey) *UserHandler {
 	return &UserHandler{
 		emailRexExp:    regexp.MustCompile(emailRegexPattern, regexp.None),
 		passwordRexExp: regexp.MustCompile(passwordRegexPattern, regexp.None),
 		svc:            svc,
 		codeSvc:        codeSvc,
+		followClient:   followClient,
+		twoFactorSvc:   twoFactorSvc,
+		guard:          guard,
+		banSvc:         banSvc,
+		mergeKey:       mergeKey,
+		twoFactorKey:   twoFactorKey,
 		Handler:        hdl,
 	}
 }
@@ -50,6 +72,8 @@ func (h *UserHandler) RegisterRoutes(server *gin.Engine) {
 	ug.POST("/signup", ginx.WrapBody(h.SignUp))
 	//ug.POST("/login", h.Login)
 	ug.POST("/login", ginx.WrapBody(h.LoginJWT))
+	// 开启了两步验证的用户，密码校验通过之后还要输入验证码
+	ug.POST("/login/2fa", ginx.WrapBody(h.LoginTwoFactor))
 	ug.POST("/logout", h.LogoutJWT)
 	ug.POST("/edit", ginx.WrapBodyAndClaims[EditReq, ijwt.UserClaims](h.Edit))
 	ug.GET("/profile", ginx.WrapClaims(h.Profile))
@@ -59,9 +83,42 @@ func (h *UserHandler) RegisterRoutes(server *gin.Engine) {
 	// 手机验证码登录相关功能
 	ug.POST("/login_sms/code/send", h.SendSMSLoginCode)
 	ug.POST("/login_sms", ginx.WrapBody(h.LoginSMS))
+
+	// 邮箱验证，注册之后还没有登录，所以不需要登录态
+	ug.POST("/email/verify/send", ginx.WrapBody(h.SendEmailVerifyCode))
+	ug.POST("/email/verify", ginx.WrapBody(h.VerifyEmail))
+
+	// 忘记密码，用邮箱或者手机验证码重置
+	ug.POST("/password/forgot/send", ginx.WrapBody(h.SendResetPasswordCode))
+	ug.POST("/password/reset", ginx.WrapBody(h.ResetPassword))
+	ug.POST("/password/change", ginx.WrapBodyAndClaims(h.ChangePassword))
+
+	// 绑定手机号和邮箱，已经是别的账号的可以合并过来
+	ug.POST("/bind/phone/send", ginx.WrapBodyAndClaims(h.SendBindPhoneCode))
+	ug.POST("/bind/phone", ginx.WrapBodyAndClaims(h.BindPhone))
+	ug.POST("/bind/email/send", ginx.WrapBodyAndClaims(h.SendBindEmailCode))
+	ug.POST("/bind/email", ginx.WrapBodyAndClaims(h.BindEmail))
+	ug.GET("/identities", ginx.WrapClaims(h.Identities))
+	ug.POST("/unbind", ginx.WrapBodyAndClaims(h.Unbind))
+	ug.POST("/merge", ginx.WrapBodyAndClaims(h.Merge))
+
+	// 登录的设备
+	ug.GET("/sessions", ginx.WrapClaims(h.Sessions))
+	ug.POST("/sessions/revoke", ginx.WrapBodyAndClaims(h.RevokeSession))
+	ug.POST("/sessions/revoke_others", ginx.WrapClaims(h.RevokeOtherSessions))
+
+	// 两步验证
+	ug.GET("/2fa", ginx.WrapClaims(h.TwoFactorStatus))
+	ug.POST("/2fa/enroll", ginx.WrapClaims(h.EnrollTwoFactor))
+	ug.POST("/2fa/confirm", ginx.WrapBodyAndClaims(h.ConfirmTwoFactor))
+	ug.POST("/2fa/disable", ginx.WrapBodyAndClaims(h.DisableTwoFactor))
 }
 
 func (h *UserHandler) LoginSMS(ctx *gin.Context, req LoginSMSReq) (ginx.Result, error) {
+	account := service.PhoneLoginAccount(req.Phone)
+	if res, ok, err := h.checkLoginGuard(ctx, account, req.Captcha); !ok {
+		return res, err
+	}
 	ok, err := h.codeSvc.Verify(ctx, bizLogin, req.Phone, req.Code)
 	if err != nil {
 		zap.L().Error("手机验证码验证失败", zap.Error(err))
@@ -71,11 +128,13 @@ func (h *UserHandler) LoginSMS(ctx *gin.Context, req LoginSMSReq) (ginx.Result,
 		}, err
 	}
 	if !ok {
+		h.loginFailed(ctx, account)
 		return ginx.Result{
 			Code: 4,
 			Msg:  "验证码错误",
 		}, nil
 	}
+	h.loginSucceeded(ctx, account)
 	u, err := h.svc.FindOrCreate(ctx, req.Phone)
 	if err != nil {
 		return ginx.Result{
@@ -83,16 +142,10 @@ func (h *UserHandler) LoginSMS(ctx *gin.Context, req LoginSMSReq) (ginx.Result,
 			Msg:  "系统异常",
 		}, err
 	}
-	err = h.SetLoginToken(ctx, u.Id)
-	if err != nil {
-		return ginx.Result{
-			Msg:  "系统错误",
-			Code: 5,
-		}, err
+	if res, ok, err := h.checkBanned(ctx, u.Id); !ok {
+		return res, err
 	}
-	return ginx.Result{
-		Msg: "登录成功",
-	}, nil
+	return setLoginTokenOrPending(ctx, h.twoFactorSvc, h.twoFactorKey, h.Handler, u.Id)
 }
 
 func (h *UserHandler) SendSMSLoginCode(ctx *gin.Context) {
@@ -179,6 +232,11 @@ func (h *UserHandler) SignUp(ctx *gin.Context, req SignUpReq) (ginx.Result, erro
 	// 判定邮箱冲突
 	switch err {
 	case nil:
+		// 账号已经建好了，验证码没发出去可以再发一次
+		er := h.codeSvc.SendEmail(ctx, bizEmailVerify, req.Email)
+		if er != nil {
+			zap.L().Error("发送邮箱验证码失败", zap.Error(er))
+		}
 		return ginx.Result{
 			Msg: "注册成功",
 		}, nil
@@ -195,25 +253,227 @@ func (h *UserHandler) SignUp(ctx *gin.Context, req SignUpReq) (ginx.Result, erro
 	}
 }
 
+func (h *UserHandler) SendEmailVerifyCode(ctx *gin.Context, req SendEmailCodeReq) (ginx.Result, error) {
+	u, err := h.svc.FindByEmail(ctx, req.Email)
+	switch {
+	case err == service.ErrUserNotFound || err == nil && u.EmailVerified:
+		// 不告诉调用方邮箱有没有注册过
+		return ginx.Result{Msg: "发送成功"}, nil
+	case err != nil:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	err = h.codeSvc.SendEmail(ctx, bizEmailVerify, req.Email)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "发送成功"}, nil
+	case service.ErrCodeSendTooMany:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "邮件发送太频繁，请稍后再试"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) VerifyEmail(ctx *gin.Context, req VerifyEmailReq) (ginx.Result, error) {
+	ok, err := h.codeSvc.VerifyEmail(ctx, bizEmailVerify, req.Email, req.Code)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	if !ok {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	}
+	err = h.svc.VerifyEmail(ctx, req.Email)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "验证成功"}, nil
+}
+
+// findByAccount 忘记密码的时候，用户填的是邮箱或者手机号
+func (h *UserHandler) findByAccount(ctx *gin.Context, email, phone string) (domain.User, error) {
+	if email != "" {
+		return h.svc.FindByEmail(ctx, email)
+	}
+	return h.svc.FindByPhone(ctx, phone)
+}
+
+func (h *UserHandler) SendResetPasswordCode(ctx *gin.Context, req SendResetPasswordCodeReq) (ginx.Result, error) {
+	if (req.Email == "") == (req.Phone == "") {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "请输入邮箱或者手机号码"}, nil
+	}
+	_, err := h.findByAccount(ctx, req.Email, req.Phone)
+	switch {
+	case err == service.ErrUserNotFound:
+		// 不告诉调用方账号存不存在
+		return ginx.Result{Msg: "发送成功"}, nil
+	case err != nil:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	if req.Email != "" {
+		err = h.codeSvc.SendEmail(ctx, bizResetPassword, req.Email)
+	} else {
+		err = h.codeSvc.Send(ctx, bizResetPassword, req.Phone)
+	}
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "发送成功"}, nil
+	case service.ErrCodeSendTooMany:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码发送太频繁，请稍后再试"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) ResetPassword(ctx *gin.Context, req ResetPasswordReq) (ginx.Result, error) {
+	if (req.Email == "") == (req.Phone == "") {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "请输入邮箱或者手机号码"}, nil
+	}
+	if res, ok := h.checkNewPassword(req.Password, req.ConfirmPassword); !ok {
+		return res, nil
+	}
+	var (
+		ok  bool
+		err error
+	)
+	if req.Email != "" {
+		ok, err = h.codeSvc.VerifyEmail(ctx, bizResetPassword, req.Email, req.Code)
+	} else {
+		ok, err = h.codeSvc.Verify(ctx, bizResetPassword, req.Phone, req.Code)
+	}
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	if !ok {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	}
+	u, err := h.findByAccount(ctx, req.Email, req.Phone)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	err = h.svc.ResetPassword(ctx, u.Id, req.Password)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	// 密码可能已经泄露了，所有设备都要重新登录
+	err = h.ClearUserTokens(ctx, u.Id)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "重置成功，请重新登录"}, nil
+}
+
+func (h *UserHandler) ChangePassword(ctx *gin.Context, req ChangePasswordReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	if res, ok := h.checkNewPassword(req.Password, req.ConfirmPassword); !ok {
+		return res, nil
+	}
+	err := h.svc.ChangePassword(ctx, uc.Uid, req.OldPassword, req.Password)
+	switch err {
+	case nil:
+	case service.ErrInvalidUserOrPassword:
+		return ginx.Result{Code: errs.UserInvalidOrPassword, Msg: "旧密码错误"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	// 其他设备都下线，当前设备换一个新的登录态
+	err = h.ClearUserTokens(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	err = h.SetLoginToken(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserSetTokenInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "修改成功"}, nil
+}
+
+// checkNewPassword 新密码的规则跟注册的时候一样
+func (h *UserHandler) checkNewPassword(password, confirmPassword string) (ginx.Result, bool) {
+	if password != confirmPassword {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "两次输入的密码不相等"}, false
+	}
+	isPassword, err := h.passwordRexExp.MatchString(password)
+	if err != nil || !isPassword {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "密码必须包含字母、数字、特殊字符"}, false
+	}
+	return ginx.Result{}, true
+}
+
+// checkBanned 身份校验通过之后、发登录态之前调用
+func (h *UserHandler) checkBanned(ctx *gin.Context, uid int64) (ginx.Result, bool, error) {
+	err := h.banSvc.Check(ctx, uid, domain.BanScopeLogin)
+	switch err {
+	case nil:
+		return ginx.Result{}, true, nil
+	case service.ErrUserBanned:
+		return ginx.Result{Code: errs.UserBanned, Msg: "账号已被封禁"}, false, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, false, err
+	}
+}
+
+// checkLoginGuard 登录之前先看看是不是被锁了，要不要人机验证
+func (h *UserHandler) checkLoginGuard(ctx *gin.Context, account, captchaToken string) (ginx.Result, bool, error) {
+	wait, err := h.guard.Check(ctx, account, ctx.ClientIP(), captchaToken)
+	switch err {
+	case nil:
+		return ginx.Result{}, true, nil
+	case service.ErrAccountLocked:
+		return ginx.Result{
+			Code: errs.UserLoginLocked,
+			Msg:  fmt.Sprintf("失败次数太多，账号已被临时锁定，请 %d 分钟后再试", int(math.Ceil(wait.Minutes()))),
+		}, false, nil
+	case service.ErrLoginTooFrequent:
+		return ginx.Result{
+			Code: errs.UserLoginLocked,
+			Msg:  fmt.Sprintf("登录太频繁，请 %d 秒后再试", int(math.Ceil(wait.Seconds()))),
+		}, false, nil
+	case service.ErrCaptchaRequired:
+		return ginx.Result{Code: errs.UserCaptchaRequired, Msg: "请完成人机验证"}, false, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, false, err
+	}
+}
+
+// loginFailed 计数失败不影响给用户的提示
+func (h *UserHandler) loginFailed(ctx *gin.Context, account string) {
+	err := h.guard.Fail(ctx, account, ctx.ClientIP())
+	if err != nil {
+		zap.L().Error("记录登录失败次数失败", zap.String("account", account), zap.Error(err))
+	}
+}
+
+func (h *UserHandler) loginSucceeded(ctx *gin.Context, account string) {
+	err := h.guard.Succeed(ctx, account)
+	if err != nil {
+		zap.L().Error("清除登录失败次数失败", zap.String("account", account), zap.Error(err))
+	}
+}
+
 func (h *UserHandler) LoginJWT(ctx *gin.Context, req LoginJWTReq) (ginx.Result, error) {
+	account := service.EmailLoginAccount(req.Email)
+	if res, ok, err := h.checkLoginGuard(ctx, account, req.Captcha); !ok {
+		return res, err
+	}
 	u, err := h.svc.Login(ctx, req.Email, req.Password)
 	switch err {
 	case nil:
-		err = h.SetLoginToken(ctx, u.Id)
-		if err != nil {
-			return ginx.Result{
-				Code: errs.UserSetTokenInternalServerError,
-				Msg:  "系统错误",
-			}, err
+		h.loginSucceeded(ctx, account)
+		if res, ok, err := h.checkBanned(ctx, u.Id); !ok {
+			return res, err
 		}
-		return ginx.Result{
-			Msg: "登陆成功",
-		}, nil
+		return setLoginTokenOrPending(ctx, h.twoFactorSvc, h.twoFactorKey, h.Handler, u.Id)
 	case service.ErrInvalidUserOrPassword:
+		h.loginFailed(ctx, account)
 		return ginx.Result{
 			Code: errs.UserInvalidOrPassword,
 			Msg:  "用户名或密码错误",
 		}, err
+	case service.ErrEmailNotVerified:
+		// 密码是对的，不算失败
+		h.loginSucceeded(ctx, account)
+		return ginx.Result{
+			Code: errs.UserEmailNotVerified,
+			Msg:  "邮箱还没有验证，请先完成验证",
+		}, nil
 	default:
 		return ginx.Result{
 			Code: errs.UserInternalServerError,
@@ -257,6 +517,8 @@ func (h *UserHandler) Login(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "登录成功")
 	case service.ErrInvalidUserOrPassword:
 		ctx.String(http.StatusOK, "用户名或密码错误")
+	case service.ErrEmailNotVerified:
+		ctx.String(http.StatusOK, "邮箱还没有验证，请先完成验证")
 	default:
 		ctx.String(http.StatusOK, "系统错误")
 	}
@@ -294,19 +556,36 @@ func (h *UserHandler) Profile(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result
 			Msg: "系统错误",
 		}, err
 	}
+	// 关注数拿不到也不影响展示个人信息
+	static, err := h.followClient.GetFollowStatic(ctx, &followv1.GetFollowStaticRequest{
+		Followee: uc.Uid,
+	})
+	if err != nil {
+		zap.L().Error("获取关注数失败", zap.Int64("uid", uc.Uid), zap.Error(err))
+	}
 	type User struct {
-		Nickname    string `json:"nickname"`
-		Email       string `json:"email"`
-		Birthday    string `json:"birthday"`
-		Description string `json:"description"`
+		Nickname      string `json:"nickname"`
+		Handle        string `json:"handle"`
+		Avatar        string `json:"avatar"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"emailVerified"`
+		Birthday      string `json:"birthday"`
+		Description   string `json:"description"`
+		Followers     int64  `json:"followers"`
+		Followees     int64  `json:"followees"`
 	}
 	return ginx.Result{
 		Msg: "获取成功",
 		Data: User{
-			Nickname:    u.Nickname,
-			Email:       u.Email,
-			Birthday:    u.Birthday.Format(time.DateOnly),
-			Description: u.Description,
+			Nickname:      u.Nickname,
+			Handle:        u.Handle,
+			Avatar:        u.Avatar,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerified,
+			Birthday:      u.Birthday.Format(time.DateOnly),
+			Description:   u.Description,
+			Followers:     static.GetFollowStatic().GetFollowers(),
+			Followees:     static.GetFollowStatic().GetFollowees(),
 		},
 	}, nil
 }
@@ -343,30 +622,62 @@ func (h *UserHandler) ProfileSess(ctx *gin.Context) {
 func (h *UserHandler) RefreshToken(ctx *gin.Context) {
 	// 约定 前端在Authorization里面带上 refresh_token
 	tokenStr := h.ExtractToken(ctx)
-	var rc ijwt.RefreshClaims
-	token, err := jwt.ParseWithClaims(tokenStr, &rc, func(token *jwt.Token) (interface{}, error) {
-		return ijwt.RCJWTKey, nil
-	})
+	rc, err := h.ParseRefreshToken(tokenStr)
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
-	if token == nil || !token.Valid {
-		ctx.AbortWithStatus(http.StatusUnauthorized)
-		return
-	}
-	err = h.CheckSession(ctx, rc.Ssid)
+	// 顺便检查了会话有没有被踢掉，refresh token 被重复使用的时候整个会话都下线
+	err = h.RotateRefreshToken(ctx, rc)
 	if err != nil {
 		// token无效或者 redis有问题
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
-	err = h.SetJWTToken(ctx, rc.Uid, rc.Ssid)
+	ctx.JSON(http.StatusOK, ginx.Result{Msg: "OK"})
+}
+
+func (h *UserHandler) Sessions(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	sessions, err := h.ListSessions(ctx, uc.Uid)
 	if err != nil {
-		ctx.AbortWithStatus(http.StatusUnauthorized)
-		return
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
 	}
-	ctx.JSON(http.StatusOK, ginx.Result{Msg: "OK"})
+	// 最近用过的排在前面
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen.After(sessions[j].LastSeen)
+	})
+	return ginx.Result{
+		Data: slice.Map(sessions, func(idx int, src ijwt.Session) SessionVo {
+			return SessionVo{
+				Ssid:      src.Ssid,
+				UserAgent: src.UserAgent,
+				IP:        src.IP,
+				Ctime:     src.Ctime.UnixMilli(),
+				LastSeen:  src.LastSeen.UnixMilli(),
+				Current:   src.Ssid == uc.Ssid,
+			}
+		}),
+	}, nil
+}
+
+func (h *UserHandler) RevokeSession(ctx *gin.Context, req RevokeSessionReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.Handler.RevokeSession(ctx, uc.Uid, req.Ssid)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "OK"}, nil
+	case ijwt.ErrSessionNotFound:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "会话不存在"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) RevokeOtherSessions(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.Handler.RevokeOtherSessions(ctx, uc.Uid, uc.Ssid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
 }
 
 func (h *UserHandler) LogoutJWT(ctx *gin.Context) {
@@ -0,0 +1,190 @@
+package web
+
+import (
+	"fmt"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/errs"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+var identityTypes = map[string]domain.IdentityType{
+	"phone": domain.IdentityTypePhone,
+	"email": domain.IdentityTypeEmail,
+}
+
+// MergeTicketKey 合并凭证的签名密钥，UserHandler 和 OAuth2Handler 都会签发凭证，要用同一个
+type MergeTicketKey []byte
+
+// MergeClaims 绑定冲突的时候发给用户，证明他刚刚验证过 Duplicate 账号上的身份
+type MergeClaims struct {
+	jwt.RegisteredClaims
+	Primary   int64
+	Duplicate int64
+}
+
+// newMergeTicket 凭证只有十分钟有效
+func newMergeTicket(key MergeTicketKey, primary, duplicate int64) (string, error) {
+	claims := MergeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 10)),
+		},
+		Primary:   primary,
+		Duplicate: duplicate,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	return token.SignedString([]byte(key))
+}
+
+func parseMergeTicket(key MergeTicketKey, ticket string) (MergeClaims, error) {
+	var mc MergeClaims
+	token, err := jwt.ParseWithClaims(ticket, &mc, func(token *jwt.Token) (interface{}, error) {
+		return []byte(key), nil
+	})
+	if err != nil {
+		return MergeClaims{}, err
+	}
+	if token == nil || !token.Valid {
+		return MergeClaims{}, fmt.Errorf("合并凭证无效")
+	}
+	return mc, nil
+}
+
+// bindResult 把绑定的结果转成响应，冲突的时候带上合并凭证
+func bindResult(key MergeTicketKey, uid int64, err error, owner func() (domain.User, error)) (ginx.Result, error) {
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "绑定成功"}, nil
+	case service.ErrIdentityConflict:
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	u, err := owner()
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	ticket, err := newMergeTicket(key, uid, u.Id)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Code: errs.UserIdentityConflict,
+		Msg:  "已经绑定了其他账号，可以把那个账号合并过来",
+		Data: MergeTicketVo{Ticket: ticket},
+	}, nil
+}
+
+func (h *UserHandler) SendBindPhoneCode(ctx *gin.Context, req SendBindCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	if req.Phone == "" {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "请输入手机号码"}, nil
+	}
+	return h.sendCodeResult(h.codeSvc.Send(ctx, bizBind, req.Phone))
+}
+
+func (h *UserHandler) SendBindEmailCode(ctx *gin.Context, req SendBindCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	isEmail, err := h.emailRexExp.MatchString(req.Email)
+	if err != nil || !isEmail {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "非法邮箱格式"}, nil
+	}
+	return h.sendCodeResult(h.codeSvc.SendEmail(ctx, bizBind, req.Email))
+}
+
+func (h *UserHandler) sendCodeResult(err error) (ginx.Result, error) {
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "发送成功"}, nil
+	case service.ErrCodeSendTooMany:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码发送太频繁，请稍后再试"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) BindPhone(ctx *gin.Context, req BindReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	ok, err := h.codeSvc.Verify(ctx, bizBind, req.Phone, req.Code)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	if !ok {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	}
+	err = h.svc.BindPhone(ctx, uc.Uid, req.Phone)
+	return bindResult(h.mergeKey, uc.Uid, err, func() (domain.User, error) {
+		return h.svc.FindByPhone(ctx, req.Phone)
+	})
+}
+
+func (h *UserHandler) BindEmail(ctx *gin.Context, req BindReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	ok, err := h.codeSvc.VerifyEmail(ctx, bizBind, req.Email, req.Code)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	if !ok {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	}
+	err = h.svc.BindEmail(ctx, uc.Uid, req.Email)
+	return bindResult(h.mergeKey, uc.Uid, err, func() (domain.User, error) {
+		return h.svc.FindByEmail(ctx, req.Email)
+	})
+}
+
+func (h *UserHandler) Unbind(ctx *gin.Context, req UnbindReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	var err error
+	if typ, ok := identityTypes[req.Type]; ok {
+		err = h.svc.Unbind(ctx, uc.Uid, typ)
+	} else {
+		// 其它的都当成第三方登录的提供方
+		err = h.svc.UnbindIdentity(ctx, uc.Uid, req.Type)
+	}
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "解绑成功"}, nil
+	case service.ErrLastIdentity:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "至少要保留一种登录方式"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) Merge(ctx *gin.Context, req MergeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	mc, err := parseMergeTicket(h.mergeKey, req.Ticket)
+	// 凭证只能由发起绑定的那个账号使用
+	if err != nil || mc.Primary != uc.Uid {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "合并凭证无效"}, nil
+	}
+	err = h.svc.Merge(ctx, mc.Primary, mc.Duplicate)
+	switch err {
+	case nil:
+	case service.ErrInvalidMerge:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "账号已经合并过了"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	// 被合并的账号不能再用了，已经登录的设备也要下线
+	err = h.ClearUserTokens(ctx, mc.Duplicate)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "合并成功"}, nil
+}
+
+// Identities 绑定了哪些第三方账号
+func (h *UserHandler) Identities(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	identities, err := h.svc.FindIdentities(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: slice.Map(identities, func(idx int, src domain.OAuthIdentity) IdentityVo {
+		return IdentityVo{
+			Provider: src.Provider,
+			Name:     src.Name,
+			Email:    src.Email,
+			Ctime:    src.Ctime.Format(time.DateTime),
+		}
+	})}, nil
+}
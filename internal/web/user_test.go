@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"webook/internal/domain"
 	"webook/internal/errs"
 	"webook/internal/service"
@@ -43,6 +44,8 @@ func TestUserHandler_SignUp(t *testing.T) {
 					Password: "hello#world123",
 				}).Return(nil)
 				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				// 注册之后要验证邮箱
+				codeSvc.EXPECT().SendEmail(gomock.Any(), "email_verify", "1234@qq.com").Return(nil)
 				return userSvc, codeSvc
 			},
 			reqBuilder: func(t *testing.T) *http.Request {
@@ -215,7 +218,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 
 			// 构造handler
 			userSvc, codeSvc := tc.mock(ctrl)
-			hdl := NewUserHandler(userSvc, nil, codeSvc)
+			hdl := NewUserHandler(userSvc, nil, codeSvc, nil, nil, nil, nil, nil, nil)
 			// 准备服务器和构造路由
 			server := gin.Default()
 			hdl.RegisterRoutes(server)
@@ -240,6 +243,76 @@ func TestUserHandler_SignUp(t *testing.T) {
 	}
 }
 
+func TestUserHandler_LoginSMS(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (service.UserService, service.CodeService,
+			service.TwoFactorService, service.LoginGuardService, service.BanService)
+
+		wantCode int
+		wantErr  error
+	}{
+		{
+			name: "开启了两步验证",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService,
+				service.TwoFactorService, service.LoginGuardService, service.BanService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				twoFactorSvc := svcmocks.NewMockTwoFactorService(ctrl)
+				guard := svcmocks.NewMockLoginGuardService(ctrl)
+				banSvc := svcmocks.NewMockBanService(ctrl)
+				account := service.PhoneLoginAccount("15212345678")
+				guard.EXPECT().Check(gomock.Any(), account, gomock.Any(), "").Return(time.Duration(0), nil)
+				codeSvc.EXPECT().Verify(gomock.Any(), bizLogin, "15212345678", "123456").Return(true, nil)
+				guard.EXPECT().Succeed(gomock.Any(), account).Return(nil)
+				userSvc.EXPECT().FindOrCreate(gomock.Any(), "15212345678").
+					Return(domain.User{Id: 123}, nil)
+				banSvc.EXPECT().Check(gomock.Any(), int64(123), domain.BanScopeLogin).Return(nil)
+				// 不能直接登录，jwt handler 是 nil，调用了就会 panic
+				twoFactorSvc.EXPECT().Enabled(gomock.Any(), int64(123)).Return(true, nil)
+				return userSvc, codeSvc, twoFactorSvc, guard, banSvc
+			},
+			wantCode: errs.UserTwoFactorRequired,
+		},
+		{
+			name: "查询两步验证失败",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService,
+				service.TwoFactorService, service.LoginGuardService, service.BanService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				twoFactorSvc := svcmocks.NewMockTwoFactorService(ctrl)
+				guard := svcmocks.NewMockLoginGuardService(ctrl)
+				banSvc := svcmocks.NewMockBanService(ctrl)
+				account := service.PhoneLoginAccount("15212345678")
+				guard.EXPECT().Check(gomock.Any(), account, gomock.Any(), "").Return(time.Duration(0), nil)
+				codeSvc.EXPECT().Verify(gomock.Any(), bizLogin, "15212345678", "123456").Return(true, nil)
+				guard.EXPECT().Succeed(gomock.Any(), account).Return(nil)
+				userSvc.EXPECT().FindOrCreate(gomock.Any(), "15212345678").
+					Return(domain.User{Id: 123}, nil)
+				banSvc.EXPECT().Check(gomock.Any(), int64(123), domain.BanScopeLogin).Return(nil)
+				twoFactorSvc.EXPECT().Enabled(gomock.Any(), int64(123)).Return(false, errors.New("db错误"))
+				return userSvc, codeSvc, twoFactorSvc, guard, banSvc
+			},
+			wantCode: errs.UserInternalServerError,
+			wantErr:  errors.New("db错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userSvc, codeSvc, twoFactorSvc, guard, banSvc := tc.mock(ctrl)
+			hdl := NewUserHandler(userSvc, nil, codeSvc, nil, twoFactorSvc, guard, banSvc, nil, TwoFactorKey("test-key"))
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/login_sms", nil)
+			res, err := hdl.LoginSMS(ctx, LoginSMSReq{Phone: "15212345678", Code: "123456"})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCode, res.Code)
+		})
+	}
+}
+
 func TestUserEmailPattern(t *testing.T) {
 	testCases := []struct {
 		name  string
@@ -263,7 +336,7 @@ func TestUserEmailPattern(t *testing.T) {
 		},
 	}
 
-	h := NewUserHandler(nil, nil, nil)
+	h := NewUserHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,180 @@
+package web
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+	"time"
+	"webook/internal/errs"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+const (
+	// 跟其它用 HS512 签的凭证区分开，别的凭证拿过来用不了
+	pendingTwoFactorIssuer  = "webook"
+	pendingTwoFactorSubject = "pending_2fa"
+)
+
+// TwoFactorKey 两步验证凭证的签名密钥，UserHandler 和 OAuth2Handler 都会签发凭证，要用同一个
+type TwoFactorKey []byte
+
+// PendingTwoFactorClaims 密码已经校验过，还差两步验证。
+// 只能用来换登录态，不能当 access token 用
+type PendingTwoFactorClaims struct {
+	jwt.RegisteredClaims
+	Uid       int64
+	UserAgent string
+}
+
+// newPendingTwoFactorToken 五分钟之内要输入验证码
+func newPendingTwoFactorToken(key TwoFactorKey, uid int64, userAgent string) (string, error) {
+	claims := PendingTwoFactorClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    pendingTwoFactorIssuer,
+			Subject:   pendingTwoFactorSubject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
+		},
+		Uid:       uid,
+		UserAgent: userAgent,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	return token.SignedString([]byte(key))
+}
+
+// setLoginTokenOrPending 开启了两步验证的先不登录，发一个凭证让用户拿着验证码再来。
+// 密码、短信验证码和第三方登录都要走这里，不然两步验证可以被绕过去
+func setLoginTokenOrPending(ctx *gin.Context, twoFactorSvc service.TwoFactorService, key TwoFactorKey,
+	hdl ijwt.Handler, uid int64) (ginx.Result, error) {
+	enabled, err := twoFactorSvc.Enabled(ctx, uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	if enabled {
+		token, err := newPendingTwoFactorToken(key, uid, ctx.GetHeader("User-Agent"))
+		if err != nil {
+			return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+		}
+		return ginx.Result{
+			Code: errs.UserTwoFactorRequired,
+			Msg:  "请输入两步验证的验证码",
+			Data: PendingTwoFactorVo{Token: token},
+		}, nil
+	}
+	err = hdl.SetLoginToken(ctx, uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserSetTokenInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "登陆成功"}, nil
+}
+
+func parsePendingTwoFactorToken(key TwoFactorKey, tokenStr string) (PendingTwoFactorClaims, error) {
+	var pc PendingTwoFactorClaims
+	token, err := jwt.ParseWithClaims(tokenStr, &pc, func(token *jwt.Token) (interface{}, error) {
+		return []byte(key), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}),
+		jwt.WithIssuer(pendingTwoFactorIssuer),
+		jwt.WithSubject(pendingTwoFactorSubject))
+	if err != nil {
+		return PendingTwoFactorClaims{}, err
+	}
+	if token == nil || !token.Valid {
+		return PendingTwoFactorClaims{}, fmt.Errorf("两步验证的凭证无效")
+	}
+	return pc, nil
+}
+
+// LoginTwoFactor 登录的第二步，验证通过了才真正登录
+func (h *UserHandler) LoginTwoFactor(ctx *gin.Context, req LoginTwoFactorReq) (ginx.Result, error) {
+	pc, err := parsePendingTwoFactorToken(h.twoFactorKey, req.Token)
+	if err != nil || pc.UserAgent != ctx.GetHeader("User-Agent") {
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "登录已过期，请重新登录"}, nil
+	}
+	account := service.TwoFactorLoginAccount(pc.Uid)
+	if res, ok, err := h.checkLoginGuard(ctx, account, req.Captcha); !ok {
+		return res, err
+	}
+	err = h.twoFactorSvc.Verify(ctx, pc.Uid, req.Code)
+	switch err {
+	case nil:
+		h.loginSucceeded(ctx, account)
+	case service.ErrInvalidTwoFactorCode:
+		h.loginFailed(ctx, account)
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	// 输入验证码的这几分钟里面也可能被封禁
+	if res, ok, err := h.checkBanned(ctx, pc.Uid); !ok {
+		return res, err
+	}
+	err = h.SetLoginToken(ctx, pc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserSetTokenInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Msg: "登陆成功"}, nil
+}
+
+func (h *UserHandler) TwoFactorStatus(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	enabled, err := h.twoFactorSvc.Enabled(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: TwoFactorStatusVo{Enabled: enabled}}, nil
+}
+
+func (h *UserHandler) EnrollTwoFactor(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	u, err := h.svc.FindById(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+	// App 上显示的账号名
+	account := u.Email
+	if account == "" {
+		account = u.Phone
+	}
+	if account == "" {
+		account = strconv.FormatInt(u.Id, 10)
+	}
+	secret, uri, err := h.twoFactorSvc.Enroll(ctx, uc.Uid, account)
+	switch err {
+	case nil:
+		return ginx.Result{Data: TwoFactorEnrollVo{Secret: secret, URI: uri}}, nil
+	case service.ErrTwoFactorEnabled:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "已经开启了两步验证"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) ConfirmTwoFactor(ctx *gin.Context, req TwoFactorCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	codes, err := h.twoFactorSvc.Confirm(ctx, uc.Uid, req.Code)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "开启成功", Data: RecoveryCodesVo{Codes: codes}}, nil
+	case service.ErrInvalidTwoFactorCode:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	case service.ErrTwoFactorNotEnrolled:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "请先扫码绑定"}, nil
+	case service.ErrTwoFactorEnabled:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "已经开启了两步验证"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
+
+func (h *UserHandler) DisableTwoFactor(ctx *gin.Context, req TwoFactorCodeReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.twoFactorSvc.Disable(ctx, uc.Uid, req.Code)
+	switch err {
+	case nil:
+		return ginx.Result{Msg: "已关闭两步验证"}, nil
+	case service.ErrInvalidTwoFactorCode:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码错误"}, nil
+	case service.ErrTwoFactorNotEnrolled:
+		return ginx.Result{Code: errs.UserInvalidInput, Msg: "还没有开启两步验证"}, nil
+	default:
+		return ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"}, err
+	}
+}
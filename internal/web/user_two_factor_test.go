@@ -0,0 +1,64 @@
+package web
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPendingTwoFactorToken(t *testing.T) {
+	key := TwoFactorKey("test-key")
+	testCases := []struct {
+		name  string
+		token func(t *testing.T) string
+
+		wantUid int64
+		wantErr bool
+	}{
+		{
+			name: "正常的凭证",
+			token: func(t *testing.T) string {
+				token, err := newPendingTwoFactorToken(key, 123, "chrome")
+				require.NoError(t, err)
+				return token
+			},
+			wantUid: 123,
+		},
+		{
+			name: "别的密钥签的",
+			token: func(t *testing.T) string {
+				token, err := newPendingTwoFactorToken(TwoFactorKey("other-key"), 123, "chrome")
+				require.NoError(t, err)
+				return token
+			},
+			wantErr: true,
+		},
+		{
+			name: "同一个密钥签的其它凭证",
+			token: func(t *testing.T) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, PendingTwoFactorClaims{
+					RegisteredClaims: jwt.RegisteredClaims{
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+					},
+					Uid: 123,
+				}).SignedString([]byte(key))
+				require.NoError(t, err)
+				return token
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pc, err := parsePendingTwoFactorToken(key, tc.token(t))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUid, pc.Uid)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package web
 type LoginSMSReq struct {
 	Phone string `json:"phone"`
 	Code  string `json:"code"`
+	// 失败次数多了之后要带上人机验证的 token
+	Captcha string `json:"captcha"`
 }
 
 type SignUpReq struct {
@@ -11,9 +13,73 @@ type SignUpReq struct {
 	ConfirmPassword string `json:"confirmPassword"`
 }
 
+type SendEmailCodeReq struct {
+	Email string `json:"email"`
+}
+
+type VerifyEmailReq struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+type SendResetPasswordCodeReq struct {
+	// 邮箱和手机号二选一
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type ResetPasswordReq struct {
+	Email           string `json:"email"`
+	Phone           string `json:"phone"`
+	Code            string `json:"code"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+type ChangePasswordReq struct {
+	OldPassword     string `json:"oldPassword"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+type SendBindCodeReq struct {
+	// 绑定手机号的时候填 phone，绑定邮箱的时候填 email
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+}
+
+type BindReq struct {
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+type UnbindReq struct {
+	// phone、email 或者第三方登录的提供方，比如 wechat、github
+	Type string `json:"type"`
+}
+
+type IdentityVo struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Ctime    string `json:"ctime"`
+}
+
+type MergeReq struct {
+	// 绑定冲突的时候返回的凭证
+	Ticket string `json:"ticket"`
+}
+
+// MergeTicketVo 绑定冲突的时候返回，拿着它可以把另外一个账号合并过来
+type MergeTicketVo struct {
+	Ticket string `json:"ticket"`
+}
+
 type LoginJWTReq struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Captcha  string `json:"captcha"`
 }
 
 type EditReq struct {
@@ -22,3 +88,92 @@ type EditReq struct {
 	Birthday    string `json:"birthday"`
 	Description string `json:"description"`
 }
+
+type SessionVo struct {
+	Ssid      string `json:"ssid"`
+	UserAgent string `json:"userAgent"`
+	IP        string `json:"ip"`
+	Ctime     int64  `json:"ctime"`
+	LastSeen  int64  `json:"lastSeen"`
+	// 是不是发起请求的这个设备
+	Current bool `json:"current"`
+}
+
+type RevokeSessionReq struct {
+	Ssid string `json:"ssid"`
+}
+
+type LoginTwoFactorReq struct {
+	// 密码登录的时候返回的凭证
+	Token string `json:"token"`
+	// App 上的验证码或者恢复码
+	Code    string `json:"code"`
+	Captcha string `json:"captcha"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+type PendingTwoFactorVo struct {
+	Token string `json:"token"`
+}
+
+type TwoFactorStatusVo struct {
+	Enabled bool `json:"enabled"`
+}
+
+type TwoFactorEnrollVo struct {
+	// 扫不了码的时候手动输入
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type RecoveryCodesVo struct {
+	Codes []string `json:"codes"`
+}
+
+type AdminUserReq struct {
+	Uid int64 `json:"uid"`
+}
+
+type CreateAccessTokenReq struct {
+	Name string `json:"name"`
+	// 比如 articles:read、articles:write
+	Scopes     []string `json:"scopes"`
+	ExpireDays int      `json:"expireDays"`
+}
+
+type RevokeAccessTokenReq struct {
+	Id int64 `json:"id"`
+}
+
+type AccessTokenVo struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+	// 明文只在创建的时候返回一次
+	Token string `json:"token,omitempty"`
+	// 令牌的开头几个字符，方便用户认出是哪一个
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	Expire int64    `json:"expire"`
+	// 从来没用过就是 0
+	LastUsed int64 `json:"lastUsed"`
+	Ctime    int64 `json:"ctime"`
+}
+
+type DataExportVo struct {
+	Id int64 `json:"id"`
+	// pending、running、done、failed、expired
+	Status string `json:"status"`
+	Ctime  int64  `json:"ctime"`
+	Utime  int64  `json:"utime"`
+}
+
+type AccountDeletionVo struct {
+	// pending、canceled、done
+	Status string `json:"status"`
+	// 冷静期结束的时间，到了之后才真正注销
+	ScheduledAt int64 `json:"scheduledAt"`
+	Ctime       int64 `json:"ctime"`
+}
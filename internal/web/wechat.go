@@ -1,137 +0,0 @@
-package web
-
-import (
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	uuid "github.com/lithammer/shortuuid/v4"
-	"net/http"
-	"webook/internal/service"
-	"webook/internal/service/oauth2/wechat"
-	ijwt "webook/internal/web/jwt"
-	"webook/pkg/ginx"
-)
-
-type OAuth2WechatHandler struct {
-	svc     wechat.Service
-	userSvc service.UserService
-	ijwt.Handler
-	key             []byte
-	stateCookieName string
-}
-
-func NewOAuth2WechatHandler(svc wechat.Service, hdl ijwt.Handler, userSvc service.UserService) *OAuth2WechatHandler {
-	return &OAuth2WechatHandler{
-		svc:             svc,
-		userSvc:         userSvc,
-		key:             []byte("07EAYgFPX316mrAzJGsQWXq1T3tfHlPB"),
-		stateCookieName: "jwt-state",
-		Handler:         hdl,
-	}
-}
-
-func (o *OAuth2WechatHandler) RegisterRoutes(server *gin.Engine) {
-	g := server.Group("/oauth2/wechat")
-	g.GET("/authurl", o.Auth2URL)
-	g.Any("/callback", o.Callback)
-}
-
-func (o *OAuth2WechatHandler) Auth2URL(ctx *gin.Context) {
-	state := uuid.New()
-	val, err := o.svc.AuthURL(ctx, state)
-	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
-			Msg:  "构造跳转URL失败",
-			Code: 5,
-		})
-		return
-	}
-	// 在构造URL的时候就将state放到cookie中，callback的时候验证
-	err = o.setStateCookie(ctx, state)
-	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
-			Msg:  "服务器异常",
-			Code: 5,
-		})
-		return
-	}
-	ctx.JSON(http.StatusOK, ginx.Result{
-		Data: val,
-	})
-}
-
-func (o *OAuth2WechatHandler) Callback(ctx *gin.Context) {
-	err := o.verifyState(ctx)
-	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
-			Msg:  "非法请求",
-			Code: 4,
-		})
-		return
-	}
-
-	code := ctx.Query("code")
-	//state := ctx.Query("state")
-	wechatInfo, err := o.svc.VerifyCode(ctx, code)
-	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{Msg: "授权码有误", Code: 4})
-		return
-	}
-	u, err := o.userSvc.FindOrCreateByWechat(ctx, wechatInfo)
-	if err != nil {
-		ctx.JSON(http.StatusOK, ginx.Result{
-			Msg:  "系统错误",
-			Code: 5,
-		})
-		return
-	}
-	err = o.SetLoginToken(ctx, u.Id)
-	if err != nil {
-		ctx.String(http.StatusOK, "系统错误")
-		return
-	}
-	ctx.JSON(http.StatusOK, ginx.Result{
-		Msg: "登录成功",
-	})
-	return
-}
-
-// verifyState 验证微信登陆的state是否是原用户的，防止csrf攻击
-func (o *OAuth2WechatHandler) verifyState(ctx *gin.Context) error {
-	state := ctx.Query("state")
-	ck, err := ctx.Cookie(o.stateCookieName)
-	if err != nil {
-		return fmt.Errorf("无法获得 cookie %w", err)
-	}
-	var sc StateClaims
-	_, err = jwt.ParseWithClaims(ck, &sc, func(token *jwt.Token) (interface{}, error) {
-		return o.key, nil
-	})
-	if err != nil {
-		return fmt.Errorf("解析token失败 %w", err)
-	}
-	if state != sc.State {
-		// state不匹配，有攻击者
-		return fmt.Errorf("state 不匹配")
-	}
-	return nil
-}
-
-func (o *OAuth2WechatHandler) setStateCookie(ctx *gin.Context, state string) error {
-	claims := StateClaims{
-		State: state,
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
-	tokenStr, err := token.SignedString(o.key)
-	if err != nil {
-		return err
-	}
-	ctx.SetCookie(o.stateCookieName, tokenStr, 600,
-		"/oauth2/wechat/callback", "", false, true)
-	return nil
-}
-
-type StateClaims struct {
-	jwt.RegisteredClaims
-	State string
-}
@@ -0,0 +1,160 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/internal/web/middleware"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// WithdrawHandler 作者提现，以及管理员审核提现
+type WithdrawHandler struct {
+	svc   service.WithdrawService
+	admin *middleware.AdminMiddlewareBuilder
+	l     logger.LoggerV1
+}
+
+func NewWithdrawHandler(svc service.WithdrawService, admin *middleware.AdminMiddlewareBuilder,
+	l logger.LoggerV1) *WithdrawHandler {
+	return &WithdrawHandler{
+		svc:   svc,
+		admin: admin,
+		l:     l,
+	}
+}
+
+func (h *WithdrawHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/withdraw")
+	g.POST("/apply", ginx.WrapBodyAndClaims(h.Apply))
+	// /withdraw/list?offset=0&limit=10
+	g.GET("/list", ginx.WrapBodyAndClaims(h.List))
+
+	ag := server.Group("/admin/withdraw", h.admin.Build(domain.PermWithdrawReview))
+	ag.GET("/pending", ginx.WrapBody(h.Pending))
+	ag.POST("/approve", ginx.WrapBodyAndClaims(h.Approve))
+	ag.POST("/reject", ginx.WrapBodyAndClaims(h.Reject))
+	// /admin/withdraw/logs?id=1
+	ag.GET("/logs", ginx.WrapBody(h.Logs))
+}
+
+func (h *WithdrawHandler) Apply(ctx *gin.Context, req WithdrawApplyReq, uc jwt.UserClaims) (ginx.Result, error) {
+	w, err := h.svc.Apply(ctx, uc.Uid, req.Amt)
+	switch err {
+	case nil:
+		return ginx.Result{Data: h.toVo(w)}, nil
+	case service.ErrWithdrawAmtTooSmall:
+		return ginx.Result{Code: 4, Msg: "提现金额太小"}, nil
+	case service.ErrInsufficientBalance:
+		return ginx.Result{Code: 4, Msg: "余额不足"}, nil
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *WithdrawHandler) List(ctx *gin.Context, req WithdrawListReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	ws, err := h.svc.List(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toVos(ws)}, nil
+}
+
+func (h *WithdrawHandler) Pending(ctx *gin.Context, req WithdrawListReq) (ginx.Result, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > 100 {
+		return ginx.Result{Code: 4, Msg: "分页参数错误"}, nil
+	}
+	ws, err := h.svc.ListPending(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{Data: h.toVos(ws)}, nil
+}
+
+func (h *WithdrawHandler) Approve(ctx *gin.Context, req WithdrawReviewReq, uc jwt.UserClaims) (ginx.Result, error) {
+	w, err := h.svc.Approve(ctx, req.Id, uc.Uid)
+	if err != nil {
+		return h.reviewResult(req.Id, uc.Uid, err)
+	}
+	h.l.Info("提现审核通过",
+		logger.Int64("wid", req.Id),
+		logger.Int64("operator", uc.Uid),
+		logger.Int("status", int(w.Status)))
+	return ginx.Result{Data: h.toVo(w)}, nil
+}
+
+func (h *WithdrawHandler) Reject(ctx *gin.Context, req WithdrawReviewReq, uc jwt.UserClaims) (ginx.Result, error) {
+	if req.Reason == "" {
+		return ginx.Result{Code: 4, Msg: "请填写拒绝原因"}, nil
+	}
+	err := h.svc.Reject(ctx, req.Id, uc.Uid, req.Reason)
+	if err != nil {
+		return h.reviewResult(req.Id, uc.Uid, err)
+	}
+	h.l.Info("提现审核拒绝",
+		logger.Int64("wid", req.Id),
+		logger.Int64("operator", uc.Uid))
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *WithdrawHandler) Logs(ctx *gin.Context, req WithdrawLogReq) (ginx.Result, error) {
+	logs, err := h.svc.Logs(ctx, req.Id)
+	if err != nil {
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return ginx.Result{
+		Data: slice.Map[domain.WithdrawalLog, WithdrawalLogVo](logs, func(idx int, src domain.WithdrawalLog) WithdrawalLogVo {
+			return WithdrawalLogVo{
+				Operator: src.Operator,
+				From:     src.From.ToUint8(),
+				To:       src.To.ToUint8(),
+				Remark:   src.Remark,
+				Ctime:    src.Ctime.Format(time.DateTime),
+			}
+		}),
+	}, nil
+}
+
+func (h *WithdrawHandler) reviewResult(id, operator int64, err error) (ginx.Result, error) {
+	switch err {
+	case service.ErrWithdrawalNotFound:
+		return ginx.Result{Code: 4, Msg: "提现不存在"}, nil
+	case service.ErrWithdrawalProcessed:
+		return ginx.Result{Code: 4, Msg: "提现已经处理过了"}, nil
+	case service.ErrInsufficientBalance:
+		return ginx.Result{Code: 4, Msg: "余额不足，已经自动关闭"}, nil
+	default:
+		h.l.Error("处理提现失败",
+			logger.Int64("wid", id),
+			logger.Int64("operator", operator),
+			logger.Error(err))
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *WithdrawHandler) toVos(ws []domain.Withdrawal) []WithdrawalVo {
+	return slice.Map[domain.Withdrawal, WithdrawalVo](ws, func(idx int, src domain.Withdrawal) WithdrawalVo {
+		return h.toVo(src)
+	})
+}
+
+func (h *WithdrawHandler) toVo(w domain.Withdrawal) WithdrawalVo {
+	return WithdrawalVo{
+		Id:       w.Id,
+		Uid:      w.Uid,
+		Amt:      w.Amt,
+		Fee:      w.Fee,
+		Currency: w.Currency,
+		Status:   w.Status.ToUint8(),
+		TxnID:    w.TxnID,
+		Ctime:    w.Ctime.Format(time.DateTime),
+		Utime:    w.Utime.Format(time.DateTime),
+	}
+}
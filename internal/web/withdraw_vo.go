@@ -0,0 +1,41 @@
+package web
+
+type WithdrawApplyReq struct {
+	// 单位是分
+	Amt int64 `json:"amt"`
+}
+
+type WithdrawListReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+type WithdrawReviewReq struct {
+	Id int64 `json:"id"`
+	// 拒绝的时候填写原因
+	Reason string `json:"reason"`
+}
+
+type WithdrawLogReq struct {
+	Id int64 `form:"id"`
+}
+
+type WithdrawalVo struct {
+	Id       int64  `json:"id"`
+	Uid      int64  `json:"uid"`
+	Amt      int64  `json:"amt"`
+	Fee      int64  `json:"fee"`
+	Currency string `json:"currency"`
+	Status   uint8  `json:"status"`
+	TxnID    string `json:"txnID"`
+	Ctime    string `json:"ctime"`
+	Utime    string `json:"utime"`
+}
+
+type WithdrawalLogVo struct {
+	Operator int64  `json:"operator"`
+	From     uint8  `json:"from"`
+	To       uint8  `json:"to"`
+	Remark   string `json:"remark"`
+	Ctime    string `json:"ctime"`
+}
@@ -0,0 +1,44 @@
+package ioc
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"webook/internal/service/captcha"
+	"webook/internal/service/captcha/local"
+	"webook/internal/service/captcha/siteverify"
+)
+
+func InitCaptchaService() captcha.Service {
+	type Config struct {
+		// local 或者 siteverify
+		Provider string `yaml:"provider"`
+		Local    struct {
+			// 本地开发的时候前端传这个值就算通过
+			Token string `yaml:"token"`
+		} `yaml:"local"`
+		SiteVerify struct {
+			// 比如 https://challenges.cloudflare.com/turnstile/v0/siteverify
+			URL string `yaml:"url"`
+		} `yaml:"siteVerify"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("captcha", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	switch cfg.Provider {
+	case "local":
+		return local.NewService(cfg.Local.Token)
+	case "siteverify":
+		// 密钥不放在配置文件里面
+		secret, ok := os.LookupEnv("CAPTCHA_SECRET")
+		if !ok {
+			panic("CAPTCHA_SECRET not found")
+		}
+		return siteverify.NewService(cfg.SiteVerify.URL, secret)
+	default:
+		// 写错了或者漏配了不能悄悄退回到 local，不然线上谁都能过人机验证
+		panic(fmt.Errorf("未知的人机验证提供方 %q", cfg.Provider))
+	}
+}
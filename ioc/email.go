@@ -0,0 +1,48 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"os"
+	"webook/internal/service/email"
+	"webook/internal/service/email/local"
+	"webook/internal/service/email/smtp"
+)
+
+func InitEmailService() email.Service {
+	type Config struct {
+		// local 或者 smtp
+		Provider string `yaml:"provider"`
+		Local    struct {
+			// 邮件写到这个目录下面，为空就只打日志
+			Dir string `yaml:"dir"`
+		} `yaml:"local"`
+		SMTP struct {
+			Host        string `yaml:"host"`
+			Port        int    `yaml:"port"`
+			Username    string `yaml:"username"`
+			From        string `yaml:"from"`
+			ImplicitTLS bool   `yaml:"implicitTLS"`
+		} `yaml:"smtp"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("email", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Provider != "smtp" {
+		return local.NewService(cfg.Local.Dir)
+	}
+	// 密码不放在配置文件里面
+	password, ok := os.LookupEnv("EMAIL_SMTP_PASSWORD")
+	if !ok {
+		panic("EMAIL_SMTP_PASSWORD not found")
+	}
+	return smtp.NewService(smtp.Config{
+		Host:        cfg.SMTP.Host,
+		Port:        cfg.SMTP.Port,
+		Username:    cfg.SMTP.Username,
+		Password:    password,
+		From:        cfg.SMTP.From,
+		ImplicitTLS: cfg.SMTP.ImplicitTLS,
+	})
+}
@@ -0,0 +1,82 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	accountv1 "webook/api/proto/gen/account/v1"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	followv1 "webook/api/proto/gen/follow/v1"
+	pmtv1 "webook/api/proto/gen/payment/v1"
+	rewardv1 "webook/api/proto/gen/reward/v1"
+	igrpc "webook/internal/grpc"
+	"webook/pkg/grpcx"
+)
+
+func InitGRPCxServer(commentServer *igrpc.CommentServiceServer,
+	followServer *igrpc.FollowServiceServer,
+	rewardServer *igrpc.RewardServiceServer,
+	paymentServer *igrpc.WechatPaymentServiceServer,
+	accountServer *igrpc.AccountServiceServer) *grpcx.Server {
+	type Config struct {
+		Addr string `yaml:"addr"`
+	}
+	var cfg = Config{
+		Addr: ":8090",
+	}
+	err := viper.UnmarshalKey("grpc.server", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	server := grpc.NewServer()
+	commentServer.Register(server)
+	followServer.Register(server)
+	rewardServer.Register(server)
+	paymentServer.Register(server)
+	accountServer.Register(server)
+	return &grpcx.Server{
+		Server: server,
+		Addr:   cfg.Addr,
+	}
+}
+
+// InitCommentClient 评论服务现在和 webook 部署在一起，默认连本地的 gRPC 服务
+// 拆出去之后改配置就可以
+func InitCommentClient() commentv1.CommentServiceClient {
+	return commentv1.NewCommentServiceClient(initClientConn("comment"))
+}
+
+func InitFollowClient() followv1.FollowServiceClient {
+	return followv1.NewFollowServiceClient(initClientConn("follow"))
+}
+
+func InitRewardClient() rewardv1.RewardServiceClient {
+	return rewardv1.NewRewardServiceClient(initClientConn("reward"))
+}
+
+func InitPaymentClient() pmtv1.WechatPaymentServiceClient {
+	return pmtv1.NewWechatPaymentServiceClient(initClientConn("payment"))
+}
+
+func InitAccountClient() accountv1.AccountServiceClient {
+	return accountv1.NewAccountServiceClient(initClientConn("account"))
+}
+
+// initClientConn 读取 grpc.client.{name} 的配置
+func initClientConn(name string) *grpc.ClientConn {
+	type Config struct {
+		Addr string `yaml:"addr"`
+	}
+	var cfg = Config{
+		Addr: "localhost:8090",
+	}
+	err := viper.UnmarshalKey("grpc.client."+name, &cfg)
+	if err != nil {
+		panic(err)
+	}
+	cc, err := grpc.NewClient(cfg.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	return cc
+}
@@ -14,7 +14,13 @@ func InitRankingJob(svc service.RankingService, client *rlock.Client, l logger.L
 	return job.NewRankingJob(svc, l, client, time.Second*30)
 }
 
-func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
+func InitRecommendJob(svc service.RecommendService, client *rlock.Client, l logger.LoggerV1) *job.RecommendJob {
+	return job.NewRecommendJob(svc, l, client, time.Minute*5)
+}
+
+func InitJobs(l logger.LoggerV1, rjob *job.RankingJob, recJob *job.RecommendJob,
+	syncJob *job.SyncWechatOrderJob, exportJob *job.DataExportJob,
+	deletionJob *job.AccountDeletionJob) *cron.Cron {
 	builder := job.NewCronJobBuilder(l, prometheus.SummaryOpts{
 		Namespace: "geekbang_zl",
 		Subsystem: "webook",
@@ -33,5 +39,23 @@ func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
 	if err != nil {
 		panic(err)
 	}
+	// 推荐结果不需要那么实时，算一次也比较久
+	_, err = expr.AddJob("@every 10m", builder.Build(recJob))
+	if err != nil {
+		panic(err)
+	}
+	_, err = expr.AddJob("@every 5m", builder.Build(syncJob))
+	if err != nil {
+		panic(err)
+	}
+	// 用户在等着下载，跑得勤一点
+	_, err = expr.AddJob("@every 1m", builder.Build(exportJob))
+	if err != nil {
+		panic(err)
+	}
+	_, err = expr.AddJob("@every 10m", builder.Build(deletionJob))
+	if err != nil {
+		panic(err)
+	}
 	return expr
 }
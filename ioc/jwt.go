@@ -0,0 +1,108 @@
+package ioc
+
+import (
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"os"
+	"time"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// InitJWTKeys 轮换密钥的时候，先把新公钥加到 keys 里面发布出去，等其它服务拉到之后再改 signingKid，
+// 旧密钥只留 publicKeyFile，等它签的 token 都过期了再删掉
+func InitJWTKeys(l logger.LoggerV1) ijwt.Keys {
+	type KeyConfig struct {
+		Kid string `yaml:"kid"`
+		// RS256 或者 EdDSA
+		Alg string `yaml:"alg"`
+		// PEM 格式，PKCS8 或者 PKCS1 的私钥，PKIX 的公钥
+		PrivateKeyFile string `yaml:"privateKeyFile"`
+		PublicKeyFile  string `yaml:"publicKeyFile"`
+	}
+	type KeySetConfig struct {
+		SigningKid string      `yaml:"signingKid"`
+		Keys       []KeyConfig `yaml:"keys"`
+	}
+	type Config struct {
+		Access  KeySetConfig `yaml:"access"`
+		Refresh KeySetConfig `yaml:"refresh"`
+		// 只有本地开发可以打开，没配 keys 的时候每次启动临时生成
+		AllowEphemeralKeys bool `yaml:"allowEphemeralKeys"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("jwt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	allowEphemeral := cfg.AllowEphemeralKeys
+	initKeySet := func(name string, cfg KeySetConfig) *ijwt.KeySet {
+		if len(cfg.Keys) == 0 {
+			// 线上漏配了的话每个实例的密钥都不一样，重启之后所有人都要重新登录
+			if !allowEphemeral {
+				panic(fmt.Errorf("没有配置 JWT 密钥 jwt.%s.keys", name))
+			}
+			l.Warn("没有配置 JWT 密钥，使用临时生成的密钥", logger.String("keySet", name))
+			return NewEphemeralJWTKeySet(name)
+		}
+		keys := make([]ijwt.Key, 0, len(cfg.Keys))
+		for _, kc := range cfg.Keys {
+			var priv, pub []byte
+			if kc.PrivateKeyFile != "" {
+				priv, err = os.ReadFile(kc.PrivateKeyFile)
+			} else {
+				pub, err = os.ReadFile(kc.PublicKeyFile)
+			}
+			if err != nil {
+				panic(fmt.Errorf("读取 JWT 密钥 %s 失败 %w", kc.Kid, err))
+			}
+			key, err := ijwt.ParseKey(kc.Kid, kc.Alg, priv, pub)
+			if err != nil {
+				panic(err)
+			}
+			keys = append(keys, key)
+		}
+		ks, err := ijwt.NewKeySet(cfg.SigningKid, keys...)
+		if err != nil {
+			panic(err)
+		}
+		return ks
+	}
+	return ijwt.Keys{
+		Access:  initKeySet("access", cfg.Access),
+		Refresh: initKeySet("refresh", cfg.Refresh),
+	}
+}
+
+// NewEphemeralJWTKeySet 临时生成的密钥，只在内存里面，本地开发和集成测试用
+func NewEphemeralJWTKeySet(name string) *ijwt.KeySet {
+	key, err := ijwt.GenerateKey(name + "-ephemeral")
+	if err != nil {
+		panic(err)
+	}
+	ks, err := ijwt.NewKeySet(key.Kid, key)
+	if err != nil {
+		panic(err)
+	}
+	return ks
+}
+
+func InitJWTHandler(client redis.Cmdable, keys ijwt.Keys, l logger.LoggerV1) ijwt.Handler {
+	type Config struct {
+		AccessExpiration  time.Duration `yaml:"accessExpiration"`
+		RefreshExpiration time.Duration `yaml:"refreshExpiration"`
+	}
+	cfg := Config{
+		AccessExpiration:  time.Minute * 30,
+		RefreshExpiration: time.Hour * 24 * 7,
+	}
+	err := viper.UnmarshalKey("jwt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return ijwt.NewRedisJWTHandler(client, keys, ijwt.Config{
+		AccessExpiration:  cfg.AccessExpiration,
+		RefreshExpiration: cfg.RefreshExpiration,
+	}, l)
+}
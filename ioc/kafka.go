@@ -5,6 +5,10 @@ import (
 	"github.com/spf13/viper"
 	"webook/internal/events"
 	"webook/internal/events/article"
+	"webook/internal/events/feed"
+	"webook/internal/events/notification"
+	"webook/internal/events/reward"
+	"webook/internal/service/push"
 )
 
 func InitSaramaClient() sarama.Client {
@@ -34,6 +38,9 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 }
 
 // InitConsumers wire没有办法找到同类型的所有实现，所以逼不得已只能写这种代码
-func InitConsumers(c1 *article.InteractiveReadEventConsumer) []events.Consumer {
-	return []events.Consumer{c1}
+func InitConsumers(c1 *article.InteractiveReadEventConsumer,
+	c2 *article.HistoryRecordConsumer, c3 *feed.EventConsumer,
+	c4 *reward.PaymentEventConsumer, c5 *notification.EventConsumer,
+	c6 *push.Hub) []events.Consumer {
+	return []events.Consumer{c1, c2, c3, c4, c5, c6}
 }
@@ -0,0 +1,20 @@
+package ioc
+
+import (
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"webook/pkg/logger"
+	"webook/pkg/sensitive"
+)
+
+// InitSensitiveFilter 敏感词放在配置文件里面，改了配置文件就会重新加载
+func InitSensitiveFilter(l logger.LoggerV1) *sensitive.Filter {
+	filter := sensitive.NewFilter(viper.GetStringSlice("moderation.sensitiveWords"))
+	viper.OnConfigChange(func(in fsnotify.Event) {
+		words := viper.GetStringSlice("moderation.sensitiveWords")
+		filter.Reload(words)
+		l.Info("重新加载敏感词", logger.Int("cnt", len(words)))
+	})
+	viper.WatchConfig()
+	return filter
+}
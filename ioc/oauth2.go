@@ -0,0 +1,127 @@
+package ioc
+
+import (
+	"crypto/rand"
+	"github.com/spf13/viper"
+	"os"
+	"strings"
+	"webook/internal/service"
+	"webook/internal/service/oauth2"
+	"webook/internal/service/oauth2/github"
+	"webook/internal/service/oauth2/oidc"
+	"webook/internal/service/oauth2/wechat"
+	"webook/internal/web"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// InitOAuth2Registry 密钥都不放在配置文件里面，没有密钥的提供方不启用
+func InitOAuth2Registry(l logger.LoggerV1) *oauth2.Registry {
+	type Config struct {
+		// 回调地址是 {redirectBase}/oauth2/{provider}/callback
+		RedirectBase string `yaml:"redirectBase"`
+		Wechat       struct {
+			// 密钥在环境变量 WECHAT_APP_SECRET 里面
+			AppID string `yaml:"appID"`
+		} `yaml:"wechat"`
+		Github struct {
+			// 密钥在环境变量 GITHUB_CLIENT_SECRET 里面
+			ClientID string `yaml:"clientID"`
+		} `yaml:"github"`
+		// 密钥在环境变量 OIDC_{NAME}_CLIENT_SECRET 里面，NAME 是大写的 name
+		OIDC []struct {
+			Name     string   `yaml:"name"`
+			Issuer   string   `yaml:"issuer"`
+			ClientID string   `yaml:"clientID"`
+			Scopes   []string `yaml:"scopes"`
+		} `yaml:"oidc"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("oauth2", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	redirectURL := func(name string) string {
+		return strings.TrimSuffix(cfg.RedirectBase, "/") + "/oauth2/" + name + "/callback"
+	}
+	secret := func(name, env string) (string, bool) {
+		val, ok := os.LookupEnv(env)
+		if !ok || val == "" {
+			l.Warn("没有配置第三方登录的密钥，不启用", logger.String("provider", name),
+				logger.String("env", env))
+			return "", false
+		}
+		return val, true
+	}
+	var providers []oauth2.Provider
+	if cfg.Wechat.AppID != "" {
+		if s, ok := secret("wechat", "WECHAT_APP_SECRET"); ok {
+			providers = append(providers, wechat.NewService(cfg.Wechat.AppID, s, redirectURL("wechat"), l))
+		}
+	}
+	if cfg.Github.ClientID != "" {
+		if s, ok := secret("github", "GITHUB_CLIENT_SECRET"); ok {
+			providers = append(providers, github.NewProvider(github.Config{
+				ClientID:     cfg.Github.ClientID,
+				ClientSecret: s,
+				RedirectURL:  redirectURL("github"),
+			}))
+		}
+	}
+	for _, oc := range cfg.OIDC {
+		env := "OIDC_" + strings.ToUpper(strings.ReplaceAll(oc.Name, "-", "_")) + "_CLIENT_SECRET"
+		if s, ok := secret(oc.Name, env); ok {
+			providers = append(providers, oidc.NewProvider(oidc.Config{
+				Name:         oc.Name,
+				Issuer:       oc.Issuer,
+				ClientID:     oc.ClientID,
+				ClientSecret: s,
+				RedirectURL:  redirectURL(oc.Name),
+				Scopes:       oc.Scopes,
+			}))
+		}
+	}
+	return oauth2.NewRegistry(providers...)
+}
+
+// InitOAuth2Handler state cookie 的签名密钥在环境变量 OAUTH2_STATE_KEY 里面，
+// 没有的话临时生成一个，多个实例部署的时候一定要配
+func InitOAuth2Handler(registry *oauth2.Registry, hdl ijwt.Handler, userSvc service.UserService,
+	banSvc service.BanService, twoFactorSvc service.TwoFactorService, mergeKey web.MergeTicketKey,
+	twoFactorKey web.TwoFactorKey, l logger.LoggerV1) *web.OAuth2Handler {
+	key := []byte(os.Getenv("OAUTH2_STATE_KEY"))
+	if len(key) == 0 {
+		l.Warn("没有配置 OAUTH2_STATE_KEY，使用临时生成的密钥")
+		key = make([]byte, 32)
+		_, err := rand.Read(key)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return web.NewOAuth2Handler(registry, hdl, userSvc, banSvc, twoFactorSvc, key, mergeKey, twoFactorKey)
+}
+
+// InitMergeTicketKey 合并凭证的签名密钥在环境变量 MERGE_TICKET_KEY 里面，
+// 没有的话临时生成一个，多个实例部署的时候一定要配
+func InitMergeTicketKey(l logger.LoggerV1) web.MergeTicketKey {
+	return hmacKeyFromEnv("MERGE_TICKET_KEY", l)
+}
+
+// InitTwoFactorKey 两步验证凭证的签名密钥在环境变量 TWO_FACTOR_KEY 里面，
+// 没有的话临时生成一个，多个实例部署的时候一定要配
+func InitTwoFactorKey(l logger.LoggerV1) web.TwoFactorKey {
+	return hmacKeyFromEnv("TWO_FACTOR_KEY", l)
+}
+
+func hmacKeyFromEnv(env string, l logger.LoggerV1) []byte {
+	key := []byte(os.Getenv(env))
+	if len(key) == 0 {
+		l.Warn("没有配置签名密钥，使用临时生成的密钥", logger.String("env", env))
+		key = make([]byte, 32)
+		_, err := rand.Read(key)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return key
+}
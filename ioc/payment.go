@@ -0,0 +1,52 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/events/payment"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/wechatpay"
+)
+
+type wechatPayConfig struct {
+	// 本地开发指向模拟器 cmd/wechatpay-simulator
+	BaseURL   string `yaml:"baseURL"`
+	AppID     string `yaml:"appID"`
+	MchID     string `yaml:"mchID"`
+	APIV3Key  string `yaml:"apiV3Key"`
+	NotifyURL string `yaml:"notifyURL"`
+}
+
+func initWechatPayConfig() wechatPayConfig {
+	// 默认对接本地的模拟器，线上一定要在配置文件里面覆盖
+	var cfg = wechatPayConfig{
+		BaseURL:   "http://localhost:8097",
+		MchID:     "1900000001",
+		APIV3Key:  "Tg1dZUa1k7qOmNvLZ7vI3m2Q8fKeBxEa",
+		NotifyURL: "http://localhost:8080/pay/callback",
+	}
+	err := viper.UnmarshalKey("wechatpay", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if len(cfg.APIV3Key) != 32 {
+		panic("wechatpay.apiV3Key 必须是 32 个字节")
+	}
+	return cfg
+}
+
+func InitWechatPaySigner() *wechatpay.Signer {
+	return wechatpay.NewSigner(initWechatPayConfig().APIV3Key)
+}
+
+func InitWechatNotifyHandler(signer *wechatpay.Signer) *wechatpay.NotifyHandler {
+	return wechatpay.NewNotifyHandler(signer)
+}
+
+func InitPaymentService(signer *wechatpay.Signer, repo repository.PaymentRepository,
+	producer payment.Producer, l logger.LoggerV1) service.PaymentService {
+	cfg := initWechatPayConfig()
+	client := wechatpay.NewClient(cfg.BaseURL, cfg.AppID, cfg.MchID, signer)
+	return service.NewWechatNativePaymentService(client, repo, producer, cfg.NotifyURL, l)
+}
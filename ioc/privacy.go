@@ -0,0 +1,47 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"time"
+	"webook/internal/events/article"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/storage"
+	"webook/internal/service/storage/local"
+	"webook/pkg/logger"
+)
+
+// InitExportStorage 导出的 zip 放在哪里，多实例部署的时候目录要挂共享存储
+func InitExportStorage() storage.Storage {
+	type Config struct {
+		Dir string `yaml:"dir"`
+	}
+	var cfg = Config{
+		Dir: "./tmp/exports",
+	}
+	err := viper.UnmarshalKey("privacy.export", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return local.NewStorage(cfg.Dir)
+}
+
+func InitAccountDeletionService(repo repository.AccountDeletionRepository, userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository, producer article.Producer, l logger.LoggerV1) service.AccountDeletionService {
+	type Config struct {
+		// 申请注销之后多少天才真正注销，这之前都可以撤销
+		GraceDays int `yaml:"graceDays"`
+	}
+	var cfg = Config{
+		GraceDays: 15,
+	}
+	err := viper.UnmarshalKey("privacy.deletion", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.GraceDays < 0 {
+		panic("privacy.deletion.graceDays 不能是负数")
+	}
+	return service.NewAccountDeletionService(repo, userRepo, artRepo, producer,
+		time.Duration(cfg.GraceDays)*time.Hour*24, l)
+}
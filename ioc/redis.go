@@ -4,6 +4,7 @@ import (
 	rlock "github.com/gotomicro/redis-lock"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
+	"webook/internal/service/push"
 )
 
 func InitRedis() redis.Cmdable {
@@ -15,3 +16,8 @@ func InitRedis() redis.Cmdable {
 func InitRlockClient(client redis.Cmdable) *rlock.Client {
 	return rlock.NewClient(client)
 }
+
+// InitPushBroker 订阅要用到具体的 client，Cmdable 里面没有 Subscribe
+func InitPushBroker(client redis.Cmdable) push.Broker {
+	return push.NewRedisBroker(client.(redis.UniversalClient))
+}
@@ -0,0 +1,30 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	accountv1 "webook/api/proto/gen/account/v1"
+	pmtv1 "webook/api/proto/gen/payment/v1"
+	"webook/internal/events/activity"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+func InitRewardService(client pmtv1.WechatPaymentServiceClient, accountClient accountv1.AccountServiceClient,
+	repo repository.RewardRepository, producer activity.Producer, l logger.LoggerV1) service.RewardService {
+	type Config struct {
+		// 平台抽成的百分比
+		PlatformRate int64 `yaml:"platformRate"`
+	}
+	var cfg = Config{
+		PlatformRate: 10,
+	}
+	err := viper.UnmarshalKey("reward", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.PlatformRate < 0 || cfg.PlatformRate > 100 {
+		panic("reward.platformRate 必须在 0 到 100 之间")
+	}
+	return service.NewWechatNativeRewardService(client, accountClient, repo, producer, cfg.PlatformRate, l)
+}
@@ -6,9 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 	prometheus2 "github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	otelgin "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"strings"
 	"time"
+	"webook/internal/repository"
+	"webook/internal/service"
 	"webook/internal/web"
 	ijwt "webook/internal/web/jwt"
 	"webook/internal/web/middleware"
@@ -31,18 +34,59 @@ import (
 
 func InitWebServer(mdls []gin.HandlerFunc, userHdl *web.UserHandler,
 	artHdl *web.ArticleHandler,
-	wechatHdl *web.OAuth2WechatHandler) *gin.Engine {
+	oauth2Hdl *web.OAuth2Handler,
+	feedHdl *web.FeedHandler,
+	commentHdl *web.CommentHandler,
+	modHdl *web.ModerationHandler,
+	followHdl *web.FollowHandler,
+	rewardHdl *web.RewardHandler,
+	pmtHdl *web.WechatPaymentHandler,
+	accountHdl *web.AccountHandler,
+	withdrawHdl *web.WithdrawHandler,
+	notificationHdl *web.NotificationHandler,
+	adminUserHdl *web.AdminUserHandler,
+	adminHdl *web.AdminHandler,
+	jwksHdl *web.JWKSHandler,
+	tokenHdl *web.AccessTokenHandler,
+	privacyHdl *web.PrivacyHandler,
+	profileHdl *web.ProfileHandler) *gin.Engine {
 	//gin.SetMode(gin.ReleaseMode)
 	server := gin.Default()
 	//server := gin.New()
 	server.Use(mdls...)
 	userHdl.RegisterRoutes(server)
-	wechatHdl.RegisterRoutes(server)
+	oauth2Hdl.RegisterRoutes(server)
 	artHdl.RegisterRoutes(server)
+	feedHdl.RegisterRoutes(server)
+	commentHdl.RegisterRoutes(server)
+	modHdl.RegisterRoutes(server)
+	followHdl.RegisterRoutes(server)
+	rewardHdl.RegisterRoutes(server)
+	pmtHdl.RegisterRoutes(server)
+	accountHdl.RegisterRoutes(server)
+	withdrawHdl.RegisterRoutes(server)
+	notificationHdl.RegisterRoutes(server)
+	adminUserHdl.RegisterRoutes(server)
+	adminHdl.RegisterRoutes(server)
+	jwksHdl.RegisterRoutes(server)
+	tokenHdl.RegisterRoutes(server)
+	privacyHdl.RegisterRoutes(server)
+	profileHdl.RegisterRoutes(server)
 	return server
 }
 
-func InitGinMiddlewares(redisClient redis.Cmdable, hdl ijwt.Handler, l logger.LoggerV1) []gin.HandlerFunc {
+// InitRBACService 配置文件里面的 admin.uids 是超级管理员，其他管理员在后台授权
+func InitRBACService(repo repository.UserRoleRepository) service.RBACService {
+	var uids []int64
+	err := viper.UnmarshalKey("admin.uids", &uids)
+	if err != nil {
+		panic(err)
+	}
+	return service.NewRBACService(repo, uids)
+}
+
+func InitGinMiddlewares(redisClient redis.Cmdable, hdl ijwt.Handler,
+	tokenSvc service.AccessTokenService, l logger.LoggerV1) []gin.HandlerFunc {
 	pb := &prometheus.Builder{
 		Namespace: "geektime_zl",
 		Subsystem: "webook",
@@ -87,6 +131,6 @@ func InitGinMiddlewares(redisClient redis.Cmdable, hdl ijwt.Handler, l logger.Lo
 			func(ctx context.Context, al middleware.AccessLog) {
 				l.Debug("", logger.Field{Key: "req", Val: al})
 			}).AllowReqBody().AllowRespBody().Build(),
-		middleware.NewLoginJWTMiddlewareBuilder(hdl).CheckLogin(),
+		middleware.NewLoginJWTMiddlewareBuilder(hdl, tokenSvc).CheckLogin(),
 	}
 }
@@ -0,0 +1,52 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/payout"
+	"webook/internal/service/payout/local"
+	"webook/pkg/logger"
+)
+
+// InitPayoutExecutor 还没有对接真实的打款渠道，先用本地的
+func InitPayoutExecutor() payout.Executor {
+	type Config struct {
+		// 超过这个金额的打款都会失败，0 表示都成功
+		FailAbove int64 `yaml:"failAbove"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("withdraw.local", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return local.NewExecutor(cfg.FailAbove)
+}
+
+func InitWithdrawService(repo repository.WithdrawalRepository, accountSvc service.AccountService,
+	executor payout.Executor, l logger.LoggerV1) service.WithdrawService {
+	type Config struct {
+		// 单位都是分
+		MinAmt int64 `yaml:"minAmt"`
+		// 手续费的千分比
+		FeeRate int64 `yaml:"feeRate"`
+		MinFee  int64 `yaml:"minFee"`
+	}
+	var cfg = Config{
+		MinAmt:  1000,
+		FeeRate: 6,
+		MinFee:  10,
+	}
+	err := viper.UnmarshalKey("withdraw", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.FeeRate < 0 || cfg.FeeRate >= 1000 {
+		panic("withdraw.feeRate 必须在 0 到 1000 之间")
+	}
+	return service.NewWithdrawService(repo, accountSvc, executor, service.WithdrawRule{
+		MinAmt:  cfg.MinAmt,
+		FeeRate: cfg.FeeRate,
+		MinFee:  cfg.MinFee,
+	}, l)
+}
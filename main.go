@@ -34,11 +34,18 @@ func main() {
 			panic(err)
 		}
 	}
-	//app.cron.Start()
-	//defer func() {
-	//	// 等待定时任务退出
-	//	<-app.cron.Stop().Done()
-	//}()
+	go func() {
+		err := app.grpcServer.Serve()
+		if err != nil {
+			panic(err)
+		}
+	}()
+	// 排行榜、推荐、微信订单对账、数据导出、注销账号这些定时任务都靠它跑
+	app.cron.Start()
+	defer func() {
+		// 等待定时任务退出
+		<-app.cron.Stop().Done()
+	}()
 	server := app.server
 	server.GET("/hello", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "hello,访问成功！")
@@ -1,7 +1,24 @@
 package ginx
 
+import "github.com/gin-gonic/gin"
+
+// resultCodeKey 包装过的 handler 会把业务错误码放在 context 里面，
+// 因为 HTTP 状态码永远是 200，后面的中间件要知道成功没有只能看这个
+const resultCodeKey = "ginx_result_code"
+
 type Result struct {
 	Code int    `json:"code"`
 	Msg  string `json:"msg"`
 	Data any    `json:"data"`
 }
+
+// ResultCode 拿到 handler 返回的业务错误码，0 是成功。
+// 没有走到业务逻辑，比如说参数绑定失败的时候，返回 false
+func ResultCode(ctx *gin.Context) (int, bool) {
+	val, ok := ctx.Get(resultCodeKey)
+	if !ok {
+		return 0, false
+	}
+	code, ok := val.(int)
+	return code, ok
+}
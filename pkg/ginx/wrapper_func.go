@@ -44,6 +44,7 @@ func WrapBodyAndClaims[Req any, Claims any](bizFn func(ctx *gin.Context, req Req
 			return
 		}
 		res, err := bizFn(ctx, req, uc)
+		ctx.Set(resultCodeKey, res.Code)
 		vector.WithLabelValues(strconv.Itoa(res.Code)).Inc()
 		if err != nil {
 			L.Error("执行业务逻辑失败", logger.Error(err))
@@ -63,6 +64,7 @@ func WrapBody[Req any](
 		}
 		L.Debug("输入参数", logger.Field{Key: "req", Val: req})
 		res, err := bizFn(ctx, req)
+		ctx.Set(resultCodeKey, res.Code)
 		vector.WithLabelValues(strconv.Itoa(res.Code)).Inc()
 		if err != nil {
 			L.Error("执行业务逻辑失败", logger.Error(err))
@@ -86,6 +88,7 @@ func WrapClaims[Claims any](
 			return
 		}
 		res, err := bizFn(ctx, uc)
+		ctx.Set(resultCodeKey, res.Code)
 		vector.WithLabelValues(strconv.Itoa(res.Code)).Inc()
 		if err != nil {
 			L.Error("执行业务逻辑失败", logger.Error(err))
@@ -0,0 +1,21 @@
+package grpcx
+
+import (
+	"google.golang.org/grpc"
+	"net"
+)
+
+// Server 对 grpc.Server 的简单封装，把监听地址也带上
+type Server struct {
+	*grpc.Server
+	Addr string
+}
+
+// Serve 会阻塞，调用方一般是新开一个 goroutine 来启动
+func (s *Server) Serve() error {
+	l, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Server.Serve(l)
+}
@@ -0,0 +1,92 @@
+// Package jwks 按照 RFC 7517 发布和读取 JSON Web Key Set，
+// 只支持签名用的 RSA 和 Ed25519 公钥
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrUnsupportedKey = errors.New("不支持的密钥类型")
+
+// Set 就是 /.well-known/jwks.json 返回的内容
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA 公钥
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 公钥
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// FromPublicKey alg 用 JWT 里面的名字，比如 RS256、EdDSA
+func FromPublicKey(kid, alg string, pub crypto.PublicKey) (JWK, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			N:   encoding.EncodeToString(key.N.Bytes()),
+			E:   encoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			Crv: "Ed25519",
+			X:   encoding.EncodeToString(key),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("%w %T", ErrUnsupportedKey, pub)
+	}
+}
+
+// PublicKey 还原成 crypto 包里面的公钥
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.Kty == "RSA":
+		n, err := encoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := encoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		eInt := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !eInt.IsInt64() || eInt.Int64() < 3 {
+			return nil, fmt.Errorf("RSA 公钥 %s 不合法", k.Kid)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(eInt.Int64())}, nil
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		x, err := encoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Ed25519 公钥 %s 不合法", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedKey, k.Kty)
+	}
+}
@@ -0,0 +1,62 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJWK_RoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaJWK, err := FromPublicKey("r1", "RS256", &rsaKey.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "AQAB", rsaJWK.E)
+	pub, err := rsaJWK.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(pub))
+
+	edJWK, err := FromPublicKey("e1", "EdDSA", edPub)
+	require.NoError(t, err)
+	assert.Equal(t, "OKP", edJWK.Kty)
+	pub, err = edJWK.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, edPub.Equal(pub))
+}
+
+func TestRemoteKeySet_Keyfunc(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	jwk, err := FromPublicKey("e1", "EdDSA", pub)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Set{Keys: []JWK{jwk}})
+	}))
+	defer server.Close()
+	rks := NewRemoteKeySet(server.URL, nil)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"uid": 123})
+	token.Header["kid"] = "e1"
+	tokenStr, err := token.SignedString(priv)
+	require.NoError(t, err)
+	parsed, err := jwt.Parse(tokenStr, rks.Keyfunc)
+	require.NoError(t, err)
+	assert.True(t, parsed.Valid)
+
+	// 不认识的 kid
+	token.Header["kid"] = "e2"
+	tokenStr, err = token.SignedString(priv)
+	require.NoError(t, err)
+	_, err = jwt.Parse(tokenStr, rks.Keyfunc)
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RemoteKeySet 给其它服务用的，从 webook 的 /.well-known/jwks.json 拉公钥来校验 token，
+// 不用再共享密钥。遇到不认识的 kid 会重新拉一次，这样轮换密钥之后不用重启
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+	// 定期刷新的间隔
+	ttl time.Duration
+	// 不认识的 kid 触发的刷新，最多这么频繁，防止被乱填的 kid 打爆
+	minRefresh time.Duration
+
+	mutex     sync.RWMutex
+	keys      map[string]remoteKey
+	fetchTime time.Time
+	// 上一次尝试拉取的时间，拉取失败也算
+	attemptTime time.Time
+}
+
+type remoteKey struct {
+	alg string
+	pub crypto.PublicKey
+}
+
+func NewRemoteKeySet(url string, client *http.Client) *RemoteKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: time.Second * 5}
+	}
+	return &RemoteKeySet{
+		url:        url,
+		client:     client,
+		ttl:        time.Minute * 10,
+		minRefresh: time.Minute,
+	}
+}
+
+// Keyfunc 传给 jwt.ParseWithClaims，按照 token 头里面的 kid 找公钥，
+// 并且 alg 要跟公钥声明的一致，防止算法混淆
+func (r *RemoteKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, err := r.key(context.Background(), kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != "" && key.alg != token.Method.Alg() {
+		return nil, fmt.Errorf("kid %s 的算法是 %s，token 用的是 %s", kid, key.alg, token.Method.Alg())
+	}
+	return key.pub, nil
+}
+
+func (r *RemoteKeySet) key(ctx context.Context, kid string) (remoteKey, error) {
+	r.mutex.RLock()
+	key, ok := r.keys[kid]
+	fresh := time.Since(r.fetchTime) < r.ttl
+	canRefresh := time.Since(r.attemptTime) >= r.minRefresh
+	r.mutex.RUnlock()
+	if ok && (fresh || !canRefresh) {
+		return key, nil
+	}
+	if !canRefresh {
+		return remoteKey{}, fmt.Errorf("未知的 kid %s", kid)
+	}
+	err := r.refresh(ctx)
+	if err != nil {
+		if ok {
+			// 拉不到就先用旧的
+			return key, nil
+		}
+		return remoteKey{}, err
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	key, ok = r.keys[kid]
+	if !ok {
+		return remoteKey{}, fmt.Errorf("未知的 kid %s", kid)
+	}
+	return key, nil
+}
+
+func (r *RemoteKeySet) refresh(ctx context.Context) error {
+	r.mutex.Lock()
+	r.attemptTime = time.Now()
+	r.mutex.Unlock()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("拉取 JWKS 失败 %d", resp.StatusCode)
+	}
+	var set Set
+	err = json.NewDecoder(resp.Body).Decode(&set)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]remoteKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.PublicKey()
+		if err != nil {
+			// 不认识的密钥跳过，不影响别的
+			continue
+		}
+		keys[k.Kid] = remoteKey{alg: k.Alg, pub: pub}
+	}
+	r.mutex.Lock()
+	r.keys = keys
+	r.fetchTime = time.Now()
+	r.mutex.Unlock()
+	return nil
+}
@@ -0,0 +1,25 @@
+package sensitive
+
+import "sync/atomic"
+
+// Filter 支持热更新的敏感词过滤器
+// 更新的时候整棵树替换，读的时候不需要加锁
+type Filter struct {
+	trie atomic.Pointer[Trie]
+}
+
+func NewFilter(words []string) *Filter {
+	f := &Filter{}
+	f.Reload(words)
+	return f
+}
+
+// Reload 用新的敏感词替换掉旧的
+func (f *Filter) Reload(words []string) {
+	f.trie.Store(NewTrie(words))
+}
+
+// Match 返回命中的敏感词
+func (f *Filter) Match(text string) []string {
+	return f.trie.Load().Match(text)
+}
@@ -0,0 +1,84 @@
+package sensitive
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Trie 敏感词前缀树，构造完成之后只读，所以并发安全
+// 需要更新敏感词的时候，重新构造一棵新的替换掉就可以
+type Trie struct {
+	root *node
+}
+
+type node struct {
+	children map[rune]*node
+	// 走到这里是一个完整的敏感词
+	end bool
+}
+
+func newNode() *node {
+	return &node{children: make(map[rune]*node)}
+}
+
+func NewTrie(words []string) *Trie {
+	t := &Trie{root: newNode()}
+	for _, w := range words {
+		t.add(w)
+	}
+	return t
+}
+
+func (t *Trie) add(word string) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return
+	}
+	cur := t.root
+	for _, r := range normalize(word) {
+		next, ok := cur.children[r]
+		if !ok {
+			next = newNode()
+			cur.children[r] = next
+		}
+		cur = next
+	}
+	cur.end = true
+}
+
+// Match 找出文本中命中的敏感词，去重，按照第一次出现的顺序
+func (t *Trie) Match(text string) []string {
+	runes := normalize(text)
+	var res []string
+	seen := make(map[string]struct{})
+	for i := range runes {
+		cur := t.root
+		for j := i; j < len(runes); j++ {
+			next, ok := cur.children[runes[j]]
+			if !ok {
+				break
+			}
+			cur = next
+			if cur.end {
+				word := string(runes[i : j+1])
+				if _, ok := seen[word]; !ok {
+					seen[word] = struct{}{}
+					res = append(res, word)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// normalize 统一转成小写，并且去掉空白，避免用 "敏 感 词" 这种方式绕过
+func normalize(text string) []rune {
+	res := make([]rune, 0, len(text))
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		res = append(res, unicode.ToLower(r))
+	}
+	return res
+}
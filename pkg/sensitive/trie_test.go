@@ -0,0 +1,35 @@
+package sensitive
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTrie_Match(t *testing.T) {
+	trie := NewTrie([]string{"赌博", "赌博网站", "Spam", " ", ""})
+	testCases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "没有命中",
+			text: "今天天气不错",
+		},
+		{
+			name: "命中多个，包括前缀重叠的",
+			text: "这里有个赌博网站，赌博违法",
+			want: []string{"赌博", "赌博网站"},
+		},
+		{
+			name: "忽略大小写和空白",
+			text: "S p a M 赌 博",
+			want: []string{"spam", "赌博"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, trie.Match(tc.text))
+		})
+	}
+}
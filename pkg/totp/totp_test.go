@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 里面 SHA1 的测试数据
+func TestHotp_RFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+	}
+	for _, tc := range testCases {
+		step := Step(time.Unix(tc.unix, 0))
+		assert.Equal(t, tc.want, hotp(key, uint64(step), 8))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).
+		EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1234567890, 0)
+	code, err := Code(secret, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		secret string
+		code   string
+		at     time.Time
+		wantOk bool
+	}{
+		{
+			name:   "当前时间步",
+			secret: secret,
+			code:   code,
+			at:     now,
+			wantOk: true,
+		},
+		{
+			name:   "手机慢了一个时间步",
+			secret: secret,
+			code:   code,
+			at:     now.Add(time.Second * period),
+			wantOk: true,
+		},
+		{
+			name:   "超出容忍范围",
+			secret: secret,
+			code:   code,
+			at:     now.Add(time.Second * period * 2),
+		},
+		{
+			name:   "用户手输的小写密钥",
+			secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq",
+			code:   code,
+			at:     now,
+			wantOk: true,
+		},
+		{
+			name:   "位数不对",
+			secret: secret,
+			code:   code[:5],
+			at:     now,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := Verify(tc.secret, tc.code, tc.at)
+			assert.Equal(t, tc.wantOk, ok)
+			if ok {
+				assert.Equal(t, Step(now), step)
+			}
+		})
+	}
+}
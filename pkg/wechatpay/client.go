@@ -0,0 +1,96 @@
+package wechatpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Client 调用微信支付 APIv3，baseURL 可以换成本地的模拟器
+type Client struct {
+	baseURL string
+	appID   string
+	mchID   string
+	signer  *Signer
+	client  *http.Client
+}
+
+func NewClient(baseURL, appID, mchID string, signer *Signer) *Client {
+	return &Client{
+		baseURL: baseURL,
+		appID:   appID,
+		mchID:   mchID,
+		signer:  signer,
+		client:  &http.Client{Timeout: time.Second * 5},
+	}
+}
+
+// NativePrepay Native 下单，拿到二维码链接
+func (c *Client) NativePrepay(ctx context.Context, req PrepayRequest) (PrepayResponse, error) {
+	req.AppID = c.appID
+	req.MchID = c.mchID
+	var resp PrepayResponse
+	err := c.do(ctx, http.MethodPost, "/v3/pay/transactions/native", req, &resp)
+	return resp, err
+}
+
+// QueryOrderByOutTradeNo 按照商户订单号查询订单
+func (c *Client) QueryOrderByOutTradeNo(ctx context.Context, outTradeNo string) (Transaction, error) {
+	path := fmt.Sprintf("/v3/pay/transactions/out-trade-no/%s?mchid=%s",
+		url.PathEscape(outTradeNo), url.QueryEscape(c.mchID))
+	var txn Transaction
+	err := c.do(ctx, http.MethodGet, path, nil, &txn)
+	return txn, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any, resp any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", c.authorization(method, path, data))
+	httpResp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	respData, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode >= 300 {
+		errResp := &ErrorResponse{}
+		if er := json.Unmarshal(respData, errResp); er != nil || errResp.Code == "" {
+			errResp.Code = strconv.Itoa(httpResp.StatusCode)
+			errResp.Message = string(respData)
+		}
+		return errResp
+	}
+	return json.Unmarshal(respData, resp)
+}
+
+// authorization 拼出 Authorization 头，签名的报文是
+// 请求方法\nURL\n时间戳\n随机串\n请求体\n
+func (c *Client) authorization(method, path string, body []byte) string {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := NonceStr()
+	signature := c.signer.Sign(BuildMessage(method, path, ts, nonce, string(body)))
+	return fmt.Sprintf(`%s mchid="%s",nonce_str="%s",timestamp="%s",signature="%s"`,
+		SignatureScheme, c.mchID, nonce, ts, signature)
+}
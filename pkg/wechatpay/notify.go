@@ -0,0 +1,103 @@
+package wechatpay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderTimestamp = "Wechatpay-Timestamp"
+	HeaderNonce     = "Wechatpay-Nonce"
+	HeaderSignature = "Wechatpay-Signature"
+
+	// 时间戳和现在差太多的请求不处理，防止重放
+	maxClockSkew = time.Minute * 5
+)
+
+var ErrExpiredRequest = errors.New("wechatpay: 请求已经过期")
+
+// SignHeaders 给响应或者支付通知签名，报文是 时间戳\n随机串\n内容\n
+func (s *Signer) SignHeaders(h http.Header, body []byte) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := NonceStr()
+	h.Set(HeaderTimestamp, ts)
+	h.Set(HeaderNonce, nonce)
+	h.Set(HeaderSignature, s.Sign(BuildMessage(ts, nonce, string(body))))
+}
+
+func (s *Signer) VerifyHeaders(h http.Header, body []byte) error {
+	ts := h.Get(HeaderTimestamp)
+	if err := checkTimestamp(ts); err != nil {
+		return err
+	}
+	return s.Verify(BuildMessage(ts, h.Get(HeaderNonce), string(body)), h.Get(HeaderSignature))
+}
+
+// VerifyAuthorization 校验商户请求的 Authorization 头，返回商户号
+func (s *Signer) VerifyAuthorization(r *http.Request, body []byte) (string, error) {
+	auth := r.Header.Get("Authorization")
+	scheme, params, ok := strings.Cut(auth, " ")
+	if !ok || scheme != SignatureScheme {
+		return "", ErrInvalidSignature
+	}
+	fields := make(map[string]string, 5)
+	for _, kv := range strings.Split(params, ",") {
+		k, v, _ := strings.Cut(kv, "=")
+		fields[k] = strings.Trim(v, `"`)
+	}
+	if err := checkTimestamp(fields["timestamp"]); err != nil {
+		return "", err
+	}
+	msg := BuildMessage(r.Method, r.URL.RequestURI(), fields["timestamp"], fields["nonce_str"], string(body))
+	return fields["mchid"], s.Verify(msg, fields["signature"])
+}
+
+func checkTimestamp(ts string) error {
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if d := time.Since(time.Unix(sec, 0)); d > maxClockSkew || d < -maxClockSkew {
+		return ErrExpiredRequest
+	}
+	return nil
+}
+
+// NotifyHandler 解析微信的支付通知
+type NotifyHandler struct {
+	signer *Signer
+}
+
+func NewNotifyHandler(signer *Signer) *NotifyHandler {
+	return &NotifyHandler{signer: signer}
+}
+
+// ParseNotifyRequest 验签并且解密，返回订单的内容
+func (n *NotifyHandler) ParseNotifyRequest(r *http.Request) (Transaction, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Transaction{}, err
+	}
+	err = n.signer.VerifyHeaders(r.Header, body)
+	if err != nil {
+		return Transaction{}, err
+	}
+	var notify Notify
+	err = json.Unmarshal(body, &notify)
+	if err != nil {
+		return Transaction{}, err
+	}
+	plaintext, err := n.signer.Decrypt(notify.Resource)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("wechatpay: 解密支付通知失败 %w", err)
+	}
+	var txn Transaction
+	err = json.Unmarshal(plaintext, &txn)
+	return txn, err
+}
@@ -0,0 +1,225 @@
+// Package simulator 本地的微信支付 APIv3，用来在没有网络、没有商户号的时候跑通整个支付流程
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+	"webook/pkg/wechatpay"
+)
+
+type order struct {
+	req wechatpay.PrepayRequest
+	txn wechatpay.Transaction
+}
+
+// Server 只在内存里面保存订单，重启就没了
+type Server struct {
+	signer *wechatpay.Signer
+	client *http.Client
+
+	mu     sync.Mutex
+	orders map[string]*order
+	// 用来生成微信支付订单号
+	seq int64
+}
+
+func NewServer(signer *wechatpay.Signer) *Server {
+	return &Server{
+		signer: signer,
+		client: &http.Client{Timeout: time.Second * 5},
+		orders: make(map[string]*order),
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/pay/transactions/native", s.prepay)
+	mux.HandleFunc("GET /v3/pay/transactions/out-trade-no/{no}", s.query)
+	// 下面两个是模拟器自己的，代替用户扫码付款
+	// POST /sim/pay {"out_trade_no": "...", "trade_state": "SUCCESS"}
+	mux.HandleFunc("POST /sim/pay", s.pay)
+	mux.HandleFunc("GET /sim/orders", s.list)
+	return mux
+}
+
+func (s *Server) prepay(w http.ResponseWriter, r *http.Request) {
+	body, mchID, ok := s.verify(w, r)
+	if !ok {
+		return
+	}
+	var req wechatpay.PrepayRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.OutTradeNo == "" || req.Amount.Total <= 0 {
+		writeError(w, http.StatusBadRequest, "PARAM_ERROR", "参数错误")
+		return
+	}
+	if req.MchID != mchID {
+		writeError(w, http.StatusBadRequest, "MCH_NOT_EXISTS", "商户号不一致")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[req.OutTradeNo]
+	if ok && o.txn.TradeState != wechatpay.TradeStateNotPay {
+		writeError(w, http.StatusBadRequest, "ORDERPAID", "订单已支付")
+		return
+	}
+	if !ok {
+		o = &order{
+			req: req,
+			txn: wechatpay.Transaction{
+				AppID:          req.AppID,
+				MchID:          req.MchID,
+				OutTradeNo:     req.OutTradeNo,
+				TradeState:     wechatpay.TradeStateNotPay,
+				TradeStateDesc: "订单未支付",
+				Amount:         req.Amount,
+			},
+		}
+		s.orders[req.OutTradeNo] = o
+	}
+	writeJSON(w, http.StatusOK, wechatpay.PrepayResponse{
+		CodeURL: "weixin://wxpay/bizpayurl?pr=" + req.OutTradeNo,
+	})
+}
+
+func (s *Server) query(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := s.verify(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	o, ok := s.orders[r.PathValue("no")]
+	var txn wechatpay.Transaction
+	if ok {
+		txn = o.txn
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "ORDER_NOT_EXIST", "订单不存在")
+		return
+	}
+	writeJSON(w, http.StatusOK, txn)
+}
+
+func (s *Server) pay(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OutTradeNo string `json:"out_trade_no"`
+		// 不传就是支付成功
+		TradeState string `json:"trade_state"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "PARAM_ERROR", "参数错误")
+		return
+	}
+	if req.TradeState == "" {
+		req.TradeState = wechatpay.TradeStateSuccess
+	}
+	s.mu.Lock()
+	o, ok := s.orders[req.OutTradeNo]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "ORDER_NOT_EXIST", "订单不存在")
+		return
+	}
+	if o.txn.TradeState != wechatpay.TradeStateNotPay {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "ORDERPAID", "订单已经有结果了")
+		return
+	}
+	s.seq++
+	o.txn.TransactionID = fmt.Sprintf("4200%s%06d", time.Now().Format("20060102"), s.seq)
+	o.txn.TradeState = req.TradeState
+	o.txn.TradeStateDesc = req.TradeState
+	if req.TradeState == wechatpay.TradeStateSuccess {
+		o.txn.SuccessTime = time.Now().Format(time.RFC3339)
+	}
+	txn, notifyURL := o.txn, o.req.NotifyURL
+	s.mu.Unlock()
+
+	// 和微信一样，通知失败了不影响支付结果，商户要自己查
+	err := s.notify(r.Context(), notifyURL, txn)
+	if err != nil {
+		log.Println("发送支付通知失败", txn.OutTradeNo, err)
+	}
+	writeJSON(w, http.StatusOK, txn)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]wechatpay.Transaction, 0, len(s.orders))
+	for _, o := range s.orders {
+		res = append(res, o.txn)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// notify 加密订单内容并且签名，然后发给商户的 notify_url
+func (s *Server) notify(ctx context.Context, notifyURL string, txn wechatpay.Transaction) error {
+	plaintext, err := json.Marshal(txn)
+	if err != nil {
+		return err
+	}
+	resource, err := s.signer.Encrypt(plaintext, "transaction")
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(wechatpay.Notify{
+		ID:           wechatpay.NonceStr(),
+		CreateTime:   time.Now().Format(time.RFC3339),
+		EventType:    "TRANSACTION." + txn.TradeState,
+		ResourceType: "encrypt-resource",
+		Resource:     resource,
+		Summary:      txn.TradeStateDesc,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.signer.SignHeaders(req.Header, body)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("商户返回 %d %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+func (s *Server) verify(w http.ResponseWriter, r *http.Request) ([]byte, string, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "PARAM_ERROR", "读取请求失败")
+		return nil, "", false
+	}
+	mchID, err := s.signer.VerifyAuthorization(r, body)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "SIGN_ERROR", err.Error())
+		return nil, "", false
+	}
+	return body, mchID, true
+}
+
+func writeJSON(w http.ResponseWriter, code int, val any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(val)
+}
+
+func writeError(w http.ResponseWriter, code int, errCode, msg string) {
+	writeJSON(w, code, wechatpay.ErrorResponse{Code: errCode, Message: msg})
+}
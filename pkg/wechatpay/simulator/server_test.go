@@ -0,0 +1,65 @@
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/pkg/wechatpay"
+)
+
+// TestServer 下单、付款、收到支付通知、查单走一遍
+func TestServer(t *testing.T) {
+	const key = "Tg1dZUa1k7qOmNvLZ7vI3m2Q8fKeBxEa"
+	signer := wechatpay.NewSigner(key)
+
+	notified := make(chan wechatpay.Transaction, 1)
+	nh := wechatpay.NewNotifyHandler(signer)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		txn, err := nh.ParseNotifyRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		notified <- txn
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer merchant.Close()
+	sim := httptest.NewServer(NewServer(signer).Handler())
+	defer sim.Close()
+
+	client := wechatpay.NewClient(sim.URL, "wx123", "1900000001", signer)
+	ctx := context.Background()
+	resp, err := client.NativePrepay(ctx, wechatpay.PrepayRequest{
+		Description: "打赏",
+		OutTradeNo:  "reward-1",
+		NotifyURL:   merchant.URL,
+		Amount:      wechatpay.Amount{Total: 100, Currency: "CNY"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.CodeURL)
+
+	txn, err := client.QueryOrderByOutTradeNo(ctx, "reward-1")
+	require.NoError(t, err)
+	assert.Equal(t, wechatpay.TradeStateNotPay, txn.TradeState)
+
+	payResp, err := http.Post(sim.URL+"/sim/pay", "application/json",
+		bytes.NewBufferString(`{"out_trade_no":"reward-1"}`))
+	require.NoError(t, err)
+	payResp.Body.Close()
+	assert.Equal(t, http.StatusOK, payResp.StatusCode)
+
+	txn = <-notified
+	assert.Equal(t, "reward-1", txn.OutTradeNo)
+	assert.Equal(t, wechatpay.TradeStateSuccess, txn.TradeState)
+	assert.NotEmpty(t, txn.TransactionID)
+
+	// 密钥不对的商户调不通
+	other := wechatpay.NewClient(sim.URL, "wx123", "1900000001",
+		wechatpay.NewSigner("00000000000000000000000000000000"))
+	_, err = other.QueryOrderByOutTradeNo(ctx, "reward-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package wechatpay
+
+// 微信支付 APIv3 的报文，只保留了 Native 支付用得上的字段
+
+const (
+	TradeStateSuccess    = "SUCCESS"
+	TradeStateRefund     = "REFUND"
+	TradeStateNotPay     = "NOTPAY"
+	TradeStateClosed     = "CLOSED"
+	TradeStateRevoked    = "REVOKED"
+	TradeStateUserPaying = "USERPAYING"
+	TradeStatePayError   = "PAYERROR"
+)
+
+type Amount struct {
+	// 单位是分
+	Total    int64  `json:"total"`
+	Currency string `json:"currency,omitempty"`
+}
+
+type PrepayRequest struct {
+	AppID       string `json:"appid"`
+	MchID       string `json:"mchid"`
+	Description string `json:"description"`
+	OutTradeNo  string `json:"out_trade_no"`
+	NotifyURL   string `json:"notify_url"`
+	Amount      Amount `json:"amount"`
+}
+
+type PrepayResponse struct {
+	CodeURL string `json:"code_url"`
+}
+
+// Transaction 查询订单的结果，也是支付通知解密之后的内容
+type Transaction struct {
+	AppID          string `json:"appid"`
+	MchID          string `json:"mchid"`
+	OutTradeNo     string `json:"out_trade_no"`
+	TransactionID  string `json:"transaction_id,omitempty"`
+	TradeState     string `json:"trade_state"`
+	TradeStateDesc string `json:"trade_state_desc,omitempty"`
+	SuccessTime    string `json:"success_time,omitempty"`
+	Amount         Amount `json:"amount"`
+}
+
+// Notify 支付通知，真正的内容加密放在 Resource 里面
+type Notify struct {
+	ID           string   `json:"id"`
+	CreateTime   string   `json:"create_time"`
+	EventType    string   `json:"event_type"`
+	ResourceType string   `json:"resource_type"`
+	Resource     Resource `json:"resource"`
+	Summary      string   `json:"summary"`
+}
+
+type Resource struct {
+	Algorithm      string `json:"algorithm"`
+	Ciphertext     string `json:"ciphertext"`
+	AssociatedData string `json:"associated_data"`
+	OriginalType   string `json:"original_type"`
+	Nonce          string `json:"nonce"`
+}
+
+// ErrorResponse 调用失败的时候微信返回的内容
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ErrorResponse) Error() string {
+	return "wechatpay: " + e.Code + " " + e.Message
+}
@@ -4,13 +4,21 @@ package main
 
 import (
 	"github.com/google/wire"
+	"webook/internal/events/activity"
 	"webook/internal/events/article"
+	"webook/internal/events/feed"
+	"webook/internal/events/notification"
+	"webook/internal/events/payment"
+	"webook/internal/events/reward"
+	igrpc "webook/internal/grpc"
+	"webook/internal/job"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
+	"webook/internal/service/push"
 	"webook/internal/web"
-	ijwt "webook/internal/web/jwt"
+	"webook/internal/web/middleware"
 	"webook/ioc"
 )
 
@@ -36,36 +44,155 @@ func InitWebServer() *App {
 		// dao部分
 		dao.NewUserDao,
 		dao.NewArticleGORMDAO,
+		dao.NewGORMTagDAO,
+		dao.NewGORMHistoryRecordDAO,
+		dao.NewGORMCommentDAO,
+		dao.NewGORMModerationDAO,
+		dao.NewGORMFollowRelationDAO,
+		dao.NewGORMFeedDAO,
+		dao.NewGORMRewardDAO,
+		dao.NewPaymentGORMDAO,
+		dao.NewAccountGORMDAO,
+		dao.NewGORMWithdrawalDAO,
+		dao.NewGORMNotificationDAO,
+		dao.NewGORMTwoFactorDAO,
+		dao.NewGORMJobDAO,
 
 		interactiveSvcSet,
 		rankingSvcSet,
 		ioc.InitRankingJob,
+		ioc.InitRecommendJob,
+		job.NewSyncWechatOrderJob,
+		job.NewDataExportJob,
+		job.NewAccountDeletionJob,
 		ioc.InitJobs,
 		article.NewSaramaSyncProducer,
 		article.NewInteractiveReadEventConsumer,
+		article.NewHistoryRecordConsumer,
+		feed.NewEventConsumer,
+		reward.NewPaymentEventConsumer,
+		activity.NewSaramaSyncProducer,
+		notification.NewEventConsumer,
 		ioc.InitConsumers,
 
 		// cache部分
 		cache.NewRedisCodeCache,
+		cache.NewRedisLoginAttemptCache,
 		cache.NewUserCache,
 		cache.NewArticleRedisCache,
+		cache.NewRecommendRedisCache,
+		cache.NewCommentRedisCache,
+		cache.NewFollowRedisCache,
+		cache.NewFeedRedisCache,
+		cache.NewRewardRedisCache,
+		cache.NewNotificationRedisCache,
 		// repository部分
 		repository.NewCachedUserRepository,
 		repository.NewCodeRepository,
 		repository.NewCachedArticleRepository,
+		repository.NewGORMHistoryRecordRepository,
+		repository.NewCachedRecommendRepository,
+		repository.NewCachedCommentRepository,
+		repository.NewGORMModerationRepository,
+		repository.NewCachedFollowRepository,
+		repository.NewCachedFeedRepository,
+		repository.NewCachedRewardRepository,
+		repository.NewGORMPaymentRepository,
+		repository.NewGORMAccountRepository,
+		repository.NewGORMWithdrawalRepository,
+		repository.NewCachedNotificationRepository,
+		repository.NewGORMTwoFactorRepository,
+		repository.NewPreemptJobRepository,
+		repository.NewCachedLoginAttemptRepository,
+		dao.NewGORMAccessTokenDAO,
+		repository.NewGORMAccessTokenRepository,
+		dao.NewGORMUserRoleDAO,
+		cache.NewRedisUserRoleCache,
+		repository.NewCachedUserRoleRepository,
+		dao.NewGORMUserBanDAO,
+		cache.NewRedisUserBanCache,
+		repository.NewCachedUserBanRepository,
+		dao.NewGORMAuditLogDAO,
+		repository.NewGORMAuditLogRepository,
+		dao.NewGORMDataExportDAO,
+		repository.NewGORMDataExportRepository,
+		dao.NewGORMAccountDeletionDAO,
+		repository.NewGORMAccountDeletionRepository,
 
 		// service部分
 		ioc.InitSMSService,
-		ioc.InitWechatService,
+		ioc.InitEmailService,
+		ioc.InitOAuth2Registry,
 		service.NewUserService,
 		service.NewCodeService,
+		service.NewTwoFactorService,
+		service.NewCronJobService,
+		service.NewLoginGuardService,
+		service.NewAccessTokenService,
+		ioc.InitRBACService,
+		service.NewAuditService,
+		service.NewBanService,
+		ioc.InitExportStorage,
+		service.NewDataExportService,
+		ioc.InitAccountDeletionService,
+		service.NewProfileService,
+		ioc.InitCaptchaService,
 		service.NewArticleService,
+		service.NewBatchRecommendService,
+		service.NewCommentService,
+		ioc.InitSensitiveFilter,
+		service.NewModerationService,
+		service.NewFollowRelationService,
+		service.NewFeedService,
+		ioc.InitRewardService,
+		ioc.InitWechatPaySigner,
+		ioc.InitWechatNotifyHandler,
+		ioc.InitPaymentService,
+		payment.NewSaramaSyncProducer,
+		service.NewAccountService,
+		ioc.InitPayoutExecutor,
+		ioc.InitWithdrawService,
+		service.NewNotificationService,
+		ioc.InitPushBroker,
+		push.NewHub,
+
+		// gRPC 部分
+		igrpc.NewCommentServiceServer,
+		igrpc.NewFollowServiceServer,
+		igrpc.NewRewardServiceServer,
+		igrpc.NewWechatPaymentServiceServer,
+		igrpc.NewAccountServiceServer,
+		ioc.InitGRPCxServer,
+		ioc.InitCommentClient,
+		ioc.InitFollowClient,
+		ioc.InitRewardClient,
+		ioc.InitPaymentClient,
+		ioc.InitAccountClient,
 
 		// handler部分
 		web.NewUserHandler,
 		web.NewArticleHandler,
-		ijwt.NewRedisJWTHandler,
-		web.NewOAuth2WechatHandler,
+		web.NewFeedHandler,
+		web.NewCommentHandler,
+		web.NewModerationHandler,
+		web.NewFollowHandler,
+		web.NewRewardHandler,
+		web.NewWechatPaymentHandler,
+		web.NewAccountHandler,
+		web.NewWithdrawHandler,
+		web.NewNotificationHandler,
+		web.NewAdminUserHandler,
+		web.NewAdminHandler,
+		web.NewJWKSHandler,
+		web.NewAccessTokenHandler,
+		web.NewPrivacyHandler,
+		web.NewProfileHandler,
+		middleware.NewAdminMiddlewareBuilder,
+		ioc.InitJWTKeys,
+		ioc.InitJWTHandler,
+		ioc.InitOAuth2Handler,
+		ioc.InitMergeTicketKey,
+		ioc.InitTwoFactorKey,
 		ioc.InitGinMiddlewares,
 		ioc.InitWebServer,
 
@@ -8,13 +8,21 @@ package main
 
 import (
 	"github.com/google/wire"
+	"webook/internal/events/activity"
 	"webook/internal/events/article"
+	"webook/internal/events/feed"
+	"webook/internal/events/notification"
+	"webook/internal/events/payment"
+	"webook/internal/events/reward"
+	"webook/internal/grpc"
+	"webook/internal/job"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
+	"webook/internal/service/push"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
+	"webook/internal/web/middleware"
 	"webook/ioc"
 )
 
@@ -26,46 +34,164 @@ import (
 
 func InitWebServer() *App {
 	cmdable := ioc.InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
 	loggerV1 := ioc.InitLogger()
-	v := ioc.InitGinMiddlewares(cmdable, handler, loggerV1)
+	keys := ioc.InitJWTKeys(loggerV1)
+	handler := ioc.InitJWTHandler(cmdable, keys, loggerV1)
 	db := ioc.InitDB(loggerV1)
+	accessTokenDAO := dao.NewGORMAccessTokenDAO(db)
+	accessTokenRepository := repository.NewGORMAccessTokenRepository(accessTokenDAO)
+	userBanDAO := dao.NewGORMUserBanDAO(db)
+	userBanCache := cache.NewRedisUserBanCache(cmdable)
+	userBanRepository := repository.NewCachedUserBanRepository(userBanDAO, userBanCache, loggerV1)
+	accessTokenService := service.NewAccessTokenService(accessTokenRepository, userBanRepository)
+	v := ioc.InitGinMiddlewares(cmdable, handler, accessTokenService, loggerV1)
 	userDAO := dao.NewUserDao(db)
 	userCache := cache.NewUserCache(cmdable)
-	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
+	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
+	userRepository := repository.NewCachedUserRepository(userDAO, userCache, interactiveCache)
 	userService := service.NewUserService(userRepository)
 	codeCache := cache.NewRedisCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
-	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, handler, codeService)
+	emailService := ioc.InitEmailService()
+	codeService := service.NewCodeService(codeRepository, smsService, emailService)
+	followServiceClient := ioc.InitFollowClient()
+	twoFactorDAO := dao.NewGORMTwoFactorDAO(db)
+	twoFactorRepository := repository.NewGORMTwoFactorRepository(twoFactorDAO)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepository)
+	loginAttemptCache := cache.NewRedisLoginAttemptCache(cmdable)
+	loginAttemptRepository := repository.NewCachedLoginAttemptRepository(loginAttemptCache)
+	captchaService := ioc.InitCaptchaService()
+	loginGuardService := service.NewLoginGuardService(loginAttemptRepository, captchaService, loggerV1)
 	articleDao := dao.NewArticleGORMDAO(db)
+	tagDAO := dao.NewGORMTagDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCachedArticleRepository(articleDao, userRepository, articleCache, loggerV1)
+	articleRepository := repository.NewCachedArticleRepository(articleDao, tagDAO, userRepository, articleCache, loggerV1)
 	client := ioc.InitSaramaClient()
 	syncProducer := ioc.InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	banService := service.NewBanService(userBanRepository, articleRepository, producer, loggerV1)
+	mergeTicketKey := ioc.InitMergeTicketKey(loggerV1)
+	twoFactorKey := ioc.InitTwoFactorKey(loggerV1)
+	userHandler := web.NewUserHandler(userService, handler, codeService, followServiceClient, twoFactorService, loginGuardService, banService, mergeTicketKey, twoFactorKey)
+	filter := ioc.InitSensitiveFilter(loggerV1)
+	moderationDAO := dao.NewGORMModerationDAO(db)
+	moderationRepository := repository.NewGORMModerationRepository(moderationDAO)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentCache := cache.NewCommentRedisCache(cmdable)
+	commentRepository := repository.NewCachedCommentRepository(commentDAO, commentCache, loggerV1)
+	activityProducer := activity.NewSaramaSyncProducer(syncProducer)
+	moderationService := service.NewModerationService(filter, moderationRepository, articleRepository, commentRepository, producer, activityProducer, loggerV1)
+	articleService := service.NewArticleService(articleRepository, producer, moderationService, banService, loggerV1)
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
-	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, loggerV1)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
-	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService)
-	wechatService := ioc.InitWechatService(loggerV1)
-	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2WechatHandler)
-	interactiveReadEventConsumer := article.NewInteractiveReadEventConsumer(interactiveRepository, client, loggerV1)
-	v2 := ioc.InitConsumers(interactiveReadEventConsumer)
+	interactiveService := service.NewInteractiveService(interactiveRepository, activityProducer, loggerV1)
+	articleHandler := web.NewArticleHandler(articleService, loggerV1, interactiveService, banService)
+	registry := ioc.InitOAuth2Registry(loggerV1)
+	oAuth2Handler := ioc.InitOAuth2Handler(registry, handler, userService, banService, twoFactorService, mergeTicketKey, twoFactorKey, loggerV1)
 	rankingCache := cache.NewRankingRedisCache(cmdable)
 	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
 	rankingService := service.NewBatchRankingService(interactiveService, articleService, rankingRepository)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewGORMHistoryRecordRepository(historyRecordDAO)
+	recommendCache := cache.NewRecommendRedisCache(cmdable)
+	recommendRepository := repository.NewCachedRecommendRepository(recommendCache)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewFollowRedisCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
+	recommendService := service.NewBatchRecommendService(articleService, interactiveService, rankingService, historyRecordRepository, followRepository, recommendRepository, loggerV1)
+	feedDAO := dao.NewGORMFeedDAO(db)
+	feedCache := cache.NewFeedRedisCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedDAO, feedCache, loggerV1)
+	followRelationService := service.NewFollowRelationService(followRepository, activityProducer, loggerV1)
+	feedService := service.NewFeedService(feedRepository, followRelationService, articleService)
+	feedHandler := web.NewFeedHandler(recommendService, feedService, interactiveService, loggerV1)
+	commentServiceClient := ioc.InitCommentClient()
+	commentHandler := web.NewCommentHandler(commentServiceClient, banService)
+	userRoleDAO := dao.NewGORMUserRoleDAO(db)
+	userRoleCache := cache.NewRedisUserRoleCache(cmdable)
+	userRoleRepository := repository.NewCachedUserRoleRepository(userRoleDAO, userRoleCache, loggerV1)
+	rbacService := ioc.InitRBACService(userRoleRepository)
+	auditLogDAO := dao.NewGORMAuditLogDAO(db)
+	auditLogRepository := repository.NewGORMAuditLogRepository(auditLogDAO)
+	auditService := service.NewAuditService(auditLogRepository)
+	adminMiddlewareBuilder := middleware.NewAdminMiddlewareBuilder(rbacService, auditService, loggerV1)
+	moderationHandler := web.NewModerationHandler(moderationService, adminMiddlewareBuilder, loggerV1)
+	followHandler := web.NewFollowHandler(followServiceClient)
+	rewardServiceClient := ioc.InitRewardClient()
+	rewardHandler := web.NewRewardHandler(rewardServiceClient, articleService)
+	signer := ioc.InitWechatPaySigner()
+	notifyHandler := ioc.InitWechatNotifyHandler(signer)
+	paymentDAO := dao.NewPaymentGORMDAO(db)
+	paymentRepository := repository.NewGORMPaymentRepository(paymentDAO)
+	paymentProducer := payment.NewSaramaSyncProducer(syncProducer)
+	paymentService := ioc.InitPaymentService(signer, paymentRepository, paymentProducer, loggerV1)
+	wechatPaymentHandler := web.NewWechatPaymentHandler(notifyHandler, paymentService, loggerV1)
+	accountDAO := dao.NewAccountGORMDAO(db)
+	accountRepository := repository.NewGORMAccountRepository(accountDAO)
+	accountService := service.NewAccountService(accountRepository)
+	accountHandler := web.NewAccountHandler(accountService)
+	withdrawalDAO := dao.NewGORMWithdrawalDAO(db)
+	withdrawalRepository := repository.NewGORMWithdrawalRepository(withdrawalDAO)
+	executor := ioc.InitPayoutExecutor()
+	withdrawService := ioc.InitWithdrawService(withdrawalRepository, accountService, executor, loggerV1)
+	withdrawHandler := web.NewWithdrawHandler(withdrawService, adminMiddlewareBuilder, loggerV1)
+	notificationDAO := dao.NewGORMNotificationDAO(db)
+	notificationCache := cache.NewNotificationRedisCache(cmdable)
+	notificationRepository := repository.NewCachedNotificationRepository(notificationDAO, notificationCache)
+	broker := ioc.InitPushBroker(cmdable)
+	notificationService := service.NewNotificationService(notificationRepository, articleService, userService, broker, loggerV1)
+	hub := push.NewHub(broker, loggerV1)
+	notificationHandler := web.NewNotificationHandler(notificationService, userService, hub, handler)
+	adminUserHandler := web.NewAdminUserHandler(handler, userService, loginGuardService, banService, rbacService, adminMiddlewareBuilder)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
+	adminHandler := web.NewAdminHandler(articleService, cronJobService, auditService, adminMiddlewareBuilder)
+	jwksHandler := web.NewJWKSHandler(keys, loggerV1)
+	accessTokenHandler := web.NewAccessTokenHandler(accessTokenService)
+	dataExportDAO := dao.NewGORMDataExportDAO(db)
+	dataExportRepository := repository.NewGORMDataExportRepository(dataExportDAO)
+	storage := ioc.InitExportStorage()
+	dataExportService := service.NewDataExportService(dataExportRepository, userRepository, articleRepository, interactiveRepository, historyRecordRepository, notificationService, storage, loggerV1)
+	accountDeletionDAO := dao.NewGORMAccountDeletionDAO(db)
+	accountDeletionRepository := repository.NewGORMAccountDeletionRepository(accountDeletionDAO)
+	accountDeletionService := ioc.InitAccountDeletionService(accountDeletionRepository, userRepository, articleRepository, producer, loggerV1)
+	privacyHandler := web.NewPrivacyHandler(dataExportService, accountDeletionService, loggerV1)
+	profileService := service.NewProfileService(userRepository, articleRepository, interactiveRepository)
+	profileHandler := web.NewProfileHandler(profileService, userService, followServiceClient, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2Handler, feedHandler, commentHandler, moderationHandler, followHandler, rewardHandler, wechatPaymentHandler, accountHandler, withdrawHandler, notificationHandler, adminUserHandler, adminHandler, jwksHandler, accessTokenHandler, privacyHandler, profileHandler)
+	interactiveReadEventConsumer := article.NewInteractiveReadEventConsumer(interactiveRepository, client, loggerV1)
+	historyRecordConsumer := article.NewHistoryRecordConsumer(historyRecordRepository, client, loggerV1)
+	eventConsumer := feed.NewEventConsumer(feedService, client, loggerV1)
+	wechatPaymentServiceClient := ioc.InitPaymentClient()
+	accountServiceClient := ioc.InitAccountClient()
+	rewardDAO := dao.NewGORMRewardDAO(db)
+	rewardCache := cache.NewRewardRedisCache(cmdable)
+	rewardRepository := repository.NewCachedRewardRepository(rewardDAO, rewardCache)
+	rewardService := ioc.InitRewardService(wechatPaymentServiceClient, accountServiceClient, rewardRepository, activityProducer, loggerV1)
+	paymentEventConsumer := reward.NewPaymentEventConsumer(rewardService, client, loggerV1)
+	notificationEventConsumer := notification.NewEventConsumer(notificationService, client, loggerV1)
+	v2 := ioc.InitConsumers(interactiveReadEventConsumer, historyRecordConsumer, eventConsumer, paymentEventConsumer, notificationEventConsumer, hub)
 	rlockClient := ioc.InitRlockClient(cmdable)
 	rankingJob := ioc.InitRankingJob(rankingService, rlockClient, loggerV1)
-	cron := ioc.InitJobs(loggerV1, rankingJob)
+	recommendJob := ioc.InitRecommendJob(recommendService, rlockClient, loggerV1)
+	syncWechatOrderJob := job.NewSyncWechatOrderJob(paymentService, loggerV1)
+	dataExportJob := job.NewDataExportJob(dataExportService, loggerV1)
+	accountDeletionJob := job.NewAccountDeletionJob(accountDeletionService, handler, loggerV1)
+	cron := ioc.InitJobs(loggerV1, rankingJob, recommendJob, syncWechatOrderJob, dataExportJob, accountDeletionJob)
+	commentService := service.NewCommentService(commentRepository, moderationService, activityProducer, loggerV1)
+	commentServiceServer := grpc.NewCommentServiceServer(commentService)
+	followServiceServer := grpc.NewFollowServiceServer(followRelationService)
+	rewardServiceServer := grpc.NewRewardServiceServer(rewardService)
+	wechatPaymentServiceServer := grpc.NewWechatPaymentServiceServer(paymentService)
+	accountServiceServer := grpc.NewAccountServiceServer(accountService)
+	server := ioc.InitGRPCxServer(commentServiceServer, followServiceServer, rewardServiceServer, wechatPaymentServiceServer, accountServiceServer)
 	app := &App{
-		server:    engine,
-		consumers: v2,
-		cron:      cron,
+		server:     engine,
+		consumers:  v2,
+		cron:       cron,
+		grpcServer: server,
 	}
 	return app
 }